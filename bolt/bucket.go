@@ -288,6 +288,16 @@ func (c *Client) findBuckets(ctx context.Context, tx *bolt.Tx, filter platform.B
 		filter.OrganizationID = &o.ID
 	}
 
+	if len(opts) > 0 && opts[0].SortBy != "" {
+		// a sorted page can only be taken once every matching bucket is sorted
+		bs, err := c.findBuckets(ctx, tx, filter)
+		if err != nil {
+			return nil, err
+		}
+		platform.SortBuckets(opts[0], bs)
+		return platform.PageBuckets(opts[0], bs), nil
+	}
+
 	var offset, limit, count int
 	var descending bool
 	if len(opts) > 0 {
@@ -523,6 +533,10 @@ func (c *Client) updateBucket(ctx context.Context, tx *bolt.Tx, id platform.ID,
 		b.RetentionPeriod = *upd.RetentionPeriod
 	}
 
+	if upd.ShardGroupDuration != nil {
+		b.ShardGroupDuration = *upd.ShardGroupDuration
+	}
+
 	if upd.Description != nil {
 		b.Description = *upd.Description
 	}
@@ -2,6 +2,7 @@ package influxdb
 
 import (
 	"context"
+	"sort"
 	"strings"
 	"time"
 )
@@ -40,6 +41,8 @@ type Bucket struct {
 	Description         string        `json:"description"`
 	RetentionPolicyName string        `json:"rp,omitempty"` // This to support v1 sources
 	RetentionPeriod     time.Duration `json:"retentionPeriod"`
+	SchemaType          SchemaType    `json:"schemaType,omitempty"`
+	ShardGroupDuration  time.Duration `json:"shardGroupDuration,omitempty"` // unset lets the storage engine pick a default from the retention period
 	CRUDLog
 }
 
@@ -62,6 +65,71 @@ func ParseBucketType(s string) BucketType {
 	return BucketTypeUser
 }
 
+// SchemaType describes how the schema of the data written to a bucket is enforced.
+type SchemaType string
+
+const (
+	// SchemaTypeImplicit is a bucket whose schema is derived from the data written to it.
+	SchemaTypeImplicit SchemaType = "implicit"
+	// SchemaTypeExplicit is a bucket whose schema must be declared before data is written to it.
+	SchemaTypeExplicit SchemaType = "explicit"
+)
+
+// Valid determines if the schema type is one of the known schema types.
+func (s SchemaType) Valid() bool {
+	switch s {
+	case SchemaTypeImplicit, SchemaTypeExplicit:
+		return true
+	default:
+		return false
+	}
+}
+
+// BucketSortByFields are the fields a list of buckets may be sorted by.
+var BucketSortByFields = []string{"name", "createdAt", "updatedAt"}
+
+// SortBuckets sorts a slice of buckets by the field of the find options,
+// in descending order when the options are descending.
+func SortBuckets(opts FindOptions, bs []*Bucket) {
+	var less func(i, j int) bool
+	switch opts.SortBy {
+	case "name":
+		less = func(i, j int) bool {
+			return bs[i].Name < bs[j].Name
+		}
+	case "createdAt":
+		less = func(i, j int) bool {
+			return bs[i].CreatedAt.Before(bs[j].CreatedAt)
+		}
+	case "updatedAt":
+		less = func(i, j int) bool {
+			return bs[i].UpdatedAt.Before(bs[j].UpdatedAt)
+		}
+	default:
+		return
+	}
+
+	sort.SliceStable(bs, func(i, j int) bool {
+		if opts.Descending {
+			return less(j, i)
+		}
+		return less(i, j)
+	})
+}
+
+// PageBuckets returns the page of buckets selected by the offset and limit
+// of the find options.
+func PageBuckets(opts FindOptions, bs []*Bucket) []*Bucket {
+	if opts.Offset >= len(bs) {
+		return []*Bucket{}
+	}
+	bs = bs[opts.Offset:]
+	if opts.Limit > 0 && len(bs) > opts.Limit {
+		bs = bs[:opts.Limit]
+	}
+	return bs
+}
+
 // ops for buckets error and buckets op logs.
 var (
 	OpFindBucketByID = "FindBucketByID"
@@ -99,9 +167,10 @@ type BucketService interface {
 // BucketUpdate represents updates to a bucket.
 // Only fields which are set are updated.
 type BucketUpdate struct {
-	Name            *string        `json:"name,omitempty"`
-	Description     *string        `json:"description,omitempty"`
-	RetentionPeriod *time.Duration `json:"retentionPeriod,omitempty"`
+	Name               *string        `json:"name,omitempty"`
+	Description        *string        `json:"description,omitempty"`
+	RetentionPeriod    *time.Duration `json:"retentionPeriod,omitempty"`
+	ShardGroupDuration *time.Duration `json:"shardGroupDuration,omitempty"`
 }
 
 // BucketFilter represents a set of filter that restrict the returned results.
@@ -2,11 +2,15 @@ package main
 
 import (
 	"context"
+	"errors"
+	"io"
 	"os"
+	"time"
 
 	platform "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/cmd/influx/internal"
 	"github.com/influxdata/influxdb/http"
+	"github.com/influxdata/influxdb/jsonweb"
 	"github.com/spf13/cobra"
 )
 
@@ -374,8 +378,9 @@ func newAuthorizationService(f Flags) (platform.AuthorizationService, error) {
 		return newLocalKVService()
 	}
 	return &http.AuthorizationService{
-		Addr:  flags.host,
-		Token: flags.token,
+		Addr:               flags.host,
+		Token:              flags.token,
+		InsecureSkipVerify: flags.skipVerify,
 	}, nil
 }
 
@@ -659,3 +664,78 @@ func authorizationInactiveF(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// AuthorizationInspectFlags are command line args used when inspecting a token
+type AuthorizationInspectFlags struct {
+	key string
+}
+
+var authorizationInspectFlags AuthorizationInspectFlags
+
+func init() {
+	authorizationInspectCmd := &cobra.Command{
+		Use:   "inspect [token]",
+		Short: "Decode the claims of a JWT token",
+		Long:  "Decode the claims of a JWT token. The signature of the token is only verified when a key is provided.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return inspectToken(os.Stdout, args[0], authorizationInspectFlags.key)
+		},
+	}
+
+	authorizationInspectCmd.Flags().StringVarP(&authorizationInspectFlags.key, "key", "k", "", "The symmetric key to verify the token signature with")
+
+	authorizationCmd.AddCommand(authorizationInspectCmd)
+}
+
+// inspectToken writes the claims of the JWT token to w. The signature of the
+// token is verified with the key when one is provided.
+func inspectToken(w io.Writer, v, key string) error {
+	var (
+		token *jsonweb.Token
+		err   error
+	)
+	if key != "" {
+		keyStore := jsonweb.KeyStoreFunc(func(string) ([]byte, error) {
+			return []byte(key), nil
+		})
+		token, err = jsonweb.NewTokenParser(keyStore).Parse(v)
+	} else {
+		token, err = jsonweb.Decode(v)
+	}
+	if jsonweb.IsMalformedError(err) {
+		return errors.New("token is not a JWT")
+	}
+	if err != nil {
+		return err
+	}
+
+	expires := "never"
+	if token.ExpiresAt != 0 {
+		expires = time.Unix(token.ExpiresAt, 0).UTC().Format(time.RFC3339)
+	}
+
+	ps := []string{}
+	for _, p := range token.Permissions {
+		ps = append(ps, p.String())
+	}
+
+	tw := internal.NewTabWriter(w)
+	tw.WriteHeaders(
+		"KeyID",
+		"Audience",
+		"Expires",
+		"Verified",
+		"Permissions",
+	)
+	tw.Write(map[string]interface{}{
+		"KeyID":       token.KeyID,
+		"Audience":    token.Audience,
+		"Expires":     expires,
+		"Verified":    key != "",
+		"Permissions": ps,
+	})
+	tw.Flush()
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/jsonweb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInspectToken(t *testing.T) {
+	bucketID, orgID := platform.ID(1), platform.ID(2)
+	token, err := jsonweb.Sign(&jsonweb.Token{
+		StandardClaims: jwt.StandardClaims{
+			Audience:  "gateway.influxdata.com",
+			ExpiresAt: 4102444800,
+		},
+		KeyID: "some-key",
+		Permissions: []platform.Permission{
+			{
+				Action: platform.WriteAction,
+				Resource: platform.Resource{
+					Type:  platform.BucketsResourceType,
+					ID:    &bucketID,
+					OrgID: &orgID,
+				},
+			},
+		},
+	}, []byte("correct-key"))
+	require.NoError(t, err)
+
+	t.Run("decodes the claims without a key", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, inspectToken(&buf, token, ""))
+
+		out := buf.String()
+		assert.Contains(t, out, "some-key")
+		assert.Contains(t, out, "gateway.influxdata.com")
+		assert.Contains(t, out, "2100-01-01T00:00:00Z")
+		assert.Contains(t, out, "false")
+		assert.Contains(t, out, "write:orgs/0000000000000002/buckets/0000000000000001")
+	})
+
+	t.Run("verifies the signature with the key", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, inspectToken(&buf, token, "correct-key"))
+		assert.Contains(t, buf.String(), "true")
+	})
+
+	t.Run("rejects a signature of another key", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.Error(t, inspectToken(&buf, token, "wrong-key"))
+	})
+
+	t.Run("reports a token that is not a JWT", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := inspectToken(&buf, "not-a-jwt-token==", "")
+		require.Error(t, err)
+		assert.Equal(t, "token is not a JWT", err.Error())
+	})
+}
@@ -2,14 +2,19 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"strings"
 	"time"
 
 	platform "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/cmd/influx/internal"
 	"github.com/influxdata/influxdb/http"
+	isatty "github.com/mattn/go-isatty"
 	"github.com/spf13/cobra"
+	input "github.com/tcnksm/go-input"
 )
 
 // Bucket Command
@@ -25,9 +30,11 @@ func bucketF(cmd *cobra.Command, args []string) {
 
 // BucketCreateFlags define the Create Command
 type BucketCreateFlags struct {
-	name      string
-	orgID     string
-	retention time.Duration
+	name        string
+	description string
+	orgID       string
+	retention   string
+	force       bool
 }
 
 var bucketCreateFlags BucketCreateFlags
@@ -40,8 +47,10 @@ func init() {
 	}
 
 	bucketCreateCmd.Flags().StringVarP(&bucketCreateFlags.name, "name", "n", "", "Name of bucket that will be created")
-	bucketCreateCmd.Flags().DurationVarP(&bucketCreateFlags.retention, "retention", "r", 0, "Duration in nanoseconds data will live in bucket")
+	bucketCreateCmd.Flags().StringVarP(&bucketCreateFlags.description, "description", "d", "", "Description of bucket that will be created")
+	bucketCreateCmd.Flags().StringVarP(&bucketCreateFlags.retention, "retention", "r", "", "Duration bucket will retain data (e.g. 1h, 3d, 2w). 0 or inf is infinite retention")
 	bucketCreateCmd.Flags().StringVarP(&bucketCreateFlags.orgID, "org-id", "", "", "The ID of the organization that owns the bucket")
+	bucketCreateCmd.Flags().BoolVar(&bucketCreateFlags.force, "force", false, "Create a bucket with infinite retention without asking for confirmation")
 	bucketCreateCmd.MarkFlagRequired("name")
 
 	bucketCmd.AddCommand(bucketCreateCmd)
@@ -52,32 +61,35 @@ func newBucketService(f Flags) (platform.BucketService, error) {
 		return newLocalKVService()
 	}
 	return &http.BucketService{
-		Addr:  f.host,
-		Token: f.token,
+		Addr:               f.host,
+		Token:              f.token,
+		InsecureSkipVerify: f.skipVerify,
 	}, nil
 }
 
 func bucketCreateF(cmd *cobra.Command, args []string) error {
-	if bucketCreateFlags.orgID == "" {
-		return fmt.Errorf("must specify org-id")
+	b, err := newBucketCreateReq(bucketCreateFlags)
+	if err != nil {
+		return err
 	}
 
-	s, err := newBucketService(flags)
+	ui := &input.UI{
+		Writer: os.Stdout,
+		Reader: os.Stdin,
+	}
+	interactive := isatty.IsTerminal(os.Stdin.Fd())
+	confirmed, err := confirmInfiniteRetention(os.Stdout, ui, interactive, bucketCreateFlags.force, b)
 	if err != nil {
-		return fmt.Errorf("failed to initialize bucket service client: %v", err)
+		return err
 	}
-
-	b := &platform.Bucket{
-		Name:            bucketCreateFlags.name,
-		RetentionPeriod: bucketCreateFlags.retention,
+	if !confirmed {
+		fmt.Fprintln(os.Stdout, "aborted creation of bucket")
+		return nil
 	}
 
-	if bucketCreateFlags.orgID != "" {
-		id, err := platform.IDFromString(bucketCreateFlags.orgID)
-		if err != nil {
-			return fmt.Errorf("failed to decode org id %q: %v", bucketCreateFlags.orgID, err)
-		}
-		b.OrgID = *id
+	s, err := newBucketService(flags)
+	if err != nil {
+		return fmt.Errorf("failed to initialize bucket service client: %v", err)
 	}
 
 	if err := s.CreateBucket(context.Background(), b); err != nil {
@@ -89,20 +101,86 @@ func bucketCreateF(cmd *cobra.Command, args []string) error {
 		"ID",
 		"Name",
 		"Retention",
-		"Org",
-		"OrganizationID",
+		"Description",
+		"OrgID",
+		"Self",
+		"Write",
 	)
 	w.Write(map[string]interface{}{
-		"ID":        b.ID.String(),
-		"Name":      b.Name,
-		"Retention": b.RetentionPeriod,
-		"OrgID":     b.OrgID.String(),
+		"ID":          b.ID.String(),
+		"Name":        b.Name,
+		"Retention":   formatDuration(b.RetentionPeriod),
+		"Description": b.Description,
+		"OrgID":       b.OrgID.String(),
+		"Self":        fmt.Sprintf("/api/v2/buckets/%s", b.ID),
+		"Write":       fmt.Sprintf("/api/v2/write?org=%s&bucket=%s", b.OrgID, b.ID),
 	})
 	w.Flush()
 
 	return nil
 }
 
+// newBucketCreateReq constructs the bucket to be created from the create
+// command flags.
+func newBucketCreateReq(f BucketCreateFlags) (*platform.Bucket, error) {
+	if f.orgID == "" {
+		return nil, fmt.Errorf("must specify org-id")
+	}
+
+	orgID, err := platform.IDFromString(f.orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode org id %q: %v", f.orgID, err)
+	}
+
+	retention, err := parseRetention(f.retention)
+	if err != nil {
+		return nil, err
+	}
+
+	return &platform.Bucket{
+		OrgID:           *orgID,
+		Name:            f.name,
+		Description:     f.description,
+		RetentionPeriod: retention,
+	}, nil
+}
+
+// confirmInfiniteRetention warns that the data of a bucket created with an
+// infinite retention will never expire and asks for confirmation. The prompt
+// is skipped with --force. Without a terminal to prompt on, the bucket is only
+// created when --force is set.
+func confirmInfiniteRetention(w io.Writer, ui *input.UI, interactive, force bool, b *platform.Bucket) (bool, error) {
+	if force || b.RetentionPeriod != platform.InfiniteRetention {
+		return true, nil
+	}
+
+	fmt.Fprintf(w, "Warning: bucket %q has infinite retention, data written to it will never expire\n", b.Name)
+	if !interactive {
+		return false, errors.New("refusing to create a bucket with infinite retention without confirmation; use --force to create it")
+	}
+
+	confirm := getInput(ui, "Confirm creation of the bucket (y/n)", "n")
+	return strings.ToLower(confirm) == "y", nil
+}
+
+// parseRetention parses a retention period in the same units a pkg bucket
+// supports. An empty value, 0, or inf all indicate an infinite retention.
+func parseRetention(s string) (time.Duration, error) {
+	switch s = strings.ToLower(strings.TrimSpace(s)); s {
+	case "", "0", "inf":
+		return 0, nil
+	}
+
+	dur, err := http.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid retention %q: must be a duration (e.g. 1h, 3d, 2w) or inf", s)
+	}
+	if dur < 0 {
+		return 0, fmt.Errorf("invalid retention %q: must not be negative", s)
+	}
+	return dur, nil
+}
+
 // BucketFindFlags define the Find Command
 type BucketFindFlags struct {
 	name  string
@@ -258,7 +336,10 @@ func bucketUpdateF(cmd *cobra.Command, args []string) error {
 
 // BucketDeleteFlags define the Delete command
 type BucketDeleteFlags struct {
-	id string
+	id    string
+	name  string
+	org   string
+	orgID string
 }
 
 var bucketDeleteFlags BucketDeleteFlags
@@ -269,19 +350,19 @@ func bucketDeleteF(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to initialize bucket service client: %v", err)
 	}
 
-	var id platform.ID
-	if err := id.DecodeFromString(bucketDeleteFlags.id); err != nil {
-		return fmt.Errorf("failed to decode bucket id %q: %v", bucketDeleteFlags.id, err)
+	orgSvc, err := newOrganizationService(flags)
+	if err != nil {
+		return fmt.Errorf("failed to initialize organization service client: %v", err)
 	}
 
 	ctx := context.Background()
-	b, err := s.FindBucketByID(ctx, id)
+	b, err := findBucketToDelete(ctx, s, orgSvc, bucketDeleteFlags)
 	if err != nil {
-		return fmt.Errorf("failed to find bucket with id %q: %v", id, err)
+		return err
 	}
 
-	if err = s.DeleteBucket(ctx, id); err != nil {
-		return fmt.Errorf("failed to delete bucket with id %q: %v", id, err)
+	if err = s.DeleteBucket(ctx, b.ID); err != nil {
+		return fmt.Errorf("failed to delete bucket with id %q: %v", b.ID, err)
 	}
 
 	w := internal.NewTabWriter(os.Stdout)
@@ -305,6 +386,65 @@ func bucketDeleteF(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// findBucketToDelete finds the bucket by its ID, or resolves the bucket by its
+// name within the organization when no ID is provided.
+func findBucketToDelete(ctx context.Context, s platform.BucketService, orgSvc platform.OrganizationService, f BucketDeleteFlags) (*platform.Bucket, error) {
+	if f.id != "" && f.name != "" {
+		return nil, fmt.Errorf("must specify exactly one of id and name")
+	}
+
+	if f.id != "" {
+		id, err := platform.IDFromString(f.id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode bucket id %q: %v", f.id, err)
+		}
+
+		b, err := s.FindBucketByID(ctx, *id)
+		if platform.ErrorCode(err) == platform.ENotFound {
+			return nil, fmt.Errorf("bucket with id %q not found", f.id)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to find bucket with id %q: %v", f.id, err)
+		}
+		return b, nil
+	}
+
+	if f.name == "" {
+		return nil, fmt.Errorf("must specify either id or name")
+	}
+
+	if (f.org == "") == (f.orgID == "") {
+		return nil, fmt.Errorf("must specify exactly one of org and org-id when deleting by name")
+	}
+
+	orgName := f.org
+	var orgID platform.ID
+	if f.orgID != "" {
+		if err := orgID.DecodeFromString(f.orgID); err != nil {
+			return nil, fmt.Errorf("failed to decode org id %q: %v", f.orgID, err)
+		}
+		orgName = f.orgID
+	} else {
+		o, err := orgSvc.FindOrganization(ctx, platform.OrganizationFilter{Name: &f.org})
+		if platform.ErrorCode(err) == platform.ENotFound {
+			return nil, fmt.Errorf("organization %q not found", f.org)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to find organization %q: %v", f.org, err)
+		}
+		orgID = o.ID
+	}
+
+	b, err := s.FindBucketByName(ctx, orgID, f.name)
+	if platform.ErrorCode(err) == platform.ENotFound {
+		return nil, fmt.Errorf("bucket %q not found in organization %q", f.name, orgName)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find bucket %q: %v", f.name, err)
+	}
+	return b, nil
+}
+
 func init() {
 	bucketDeleteCmd := &cobra.Command{
 		Use:   "delete",
@@ -312,8 +452,10 @@ func init() {
 		RunE:  wrapCheckSetup(bucketDeleteF),
 	}
 
-	bucketDeleteCmd.Flags().StringVarP(&bucketDeleteFlags.id, "id", "i", "", "The bucket ID (required)")
-	bucketDeleteCmd.MarkFlagRequired("id")
+	bucketDeleteCmd.Flags().StringVarP(&bucketDeleteFlags.id, "id", "i", "", "The bucket ID, required if name isn't provided")
+	bucketDeleteCmd.Flags().StringVarP(&bucketDeleteFlags.name, "name", "n", "", "The bucket name, org or org-id will be required by choosing this")
+	bucketDeleteCmd.Flags().StringVarP(&bucketDeleteFlags.org, "org", "o", "", "The name of the organization that owns the bucket")
+	bucketDeleteCmd.Flags().StringVarP(&bucketDeleteFlags.orgID, "org-id", "", "", "The ID of the organization that owns the bucket")
 
 	bucketCmd.AddCommand(bucketDeleteCmd)
 }
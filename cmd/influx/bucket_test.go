@@ -0,0 +1,223 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	input "github.com/tcnksm/go-input"
+)
+
+func TestBucketCreate(t *testing.T) {
+	t.Run("parses retention", func(t *testing.T) {
+		tests := []struct {
+			in       string
+			expected time.Duration
+		}{
+			{in: "", expected: 0},
+			{in: "0", expected: 0},
+			{in: "inf", expected: 0},
+			{in: "INF", expected: 0},
+			{in: "1h", expected: time.Hour},
+			{in: "3d", expected: 72 * time.Hour},
+			{in: "2w", expected: 14 * 24 * time.Hour},
+			{in: "1w2d", expected: 9 * 24 * time.Hour},
+		}
+
+		for _, tt := range tests {
+			fn := func(t *testing.T) {
+				dur, err := parseRetention(tt.in)
+				require.NoError(t, err)
+				assert.Equal(t, tt.expected, dur)
+			}
+			t.Run(tt.in, fn)
+		}
+	})
+
+	t.Run("rejects invalid retention", func(t *testing.T) {
+		for _, in := range []string{"foo", "1x", "-1h"} {
+			_, err := parseRetention(in)
+			assert.Error(t, err, in)
+		}
+	})
+
+	t.Run("constructs create request", func(t *testing.T) {
+		b, err := newBucketCreateReq(BucketCreateFlags{
+			name:        "buck",
+			description: "desc",
+			orgID:       platform.ID(3).String(),
+			retention:   "1w",
+		})
+		require.NoError(t, err)
+
+		expected := &platform.Bucket{
+			OrgID:           platform.ID(3),
+			Name:            "buck",
+			Description:     "desc",
+			RetentionPeriod: 7 * 24 * time.Hour,
+		}
+		assert.Equal(t, expected, b)
+	})
+
+	t.Run("requires org id", func(t *testing.T) {
+		_, err := newBucketCreateReq(BucketCreateFlags{name: "buck"})
+		require.Error(t, err)
+	})
+
+	t.Run("infinite retention", func(t *testing.T) {
+		infBucket := &platform.Bucket{Name: "buck", RetentionPeriod: platform.InfiniteRetention}
+
+		newUI := func(answer string) *input.UI {
+			return &input.UI{
+				Writer: ioutil.Discard,
+				Reader: strings.NewReader(answer + "\n"),
+			}
+		}
+
+		t.Run("warns and prompts for confirmation", func(t *testing.T) {
+			for _, answer := range []string{"y", "n"} {
+				var buf bytes.Buffer
+				confirmed, err := confirmInfiniteRetention(&buf, newUI(answer), true, false, infBucket)
+				require.NoError(t, err)
+
+				assert.Equal(t, answer == "y", confirmed)
+				assert.Contains(t, buf.String(), "never expire")
+			}
+		})
+
+		t.Run("errors when not interactive", func(t *testing.T) {
+			var buf bytes.Buffer
+			confirmed, err := confirmInfiniteRetention(&buf, newUI("y"), false, false, infBucket)
+			require.Error(t, err)
+
+			assert.False(t, confirmed)
+			assert.Contains(t, buf.String(), "never expire")
+		})
+
+		t.Run("force skips the prompt", func(t *testing.T) {
+			for _, interactive := range []bool{true, false} {
+				var buf bytes.Buffer
+				confirmed, err := confirmInfiniteRetention(&buf, newUI("n"), interactive, true, infBucket)
+				require.NoError(t, err)
+
+				assert.True(t, confirmed)
+				assert.Empty(t, buf.String())
+			}
+		})
+
+		t.Run("finite retention is not prompted", func(t *testing.T) {
+			var buf bytes.Buffer
+			b := &platform.Bucket{Name: "buck", RetentionPeriod: time.Hour}
+			confirmed, err := confirmInfiniteRetention(&buf, newUI("n"), false, false, b)
+			require.NoError(t, err)
+
+			assert.True(t, confirmed)
+			assert.Empty(t, buf.String())
+		})
+	})
+}
+
+func TestBucketDelete(t *testing.T) {
+	notFoundErr := &platform.Error{Code: platform.ENotFound, Msg: "not found"}
+
+	newBucketSVC := func(expected *platform.Bucket) *mock.BucketService {
+		s := mock.NewBucketService()
+		s.FindBucketByIDFn = func(_ context.Context, id platform.ID) (*platform.Bucket, error) {
+			if id != expected.ID {
+				return nil, notFoundErr
+			}
+			return expected, nil
+		}
+		s.FindBucketByNameFn = func(_ context.Context, orgID platform.ID, name string) (*platform.Bucket, error) {
+			if orgID != expected.OrgID || name != expected.Name {
+				return nil, notFoundErr
+			}
+			return expected, nil
+		}
+		return s
+	}
+
+	newOrgSVC := func(expected *platform.Organization) *mock.OrganizationService {
+		s := mock.NewOrganizationService()
+		s.FindOrganizationF = func(_ context.Context, f platform.OrganizationFilter) (*platform.Organization, error) {
+			if f.Name == nil || *f.Name != expected.Name {
+				return nil, notFoundErr
+			}
+			return expected, nil
+		}
+		return s
+	}
+
+	org := &platform.Organization{ID: 3, Name: "org1"}
+	bkt := &platform.Bucket{ID: 1, OrgID: org.ID, Name: "buck"}
+
+	t.Run("finds bucket by id", func(t *testing.T) {
+		b, err := findBucketToDelete(context.Background(), newBucketSVC(bkt), newOrgSVC(org), BucketDeleteFlags{
+			id: bkt.ID.String(),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, bkt, b)
+	})
+
+	t.Run("resolves bucket by name and org name", func(t *testing.T) {
+		b, err := findBucketToDelete(context.Background(), newBucketSVC(bkt), newOrgSVC(org), BucketDeleteFlags{
+			name: bkt.Name,
+			org:  org.Name,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, bkt, b)
+	})
+
+	t.Run("resolves bucket by name and org id", func(t *testing.T) {
+		b, err := findBucketToDelete(context.Background(), newBucketSVC(bkt), newOrgSVC(org), BucketDeleteFlags{
+			name:  bkt.Name,
+			orgID: org.ID.String(),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, bkt, b)
+	})
+
+	t.Run("reports missing bucket by id", func(t *testing.T) {
+		_, err := findBucketToDelete(context.Background(), newBucketSVC(bkt), newOrgSVC(org), BucketDeleteFlags{
+			id: platform.ID(2).String(),
+		})
+		require.Error(t, err)
+		assert.Equal(t, `bucket with id "0000000000000002" not found`, err.Error())
+	})
+
+	t.Run("reports missing bucket by name", func(t *testing.T) {
+		_, err := findBucketToDelete(context.Background(), newBucketSVC(bkt), newOrgSVC(org), BucketDeleteFlags{
+			name: "other",
+			org:  org.Name,
+		})
+		require.Error(t, err)
+		assert.Equal(t, `bucket "other" not found in organization "org1"`, err.Error())
+	})
+
+	t.Run("rejects invalid flag combinations", func(t *testing.T) {
+		tests := []struct {
+			name  string
+			flags BucketDeleteFlags
+		}{
+			{name: "no id or name", flags: BucketDeleteFlags{}},
+			{name: "id and name", flags: BucketDeleteFlags{id: bkt.ID.String(), name: bkt.Name}},
+			{name: "name without org", flags: BucketDeleteFlags{name: bkt.Name}},
+			{name: "name with org and org id", flags: BucketDeleteFlags{name: bkt.Name, org: org.Name, orgID: org.ID.String()}},
+		}
+
+		for _, tt := range tests {
+			fn := func(t *testing.T) {
+				_, err := findBucketToDelete(context.Background(), newBucketSVC(bkt), newOrgSVC(org), tt.flags)
+				require.Error(t, err)
+			}
+			t.Run(tt.name, fn)
+		}
+	})
+}
@@ -48,6 +48,7 @@ func init() {
 	deleteCmd.PersistentFlags().StringVarP(&deleteFlags.Start, "start", "", "", "the start time in RFC3339Nano format, exp 2009-01-02T23:00:00Z")
 	deleteCmd.PersistentFlags().StringVarP(&deleteFlags.Stop, "stop", "", "", "the stop time in RFC3339Nano format, exp 2009-01-02T23:00:00Z")
 	deleteCmd.PersistentFlags().StringVarP(&deleteFlags.Predicate, "predicate", "p", "", "sql like predicate string, exp 'tag1=\"v1\" and (tag2=123)'")
+	deleteCmd.PersistentFlags().BoolVar(&deleteFlags.ConfirmFullDelete, "confirm-full-delete", false, "Delete all data in the time range when no predicate is provided")
 }
 
 func fluxDeleteF(cmd *cobra.Command, args []string) error {
@@ -65,9 +66,14 @@ func fluxDeleteF(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("both start and stop are required")
 	}
 
+	if deleteFlags.Predicate == "" && !deleteFlags.ConfirmFullDelete {
+		return fmt.Errorf("deleting without a predicate removes all data in the time range; provide --confirm-full-delete to continue")
+	}
+
 	s := &http.DeleteService{
-		Addr:  flags.host,
-		Token: flags.token,
+		Addr:               flags.host,
+		Token:              flags.token,
+		InsecureSkipVerify: flags.skipVerify,
 	}
 
 	ctx = signals.WithStandardSignals(ctx)
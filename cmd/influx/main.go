@@ -3,10 +3,13 @@ package main
 import (
 	"context"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
 
+	"github.com/BurntSushi/toml"
 	"github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/bolt"
 	"github.com/influxdata/influxdb/cmd/influx/internal"
@@ -41,6 +44,7 @@ func init() {
 		queryCmd,
 		replCmd,
 		setupCmd,
+		sourceCmd,
 		taskCmd,
 		userCmd,
 		writeCmd,
@@ -56,14 +60,22 @@ func init() {
 		flags.token = tok
 	}
 
-	influxCmd.PersistentFlags().StringVar(&flags.host, "host", "http://localhost:9999", "HTTP address of Influx")
+	influxCmd.PersistentFlags().StringVar(&flags.host, "host", defaultHostAddr, "HTTP address of Influx")
 	viper.BindEnv("HOST")
-	if h := viper.GetString("HOST"); h != "" {
+	if configsPath, err := defaultConfigsPath(); err == nil {
+		flags.host = defaultHost(viper.GetString("HOST"), configsPath)
+	} else if h := viper.GetString("HOST"); h != "" {
 		flags.host = h
 	}
 
 	influxCmd.PersistentFlags().BoolVar(&flags.local, "local", false, "Run commands locally against the filesystem")
 
+	influxCmd.PersistentFlags().BoolVar(&flags.skipVerify, "skip-verify", false, "SkipVerify controls whether a client verifies the server's certificate chain and host name")
+	influxCmd.PersistentFlags().BoolVar(&flags.verbose, "verbose", false, "Print the version of the server the command ran against")
+	influxCmd.PersistentPreRun = func(cmd *cobra.Command, args []string) {
+		warnInsecureSkipVerify(os.Stderr, flags.skipVerify)
+	}
+
 	// Override help on all the commands tree
 	walk(influxCmd, func(c *cobra.Command) {
 		c.Flags().BoolP("help", "h", false, fmt.Sprintf("Help for the %s command ", c.Name()))
@@ -71,16 +83,40 @@ func init() {
 }
 
 func main() {
-	if err := influxCmd.Execute(); err != nil {
+	err := influxCmd.Execute()
+	if flags.verbose {
+		printServerVersion(os.Stderr)
+	}
+	if err != nil {
 		os.Exit(1)
 	}
 }
 
+// printServerVersion prints the version of the server last reported in a
+// response, commands that never reach the server print nothing.
+func printServerVersion(w io.Writer) {
+	v, ok := http.LastServerVersion()
+	if !ok {
+		return
+	}
+	fmt.Fprintf(w, "server version: %s\n", v)
+}
+
+// warnInsecureSkipVerify writes the warning for the clients of the command
+// skipping TLS verification, it is written once before the command runs.
+func warnInsecureSkipVerify(w io.Writer, skipVerify bool) {
+	if skipVerify {
+		fmt.Fprintln(w, http.InsecureSkipVerifyWarning)
+	}
+}
+
 // Flags contains all the CLI flag values for influx.
 type Flags struct {
-	token string
-	host  string
-	local bool
+	token      string
+	host       string
+	local      bool
+	skipVerify bool
+	verbose    bool
 }
 
 var flags Flags
@@ -105,6 +141,64 @@ func getTokenFromDefaultPath() (string, error) {
 	return string(b), nil
 }
 
+const defaultHostAddr = "http://localhost:9999"
+
+func defaultConfigsPath() (string, error) {
+	dir, err := fs.InfluxDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "configs"), nil
+}
+
+// defaultHost is the host used when the --host flag is not given. The
+// INFLUX_HOST environment variable takes precedence over the url of the
+// active profile in the configs file.
+func defaultHost(envHost, configsPath string) string {
+	if envHost != "" {
+		return envHost
+	}
+	if h, err := getHostFromConfigs(configsPath); err == nil && h != "" {
+		return h
+	}
+	return defaultHostAddr
+}
+
+// configProfile is a named set of connection defaults in the configs file:
+//
+//	[default]
+//	  url = "http://localhost:9999"
+//	  active = true
+type configProfile struct {
+	URL    string `toml:"url"`
+	Active bool   `toml:"active"`
+}
+
+// getHostFromConfigs returns the url of the active profile in the configs
+// file, the default profile is used when no profile is active.
+func getHostFromConfigs(path string) (string, error) {
+	var profiles map[string]configProfile
+	if _, err := toml.DecodeFile(path, &profiles); err != nil {
+		return "", err
+	}
+
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if p := profiles[name]; p.Active {
+			return p.URL, nil
+		}
+	}
+	if p, ok := profiles["default"]; ok {
+		return p.URL, nil
+	}
+	return "", fmt.Errorf("no active profile in %s", path)
+}
+
 func writeTokenToPath(tok, path, dir string) error {
 	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
 		return err
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/influxdata/influxdb/http"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultHost(t *testing.T) {
+	dir, err := ioutil.TempDir("", "influx-configs")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	writeConfigs := func(t *testing.T, name, configs string) string {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		require.NoError(t, ioutil.WriteFile(path, []byte(configs), 0600))
+		return path
+	}
+
+	configsPath := writeConfigs(t, "configs", `
+[default]
+  url = "http://default:9999"
+
+[prod]
+  url = "http://prod:9999"
+  active = true
+`)
+
+	t.Run("flag takes precedence over env and config", func(t *testing.T) {
+		var host string
+		cmd := &cobra.Command{Run: func(*cobra.Command, []string) {}}
+		cmd.Flags().StringVar(&host, "host", defaultHostAddr, "")
+		host = defaultHost("http://env:9999", configsPath)
+
+		cmd.SetArgs([]string{"--host", "http://flag:9999"})
+		require.NoError(t, cmd.Execute())
+		assert.Equal(t, "http://flag:9999", host)
+	})
+
+	t.Run("env takes precedence over config", func(t *testing.T) {
+		assert.Equal(t, "http://env:9999", defaultHost("http://env:9999", configsPath))
+	})
+
+	t.Run("config provides the active profile url", func(t *testing.T) {
+		assert.Equal(t, "http://prod:9999", defaultHost("", configsPath))
+	})
+
+	t.Run("config falls back to the default profile", func(t *testing.T) {
+		path := writeConfigs(t, "configs_default", `
+[default]
+  url = "http://default:9999"
+`)
+		assert.Equal(t, "http://default:9999", defaultHost("", path))
+	})
+
+	t.Run("without env or config the local host is used", func(t *testing.T) {
+		assert.Equal(t, defaultHostAddr, defaultHost("", filepath.Join(dir, "missing")))
+	})
+}
+
+func TestWarnInsecureSkipVerify(t *testing.T) {
+	t.Run("warns when skip verify is set", func(t *testing.T) {
+		var buf bytes.Buffer
+		warnInsecureSkipVerify(&buf, true)
+		assert.Equal(t, http.InsecureSkipVerifyWarning+"\n", buf.String())
+	})
+
+	t.Run("does not warn when skip verify is not set", func(t *testing.T) {
+		var buf bytes.Buffer
+		warnInsecureSkipVerify(&buf, false)
+		assert.Empty(t, buf.String())
+	})
+}
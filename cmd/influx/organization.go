@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sync"
 
 	platform "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/cmd/influx/internal"
@@ -43,14 +44,121 @@ func init() {
 	organizationCmd.AddCommand(organizationCreateCmd)
 }
 
+// orgSVC is the org service of the invocation. The orgs it resolves are cached
+// for the remainder of the invocation.
+var orgSVC *cachedOrganizationService
+
 func newOrganizationService(f Flags) (platform.OrganizationService, error) {
+	if orgSVC != nil {
+		return orgSVC, nil
+	}
+
+	var svc platform.OrganizationService
 	if flags.local {
-		return newLocalKVService()
+		kvSVC, err := newLocalKVService()
+		if err != nil {
+			return nil, err
+		}
+		svc = kvSVC
+	} else {
+		svc = &http.OrganizationService{
+			Addr:               flags.host,
+			Token:              flags.token,
+			InsecureSkipVerify: flags.skipVerify,
+		}
+	}
+
+	orgSVC = newCachedOrganizationService(svc)
+	return orgSVC, nil
+}
+
+// cachedOrganizationService caches the orgs found by id or name, repeated
+// resolutions of an org are served from the cache rather than the underlying
+// service. Updating or deleting an org evicts it from the cache.
+type cachedOrganizationService struct {
+	platform.OrganizationService
+
+	mu     sync.Mutex
+	byID   map[platform.ID]*platform.Organization
+	byName map[string]*platform.Organization
+}
+
+func newCachedOrganizationService(svc platform.OrganizationService) *cachedOrganizationService {
+	return &cachedOrganizationService{
+		OrganizationService: svc,
+		byID:                make(map[platform.ID]*platform.Organization),
+		byName:              make(map[string]*platform.Organization),
+	}
+}
+
+func (s *cachedOrganizationService) FindOrganizationByID(ctx context.Context, id platform.ID) (*platform.Organization, error) {
+	s.mu.Lock()
+	o, ok := s.byID[id]
+	s.mu.Unlock()
+	if ok {
+		return o, nil
+	}
+
+	o, err := s.OrganizationService.FindOrganizationByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	s.put(o)
+	return o, nil
+}
+
+func (s *cachedOrganizationService) FindOrganization(ctx context.Context, filter platform.OrganizationFilter) (*platform.Organization, error) {
+	s.mu.Lock()
+	var (
+		o  *platform.Organization
+		ok bool
+	)
+	switch {
+	case filter.ID != nil && filter.Name == nil:
+		o, ok = s.byID[*filter.ID]
+	case filter.Name != nil && filter.ID == nil:
+		o, ok = s.byName[*filter.Name]
+	}
+	s.mu.Unlock()
+	if ok {
+		return o, nil
+	}
+
+	o, err := s.OrganizationService.FindOrganization(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	s.put(o)
+	return o, nil
+}
+
+func (s *cachedOrganizationService) UpdateOrganization(ctx context.Context, id platform.ID, upd platform.OrganizationUpdate) (*platform.Organization, error) {
+	s.evict(id)
+	return s.OrganizationService.UpdateOrganization(ctx, id, upd)
+}
+
+func (s *cachedOrganizationService) DeleteOrganization(ctx context.Context, id platform.ID) error {
+	s.evict(id)
+	return s.OrganizationService.DeleteOrganization(ctx, id)
+}
+
+func (s *cachedOrganizationService) put(o *platform.Organization) {
+	if o == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.byID[o.ID] = o
+	s.byName[o.Name] = o
+}
+
+func (s *cachedOrganizationService) evict(id platform.ID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if o, ok := s.byID[id]; ok {
+		delete(s.byName, o.Name)
+		delete(s.byID, id)
 	}
-	return &http.OrganizationService{
-		Addr:  flags.host,
-		Token: flags.token,
-	}, nil
 }
 
 func organizationCreateF(cmd *cobra.Command, args []string) error {
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCachedOrganizationService(t *testing.T) {
+	newSVC := func() (*cachedOrganizationService, *int) {
+		calls := 0
+		fakeSVC := mock.NewOrganizationService()
+		fakeSVC.FindOrganizationF = func(ctx context.Context, filter platform.OrganizationFilter) (*platform.Organization, error) {
+			calls++
+			return &platform.Organization{ID: platform.ID(1), Name: "org_1"}, nil
+		}
+		fakeSVC.FindOrganizationByIDF = func(ctx context.Context, id platform.ID) (*platform.Organization, error) {
+			calls++
+			return &platform.Organization{ID: id, Name: "org_1"}, nil
+		}
+		fakeSVC.UpdateOrganizationF = func(ctx context.Context, id platform.ID, upd platform.OrganizationUpdate) (*platform.Organization, error) {
+			return &platform.Organization{ID: id, Name: *upd.Name}, nil
+		}
+		return newCachedOrganizationService(fakeSVC), &calls
+	}
+
+	t.Run("repeated resolutions by name call the service once", func(t *testing.T) {
+		svc, calls := newSVC()
+
+		name := "org_1"
+		for i := 0; i < 3; i++ {
+			o, err := svc.FindOrganization(context.Background(), platform.OrganizationFilter{Name: &name})
+			require.NoError(t, err)
+			assert.Equal(t, platform.ID(1), o.ID)
+		}
+		assert.Equal(t, 1, *calls)
+	})
+
+	t.Run("resolution by name populates resolution by id", func(t *testing.T) {
+		svc, calls := newSVC()
+
+		name := "org_1"
+		_, err := svc.FindOrganization(context.Background(), platform.OrganizationFilter{Name: &name})
+		require.NoError(t, err)
+
+		id := platform.ID(1)
+		o, err := svc.FindOrganization(context.Background(), platform.OrganizationFilter{ID: &id})
+		require.NoError(t, err)
+		assert.Equal(t, "org_1", o.Name)
+
+		o, err = svc.FindOrganizationByID(context.Background(), id)
+		require.NoError(t, err)
+		assert.Equal(t, "org_1", o.Name)
+
+		assert.Equal(t, 1, *calls)
+	})
+
+	t.Run("update evicts the cached org", func(t *testing.T) {
+		svc, calls := newSVC()
+
+		id := platform.ID(1)
+		_, err := svc.FindOrganizationByID(context.Background(), id)
+		require.NoError(t, err)
+
+		newName := "org_2"
+		_, err = svc.UpdateOrganization(context.Background(), id, platform.OrganizationUpdate{Name: &newName})
+		require.NoError(t, err)
+
+		_, err = svc.FindOrganizationByID(context.Background(), id)
+		require.NoError(t, err)
+		assert.Equal(t, 2, *calls)
+	})
+}
@@ -1,12 +1,19 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
+	nethttp "net/http"
+	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -18,6 +25,7 @@ import (
 	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
 	input "github.com/tcnksm/go-input"
+	"gopkg.in/yaml.v3"
 )
 
 func pkgCmd() *cobra.Command {
@@ -26,9 +34,8 @@ func pkgCmd() *cobra.Command {
 		Short: "Create a reusable pkg to create resources in a declarative manner",
 	}
 
-	path := cmd.Flags().String("path", "", "path to manifest file")
+	path := cmd.Flags().String("path", "", "path to manifest file or to a directory of manifest files applied together")
 	cmd.MarkFlagFilename("path", "yaml", "yml", "json")
-	cmd.MarkFlagRequired("path")
 
 	orgID := cmd.Flags().String("org-id", "", "The ID of the organization that owns the bucket")
 	cmd.MarkFlagRequired("org-id")
@@ -36,17 +43,319 @@ func pkgCmd() *cobra.Command {
 	hasColor := cmd.Flags().Bool("color", true, "Enable color in output, defaults true")
 	hasTableBorders := cmd.Flags().Bool("table-borders", true, "Enable table borders, defaults true")
 
-	cmd.RunE = pkgApply(orgID, path, hasColor, hasTableBorders)
+	opts := &pkgApplyOpts{}
+	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Print the diff of the pkg and exit without applying it")
+	cmd.Flags().BoolVar(&opts.dryRunExitCode, "dry-run-exit-code", true, "Exit non zero from a dry run when the pkg has pending changes, defaults true")
+	cmd.Flags().BoolVar(&opts.replace, "replace", false, "Make the resources in the org applied from this pkg with replace or prune match the pkg, deleting those no longer present in it")
+	cmd.Flags().BoolVar(&opts.prune, "prune", false, "Delete the existing resources in the org applied from this pkg with replace or prune that are no longer present in it")
+	cmd.Flags().BoolVar(&opts.force, "force", false, "Apply the pkg without asking for confirmation")
+	cmd.Flags().BoolVar(&opts.verboseErrors, "verbose-errors", false, "List every failure found when parsing the pkg")
+	cmd.Flags().BoolVar(&opts.writeBackIDs, "write-back-ids", false, "Write the ids of the applied resources back into the pkg file")
+	cmd.Flags().BoolVar(&opts.onlyChanged, "only-changed", false, "Print only the resources created or updated by the apply in the summary")
+	cmd.Flags().StringVar(&opts.diffContext, "context", pkgDiffContextFull, "Rows of the diff to print, one of: full, changed; changed hides the unchanged resources")
+	cmd.Flags().StringVar(&opts.ref, "ref", "", "Reference to a pkg in an OCI compatible registry to apply instead of a file (e.g. registry.example.com/team/pkg:1.2.0)")
+	cmd.Flags().StringVar(&opts.registryToken, "registry-token", "", "Token to authenticate with the registry the pkg ref is pulled from")
+	cmd.Flags().StringVarP(&opts.output, "output", "o", pkgOutputTable, "Output format of the diff and summary, one of: table, json; json ignores the color and table-borders flags")
+
+	cmd.RunE = pkgApply(orgID, path, hasColor, hasTableBorders, opts)
+
+	cmd.AddCommand(pkgExportCmd())
+	cmd.AddCommand(pkgFmtCmd())
 
 	return cmd
 }
 
-func pkgApply(orgID, path *string, hasColor, hasTableBorders *bool) func(*cobra.Command, []string) error {
+func pkgExportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export existing resources as a pkg",
+		Long: `Export existing resources as a pkg. Resources are exported with the labels
+associated with them, and dashboards with the variables referenced by their chart
+queries. Providing an org ID exports all the resources in the org.`,
+	}
+
+	opts := &pkgExportOpts{}
+	cmd.Flags().StringVarP(&opts.outPath, "file", "f", "", "Output file for the pkg, the extension (yml/yaml/json) dictates the encoding; defaults to yaml on stdout")
+	cmd.Flags().StringVar(&opts.meta.Name, "name", "", "Name of the pkg")
+	cmd.Flags().StringVar(&opts.meta.Description, "description", "", "Description of the pkg")
+	cmd.Flags().StringVar(&opts.meta.Version, "version", "", "Version of the pkg")
+	cmd.Flags().StringSliceVar(&opts.buckets, "bucket", nil, "List of bucket ids to export, comma separated")
+	cmd.Flags().StringSliceVar(&opts.dashboards, "dashboard", nil, "List of dashboard ids to export, comma separated")
+	cmd.Flags().StringSliceVar(&opts.labels, "label", nil, "List of label ids to export, comma separated")
+	cmd.Flags().StringSliceVar(&opts.variables, "variable", nil, "List of variable ids to export, comma separated")
+	cmd.Flags().StringVar(&opts.orgID, "org-id", "", "The ID of the organization to export all resources from")
+	cmd.Flags().StringSliceVar(&opts.kinds, "kind", nil, "List of resource kinds to export from the organization, comma separated; defaults to all")
+
+	cmd.RunE = pkgExport(opts)
+
+	return cmd
+}
+
+type pkgExportOpts struct {
+	outPath    string
+	meta       pkger.Metadata
+	buckets    []string
+	dashboards []string
+	labels     []string
+	variables  []string
+	orgID      string
+	kinds      []string
+}
+
+func (o pkgExportOpts) resourcesToClone() ([]pkger.ResourceToClone, error) {
+	var resources []pkger.ResourceToClone
+	for _, res := range []struct {
+		kind pkger.Kind
+		ids  []string
+	}{
+		{kind: pkger.KindBucket, ids: o.buckets},
+		{kind: pkger.KindDashboard, ids: o.dashboards},
+		{kind: pkger.KindLabel, ids: o.labels},
+		{kind: pkger.KindVariable, ids: o.variables},
+	} {
+		for _, idStr := range res.ids {
+			id, err := influxdb.IDFromString(strings.TrimSpace(idStr))
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s id %q: %v", res.kind, idStr, err)
+			}
+			resources = append(resources, pkger.ResourceToClone{
+				Kind: res.kind,
+				ID:   *id,
+			})
+		}
+	}
+	if len(resources) == 0 {
+		return nil, errors.New("must provide at least one resource to export")
+	}
+	return resources, nil
+}
+
+func (o pkgExportOpts) exportFilter() (pkger.ExportFilter, error) {
+	var filter pkger.ExportFilter
+	for _, k := range o.kinds {
+		kind := pkger.Kind(strings.TrimSpace(k))
+		if err := kind.OK(); err != nil {
+			return pkger.ExportFilter{}, err
+		}
+		filter.Kinds = append(filter.Kinds, kind)
+	}
+	return filter, nil
+}
+
+func pkgExport(opts *pkgExportOpts) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		svc, err := newPkgerSVC(flags)
+		if err != nil {
+			return err
+		}
+
+		var pkg *pkger.Pkg
+		if opts.orgID != "" {
+			pkg, err = pkgExportOrg(svc, *opts)
+		} else {
+			pkg, err = pkgExportResources(svc, *opts)
+		}
+		if err != nil {
+			return err
+		}
+
+		if opts.outPath == "" {
+			return writePkg(os.Stdout, pkger.EncodingYAML, pkg)
+		}
+
+		enc, err := pkgEncoding(opts.outPath)
+		if err != nil {
+			return err
+		}
+
+		f, err := os.Create(opts.outPath)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		return writePkg(f, enc, pkg)
+	}
+}
+
+func pkgExportOrg(svc *pkger.Service, opts pkgExportOpts) (*pkger.Pkg, error) {
+	if len(opts.buckets)+len(opts.dashboards)+len(opts.labels)+len(opts.variables) > 0 {
+		return nil, errors.New("resource ids may not be provided when exporting an organization")
+	}
+
+	orgID, err := influxdb.IDFromString(opts.orgID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid org id %q: %v", opts.orgID, err)
+	}
+
+	filter, err := opts.exportFilter()
+	if err != nil {
+		return nil, err
+	}
+
+	return svc.Export(context.Background(), *orgID, filter, pkger.WithMetadata(opts.meta))
+}
+
+func pkgExportResources(svc *pkger.Service, opts pkgExportOpts) (*pkger.Pkg, error) {
+	if len(opts.kinds) > 0 {
+		return nil, errors.New("kinds may only be provided when exporting an organization")
+	}
+
+	resources, err := opts.resourcesToClone()
+	if err != nil {
+		return nil, err
+	}
+
+	return svc.CreatePkg(context.Background(),
+		pkger.WithMetadata(opts.meta),
+		pkger.WithResourceClones(resources...),
+	)
+}
+
+func writePkg(w io.Writer, enc pkger.Encoding, pkg *pkger.Pkg) error {
+	b, err := pkg.Encode(enc)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+func pkgFmtCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "fmt",
+		Short: "Rewrite a pkg file in canonical form",
+		Long: `Rewrite a pkg file in canonical form. Resources are ordered by kind and name,
+and the file is re-encoded so semantically identical pkgs produce identical files.
+Comments are not preserved, document resources with their annotations instead:
+
+  - kind: Bucket
+    name: rucket_1
+    annotations:
+      owner: team-storage
+      notes: holds the raw metrics`,
+	}
+
+	path := cmd.Flags().String("path", "", "path to manifest file")
+	cmd.MarkFlagFilename("path", "yaml", "yml", "json")
+	cmd.MarkFlagRequired("path")
+
+	cmd.RunE = pkgFmt(path)
+
+	return cmd
+}
+
+func pkgFmt(path *string) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		enc, err := pkgEncoding(*path)
+		if err != nil {
+			return err
+		}
+
+		pkg, err := pkger.Parse(enc, pkger.FromFile(*path))
+		if err != nil {
+			return err
+		}
+		pkg.Normalize()
+
+		b, err := pkg.Encode(enc)
+		if err != nil {
+			return err
+		}
+
+		fi, err := os.Stat(*path)
+		if err != nil {
+			return err
+		}
+		return ioutil.WriteFile(*path, b, fi.Mode())
+	}
+}
+
+type pkgApplyOpts struct {
+	dryRun         bool
+	dryRunExitCode bool
+	replace        bool
+	prune          bool
+	force          bool
+	verboseErrors  bool
+	writeBackIDs   bool
+	onlyChanged    bool
+	diffContext    string
+	ref            string
+	registryToken  string
+	output         string
+}
+
+// pkg output formats
+const (
+	pkgOutputTable = "table"
+	pkgOutputJSON  = "json"
+)
+
+// pkg diff contexts
+const (
+	pkgDiffContextFull    = "full"
+	pkgDiffContextChanged = "changed"
+)
+
+func (o pkgApplyOpts) validDiffContext() error {
+	switch o.diffContext {
+	case pkgDiffContextFull, pkgDiffContextChanged:
+		return nil
+	default:
+		return fmt.Errorf("invalid context %q; must be one of: %s, %s", o.diffContext, pkgDiffContextFull, pkgDiffContextChanged)
+	}
+}
+
+func (o pkgApplyOpts) validOutput() error {
+	switch o.output {
+	case pkgOutputTable:
+		return nil
+	case pkgOutputJSON:
+		if !o.dryRun && !o.force {
+			return errors.New("json output requires --force or --dry-run, the apply confirmation can not be answered with json output")
+		}
+		return nil
+	default:
+		return fmt.Errorf("invalid output %q; must be one of: %s, %s", o.output, pkgOutputTable, pkgOutputJSON)
+	}
+}
+
+func (o pkgApplyOpts) applyOpts() []pkger.ApplyOptFn {
+	var opts []pkger.ApplyOptFn
+	if o.replace {
+		opts = append(opts, pkger.WithReplace())
+	}
+	if o.prune {
+		opts = append(opts, pkger.WithPrune())
+	}
+	return opts
+}
+
+// errPkgHasChanges is returned from a dry run that finds pending changes. This
+// provides a non zero exit code for callers gating on a pkg being fully applied.
+var errPkgHasChanges = errors.New("package has pending changes")
+
+func pkgDryRunErr(diff pkger.Diff, useExitCode bool) error {
+	if useExitCode && diff.HasChanges() {
+		return errPkgHasChanges
+	}
+	return nil
+}
+
+func pkgApply(orgID, path *string, hasColor, hasTableBorders *bool, opts *pkgApplyOpts) func(*cobra.Command, []string) error {
 	return func(cmd *cobra.Command, args []string) (e error) {
 		if !*hasColor {
 			color.NoColor = true
 		}
 
+		if err := opts.validOutput(); err != nil {
+			return err
+		}
+		if err := opts.validDiffContext(); err != nil {
+			return err
+		}
+
 		influxOrgID, err := influxdb.IDFromString(*orgID)
 		if err != nil {
 			return err
@@ -57,38 +366,297 @@ func pkgApply(orgID, path *string, hasColor, hasTableBorders *bool) func(*cobra.
 			return err
 		}
 
-		pkg, err := pkgFromFile(*path)
+		pkg, err := pkgFromSource(*path, opts)
 		if err != nil {
+			if pErr, ok := pkger.IsParseErr(err); ok && opts.verboseErrors {
+				return errors.New(verboseParseErr(pErr))
+			}
 			return err
 		}
 
-		_, diff, err := svc.DryRun(context.Background(), *influxOrgID, pkg)
+		_, diff, err := svc.DryRun(context.Background(), *influxOrgID, pkg, opts.applyOpts()...)
 		if err != nil {
 			return err
 		}
 
-		printPkgDiff(*hasColor, *hasTableBorders, diff)
+		if opts.output == pkgOutputJSON {
+			if opts.dryRun {
+				if err := writePkgJSON(os.Stdout, diff); err != nil {
+					return err
+				}
+			}
+		} else {
+			printDiff, hidden := diff, 0
+			if opts.diffContext == pkgDiffContextChanged {
+				printDiff, hidden = changedDiff(diff)
+			}
+			printPkgDiff(*hasColor, *hasTableBorders, printDiff)
+			if hidden > 0 {
+				fmt.Fprintf(os.Stdout, "%d unchanged resources hidden\n", hidden)
+			}
+		}
+
+		if opts.dryRun {
+			// the diff is the output, the exit code is all that's left
+			// to communicate so usage is not printed on pending changes.
+			cmd.SilenceUsage = true
+			return pkgDryRunErr(diff, opts.dryRunExitCode)
+		}
+
+		if !opts.force {
+			ui := &input.UI{
+				Writer: os.Stdout,
+				Reader: os.Stdin,
+			}
 
-		ui := &input.UI{
-			Writer: os.Stdout,
-			Reader: os.Stdin,
+			msg := "Confirm application of the above resources (y/n)"
+			if n := len(diff.Deletions) + len(diff.Prunes); n > 0 {
+				msg = fmt.Sprintf("Confirm deletion of %d resources and application of the above resources (y/n)", n)
+			}
+			confirm := getInput(ui, msg, "n")
+			if strings.ToLower(confirm) != "y" {
+				fmt.Fprintln(os.Stdout, "aborted application of package")
+				return nil
+			}
 		}
 
-		confirm := getInput(ui, "Confirm application of the above resources (y/n)", "n")
-		if strings.ToLower(confirm) != "y" {
-			fmt.Fprintln(os.Stdout, "aborted application of package")
-			return nil
+		applyOpts := opts.applyOpts()
+		if !flags.local {
+			// the cli has no authorizer in its context, the checks, notification
+			// endpoints, rules and telegrafs are created for the owner of the token.
+			userSVC := &http.UserService{
+				Addr:               flags.host,
+				Token:              flags.token,
+				InsecureSkipVerify: flags.skipVerify,
+			}
+			me, err := userSVC.FindMe(context.Background(), 0)
+			if err != nil {
+				return err
+			}
+			applyOpts = append(applyOpts, pkger.WithApplyUserID(me.ID))
 		}
 
-		summary, err := svc.Apply(context.Background(), *influxOrgID, pkg)
-		if err != nil {
+		// a failed deletion leaves the pkg applied, the summary is still
+		// reported before the deletion failures are.
+		summary, err := svc.Apply(context.Background(), *influxOrgID, pkg, applyOpts...)
+		deleteErr, deleteFailed := pkger.IsDeleteErr(err)
+		if err != nil && !deleteFailed {
 			return err
 		}
 
-		printPkgSummary(*hasColor, *hasTableBorders, summary)
+		printSum := summary
+		if opts.onlyChanged {
+			printSum = changedSummary(summary, diff)
+		}
+		if opts.output == pkgOutputJSON {
+			if err := writePkgJSON(os.Stdout, printSum); err != nil {
+				return err
+			}
+		} else {
+			printPkgSummary(*hasColor, *hasTableBorders, printSum)
+		}
+
+		if opts.writeBackIDs {
+			if err := writeBackPkgIDs(*path, summary); err != nil {
+				return fmt.Errorf("pkg applied but failed to write resource ids back to %s: %v", *path, err)
+			}
+		}
+
+		if deleteFailed {
+			return fmt.Errorf("pkg applied but failed to delete resources:\n%s", deleteErr.Error())
+		}
+		return nil
+	}
+}
+
+// validWriteBackPath ensures the ids of the applied resources can be written
+// back to the pkg file, so a pkg is not applied when they can not be.
+func validWriteBackPath(path string) error {
+	if strings.HasSuffix(path, ".gz") {
+		return errors.New("--write-back-ids does not support gzipped pkg files")
+	}
+	if _, err := pkgEncoding(path); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		return fmt.Errorf("--write-back-ids requires a writable pkg file: %v", err)
+	}
+	return f.Close()
+}
+
+// writeBackPkgIDs sets the id field of every resource in the pkg file to the id
+// of the resource it was applied as. Yaml files keep their comments and field
+// order, json files are re-encoded.
+func writeBackPkgIDs(path string, sum pkger.Summary) error {
+	enc, err := pkgEncoding(path)
+	if err != nil {
+		return err
+	}
+
+	fi, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	ids := summaryIDs(sum)
+	if enc == pkger.EncodingJSON {
+		b, err = jsonWithIDs(b, ids)
+	} else {
+		b, err = yamlWithIDs(b, ids)
+	}
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, b, fi.Mode())
+}
+
+// summaryIDs returns the ids of the summarized resources, keyed by kind and
+// resource name.
+func summaryIDs(sum pkger.Summary) map[pkger.Kind]map[string]influxdb.ID {
+	ids := map[pkger.Kind]map[string]influxdb.ID{
+		pkger.KindBucket:    make(map[string]influxdb.ID),
+		pkger.KindCheck:     make(map[string]influxdb.ID),
+		pkger.KindDashboard: make(map[string]influxdb.ID),
+		pkger.KindLabel:     make(map[string]influxdb.ID),
+		pkger.KindTelegraf:  make(map[string]influxdb.ID),
+		pkger.KindVariable:  make(map[string]influxdb.ID),
+
+		pkger.KindNotificationEndpoint: make(map[string]influxdb.ID),
+		pkger.KindNotificationRule:     make(map[string]influxdb.ID),
+	}
+	for _, b := range sum.Buckets {
+		ids[pkger.KindBucket][b.Name] = b.ID
+	}
+	for _, c := range sum.Checks {
+		ids[pkger.KindCheck][c.Check.GetName()] = c.Check.GetID()
+	}
+	for _, d := range sum.Dashboards {
+		ids[pkger.KindDashboard][d.Name] = influxdb.ID(d.ID)
+	}
+	for _, l := range sum.Labels {
+		ids[pkger.KindLabel][l.Name] = l.ID
+	}
+	for _, e := range sum.NotificationEndpoints {
+		ids[pkger.KindNotificationEndpoint][e.NotificationEndpoint.GetName()] = e.NotificationEndpoint.GetID()
+	}
+	for _, r := range sum.NotificationRules {
+		ids[pkger.KindNotificationRule][r.NotificationRule.GetName()] = r.NotificationRule.GetID()
+	}
+	for _, t := range sum.TelegrafConfigs {
+		ids[pkger.KindTelegraf][t.TelegrafConfig.Name] = t.TelegrafConfig.ID
+	}
+	for _, v := range sum.Variables {
+		ids[pkger.KindVariable][v.Name] = v.ID
+	}
+	return ids
+}
+
+func resourceID(ids map[pkger.Kind]map[string]influxdb.ID, kind, name string) (influxdb.ID, bool) {
+	id, ok := ids[pkger.Kind(kind).Normed()][strings.TrimSpace(name)]
+	return id, ok && id.Valid()
+}
+
+func yamlWithIDs(b []byte, ids map[pkger.Kind]map[string]influxdb.ID) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return nil, err
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		return nil, errors.New("pkg file is empty")
+	}
+
+	resources := yamlMapValue(yamlMapValue(doc.Content[0], "spec"), "resources")
+	if resources == nil {
+		return nil, errors.New("pkg file has no resources")
+	}
+
+	for _, res := range resources.Content {
+		kind, name := yamlMapValue(res, "kind"), yamlMapValue(res, "name")
+		if kind == nil || name == nil {
+			continue
+		}
+		id, ok := resourceID(ids, kind.Value, name.Value)
+		if !ok {
+			continue
+		}
+
+		idNode := &yaml.Node{
+			Kind:  yaml.ScalarNode,
+			Tag:   "!!str",
+			Style: yaml.DoubleQuotedStyle,
+			Value: id.String(),
+		}
+		if existing := yamlMapValue(res, "id"); existing != nil {
+			*existing = *idNode
+			continue
+		}
+		res.Content = append(res.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: "id"}, idNode)
+	}
+
+	var buf bytes.Buffer
+	e := yaml.NewEncoder(&buf)
+	e.SetIndent(2)
+	if err := e.Encode(&doc); err != nil {
+		return nil, err
+	}
+	if err := e.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
 
+// yamlMapValue returns the value of the key in the yaml mapping node.
+func yamlMapValue(n *yaml.Node, key string) *yaml.Node {
+	if n == nil || n.Kind != yaml.MappingNode {
 		return nil
 	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return n.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func jsonWithIDs(b []byte, ids map[pkger.Kind]map[string]influxdb.ID) ([]byte, error) {
+	var doc map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	spec, _ := doc["spec"].(map[string]interface{})
+	resources, ok := spec["resources"].([]interface{})
+	if !ok {
+		return nil, errors.New("pkg file has no resources")
+	}
+
+	for _, r := range resources {
+		res, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		kind, _ := res["kind"].(string)
+		name, _ := res["name"].(string)
+		if id, ok := resourceID(ids, kind, name); ok {
+			res["id"] = id.String()
+		}
+	}
+
+	out, err := json.MarshalIndent(doc, "", "\t")
+	if err != nil {
+		return nil, err
+	}
+	return append(out, '\n'), nil
 }
 
 func newPkgerSVC(f Flags) (*pkger.Service, error) {
@@ -112,21 +680,59 @@ func newPkgerSVC(f Flags) (*pkger.Service, error) {
 		return nil, err
 	}
 
+	checkSVC, err := newCheckService(f)
+	if err != nil {
+		return nil, err
+	}
+
+	endpointSVC, err := newNotificationEndpointService(f)
+	if err != nil {
+		return nil, err
+	}
+
+	ruleSVC, err := newNotificationRuleService(f)
+	if err != nil {
+		return nil, err
+	}
+
+	taskSVC, err := newPkgerTaskService(f)
+	if err != nil {
+		return nil, err
+	}
+
+	teleSVC, err := newTelegrafService(f)
+	if err != nil {
+		return nil, err
+	}
+
 	return pkger.NewService(
 		pkger.WithBucketSVC(bucketSVC),
+		pkger.WithCheckSVC(checkSVC),
 		pkger.WithDashboardSVC(dashSVC),
 		pkger.WithLabelSVC(labelSVC),
+		pkger.WithNotificationEndpointSVC(endpointSVC),
+		pkger.WithNotificationRuleSVC(ruleSVC),
+		pkger.WithTaskSVC(taskSVC),
+		pkger.WithTelegrafSVC(teleSVC),
 		pkger.WithVariableSVC(varSVC),
 	), nil
 }
 
+func newCheckService(f Flags) (influxdb.CheckService, error) {
+	if f.local {
+		return newLocalKVService()
+	}
+	return http.NewCheckService(f.host, f.token, f.skipVerify), nil
+}
+
 func newDashboardService(f Flags) (influxdb.DashboardService, error) {
 	if f.local {
 		return newLocalKVService()
 	}
 	return &http.DashboardService{
-		Addr:  f.host,
-		Token: f.token,
+		Addr:               f.host,
+		Token:              f.token,
+		InsecureSkipVerify: f.skipVerify,
 	}, nil
 }
 
@@ -135,38 +741,217 @@ func newLabelService(f Flags) (influxdb.LabelService, error) {
 		return newLocalKVService()
 	}
 	return &http.LabelService{
-		Addr:  f.host,
-		Token: f.token,
+		Addr:               f.host,
+		Token:              f.token,
+		InsecureSkipVerify: f.skipVerify,
+	}, nil
+}
+
+func newNotificationEndpointService(f Flags) (influxdb.NotificationEndpointService, error) {
+	if f.local {
+		return newLocalKVService()
+	}
+	return http.NewNotificationEndpointService(f.host, f.token, f.skipVerify), nil
+}
+
+func newNotificationRuleService(f Flags) (influxdb.NotificationRuleStore, error) {
+	if f.local {
+		return newLocalKVService()
+	}
+	return http.NewNotificationRuleService(f.host, f.token, f.skipVerify), nil
+}
+
+func newPkgerTaskService(f Flags) (influxdb.TaskService, error) {
+	if f.local {
+		return newLocalKVService()
+	}
+	return pkgerTaskService{svc: newTaskService(f)}, nil
+}
+
+// pkgerTaskService provides pkger the tasks of existing checks, whose status
+// lives on their task. The http task service returns its own task type, so it
+// does not implement influxdb.TaskService; pkger only ever finds a task by id.
+type pkgerTaskService struct {
+	influxdb.TaskService
+	svc *http.TaskService
+}
+
+func (s pkgerTaskService) FindTaskByID(ctx context.Context, id influxdb.ID) (*influxdb.Task, error) {
+	t, err := s.svc.FindTaskByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &influxdb.Task{
+		ID:             t.ID,
+		OrganizationID: t.OrganizationID,
+		Organization:   t.Organization,
+		OwnerID:        t.OwnerID,
+		Name:           t.Name,
+		Description:    t.Description,
+		Status:         t.Status,
+		Flux:           t.Flux,
+		Every:          t.Every,
+		Cron:           t.Cron,
+		Offset:         t.Offset,
 	}, nil
 }
 
+func newTelegrafService(f Flags) (influxdb.TelegrafConfigStore, error) {
+	if f.local {
+		return newLocalKVService()
+	}
+	return http.NewTelegrafService(f.host, f.token, f.skipVerify), nil
+}
+
 func newVariableService(f Flags) (influxdb.VariableService, error) {
 	if f.local {
 		return newLocalKVService()
 	}
 	return &http.VariableService{
-		Addr:  f.host,
-		Token: f.token,
+		Addr:               f.host,
+		Token:              f.token,
+		InsecureSkipVerify: f.skipVerify,
 	}, nil
 }
 
+// pkgFromSource reads the pkg from the file path or pulls it from the
+// registry the ref points to. Exactly one of the two must be provided.
+func pkgFromSource(path string, opts *pkgApplyOpts) (*pkger.Pkg, error) {
+	switch {
+	case path != "" && opts.ref != "":
+		return nil, errors.New("only one of --path or --ref may be provided")
+	case opts.ref != "":
+		if opts.writeBackIDs {
+			return nil, errors.New("--write-back-ids requires a pkg file provided by --path")
+		}
+		ref, err := parsePkgRef(opts.ref)
+		if err != nil {
+			return nil, err
+		}
+		hc := http.NewClient("https", flags.skipVerify)
+		hc.Timeout = registryTimeout
+		c := &registryClient{
+			client: hc,
+			token:  opts.registryToken,
+		}
+		return c.pull(context.Background(), ref)
+	case path != "":
+		fi, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !fi.IsDir() {
+			if opts.writeBackIDs {
+				if err := validWriteBackPath(path); err != nil {
+					return nil, err
+				}
+			}
+			return pkgFromFile(path)
+		}
+		if opts.writeBackIDs {
+			return nil, errors.New("--write-back-ids requires a pkg file provided by --path, not a directory")
+		}
+		return pkgFromDir(path)
+	default:
+		return nil, errors.New("one of --path or --ref must be provided")
+	}
+}
+
+// pkgFromFile parses the pkg file. A gzipped pkg file, i.e. pkg.yml.gz, is
+// decompressed and parsed by the encoding of its inner extension. Files the pkg
+// references are read relative to the directory of the pkg file.
 func pkgFromFile(path string) (*pkger.Pkg, error) {
-	var enc pkger.Encoding
+	enc, err := pkgEncoding(strings.TrimSuffix(path, ".gz"))
+	if err != nil {
+		return nil, err
+	}
+
+	return pkger.Parse(enc, pkger.FromFile(path), pkger.WithFileDir(filepath.Dir(path)))
+}
+
+// pkgFromDir parses every yaml/yml/json pkg file within the directory and
+// combines them into a single pkg. Other files and sub directories are ignored.
+func pkgFromDir(dir string) (*pkger.Pkg, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var pkgs []*pkger.Pkg
+	for _, fi := range files {
+		if fi.IsDir() {
+			continue
+		}
+		path := filepath.Join(dir, fi.Name())
+		enc, err := pkgEncoding(strings.TrimSuffix(path, ".gz"))
+		if err != nil {
+			continue
+		}
+
+		pkg, err := pkger.Parse(enc, pkger.FromFile(path), pkger.WithFileDir(dir), pkger.WithoutValidation())
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse pkg file %s: %v", path, err)
+		}
+		pkgs = append(pkgs, pkg)
+	}
+	if len(pkgs) == 0 {
+		return nil, fmt.Errorf("no yaml/yml/json pkg files found in directory %s", dir)
+	}
+
+	return pkger.Combine(pkgs...)
+}
+
+// pkgEncoding returns the encoding of the pkg file dictated by its extension.
+func pkgEncoding(path string) (pkger.Encoding, error) {
 	switch ext := filepath.Ext(path); ext {
 	case ".yaml", ".yml":
-		enc = pkger.EncodingYAML
+		return pkger.EncodingYAML, nil
 	case ".json":
-		enc = pkger.EncodingJSON
+		return pkger.EncodingJSON, nil
 	default:
-		return nil, errors.New("file provided must be one of yaml/yml/json extension but got: " + ext)
+		return pkger.EncodingUnknown, errors.New("file provided must be one of yaml/yml/json extension but got: " + ext)
+	}
+}
+
+// verboseParseErr lists every failure of the parse error, grouped by the
+// resource they belong to, so all issues in a pkg can be fixed at once.
+func verboseParseErr(pErr *pkger.ParseErr) string {
+	var numFails int
+	var b strings.Builder
+	for _, r := range pErr.Resources {
+		resource := fmt.Sprintf("resource %d", r.Idx)
+		if r.Idx == -1 {
+			resource = "root"
+		}
+		fmt.Fprintf(&b, "\n  %s (kind %q):", resource, r.Kind)
+		for _, f := range r.ValidationFails {
+			fmt.Fprintf(&b, "\n    - field %q: %s", f.Field, f.Msg)
+			numFails++
+		}
+		for _, f := range r.AssociationFails {
+			fmt.Fprintf(&b, "\n    - association %d field %q: %s", f.Index, f.Field, f.Msg)
+			numFails++
+		}
 	}
+	return fmt.Sprintf("failed to parse pkg, found %d errors:", numFails) + b.String()
+}
 
-	return pkger.Parse(enc, pkger.FromFile(path))
+// writePkgJSON writes the pkg diff or summary as JSON, for callers that parse
+// the result rather than read it.
+func writePkgJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	return enc.Encode(v)
 }
 
 func printPkgDiff(hasColor, hasTableBorders bool, diff pkger.Diff) {
 	red := color.New(color.FgRed).SprintfFunc()
 	green := color.New(color.FgHiGreen, color.Bold).SprintfFunc()
+	destructive := color.New(color.FgHiMagenta, color.Bold).SprintfFunc()
+
+	if n := destructiveChanges(diff); n > 0 {
+		fmt.Fprintln(os.Stdout, destructive("WARNING: %d DESTRUCTIVE CHANGES", n))
+	}
 
 	strDiff := func(isNew bool, old, new string) string {
 		if isNew {
@@ -201,10 +986,39 @@ func printPkgDiff(hasColor, hasTableBorders bool, diff pkger.Diff) {
 		if oldDur == newDur {
 			return n
 		}
+		if isRetentionDecrease(oldDur, newDur) {
+			return fmt.Sprintf("%s\n%s", red(o), destructive(n))
+		}
 		return fmt.Sprintf("%s\n%s", red(o), green(n))
 	}
 
 	tablePrintFn := tablePrinterGen(hasColor, hasTableBorders)
+	if dels := diff.Deletions; len(dels) > 0 {
+		headers := []string{"Kind", "ID", "Name"}
+		tablePrintFn("DELETIONS", headers, len(dels), func(w *tablewriter.Table) {
+			for _, d := range dels {
+				w.Append([]string{
+					destructive(string(d.Kind)),
+					destructive(d.ID.String()),
+					destructive(d.Name),
+				})
+			}
+		})
+	}
+
+	if prunes := diff.Prunes; len(prunes) > 0 {
+		headers := []string{"Kind", "ID", "Name"}
+		tablePrintFn("PRUNES", headers, len(prunes), func(w *tablewriter.Table) {
+			for _, d := range prunes {
+				w.Append([]string{
+					destructive(string(d.Kind)),
+					destructive(d.ID.String()),
+					destructive(d.Name),
+				})
+			}
+		})
+	}
+
 	if labels := diff.Labels; len(labels) > 0 {
 		headers := []string{"New", "ID", "Name", "Color", "Description"}
 		tablePrintFn("LABELS", headers, len(labels), func(w *tablewriter.Table) {
@@ -221,7 +1035,7 @@ func printPkgDiff(hasColor, hasTableBorders bool, diff pkger.Diff) {
 	}
 
 	if bkts := diff.Buckets; len(bkts) > 0 {
-		headers := []string{"New", "ID", "Name", "Retention Period", "Description"}
+		headers := []string{"New", "ID", "Name", "Retention Period", "Shard Group Duration", "Description"}
 		tablePrintFn("BUCKETS", headers, len(bkts), func(w *tablewriter.Table) {
 			for _, b := range bkts {
 				w.Append([]string{
@@ -229,12 +1043,35 @@ func printPkgDiff(hasColor, hasTableBorders bool, diff pkger.Diff) {
 					b.ID.String(),
 					b.Name,
 					durDiff(b.IsNew(), b.OldRetention, b.NewRetention),
+					strDiff(b.IsNew(), formatShardGroupDuration(b.OldShardDur), formatShardGroupDuration(b.NewShardDur)),
 					strDiff(b.IsNew(), b.OldDesc, b.NewDesc),
 				})
 			}
 		})
 	}
 
+	if checks := diff.Checks; len(checks) > 0 {
+		headers := []string{"New", "ID", "Name", "Description"}
+		tablePrintFn("CHECKS", headers, len(checks), func(w *tablewriter.Table) {
+			for _, c := range checks {
+				var oldDesc string
+				if c.Old != nil {
+					oldDesc = c.Old.GetDescription()
+				}
+				var newDesc string
+				if c.New != nil {
+					newDesc = c.New.GetDescription()
+				}
+				w.Append([]string{
+					boolDiff(c.IsNew()),
+					c.ID.String(),
+					c.Name,
+					strDiff(c.IsNew(), oldDesc, newDesc),
+				})
+			}
+		})
+	}
+
 	if dashes := diff.Dashboards; len(dashes) > 0 {
 		headers := []string{"New", "Name", "Description", "Num Charts"}
 		tablePrintFn("DASHBOARDS", headers, len(dashes), func(w *tablewriter.Table) {
@@ -249,6 +1086,51 @@ func printPkgDiff(hasColor, hasTableBorders bool, diff pkger.Diff) {
 		})
 	}
 
+	if endpoints := diff.NotificationEndpoints; len(endpoints) > 0 {
+		headers := []string{"New", "Name", "Type", "Description"}
+		tablePrintFn("NOTIFICATION ENDPOINTS", headers, len(endpoints), func(w *tablewriter.Table) {
+			for _, e := range endpoints {
+				w.Append([]string{
+					boolDiff(true),
+					e.Name,
+					green(e.Type),
+					green(e.Desc),
+				})
+			}
+		})
+	}
+
+	if rules := diff.NotificationRules; len(rules) > 0 {
+		headers := []string{"New", "Name", "Check Name", "Endpoint Name", "Endpoint Type", "Every", "Offset", "Description"}
+		tablePrintFn("NOTIFICATION RULES", headers, len(rules), func(w *tablewriter.Table) {
+			for _, r := range rules {
+				w.Append([]string{
+					boolDiff(true),
+					r.Name,
+					green(r.CheckName),
+					green(r.EndpointName),
+					green(r.EndpointType),
+					green(r.Every),
+					green(r.Offset),
+					green(r.Desc),
+				})
+			}
+		})
+	}
+
+	if teles := diff.Telegrafs; len(teles) > 0 {
+		headers := []string{"New", "Name", "Description"}
+		tablePrintFn("TELEGRAF CONFIGS", headers, len(teles), func(w *tablewriter.Table) {
+			for _, t := range teles {
+				w.Append([]string{
+					boolDiff(true),
+					t.Name,
+					green(t.Desc),
+				})
+			}
+		})
+	}
+
 	if vars := diff.Variables; len(vars) > 0 {
 		headers := []string{"New", "ID", "Name", "Description", "Arg Type", "Arg Values"}
 		tablePrintFn("VARIABLES", headers, len(vars), func(w *tablewriter.Table) {
@@ -261,13 +1143,17 @@ func printPkgDiff(hasColor, hasTableBorders bool, diff pkger.Diff) {
 				if v.NewArgs != nil {
 					newArgType = v.NewArgs.Type
 				}
+				argsDiff := strDiff(v.IsNew(), printVarArgs(v.OldArgs), printVarArgs(v.NewArgs))
+				if !v.IsNew() && isMapVarArgs(v.OldArgs) && isMapVarArgs(v.NewArgs) {
+					argsDiff = mapVarArgsDiffStr(red, green, v.OldArgs, v.NewArgs)
+				}
 				w.Append([]string{
 					boolDiff(v.IsNew()),
 					v.ID.String(),
 					v.Name,
 					strDiff(v.IsNew(), v.OldDesc, v.NewDesc),
 					strDiff(v.IsNew(), oldArgType, newArgType),
-					strDiff(v.IsNew(), printVarArgs(v.OldArgs), printVarArgs(v.NewArgs)),
+					argsDiff,
 				})
 			}
 		})
@@ -290,6 +1176,28 @@ func printPkgDiff(hasColor, hasTableBorders bool, diff pkger.Diff) {
 	}
 }
 
+// destructiveChanges counts the changes of the diff that lose data, the
+// deletions and prunes of existing resources and the buckets whose retention
+// period is decreased.
+func destructiveChanges(diff pkger.Diff) int {
+	n := len(diff.Deletions) + len(diff.Prunes)
+	for _, b := range diff.Buckets {
+		if !b.IsNew() && isRetentionDecrease(b.OldRetention, b.NewRetention) {
+			n++
+		}
+	}
+	return n
+}
+
+// isRetentionDecrease reports whether the retention period is shortened, data
+// older than the new period is dropped. A zero retention is infinite.
+func isRetentionDecrease(oldDur, newDur time.Duration) bool {
+	if newDur == 0 {
+		return false
+	}
+	return oldDur == 0 || newDur < oldDur
+}
+
 func printVarArgs(a *influxdb.VariableArguments) string {
 	if a == nil {
 		return "<nil>"
@@ -322,6 +1230,191 @@ func printVarArgs(a *influxdb.VariableArguments) string {
 	return "unknown variable argument"
 }
 
+func isMapVarArgs(a *influxdb.VariableArguments) bool {
+	if a == nil || a.Type != "map" {
+		return false
+	}
+	_, ok := a.Values.(influxdb.VariableMapValues)
+	return ok
+}
+
+// varMapKeyDiff is a diff of a single key within a map variable's arguments.
+// A key that is only in the new args is added, a key only in the old args is
+// removed, and a key in both with differing values is changed.
+type varMapKeyDiff struct {
+	Key     string
+	OldVal  string
+	NewVal  string
+	Added   bool
+	Removed bool
+}
+
+// mapVarArgsDiff provides a key level diff of two map variable arguments. Keys
+// whose values are unchanged are omitted and the results are sorted by key.
+func mapVarArgsDiff(oldArgs, newArgs *influxdb.VariableArguments) []varMapKeyDiff {
+	oldVals, _ := oldArgs.Values.(influxdb.VariableMapValues)
+	newVals, _ := newArgs.Values.(influxdb.VariableMapValues)
+
+	var diffs []varMapKeyDiff
+	for k, newVal := range newVals {
+		oldVal, ok := oldVals[k]
+		switch {
+		case !ok:
+			diffs = append(diffs, varMapKeyDiff{Key: k, NewVal: newVal, Added: true})
+		case oldVal != newVal:
+			diffs = append(diffs, varMapKeyDiff{Key: k, OldVal: oldVal, NewVal: newVal})
+		}
+	}
+	for k, oldVal := range oldVals {
+		if _, ok := newVals[k]; !ok {
+			diffs = append(diffs, varMapKeyDiff{Key: k, OldVal: oldVal, Removed: true})
+		}
+	}
+
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].Key < diffs[j].Key
+	})
+	return diffs
+}
+
+func mapVarArgsDiffStr(red, green func(string, ...interface{}) string, oldArgs, newArgs *influxdb.VariableArguments) string {
+	diffs := mapVarArgsDiff(oldArgs, newArgs)
+	if len(diffs) == 0 {
+		return printVarArgs(newArgs)
+	}
+
+	var lines []string
+	for _, d := range diffs {
+		switch {
+		case d.Added:
+			lines = append(lines, green("+ %q: %q", d.Key, d.NewVal))
+		case d.Removed:
+			lines = append(lines, red("- %q: %q", d.Key, d.OldVal))
+		default:
+			lines = append(lines, red("- %q: %q", d.Key, d.OldVal), green("+ %q: %q", d.Key, d.NewVal))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// changedDiff returns the diff without the resources it leaves unchanged,
+// along with the number of resources left out. Dashboards, notification
+// endpoints, notification rules and telegraf configs are always created new,
+// they are never left out.
+func changedDiff(diff pkger.Diff) (pkger.Diff, int) {
+	changed := pkger.Diff{
+		Dashboards:            diff.Dashboards,
+		NotificationEndpoints: diff.NotificationEndpoints,
+		NotificationRules:     diff.NotificationRules,
+		Telegrafs:             diff.Telegrafs,
+		Deletions:             diff.Deletions,
+		Prunes:                diff.Prunes,
+	}
+
+	var hidden int
+	for _, b := range diff.Buckets {
+		if !b.HasChanges() {
+			hidden++
+			continue
+		}
+		changed.Buckets = append(changed.Buckets, b)
+	}
+	for _, c := range diff.Checks {
+		if !c.HasChanges() {
+			hidden++
+			continue
+		}
+		changed.Checks = append(changed.Checks, c)
+	}
+	for _, l := range diff.Labels {
+		if !l.HasChanges() {
+			hidden++
+			continue
+		}
+		changed.Labels = append(changed.Labels, l)
+	}
+	for _, v := range diff.Variables {
+		if !v.HasChanges() {
+			hidden++
+			continue
+		}
+		changed.Variables = append(changed.Variables, v)
+	}
+	for _, m := range diff.LabelMappings {
+		if !m.IsNew {
+			hidden++
+			continue
+		}
+		changed.LabelMappings = append(changed.LabelMappings, m)
+	}
+	return changed, hidden
+}
+
+// changedSummary returns the summary of the resources the diff creates or
+// updates. Dashboards, notification endpoints, notification rules and telegraf
+// configs are always created new, they are always provided.
+func changedSummary(sum pkger.Summary, diff pkger.Diff) pkger.Summary {
+	changedBkts := make(map[string]bool)
+	for _, b := range diff.Buckets {
+		changedBkts[b.Name] = b.HasChanges()
+	}
+	changedChecks := make(map[string]bool)
+	for _, c := range diff.Checks {
+		changedChecks[c.Name] = c.HasChanges()
+	}
+	changedLabels := make(map[string]bool)
+	for _, l := range diff.Labels {
+		changedLabels[l.Name] = l.HasChanges()
+	}
+	changedVars := make(map[string]bool)
+	for _, v := range diff.Variables {
+		changedVars[v.Name] = v.HasChanges()
+	}
+	type mappingKey struct {
+		resType   influxdb.ResourceType
+		resName   string
+		labelName string
+	}
+	newMappings := make(map[mappingKey]bool)
+	for _, m := range diff.LabelMappings {
+		newMappings[mappingKey{resType: m.ResType, resName: m.ResName, labelName: m.LabelName}] = m.IsNew
+	}
+
+	changed := pkger.Summary{
+		Dashboards:            sum.Dashboards,
+		NotificationEndpoints: sum.NotificationEndpoints,
+		NotificationRules:     sum.NotificationRules,
+		TelegrafConfigs:       sum.TelegrafConfigs,
+		Breakdown:             sum.Breakdown,
+	}
+	for _, b := range sum.Buckets {
+		if changedBkts[b.Name] {
+			changed.Buckets = append(changed.Buckets, b)
+		}
+	}
+	for _, c := range sum.Checks {
+		if changedChecks[c.Check.GetName()] {
+			changed.Checks = append(changed.Checks, c)
+		}
+	}
+	for _, l := range sum.Labels {
+		if changedLabels[l.Name] {
+			changed.Labels = append(changed.Labels, l)
+		}
+	}
+	for _, v := range sum.Variables {
+		if changedVars[v.Name] {
+			changed.Variables = append(changed.Variables, v)
+		}
+	}
+	for _, m := range sum.LabelMappings {
+		if newMappings[mappingKey{resType: m.ResourceType, resName: m.ResourceName, labelName: m.LabelName}] {
+			changed.LabelMappings = append(changed.LabelMappings, m)
+		}
+	}
+	return changed
+}
+
 func printPkgSummary(hasColor, hasTableBorders bool, sum pkger.Summary) {
 	tablePrintFn := tablePrinterGen(hasColor, hasTableBorders)
 	if labels := sum.Labels; len(labels) > 0 {
@@ -339,27 +1432,80 @@ func printPkgSummary(hasColor, hasTableBorders bool, sum pkger.Summary) {
 	}
 
 	if buckets := sum.Buckets; len(buckets) > 0 {
-		headers := []string{"ID", "Name", "Retention", "Description"}
+		headers := []string{"ID", "Name", "Retention", "Shard Group Duration", "Description"}
 		tablePrintFn("BUCKETS", headers, len(buckets), func(w *tablewriter.Table) {
 			for _, bucket := range buckets {
 				w.Append([]string{
 					bucket.ID.String(),
 					bucket.Name,
 					formatDuration(bucket.RetentionPeriod),
+					formatShardGroupDuration(bucket.ShardGroupDuration),
 					bucket.Description,
 				})
 			}
 		})
 	}
 
-	if dashes := sum.Dashboards; len(dashes) > 0 {
+	if checks := sum.Checks; len(checks) > 0 {
 		headers := []string{"ID", "Name", "Description"}
+		tablePrintFn("CHECKS", headers, len(checks), func(w *tablewriter.Table) {
+			for _, c := range checks {
+				w.Append([]string{
+					c.Check.GetID().String(),
+					c.Check.GetName(),
+					c.Check.GetDescription(),
+				})
+			}
+		})
+	}
+
+	if dashes := sum.Dashboards; len(dashes) > 0 {
+		headers := []string{"ID", "Name", "Num Charts", "Chart Types", "Description"}
 		tablePrintFn("DASHBOARDS", headers, len(dashes), func(w *tablewriter.Table) {
 			for _, d := range dashes {
+				w.Append(summaryDashboardRow(d))
+			}
+		})
+	}
+
+	if endpoints := sum.NotificationEndpoints; len(endpoints) > 0 {
+		headers := []string{"ID", "Name", "Type", "Description"}
+		tablePrintFn("NOTIFICATION ENDPOINTS", headers, len(endpoints), func(w *tablewriter.Table) {
+			for _, e := range endpoints {
+				ne := e.NotificationEndpoint
 				w.Append([]string{
-					d.ID.String(),
-					d.Name,
-					d.Description,
+					ne.GetID().String(),
+					ne.GetName(),
+					ne.Type(),
+					ne.GetDescription(),
+				})
+			}
+		})
+	}
+
+	if rules := sum.NotificationRules; len(rules) > 0 {
+		headers := []string{"ID", "Name", "Check Name", "Endpoint Name", "Description"}
+		tablePrintFn("NOTIFICATION RULES", headers, len(rules), func(w *tablewriter.Table) {
+			for _, r := range rules {
+				w.Append([]string{
+					r.NotificationRule.GetID().String(),
+					r.NotificationRule.GetName(),
+					r.CheckName,
+					r.EndpointName,
+					r.NotificationRule.GetDescription(),
+				})
+			}
+		})
+	}
+
+	if teles := sum.TelegrafConfigs; len(teles) > 0 {
+		headers := []string{"ID", "Name", "Description"}
+		tablePrintFn("TELEGRAF CONFIGS", headers, len(teles), func(w *tablewriter.Table) {
+			for _, t := range teles {
+				w.Append([]string{
+					t.TelegrafConfig.ID.String(),
+					t.TelegrafConfig.Name,
+					t.TelegrafConfig.Description,
 				})
 			}
 		})
@@ -395,6 +1541,46 @@ func printPkgSummary(hasColor, hasTableBorders bool, sum pkger.Summary) {
 			}
 		})
 	}
+
+	if breakdown := sum.Breakdown; len(breakdown) > 0 {
+		headers := []string{"Kind", "Created", "Updated", "Unchanged", "Skipped"}
+		tablePrintFn("SUMMARY", headers, len(breakdown), func(w *tablewriter.Table) {
+			for _, b := range breakdown {
+				w.Append([]string{
+					b.Kind.String(),
+					strconv.Itoa(b.Created),
+					strconv.Itoa(b.Updated),
+					strconv.Itoa(b.Unchanged),
+					strconv.Itoa(b.Skipped),
+				})
+			}
+		})
+	}
+}
+
+// summaryDashboardRow provides the summary table row of the dashboard. The
+// chart types are the distinct types of its charts.
+func summaryDashboardRow(d pkger.SummaryDashboard) []string {
+	seen := make(map[string]bool)
+	var types []string
+	for _, c := range d.Charts {
+		if c.Properties == nil {
+			continue
+		}
+		if t := c.Properties.GetType(); t != "" && !seen[t] {
+			seen[t] = true
+			types = append(types, t)
+		}
+	}
+	sort.Strings(types)
+
+	return []string{
+		d.ID.String(),
+		d.Name,
+		strconv.Itoa(len(d.Charts)),
+		strings.Join(types, ", "),
+		d.Description,
+	}
 }
 
 func tablePrinterGen(hasColor, hasTableBorder bool) func(table string, headers []string, count int, appendFn func(w *tablewriter.Table)) {
@@ -456,3 +1642,288 @@ func formatDuration(d time.Duration) string {
 	}
 	return d.String()
 }
+
+// formatShardGroupDuration formats a shard group duration, an unset one
+// is left to the server default.
+func formatShardGroupDuration(d time.Duration) string {
+	if d == 0 {
+		return "default"
+	}
+	return d.String()
+}
+
+// pkgRef references a pkg stored in an OCI compatible registry, i.e.
+// registry.example.com/team/pkg:1.2.0. The tag defaults to latest.
+type pkgRef struct {
+	registry string
+	repo     string
+	tag      string
+}
+
+func (r pkgRef) String() string {
+	return fmt.Sprintf("%s/%s:%s", r.registry, r.repo, r.tag)
+}
+
+func parsePkgRef(ref string) (pkgRef, error) {
+	ref = strings.TrimSpace(ref)
+	invalidErr := fmt.Errorf("invalid pkg ref %q: must be of the form registry/repository[:tag]", ref)
+
+	slash := strings.Index(ref, "/")
+	if slash <= 0 {
+		return pkgRef{}, invalidErr
+	}
+	r := pkgRef{
+		registry: ref[:slash],
+		repo:     ref[slash+1:],
+		tag:      "latest",
+	}
+	// the registry may include a port, so only a colon following the
+	// last slash separates the tag from the repository.
+	if i := strings.LastIndex(r.repo, ":"); i > strings.LastIndex(r.repo, "/") {
+		r.repo, r.tag = r.repo[:i], r.repo[i+1:]
+	}
+	if r.repo == "" || r.tag == "" {
+		return pkgRef{}, invalidErr
+	}
+	return r, nil
+}
+
+const (
+	ociManifestMediaType    = "application/vnd.oci.image.manifest.v1+json"
+	dockerManifestMediaType = "application/vnd.docker.distribution.manifest.v2+json"
+
+	// registryTimeout bounds every request made to a registry.
+	registryTimeout = time.Minute
+	// maxRegistryPkgSize bounds the size of the manifests and pkgs pulled
+	// from a registry.
+	maxRegistryPkgSize = 16 << 20
+)
+
+// ociManifest is the subset of an OCI image manifest needed to locate the
+// layer holding the pkg.
+type ociManifest struct {
+	Layers []struct {
+		MediaType   string            `json:"mediaType"`
+		Digest      string            `json:"digest"`
+		Size        int64             `json:"size"`
+		Annotations map[string]string `json:"annotations"`
+	} `json:"layers"`
+}
+
+// registryClient pulls pkgs from an OCI compatible registry. The pkg is
+// the first layer of the manifest the ref points to.
+type registryClient struct {
+	client interface {
+		Do(*nethttp.Request) (*nethttp.Response, error)
+	}
+	token string
+
+	// bearer is the token exchanged for the registry token when the registry
+	// challenges a request, it authenticates every request that follows.
+	bearer string
+}
+
+func (c *registryClient) pull(ctx context.Context, ref pkgRef) (*pkger.Pkg, error) {
+	b, err := c.get(ctx, ref, "manifests/"+ref.tag, ociManifestMediaType+", "+dockerManifestMediaType)
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest ociManifest
+	if err := json.Unmarshal(b, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to decode manifest of pkg %s: %v", ref, err)
+	}
+	if len(manifest.Layers) == 0 {
+		return nil, fmt.Errorf("manifest of pkg %s has no layers", ref)
+	}
+	layer := manifest.Layers[0]
+	if layer.Size > maxRegistryPkgSize {
+		return nil, fmt.Errorf("pkg %s of %d bytes exceeds the maximum size of %d bytes", ref, layer.Size, maxRegistryPkgSize)
+	}
+
+	b, err = c.get(ctx, ref, "blobs/"+layer.Digest, "")
+	if err != nil {
+		return nil, err
+	}
+	if digest := fmt.Sprintf("sha256:%x", sha256.Sum256(b)); digest != layer.Digest {
+		return nil, fmt.Errorf("pkg %s does not match its digest: expected %s but got %s", ref, layer.Digest, digest)
+	}
+
+	enc := pkger.EncodingYAML
+	if strings.Contains(layer.MediaType, "json") {
+		enc = pkger.EncodingJSON
+	} else if e, err := pkgEncoding(layer.Annotations["org.opencontainers.image.title"]); err == nil {
+		enc = e
+	}
+
+	return pkger.Parse(enc, pkger.FromReader(bytes.NewReader(b)))
+}
+
+func (c *registryClient) get(ctx context.Context, ref pkgRef, resource, accept string) ([]byte, error) {
+	resp, err := c.do(ctx, ref, resource, accept)
+	if err != nil {
+		return nil, err
+	}
+
+	// registries that issue their own tokens challenge the first request,
+	// the token is exchanged once and the request is retried with it.
+	if challenge, ok := parseBearerChallenge(resp.Header.Get("WWW-Authenticate")); ok && resp.StatusCode == nethttp.StatusUnauthorized && c.bearer == "" {
+		resp.Body.Close()
+		if c.bearer, err = c.exchangeToken(ctx, ref, challenge); err != nil {
+			return nil, err
+		}
+		if resp, err = c.do(ctx, ref, resource, accept); err != nil {
+			return nil, err
+		}
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case nethttp.StatusOK:
+	case nethttp.StatusUnauthorized, nethttp.StatusForbidden:
+		return nil, fmt.Errorf("not authorized to pull pkg %s: registry responded %s; provide a valid --registry-token", ref, resp.Status)
+	case nethttp.StatusNotFound:
+		return nil, fmt.Errorf("pkg %s not found in registry", ref)
+	default:
+		return nil, fmt.Errorf("failed to pull pkg %s: registry responded %s", ref, resp.Status)
+	}
+
+	return readRegistryBody(resp.Body, ref)
+}
+
+func (c *registryClient) do(ctx context.Context, ref pkgRef, resource, accept string) (*nethttp.Response, error) {
+	u := fmt.Sprintf("https://%s/v2/%s/%s", ref.registry, ref.repo, resource)
+	req, err := nethttp.NewRequest(nethttp.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	if token := c.authToken(); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := c.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach registry %s: %v", ref.registry, err)
+	}
+	return resp, nil
+}
+
+func (c *registryClient) authToken() string {
+	if c.bearer != "" {
+		return c.bearer
+	}
+	return c.token
+}
+
+// exchangeToken exchanges the registry token for a token scoped to pulling
+// the pkg from the realm of the bearer challenge. Without a registry token
+// the exchange is anonymous.
+func (c *registryClient) exchangeToken(ctx context.Context, ref pkgRef, challenge map[string]string) (string, error) {
+	u, err := url.Parse(challenge["realm"])
+	if err != nil || u.Scheme != "https" {
+		return "", fmt.Errorf("registry %s challenged with an invalid realm %q: must be an https url", ref.registry, challenge["realm"])
+	}
+	scope := challenge["scope"]
+	if scope == "" {
+		scope = fmt.Sprintf("repository:%s:pull", ref.repo)
+	}
+	params := u.Query()
+	params.Set("scope", scope)
+	if service := challenge["service"]; service != "" {
+		params.Set("service", service)
+	}
+	u.RawQuery = params.Encode()
+
+	req, err := nethttp.NewRequest(nethttp.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return "", fmt.Errorf("failed to reach registry auth %s: %v", u.Host, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != nethttp.StatusOK {
+		return "", fmt.Errorf("not authorized to pull pkg %s: registry auth responded %s; provide a valid --registry-token", ref, resp.Status)
+	}
+
+	b, err := readRegistryBody(resp.Body, ref)
+	if err != nil {
+		return "", err
+	}
+	var out struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.Unmarshal(b, &out); err != nil {
+		return "", fmt.Errorf("failed to decode registry auth token: %v", err)
+	}
+	if out.Token != "" {
+		return out.Token, nil
+	}
+	if out.AccessToken != "" {
+		return out.AccessToken, nil
+	}
+	return "", fmt.Errorf("registry auth %s responded without a token", u.Host)
+}
+
+// readRegistryBody reads the body of a registry response, failing when it
+// exceeds the maximum pkg size.
+func readRegistryBody(r io.Reader, ref pkgRef) ([]byte, error) {
+	b, err := ioutil.ReadAll(io.LimitReader(r, maxRegistryPkgSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(b) > maxRegistryPkgSize {
+		return nil, fmt.Errorf("pkg %s exceeds the maximum size of %d bytes", ref, maxRegistryPkgSize)
+	}
+	return b, nil
+}
+
+// parseBearerChallenge parses the parameters of a bearer challenge, i.e.
+// Bearer realm="https://auth.example.com/token",service="registry.example.com".
+// The challenge is only valid with a realm.
+func parseBearerChallenge(header string) (map[string]string, bool) {
+	const scheme = "bearer "
+	if len(header) < len(scheme) || !strings.EqualFold(header[:len(scheme)], scheme) {
+		return nil, false
+	}
+
+	params := make(map[string]string)
+	s := header[len(scheme):]
+	for {
+		s = strings.TrimLeft(s, " ,")
+		eq := strings.Index(s, "=")
+		if eq < 0 {
+			break
+		}
+		key := strings.ToLower(strings.TrimSpace(s[:eq]))
+		s = s[eq+1:]
+
+		var val string
+		if strings.HasPrefix(s, `"`) {
+			end := strings.Index(s[1:], `"`)
+			if end < 0 {
+				return nil, false
+			}
+			val, s = s[1:end+1], s[end+2:]
+		} else {
+			end := strings.Index(s, ",")
+			if end < 0 {
+				end = len(s)
+			}
+			val, s = s[:end], s[end:]
+		}
+		params[key] = val
+	}
+
+	return params, params["realm"] != ""
+}
@@ -0,0 +1,875 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	nethttp "net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/notification/check"
+	"github.com/influxdata/influxdb/notification/endpoint"
+	"github.com/influxdata/influxdb/notification/rule"
+	"github.com/influxdata/influxdb/pkger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPkgDiff(t *testing.T) {
+	t.Run("map variable args", func(t *testing.T) {
+		diff := pkger.Diff{
+			Variables: []pkger.DiffVariable{
+				{
+					ID:   pkger.SafeID(1),
+					Name: "var_map",
+					OldArgs: &influxdb.VariableArguments{
+						Type:   "map",
+						Values: influxdb.VariableMapValues{"k1": "v1", "k2": "v2", "k3": "v3"},
+					},
+					NewArgs: &influxdb.VariableArguments{
+						Type:   "map",
+						Values: influxdb.VariableMapValues{"k1": "v1", "k2": "new v2", "k4": "v4"},
+					},
+				},
+			},
+		}
+
+		v := diff.Variables[0]
+		require.True(t, isMapVarArgs(v.OldArgs))
+		require.True(t, isMapVarArgs(v.NewArgs))
+
+		expected := []varMapKeyDiff{
+			{Key: "k2", OldVal: "v2", NewVal: "new v2"},
+			{Key: "k3", OldVal: "v3", Removed: true},
+			{Key: "k4", NewVal: "v4", Added: true},
+		}
+		assert.Equal(t, expected, mapVarArgsDiff(v.OldArgs, v.NewArgs))
+
+		noColor := func(format string, args ...interface{}) string {
+			return fmt.Sprintf(format, args...)
+		}
+		expectedStr := `- "k2": "v2"
++ "k2": "new v2"
+- "k3": "v3"
++ "k4": "v4"`
+		assert.Equal(t, expectedStr, mapVarArgsDiffStr(noColor, noColor, v.OldArgs, v.NewArgs))
+	})
+
+	t.Run("map variable args without changes", func(t *testing.T) {
+		args := &influxdb.VariableArguments{
+			Type:   "map",
+			Values: influxdb.VariableMapValues{"k1": "v1"},
+		}
+
+		assert.Empty(t, mapVarArgsDiff(args, args))
+	})
+}
+
+func TestPkgDryRunErr(t *testing.T) {
+	unchanged := pkger.Diff{
+		Buckets: []pkger.DiffBucket{
+			{
+				ID:           pkger.SafeID(1),
+				Name:         "rucket_11",
+				OldDesc:      "desc",
+				NewDesc:      "desc",
+				OldRetention: time.Hour,
+				NewRetention: time.Hour,
+			},
+		},
+		Labels: []pkger.DiffLabel{
+			{
+				ID:       pkger.SafeID(2),
+				Name:     "label_1",
+				OldColor: "#FFFFFF",
+				NewColor: "#FFFFFF",
+			},
+		},
+		LabelMappings: []pkger.DiffLabelMapping{
+			{
+				ResType:   influxdb.BucketsResourceType,
+				ResID:     pkger.SafeID(1),
+				ResName:   "rucket_11",
+				LabelID:   pkger.SafeID(2),
+				LabelName: "label_1",
+			},
+		},
+	}
+
+	t.Run("unchanged diff exits zero", func(t *testing.T) {
+		assert.NoError(t, pkgDryRunErr(unchanged, true))
+	})
+
+	t.Run("diff with changes exits non zero", func(t *testing.T) {
+		changed := unchanged
+		changed.Buckets = []pkger.DiffBucket{unchanged.Buckets[0]}
+		changed.Buckets[0].NewRetention = 2 * time.Hour
+
+		assert.Equal(t, errPkgHasChanges, pkgDryRunErr(changed, true))
+	})
+
+	t.Run("diff with deletions exits non zero", func(t *testing.T) {
+		changed := unchanged
+		changed.Deletions = []pkger.DiffDeletion{
+			{Kind: pkger.KindBucket, ID: pkger.SafeID(3), Name: "removed_rucket"},
+		}
+
+		assert.Equal(t, errPkgHasChanges, pkgDryRunErr(changed, true))
+	})
+
+	t.Run("diff with changes exits zero when opted out", func(t *testing.T) {
+		changed := unchanged
+		changed.LabelMappings = []pkger.DiffLabelMapping{{IsNew: true}}
+
+		assert.NoError(t, pkgDryRunErr(changed, false))
+	})
+}
+
+func TestPkgJSONOutput(t *testing.T) {
+	t.Run("validates the output format", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			opts    pkgApplyOpts
+			wantErr bool
+		}{
+			{name: "table", opts: pkgApplyOpts{output: pkgOutputTable}},
+			{name: "json dry run", opts: pkgApplyOpts{output: pkgOutputJSON, dryRun: true}},
+			{name: "json forced apply", opts: pkgApplyOpts{output: pkgOutputJSON, force: true}},
+			{name: "json apply requiring confirmation", opts: pkgApplyOpts{output: pkgOutputJSON}, wantErr: true},
+			{name: "unknown format", opts: pkgApplyOpts{output: "xml", force: true}, wantErr: true},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				err := tt.opts.validOutput()
+				if tt.wantErr {
+					assert.Error(t, err)
+					return
+				}
+				assert.NoError(t, err)
+			})
+		}
+	})
+
+	t.Run("diff round trips as json", func(t *testing.T) {
+		diff := pkger.Diff{
+			Buckets: []pkger.DiffBucket{
+				{ID: pkger.SafeID(1), Name: "rucket_1", NewDesc: "desc", NewRetention: time.Hour},
+			},
+			Deletions: []pkger.DiffDeletion{
+				{Kind: pkger.KindLabel, ID: pkger.SafeID(2), Name: "label_1"},
+			},
+		}
+
+		var buf strings.Builder
+		require.NoError(t, writePkgJSON(&buf, diff))
+
+		var actual pkger.Diff
+		require.NoError(t, json.Unmarshal([]byte(buf.String()), &actual))
+		assert.Equal(t, diff, actual)
+	})
+
+	t.Run("summary round trips as json", func(t *testing.T) {
+		sum := pkger.Summary{
+			Buckets: []pkger.SummaryBucket{
+				{Bucket: influxdb.Bucket{ID: influxdb.ID(1), Name: "rucket_1"}},
+			},
+		}
+
+		var buf strings.Builder
+		require.NoError(t, writePkgJSON(&buf, sum))
+
+		var actual pkger.Summary
+		require.NoError(t, json.Unmarshal([]byte(buf.String()), &actual))
+		require.Len(t, actual.Buckets, 1)
+		assert.Equal(t, influxdb.ID(1), actual.Buckets[0].ID)
+		assert.Equal(t, "rucket_1", actual.Buckets[0].Name)
+	})
+}
+
+func TestPkgChangedSummary(t *testing.T) {
+	sum := pkger.Summary{
+		Buckets: []pkger.SummaryBucket{
+			{Bucket: influxdb.Bucket{ID: influxdb.ID(1), Name: "rucket_1"}},
+			{Bucket: influxdb.Bucket{ID: influxdb.ID(2), Name: "rucket_2"}},
+		},
+		Dashboards: []pkger.SummaryDashboard{
+			{ID: pkger.SafeID(3), Name: "dash_1"},
+		},
+		Labels: []pkger.SummaryLabel{
+			{Label: influxdb.Label{ID: influxdb.ID(4), Name: "label_1"}},
+			{Label: influxdb.Label{ID: influxdb.ID(5), Name: "label_2"}},
+		},
+		LabelMappings: []pkger.SummaryLabelMapping{
+			{
+				ResourceName: "rucket_1",
+				LabelName:    "label_1",
+				LabelMapping: influxdb.LabelMapping{ResourceType: influxdb.BucketsResourceType},
+			},
+			{
+				ResourceName: "rucket_2",
+				LabelName:    "label_1",
+				LabelMapping: influxdb.LabelMapping{ResourceType: influxdb.BucketsResourceType},
+			},
+		},
+		Variables: []pkger.SummaryVariable{
+			{Variable: influxdb.Variable{ID: influxdb.ID(6), Name: "var_1"}},
+		},
+	}
+
+	diff := pkger.Diff{
+		Buckets: []pkger.DiffBucket{
+			// unchanged
+			{ID: pkger.SafeID(1), Name: "rucket_1", OldDesc: "desc", NewDesc: "desc"},
+			// updated
+			{ID: pkger.SafeID(2), Name: "rucket_2", OldDesc: "desc", NewDesc: "new desc"},
+		},
+		Dashboards: []pkger.DiffDashboard{{Name: "dash_1"}},
+		Labels: []pkger.DiffLabel{
+			// created
+			{Name: "label_1", NewColor: "#FFFFFF"},
+			// unchanged
+			{ID: pkger.SafeID(5), Name: "label_2", OldColor: "#000000", NewColor: "#000000"},
+		},
+		LabelMappings: []pkger.DiffLabelMapping{
+			{ResType: influxdb.BucketsResourceType, ResName: "rucket_1", LabelName: "label_1"},
+			{IsNew: true, ResType: influxdb.BucketsResourceType, ResName: "rucket_2", LabelName: "label_1"},
+		},
+		Variables: []pkger.DiffVariable{
+			// unchanged
+			{ID: pkger.SafeID(6), Name: "var_1"},
+		},
+	}
+
+	changed := changedSummary(sum, diff)
+
+	require.Len(t, changed.Buckets, 1)
+	assert.Equal(t, "rucket_2", changed.Buckets[0].Name)
+
+	require.Len(t, changed.Labels, 1)
+	assert.Equal(t, "label_1", changed.Labels[0].Name)
+
+	require.Len(t, changed.LabelMappings, 1)
+	assert.Equal(t, "rucket_2", changed.LabelMappings[0].ResourceName)
+
+	assert.Equal(t, sum.Dashboards, changed.Dashboards)
+	assert.Empty(t, changed.Variables)
+}
+
+func TestPkgChangedDiff(t *testing.T) {
+	diff := pkger.Diff{
+		Buckets: []pkger.DiffBucket{
+			// unchanged
+			{ID: pkger.SafeID(1), Name: "rucket_1", OldDesc: "desc", NewDesc: "desc"},
+			// updated
+			{ID: pkger.SafeID(2), Name: "rucket_2", OldDesc: "desc", NewDesc: "new desc"},
+		},
+		Dashboards: []pkger.DiffDashboard{{Name: "dash_1"}},
+		Labels: []pkger.DiffLabel{
+			// created
+			{Name: "label_1", NewColor: "#FFFFFF"},
+			// unchanged
+			{ID: pkger.SafeID(5), Name: "label_2", OldColor: "#000000", NewColor: "#000000"},
+		},
+		LabelMappings: []pkger.DiffLabelMapping{
+			{ResType: influxdb.BucketsResourceType, ResName: "rucket_1", LabelName: "label_1"},
+			{IsNew: true, ResType: influxdb.BucketsResourceType, ResName: "rucket_2", LabelName: "label_1"},
+		},
+		Variables: []pkger.DiffVariable{
+			// unchanged
+			{ID: pkger.SafeID(6), Name: "var_1"},
+		},
+	}
+
+	changed, hidden := changedDiff(diff)
+	assert.Equal(t, 4, hidden)
+
+	require.Len(t, changed.Buckets, 1)
+	assert.Equal(t, "rucket_2", changed.Buckets[0].Name)
+
+	require.Len(t, changed.Labels, 1)
+	assert.Equal(t, "label_1", changed.Labels[0].Name)
+
+	require.Len(t, changed.LabelMappings, 1)
+	assert.Equal(t, "rucket_2", changed.LabelMappings[0].ResName)
+
+	assert.Equal(t, diff.Dashboards, changed.Dashboards)
+	assert.Empty(t, changed.Variables)
+}
+
+func TestPkgDiffDestructiveChanges(t *testing.T) {
+	diff := pkger.Diff{
+		Buckets: []pkger.DiffBucket{
+			// retention decreased
+			{ID: pkger.SafeID(1), Name: "rucket_1", OldRetention: 2 * time.Hour, NewRetention: time.Hour},
+			// retention decreased from infinite
+			{ID: pkger.SafeID(2), Name: "rucket_2", NewRetention: time.Hour},
+			// retention increased
+			{ID: pkger.SafeID(3), Name: "rucket_3", OldRetention: time.Hour, NewRetention: 2 * time.Hour},
+			// retention increased to infinite
+			{ID: pkger.SafeID(4), Name: "rucket_4", OldRetention: time.Hour},
+			// created
+			{Name: "rucket_5", NewRetention: time.Hour},
+		},
+		Prunes: []pkger.DiffDeletion{
+			{Kind: pkger.KindLabel, ID: pkger.SafeID(5), Name: "label_1"},
+		},
+	}
+
+	assert.True(t, isRetentionDecrease(2*time.Hour, time.Hour))
+	assert.False(t, isRetentionDecrease(time.Hour, time.Hour))
+	assert.Equal(t, 3, destructiveChanges(diff))
+}
+
+func TestPkgSummaryDashboardRow(t *testing.T) {
+	dash := pkger.SummaryDashboard{
+		ID:          pkger.SafeID(1),
+		Name:        "dash_1",
+		Description: "desc",
+		Charts: []pkger.SummaryChart{
+			{Properties: influxdb.XYViewProperties{Type: influxdb.ViewPropertyTypeXY}},
+			{Properties: influxdb.SingleStatViewProperties{Type: influxdb.ViewPropertyTypeSingleStat}},
+			{Properties: influxdb.XYViewProperties{Type: influxdb.ViewPropertyTypeXY}},
+		},
+	}
+
+	expected := []string{
+		pkger.SafeID(1).String(),
+		"dash_1",
+		"3",
+		"single-stat, xy",
+		"desc",
+	}
+	assert.Equal(t, expected, summaryDashboardRow(dash))
+}
+
+func TestPkgExportResources(t *testing.T) {
+	t.Run("converts ids to resources to clone", func(t *testing.T) {
+		opts := pkgExportOpts{
+			buckets:    []string{influxdb.ID(1).String()},
+			dashboards: []string{influxdb.ID(2).String(), influxdb.ID(3).String()},
+		}
+
+		resources, err := opts.resourcesToClone()
+		require.NoError(t, err)
+
+		expected := []pkger.ResourceToClone{
+			{Kind: pkger.KindBucket, ID: 1},
+			{Kind: pkger.KindDashboard, ID: 2},
+			{Kind: pkger.KindDashboard, ID: 3},
+		}
+		assert.Equal(t, expected, resources)
+	})
+
+	t.Run("rejects invalid ids", func(t *testing.T) {
+		_, err := pkgExportOpts{dashboards: []string{"not an id"}}.resourcesToClone()
+		require.Error(t, err)
+	})
+
+	t.Run("requires a resource", func(t *testing.T) {
+		_, err := pkgExportOpts{}.resourcesToClone()
+		require.Error(t, err)
+	})
+
+	t.Run("converts kinds to an export filter", func(t *testing.T) {
+		filter, err := pkgExportOpts{kinds: []string{"bucket", " dashboard"}}.exportFilter()
+		require.NoError(t, err)
+
+		assert.Equal(t, []pkger.Kind{pkger.KindBucket, pkger.KindDashboard}, filter.Kinds)
+	})
+
+	t.Run("rejects invalid kinds", func(t *testing.T) {
+		_, err := pkgExportOpts{kinds: []string{"not a kind"}}.exportFilter()
+		require.Error(t, err)
+	})
+}
+
+func TestPkgVerboseParseErr(t *testing.T) {
+	pkgStr := `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName:      pkg_name
+  pkgVersion:   1
+spec:
+  resources:
+    - kind: Bucket
+    - kind: Bucket
+      name: rucket_1
+      associations:
+        - kind: Label
+          name: label_1
+`
+	_, err := pkger.Parse(pkger.EncodingYAML, pkger.FromString(pkgStr))
+	require.Error(t, err)
+
+	pErr, ok := pkger.IsParseErr(err)
+	require.True(t, ok)
+
+	expected := `failed to parse pkg, found 2 errors:
+  resource 0 (kind "bucket"):
+    - field "name": must be a string of at least 2 chars in length
+  resource 1 (kind "bucket"):
+    - association 0 field "associations": label "label_1" does not exist in pkg`
+	assert.Equal(t, expected, verboseParseErr(pErr))
+}
+
+func TestPkgWriteBackIDs(t *testing.T) {
+	sum := pkger.Summary{
+		Buckets: []pkger.SummaryBucket{
+			{Bucket: influxdb.Bucket{ID: influxdb.ID(1), Name: "rucket_1"}},
+		},
+		Labels: []pkger.SummaryLabel{
+			{Label: influxdb.Label{ID: influxdb.ID(2), Name: "label_1"}},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		ext    string
+		pkgStr string
+	}{
+		{
+			name: "yaml",
+			ext:  ".yml",
+			pkgStr: `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName: pkg_name
+  pkgVersion: 1
+spec:
+  resources:
+    # the label shared by the pkg
+    - kind: Label
+      name: label_1
+    - kind: Buckets
+      name: rucket_1
+      associations:
+        - kind: Label
+          name: label_1
+`,
+		},
+		{
+			name: "json",
+			ext:  ".json",
+			pkgStr: `{
+  "apiVersion": "0.1.0",
+  "kind": "Package",
+  "meta": {"pkgName": "pkg_name", "pkgVersion": "1"},
+  "spec": {
+    "resources": [
+      {"kind": "Label", "name": "label_1"},
+      {"kind": "Bucket", "name": "rucket_1", "associations": [{"kind": "Label", "name": "label_1"}]}
+    ]
+  }
+}
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		fn := func(t *testing.T) {
+			f, err := ioutil.TempFile("", "pkg_*"+tt.ext)
+			require.NoError(t, err)
+			defer os.Remove(f.Name())
+
+			_, err = f.WriteString(tt.pkgStr)
+			require.NoError(t, err)
+			require.NoError(t, f.Close())
+
+			require.NoError(t, writeBackPkgIDs(f.Name(), sum))
+
+			pkg, err := pkgFromFile(f.Name())
+			require.NoError(t, err)
+
+			ids := make(map[string]interface{})
+			for _, r := range pkg.Spec.Resources {
+				ids[r.Name()] = r["id"]
+			}
+			expected := map[string]interface{}{
+				"label_1":  influxdb.ID(2).String(),
+				"rucket_1": influxdb.ID(1).String(),
+			}
+			assert.Equal(t, expected, ids)
+
+			if tt.ext == ".yml" {
+				b, err := ioutil.ReadFile(f.Name())
+				require.NoError(t, err)
+				assert.Contains(t, string(b), "# the label shared by the pkg")
+			}
+		}
+		t.Run(tt.name, fn)
+	}
+}
+
+func TestPkgSummaryIDs(t *testing.T) {
+	sum := pkger.Summary{
+		Checks: []pkger.SummaryCheck{
+			{Check: &check.Deadman{Base: check.Base{ID: influxdb.ID(1), Name: "check_1"}}},
+		},
+		NotificationEndpoints: []pkger.SummaryNotificationEndpoint{
+			{NotificationEndpoint: &endpoint.Slack{Base: endpoint.Base{ID: influxdb.ID(2), Name: "endpoint_1"}}},
+		},
+		NotificationRules: []pkger.SummaryNotificationRule{
+			{NotificationRule: &rule.Slack{Base: rule.Base{ID: influxdb.ID(3), Name: "rule_1"}}},
+		},
+		TelegrafConfigs: []pkger.SummaryTelegraf{
+			{TelegrafConfig: influxdb.TelegrafConfig{ID: influxdb.ID(4), Name: "tele_1"}},
+		},
+	}
+	ids := summaryIDs(sum)
+
+	tests := []struct {
+		kind     string
+		name     string
+		expected influxdb.ID
+	}{
+		{kind: "Check", name: "check_1", expected: influxdb.ID(1)},
+		{kind: "NotificationEndpoints", name: "endpoint_1", expected: influxdb.ID(2)},
+		{kind: "NotificationRule", name: "rule_1", expected: influxdb.ID(3)},
+		{kind: "Telegrafs", name: "tele_1", expected: influxdb.ID(4)},
+	}
+	for _, tt := range tests {
+		fn := func(t *testing.T) {
+			id, ok := resourceID(ids, tt.kind, tt.name)
+			require.True(t, ok)
+			assert.Equal(t, tt.expected, id)
+		}
+		t.Run(tt.kind, fn)
+	}
+}
+
+func TestPkgWriteBackPath(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pkgs")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	const pkgStr = `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName: pkg_name
+  pkgVersion: 1
+spec:
+  resources:
+    - kind: Label
+      name: label_1
+`
+
+	t.Run("rejects gzipped pkg files", func(t *testing.T) {
+		path := filepath.Join(dir, "pkg.yml.gz")
+		require.NoError(t, ioutil.WriteFile(path, []byte(pkgStr), 0600))
+
+		_, err := pkgFromSource(path, &pkgApplyOpts{writeBackIDs: true})
+		require.Error(t, err)
+	})
+
+	t.Run("rejects read only pkg files", func(t *testing.T) {
+		if os.Geteuid() == 0 {
+			t.Skip("file permissions are not enforced for root")
+		}
+		path := filepath.Join(dir, "read_only.yml")
+		require.NoError(t, ioutil.WriteFile(path, []byte(pkgStr), 0400))
+
+		_, err := pkgFromSource(path, &pkgApplyOpts{writeBackIDs: true})
+		require.Error(t, err)
+	})
+}
+
+func TestPkgFromDir(t *testing.T) {
+	files := map[string]string{
+		"labels.yml": `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName: pkg_name
+  pkgVersion: 1
+spec:
+  resources:
+    - kind: Label
+      name: label_1
+`,
+		"buckets.json": `{
+  "apiVersion": "0.1.0",
+  "kind": "Package",
+  "meta": {"pkgName": "pkg_name", "pkgVersion": "1"},
+  "spec": {
+    "resources": [
+      {"kind": "Bucket", "name": "rucket_1", "associations": [{"kind": "Label", "name": "label_1"}]}
+    ]
+  }
+}
+`,
+		"README.md": "not a pkg",
+	}
+
+	dir, err := ioutil.TempDir("", "pkgs")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	for name, contents := range files {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(contents), 0600))
+	}
+
+	t.Run("merges the pkg files into one pkg", func(t *testing.T) {
+		pkg, err := pkgFromSource(dir, &pkgApplyOpts{})
+		require.NoError(t, err)
+
+		sum := pkg.Summary()
+		require.Len(t, sum.Labels, 1)
+		assert.Equal(t, "label_1", sum.Labels[0].Name)
+		require.Len(t, sum.Buckets, 1)
+		assert.Equal(t, "rucket_1", sum.Buckets[0].Name)
+		require.Len(t, sum.Buckets[0].LabelAssociations, 1)
+		assert.Equal(t, "label_1", sum.Buckets[0].LabelAssociations[0].Name)
+	})
+
+	t.Run("rejects writing ids back to a directory", func(t *testing.T) {
+		_, err := pkgFromSource(dir, &pkgApplyOpts{writeBackIDs: true})
+		require.Error(t, err)
+	})
+}
+
+func TestPkgFmtAnnotations(t *testing.T) {
+	const pkgStr = `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName: pkg_name
+  pkgVersion: 1
+spec:
+  resources:
+    - kind: Bucket
+      name: rucket_1
+      annotations:
+        owner: team-storage
+        notes: holds the raw metrics
+`
+
+	dir, err := ioutil.TempDir("", "pkgs")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "pkg.yml")
+	require.NoError(t, ioutil.WriteFile(path, []byte(pkgStr), 0600))
+
+	require.NoError(t, pkgFmt(&path)(nil, nil))
+
+	pkg, err := pkger.Parse(pkger.EncodingYAML, pkger.FromFile(path))
+	require.NoError(t, err)
+
+	require.Len(t, pkg.Spec.Resources, 1)
+	expected := map[string]interface{}{
+		"owner": "team-storage",
+		"notes": "holds the raw metrics",
+	}
+	assert.Equal(t, expected, pkg.Spec.Resources[0]["annotations"])
+}
+
+func TestPkgRef(t *testing.T) {
+	tests := []struct {
+		ref      string
+		expected pkgRef
+	}{
+		{
+			ref:      "registry.example.com/team/pkg:1.2.0",
+			expected: pkgRef{registry: "registry.example.com", repo: "team/pkg", tag: "1.2.0"},
+		},
+		{
+			ref:      "registry.example.com/team/pkg",
+			expected: pkgRef{registry: "registry.example.com", repo: "team/pkg", tag: "latest"},
+		},
+		{
+			ref:      "localhost:5000/pkg:v1",
+			expected: pkgRef{registry: "localhost:5000", repo: "pkg", tag: "v1"},
+		},
+	}
+
+	for _, tt := range tests {
+		fn := func(t *testing.T) {
+			ref, err := parsePkgRef(tt.ref)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, ref)
+		}
+		t.Run(tt.ref, fn)
+	}
+
+	t.Run("rejects invalid refs", func(t *testing.T) {
+		for _, ref := range []string{"", "pkg", "/team/pkg", "registry.example.com/", "registry.example.com/pkg:"} {
+			_, err := parsePkgRef(ref)
+			assert.Error(t, err, ref)
+		}
+	})
+}
+
+func TestPkgRegistryPull(t *testing.T) {
+	pkgBytes := []byte(`apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName: pkg_name
+  pkgVersion: 1
+spec:
+  resources:
+    - kind: Bucket
+      name: rucket_1
+`)
+	digest := fmt.Sprintf("sha256:%x", sha256.Sum256(pkgBytes))
+
+	const token = "secret"
+	newRegistry := func(t *testing.T) *httptest.Server {
+		return httptest.NewTLSServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+			if r.Header.Get("Authorization") != "Bearer "+token {
+				w.WriteHeader(nethttp.StatusUnauthorized)
+				return
+			}
+
+			switch r.URL.Path {
+			case "/v2/team/pkg/manifests/1.2.0":
+				assert.Contains(t, r.Header.Get("Accept"), ociManifestMediaType)
+				w.Header().Set("Content-Type", ociManifestMediaType)
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"schemaVersion": 2,
+					"layers": []map[string]interface{}{
+						{
+							"mediaType":   "application/vnd.influxdata.pkg.v1+yaml",
+							"digest":      digest,
+							"size":        len(pkgBytes),
+							"annotations": map[string]string{"org.opencontainers.image.title": "pkg.yml"},
+						},
+					},
+				})
+			case "/v2/team/pkg/blobs/" + digest:
+				w.Write(pkgBytes)
+			default:
+				nethttp.NotFound(w, r)
+			}
+		}))
+	}
+
+	pull := func(svr *httptest.Server, refStr, token string) (*pkger.Pkg, error) {
+		ref, err := parsePkgRef(strings.TrimPrefix(svr.URL, "https://") + refStr)
+		if err != nil {
+			return nil, err
+		}
+		c := &registryClient{client: svr.Client(), token: token}
+		return c.pull(context.Background(), ref)
+	}
+
+	t.Run("pulls and parses the pkg", func(t *testing.T) {
+		svr := newRegistry(t)
+		defer svr.Close()
+
+		pkg, err := pull(svr, "/team/pkg:1.2.0", token)
+		require.NoError(t, err)
+
+		sum := pkg.Summary()
+		require.Len(t, sum.Buckets, 1)
+		assert.Equal(t, "rucket_1", sum.Buckets[0].Name)
+	})
+
+	t.Run("reports unauthorized pulls", func(t *testing.T) {
+		svr := newRegistry(t)
+		defer svr.Close()
+
+		_, err := pull(svr, "/team/pkg:1.2.0", "wrong token")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not authorized")
+		assert.Contains(t, err.Error(), "--registry-token")
+	})
+
+	t.Run("reports missing pkgs", func(t *testing.T) {
+		svr := newRegistry(t)
+		defer svr.Close()
+
+		_, err := pull(svr, "/team/pkg:9.9.9", token)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not found")
+	})
+
+	t.Run("reports unreachable registries", func(t *testing.T) {
+		svr := newRegistry(t)
+		svr.Close()
+
+		_, err := pull(svr, "/team/pkg:1.2.0", token)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to reach registry")
+	})
+
+	t.Run("exchanges the token for the bearer challenge of the registry", func(t *testing.T) {
+		const exchanged = "exchanged"
+		registry := newRegistry(t)
+		defer registry.Close()
+
+		var svr *httptest.Server
+		svr = httptest.NewTLSServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+			if r.URL.Path == "/token" {
+				if r.Header.Get("Authorization") != "Bearer "+token {
+					w.WriteHeader(nethttp.StatusUnauthorized)
+					return
+				}
+				assert.Equal(t, "repository:team/pkg:pull", r.URL.Query().Get("scope"))
+				assert.Equal(t, "registry.example.com", r.URL.Query().Get("service"))
+				json.NewEncoder(w).Encode(map[string]string{"token": exchanged})
+				return
+			}
+
+			if r.Header.Get("Authorization") != "Bearer "+exchanged {
+				w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="registry.example.com",scope="repository:team/pkg:pull"`, svr.URL))
+				w.WriteHeader(nethttp.StatusUnauthorized)
+				return
+			}
+			// the registry serves the pkg for the exchanged token
+			r.Header.Set("Authorization", "Bearer "+token)
+			registry.Config.Handler.ServeHTTP(w, r)
+		}))
+		defer svr.Close()
+
+		pkg, err := pull(svr, "/team/pkg:1.2.0", token)
+		require.NoError(t, err)
+		require.Len(t, pkg.Summary().Buckets, 1)
+
+		_, err = pull(svr, "/team/pkg:1.2.0", "wrong token")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not authorized")
+	})
+
+	t.Run("rejects pkgs exceeding the maximum size", func(t *testing.T) {
+		svr := httptest.NewTLSServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"schemaVersion": 2,
+				"layers": []map[string]interface{}{
+					{"mediaType": "application/vnd.influxdata.pkg.v1+yaml", "digest": digest, "size": maxRegistryPkgSize + 1},
+				},
+			})
+		}))
+		defer svr.Close()
+
+		_, err := pull(svr, "/team/pkg:1.2.0", token)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "exceeds the maximum size")
+	})
+}
+
+func TestParseBearerChallenge(t *testing.T) {
+	t.Run("parses the challenge parameters", func(t *testing.T) {
+		params, ok := parseBearerChallenge(`Bearer realm="https://auth.example.com/token",service="registry.example.com",scope="repository:team/pkg:pull,push"`)
+		require.True(t, ok)
+
+		expected := map[string]string{
+			"realm":   "https://auth.example.com/token",
+			"service": "registry.example.com",
+			"scope":   "repository:team/pkg:pull,push",
+		}
+		assert.Equal(t, expected, params)
+	})
+
+	t.Run("rejects challenges that are not bearer or lack a realm", func(t *testing.T) {
+		for _, header := range []string{"", `Basic realm="registry"`, `Bearer service="registry.example.com"`, `Bearer realm="unterminated`} {
+			_, ok := parseBearerChallenge(header)
+			assert.False(t, ok, header)
+		}
+	})
+}
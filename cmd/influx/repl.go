@@ -73,7 +73,7 @@ func replF(cmd *cobra.Command, args []string) error {
 
 	flux.FinalizeBuiltIns()
 
-	r, err := getFluxREPL(flags.host, flags.token, orgID)
+	r, err := getFluxREPL(flags.host, flags.token, flags.skipVerify, orgID)
 	if err != nil {
 		return err
 	}
@@ -84,8 +84,9 @@ func replF(cmd *cobra.Command, args []string) error {
 
 func findOrgID(ctx context.Context, org string) (platform.ID, error) {
 	svc := &http.OrganizationService{
-		Addr:  flags.host,
-		Token: flags.token,
+		Addr:               flags.host,
+		Token:              flags.token,
+		InsecureSkipVerify: flags.skipVerify,
 	}
 
 	o, err := svc.FindOrganization(ctx, platform.OrganizationFilter{
@@ -98,10 +99,11 @@ func findOrgID(ctx context.Context, org string) (platform.ID, error) {
 	return o.ID, nil
 }
 
-func getFluxREPL(addr, token string, orgID platform.ID) (*repl.REPL, error) {
+func getFluxREPL(addr, token string, skipVerify bool, orgID platform.ID) (*repl.REPL, error) {
 	qs := &http.FluxQueryService{
-		Addr:  addr,
-		Token: token,
+		Addr:               addr,
+		Token:              token,
+		InsecureSkipVerify: skipVerify,
 	}
 	q := &query.REPLQuerier{
 		OrganizationID: orgID,
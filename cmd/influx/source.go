@@ -0,0 +1,517 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	nethttp "net/http"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/flux/repl"
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/cmd/influx/internal"
+	"github.com/influxdata/influxdb/http"
+	"github.com/influxdata/influxdb/query/influxql"
+	"github.com/spf13/cobra"
+)
+
+var sourceCmd = &cobra.Command{
+	Use:   "source",
+	Short: "Source management commands",
+	Run:   sourceF,
+}
+
+func sourceF(cmd *cobra.Command, args []string) {
+	cmd.Usage()
+}
+
+// SourceQueryFlags define the query source command
+type SourceQueryFlags struct {
+	id       string
+	typ      string
+	db       string
+	rp       string
+	cluster  string
+	orgID    string
+	isPretty bool
+}
+
+var sourceQueryFlags SourceQueryFlags
+
+// sourceQueryCompilerTypes are the compiler types a query may be
+// submitted to a source with from the CLI.
+var sourceQueryCompilerTypes = []string{
+	lang.FluxCompilerType,
+	influxql.CompilerType,
+}
+
+func init() {
+	sourceQueryCmd := &cobra.Command{
+		Use:   "query [query literal or @/path/to/query]",
+		Short: "Execute a query against a source",
+		Long: `Execute a literal query provided as a string against a source,
+or execute a query contained in a file by specifying the file prefixed with an @ sign.`,
+		Args: cobra.ExactArgs(1),
+		RunE: wrapCheckSetup(sourceQueryF),
+	}
+
+	sourceQueryCmd.Flags().StringVarP(&sourceQueryFlags.id, "id", "i", "", "The source ID (required)")
+	sourceQueryCmd.Flags().StringVar(&sourceQueryFlags.typ, "type", lang.FluxCompilerType, fmt.Sprintf("The query type, one of: %s", strings.Join(sourceQueryCompilerTypes, ", ")))
+	sourceQueryCmd.Flags().StringVar(&sourceQueryFlags.db, "db", "", "The database to query, influxql only")
+	sourceQueryCmd.Flags().StringVar(&sourceQueryFlags.rp, "rp", "", "The retention policy to query, influxql only")
+	sourceQueryCmd.Flags().StringVar(&sourceQueryFlags.cluster, "cluster", "", "The cluster to query, influxql only")
+	sourceQueryCmd.Flags().StringVar(&sourceQueryFlags.orgID, "org-id", "", "The organization ID")
+	sourceQueryCmd.Flags().BoolVar(&sourceQueryFlags.isPretty, "pretty", false, "Print the result as a table rather than raw CSV")
+	sourceQueryCmd.MarkFlagRequired("id")
+
+	sourceCmd.AddCommand(sourceQueryCmd)
+}
+
+// sourceQueryRequest is the body of a POST /api/v2/sources/:id/query request.
+type sourceQueryRequest struct {
+	Query          string              `json:"query"`
+	Type           string              `json:"type"`
+	DB             string              `json:"db,omitempty"`
+	RP             string              `json:"rp,omitempty"`
+	Cluster        string              `json:"cluster,omitempty"`
+	OrganizationID *platform.ID        `json:"organizationID,omitempty"`
+	Dialect        *sourceQueryDialect `json:"dialect,omitempty"`
+}
+
+// sourceQueryDialect selects how the results of a source query are encoded.
+type sourceQueryDialect struct {
+	Type string `json:"type"`
+}
+
+func newSourceQueryReq(f SourceQueryFlags, q string) (*sourceQueryRequest, error) {
+	if !isValidSourceCompilerType(f.typ) {
+		return nil, fmt.Errorf("invalid query type %q; must be one of: %s", f.typ, strings.Join(sourceQueryCompilerTypes, ", "))
+	}
+
+	if strings.TrimSpace(q) == "" {
+		return nil, fmt.Errorf("must provide a query")
+	}
+
+	req := &sourceQueryRequest{
+		Query: q,
+		Type:  f.typ,
+	}
+
+	if f.orgID != "" {
+		orgID, err := platform.IDFromString(f.orgID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode org-id: %v", err)
+		}
+		req.OrganizationID = orgID
+	}
+
+	switch f.typ {
+	case influxql.CompilerType:
+		req.DB = f.db
+		req.RP = f.rp
+		req.Cluster = f.cluster
+		req.Dialect = &sourceQueryDialect{Type: influxql.DialectType}
+	default:
+		if f.db != "" || f.rp != "" || f.cluster != "" {
+			return nil, fmt.Errorf("db, rp and cluster are only supported by %s queries", influxql.CompilerType)
+		}
+	}
+
+	return req, nil
+}
+
+func isValidSourceCompilerType(typ string) bool {
+	for _, t := range sourceQueryCompilerTypes {
+		if t == typ {
+			return true
+		}
+	}
+	return false
+}
+
+func sourceQueryF(cmd *cobra.Command, args []string) error {
+	if flags.local {
+		return fmt.Errorf("local flag not supported for source query command")
+	}
+
+	id, err := platform.IDFromString(sourceQueryFlags.id)
+	if err != nil {
+		return fmt.Errorf("failed to decode source id %q: %v", sourceQueryFlags.id, err)
+	}
+
+	q, err := repl.LoadQuery(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to load query: %v", err)
+	}
+
+	req, err := newSourceQueryReq(sourceQueryFlags, q)
+	if err != nil {
+		return err
+	}
+
+	w := io.Writer(os.Stdout)
+	var buf bytes.Buffer
+	if sourceQueryFlags.isPretty {
+		w = &buf
+	}
+
+	if err := postSourceQuery(context.Background(), *id, req, w); err != nil {
+		return fmt.Errorf("failed to query source: %v", err)
+	}
+
+	if sourceQueryFlags.isPretty {
+		return writePrettyCSV(os.Stdout, &buf)
+	}
+	return nil
+}
+
+func postSourceQuery(ctx context.Context, id platform.ID, qr *sourceQueryRequest, w io.Writer) error {
+	u, err := http.NewURL(flags.host, fmt.Sprintf("/api/v2/sources/%s/query", id))
+	if err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(qr)
+	if err != nil {
+		return err
+	}
+
+	req, err := nethttp.NewRequest("POST", u.String(), bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	http.SetToken(flags.token, req)
+
+	hc := http.NewClient(u.Scheme, flags.skipVerify)
+	resp, err := hc.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := http.CheckError(resp); err != nil {
+		return err
+	}
+
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
+
+// writePrettyCSV renders the CSV result as aligned columns. Annotation rows
+// and the blank lines separating result tables are dropped.
+func writePrettyCSV(w io.Writer, r io.Reader) error {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	for {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		if len(rec) == 0 || strings.HasPrefix(rec[0], "#") {
+			continue
+		}
+		fmt.Fprintln(tw, strings.Join(rec, "\t"))
+	}
+	return tw.Flush()
+}
+
+// SourceCloneFlags define the clone source command
+type SourceCloneFlags struct {
+	id              string
+	name            string
+	password        string
+	passwordEnv     string
+	sharedSecret    string
+	sharedSecretEnv string
+	token           string
+	tokenEnv        string
+}
+
+var sourceCloneFlags SourceCloneFlags
+
+func init() {
+	sourceCloneCmd := &cobra.Command{
+		Use:   "clone",
+		Short: "Create a new source from the configuration of an existing source",
+		Long: `Create a new source from the configuration of an existing source.
+Secrets are stripped from sources when they are read, they must be provided
+again for the new source with the password, shared-secret and source-token
+flags, or read from the environment variables named by the password-env,
+shared-secret-env and token-env flags.`,
+		RunE: wrapCheckSetup(sourceCloneF),
+	}
+
+	sourceCloneCmd.Flags().StringVarP(&sourceCloneFlags.id, "id", "i", "", "The ID of the source to clone (required)")
+	sourceCloneCmd.Flags().StringVarP(&sourceCloneFlags.name, "new-name", "n", "", "The name of the new source (required)")
+	sourceCloneCmd.Flags().StringVar(&sourceCloneFlags.password, "password", "", "The password of the new source, 1.x sources only")
+	sourceCloneCmd.Flags().StringVar(&sourceCloneFlags.passwordEnv, "password-env", "", "The environment variable the password of the new source is read from, 1.x sources only")
+	sourceCloneCmd.Flags().StringVar(&sourceCloneFlags.sharedSecret, "shared-secret", "", "The shared secret of the new source, 1.x sources only")
+	sourceCloneCmd.Flags().StringVar(&sourceCloneFlags.sharedSecretEnv, "shared-secret-env", "", "The environment variable the shared secret of the new source is read from, 1.x sources only")
+	sourceCloneCmd.Flags().StringVar(&sourceCloneFlags.token, "source-token", "", "The token of the new source, 2.x sources only")
+	sourceCloneCmd.Flags().StringVar(&sourceCloneFlags.tokenEnv, "token-env", "", "The environment variable the token of the new source is read from, 2.x sources only")
+	sourceCloneCmd.MarkFlagRequired("id")
+	sourceCloneCmd.MarkFlagRequired("new-name")
+
+	sourceCmd.AddCommand(sourceCloneCmd)
+}
+
+// newSourceCloneReq returns the source to create as a clone of src. The clone
+// has no ID, is never the default source, and only has the secrets provided
+// by the flags, either directly or through the environment variables they
+// name.
+func newSourceCloneReq(src platform.Source, f SourceCloneFlags, lookupEnv func(string) (string, bool)) (*platform.Source, error) {
+	if strings.TrimSpace(f.name) == "" {
+		return nil, fmt.Errorf("must provide a name for the new source")
+	}
+	if f.name == src.Name {
+		return nil, fmt.Errorf("new source name %q must differ from the source being cloned", f.name)
+	}
+
+	secret := func(flag, v, envFlag, env string) (string, error) {
+		if v != "" && env != "" {
+			return "", fmt.Errorf("must specify exactly one of %s or %s", flag, envFlag)
+		}
+		if v != "" {
+			return v, nil
+		}
+		return lookupSourceSecret(lookupEnv, envFlag, env)
+	}
+
+	password, err := secret("password", f.password, "password-env", f.passwordEnv)
+	if err != nil {
+		return nil, err
+	}
+	sharedSecret, err := secret("shared-secret", f.sharedSecret, "shared-secret-env", f.sharedSecretEnv)
+	if err != nil {
+		return nil, err
+	}
+	token, err := secret("source-token", f.token, "token-env", f.tokenEnv)
+	if err != nil {
+		return nil, err
+	}
+
+	clone := src
+	clone.ID = 0
+	clone.Default = false
+	clone.Name = f.name
+	clone.Password = password
+	clone.SharedSecret = sharedSecret
+	clone.Token = token
+	return &clone, nil
+}
+
+// lookupSourceSecret resolves the secret of a source from the environment
+// variable named by the flag. No variable is no secret, a named variable that
+// is not set is an error.
+func lookupSourceSecret(lookupEnv func(string) (string, bool), flag, env string) (string, error) {
+	if env == "" {
+		return "", nil
+	}
+	v, ok := lookupEnv(env)
+	if !ok {
+		return "", fmt.Errorf("environment variable %q of the %s flag is not set", env, flag)
+	}
+	return v, nil
+}
+
+func sourceCloneF(cmd *cobra.Command, args []string) error {
+	if flags.local {
+		return fmt.Errorf("local flag not supported for source clone command")
+	}
+
+	id, err := platform.IDFromString(sourceCloneFlags.id)
+	if err != nil {
+		return fmt.Errorf("failed to decode source id %q: %v", sourceCloneFlags.id, err)
+	}
+
+	s := &http.SourceService{
+		Addr:               flags.host,
+		Token:              flags.token,
+		InsecureSkipVerify: flags.skipVerify,
+	}
+
+	ctx := context.Background()
+	src, err := s.FindSourceByID(ctx, *id)
+	if err != nil {
+		return fmt.Errorf("failed to find source %s: %v", id, err)
+	}
+
+	clone, err := newSourceCloneReq(*src, sourceCloneFlags, os.LookupEnv)
+	if err != nil {
+		return err
+	}
+
+	if err := s.CreateSource(ctx, clone); err != nil {
+		return fmt.Errorf("failed to create source: %v", err)
+	}
+
+	w := internal.NewTabWriter(os.Stdout)
+	w.WriteHeaders(
+		"ID",
+		"Name",
+		"Type",
+		"URL",
+	)
+	w.Write(map[string]interface{}{
+		"ID":   clone.ID.String(),
+		"Name": clone.Name,
+		"Type": clone.Type,
+		"URL":  clone.URL,
+	})
+	w.Flush()
+
+	return nil
+}
+
+// SourceCreateFlags define the create source command
+type SourceCreateFlags struct {
+	name               string
+	typ                string
+	url                string
+	orgID              string
+	username           string
+	passwordEnv        string
+	sharedSecretEnv    string
+	tokenEnv           string
+	metaURL            string
+	defaultRP          string
+	telegraf           string
+	isDefault          bool
+	insecureSkipVerify bool
+}
+
+var sourceCreateFlags SourceCreateFlags
+
+func init() {
+	sourceCreateCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a source",
+		Long: `Create a source. Secrets are read from the environment variables named by
+the password-env, shared-secret-env and token-env flags, so they are never
+passed as flags that are visible in process listings.`,
+		RunE: wrapCheckSetup(sourceCreateF),
+	}
+
+	sourceCreateCmd.Flags().StringVarP(&sourceCreateFlags.name, "name", "n", "", "The name of the source (required)")
+	sourceCreateCmd.Flags().StringVar(&sourceCreateFlags.typ, "type", platform.V1SourceType, "The type of the source, one of: v1, v2, self")
+	sourceCreateCmd.Flags().StringVar(&sourceCreateFlags.url, "url", "", "The URL of the source")
+	sourceCreateCmd.Flags().StringVar(&sourceCreateFlags.orgID, "org-id", "", "The ID of the organization that owns the source (required)")
+	sourceCreateCmd.Flags().StringVar(&sourceCreateFlags.username, "username", "", "The username to connect to the source with, 1.x sources only")
+	sourceCreateCmd.Flags().StringVar(&sourceCreateFlags.passwordEnv, "password-env", "", "The environment variable the password of the source is read from, 1.x sources only")
+	sourceCreateCmd.Flags().StringVar(&sourceCreateFlags.sharedSecretEnv, "shared-secret-env", "", "The environment variable the shared secret of the source is read from, 1.x sources only")
+	sourceCreateCmd.Flags().StringVar(&sourceCreateFlags.tokenEnv, "token-env", "", "The environment variable the token of the source is read from, 2.x sources only")
+	sourceCreateCmd.Flags().StringVar(&sourceCreateFlags.metaURL, "meta-url", "", "The URL of the meta node, 1.x enterprise sources only")
+	sourceCreateCmd.Flags().StringVar(&sourceCreateFlags.defaultRP, "default-rp", "", "The default retention policy of queries to the source, 1.x sources only")
+	sourceCreateCmd.Flags().StringVar(&sourceCreateFlags.telegraf, "telegraf", "telegraf", "The database telegraf writes to")
+	sourceCreateCmd.Flags().BoolVar(&sourceCreateFlags.isDefault, "default", false, "Make the source the default source")
+	sourceCreateCmd.Flags().BoolVar(&sourceCreateFlags.insecureSkipVerify, "insecure-skip-verify", false, "Accept any certificate presented by the source")
+	sourceCreateCmd.MarkFlagRequired("name")
+	sourceCreateCmd.MarkFlagRequired("org-id")
+
+	sourceCmd.AddCommand(sourceCreateCmd)
+}
+
+// newSourceCreateReq returns the source to create from the flags. Its secrets
+// are resolved by lookupEnv from the environment variables the flags name, a
+// named variable that is not set is an error.
+func newSourceCreateReq(f SourceCreateFlags, lookupEnv func(string) (string, bool)) (*platform.Source, error) {
+	if strings.TrimSpace(f.name) == "" {
+		return nil, fmt.Errorf("must provide a name for the source")
+	}
+
+	typ := platform.SourceType(f.typ)
+	switch typ {
+	case platform.V1SourceType, platform.V2SourceType, platform.SelfSourceType:
+	default:
+		return nil, fmt.Errorf("invalid source type %q; must be one of: v1, v2, self", f.typ)
+	}
+
+	orgID, err := platform.IDFromString(f.orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode org id %q: %v", f.orgID, err)
+	}
+
+	password, err := lookupSourceSecret(lookupEnv, "password-env", f.passwordEnv)
+	if err != nil {
+		return nil, err
+	}
+	sharedSecret, err := lookupSourceSecret(lookupEnv, "shared-secret-env", f.sharedSecretEnv)
+	if err != nil {
+		return nil, err
+	}
+	token, err := lookupSourceSecret(lookupEnv, "token-env", f.tokenEnv)
+	if err != nil {
+		return nil, err
+	}
+
+	return &platform.Source{
+		OrganizationID:     *orgID,
+		Default:            f.isDefault,
+		Name:               f.name,
+		Type:               typ,
+		URL:                f.url,
+		InsecureSkipVerify: f.insecureSkipVerify,
+		Telegraf:           f.telegraf,
+		SourceFields: platform.SourceFields{
+			Token: token,
+		},
+		V1SourceFields: platform.V1SourceFields{
+			Username:     f.username,
+			Password:     password,
+			SharedSecret: sharedSecret,
+			MetaURL:      f.metaURL,
+			DefaultRP:    f.defaultRP,
+		},
+	}, nil
+}
+
+func sourceCreateF(cmd *cobra.Command, args []string) error {
+	if flags.local {
+		return fmt.Errorf("local flag not supported for source create command")
+	}
+
+	src, err := newSourceCreateReq(sourceCreateFlags, os.LookupEnv)
+	if err != nil {
+		return err
+	}
+
+	s := &http.SourceService{
+		Addr:               flags.host,
+		Token:              flags.token,
+		InsecureSkipVerify: flags.skipVerify,
+	}
+
+	if err := s.CreateSource(context.Background(), src); err != nil {
+		return fmt.Errorf("failed to create source: %v", err)
+	}
+
+	w := internal.NewTabWriter(os.Stdout)
+	w.WriteHeaders(
+		"ID",
+		"Name",
+		"Type",
+		"URL",
+	)
+	w.Write(map[string]interface{}{
+		"ID":   src.ID.String(),
+		"Name": src.Name,
+		"Type": src.Type,
+		"URL":  src.URL,
+	})
+	w.Flush()
+
+	return nil
+}
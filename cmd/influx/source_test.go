@@ -0,0 +1,263 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSourceQuery(t *testing.T) {
+	orgID := platform.ID(3)
+
+	t.Run("constructs request per compiler type", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			flags    SourceQueryFlags
+			query    string
+			expected *sourceQueryRequest
+		}{
+			{
+				name:  "flux",
+				flags: SourceQueryFlags{typ: "flux", orgID: orgID.String()},
+				query: `from(bucket: "b") |> range(start: -1h)`,
+				expected: &sourceQueryRequest{
+					Query:          `from(bucket: "b") |> range(start: -1h)`,
+					Type:           "flux",
+					OrganizationID: &orgID,
+				},
+			},
+			{
+				name: "influxql",
+				flags: SourceQueryFlags{
+					typ:     "influxql",
+					db:      "telegraf",
+					rp:      "autogen",
+					cluster: "c1",
+				},
+				query: "SELECT * FROM cpu",
+				expected: &sourceQueryRequest{
+					Query:   "SELECT * FROM cpu",
+					Type:    "influxql",
+					DB:      "telegraf",
+					RP:      "autogen",
+					Cluster: "c1",
+					Dialect: &sourceQueryDialect{Type: "influxql"},
+				},
+			},
+		}
+
+		for _, tt := range tests {
+			fn := func(t *testing.T) {
+				req, err := newSourceQueryReq(tt.flags, tt.query)
+				require.NoError(t, err)
+				assert.Equal(t, tt.expected, req)
+			}
+			t.Run(tt.name, fn)
+		}
+	})
+
+	t.Run("rejects invalid requests", func(t *testing.T) {
+		tests := []struct {
+			name  string
+			flags SourceQueryFlags
+			query string
+		}{
+			{
+				name:  "unsupported compiler type",
+				flags: SourceQueryFlags{typ: "REPL"},
+				query: "SELECT * FROM cpu",
+			},
+			{
+				name:  "empty query",
+				flags: SourceQueryFlags{typ: "flux"},
+				query: "  ",
+			},
+			{
+				name:  "influxql fields on flux query",
+				flags: SourceQueryFlags{typ: "flux", db: "telegraf"},
+				query: `from(bucket: "b")`,
+			},
+			{
+				name:  "invalid org id",
+				flags: SourceQueryFlags{typ: "flux", orgID: "bad"},
+				query: `from(bucket: "b")`,
+			},
+		}
+
+		for _, tt := range tests {
+			fn := func(t *testing.T) {
+				_, err := newSourceQueryReq(tt.flags, tt.query)
+				require.Error(t, err)
+			}
+			t.Run(tt.name, fn)
+		}
+	})
+
+	t.Run("pretty prints csv", func(t *testing.T) {
+		in := `#datatype,string,long,double
+#group,false,false,false
+#default,_result,,
+,result,table,_value
+,,0,1.5
+,,0,10
+
+`
+		var buf bytes.Buffer
+		require.NoError(t, writePrettyCSV(&buf, strings.NewReader(in)))
+
+		expected := "  result  table  _value\n" +
+			"          0      1.5\n" +
+			"          0      10\n"
+		assert.Equal(t, expected, buf.String())
+	})
+}
+
+func TestSourceClone(t *testing.T) {
+	src := platform.Source{
+		ID:             platform.ID(1),
+		OrganizationID: platform.ID(2),
+		Default:        true,
+		Name:           "prod",
+		Type:           platform.V1SourceType,
+		URL:            "http://prod.example.com:8086",
+		Telegraf:       "telegraf",
+		V1SourceFields: platform.V1SourceFields{
+			Username:  "admin",
+			DefaultRP: "autogen",
+		},
+	}
+
+	env := map[string]string{
+		"STAGING_SHARED_SECRET": "shared",
+	}
+	lookupEnv := func(k string) (string, bool) {
+		v, ok := env[k]
+		return v, ok
+	}
+
+	t.Run("constructs the new source", func(t *testing.T) {
+		clone, err := newSourceCloneReq(src, SourceCloneFlags{
+			name:         "staging",
+			password:     "secret",
+			sharedSecret: "shared",
+		}, lookupEnv)
+		require.NoError(t, err)
+
+		expected := &platform.Source{
+			OrganizationID: platform.ID(2),
+			Name:           "staging",
+			Type:           platform.V1SourceType,
+			URL:            "http://prod.example.com:8086",
+			Telegraf:       "telegraf",
+			V1SourceFields: platform.V1SourceFields{
+				Username:     "admin",
+				Password:     "secret",
+				SharedSecret: "shared",
+				DefaultRP:    "autogen",
+			},
+		}
+		assert.Equal(t, expected, clone)
+		assert.Equal(t, platform.ID(1), src.ID, "the cloned source is left untouched")
+	})
+
+	t.Run("resolves secrets from the environment", func(t *testing.T) {
+		clone, err := newSourceCloneReq(src, SourceCloneFlags{
+			name:            "staging",
+			password:        "secret",
+			sharedSecretEnv: "STAGING_SHARED_SECRET",
+		}, lookupEnv)
+		require.NoError(t, err)
+
+		assert.Equal(t, "secret", clone.Password)
+		assert.Equal(t, "shared", clone.SharedSecret)
+	})
+
+	t.Run("errors on an unset environment variable", func(t *testing.T) {
+		_, err := newSourceCloneReq(src, SourceCloneFlags{
+			name:     "staging",
+			tokenEnv: "MISSING_TOKEN",
+		}, lookupEnv)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "MISSING_TOKEN")
+	})
+
+	t.Run("rejects a secret and its environment variable", func(t *testing.T) {
+		_, err := newSourceCloneReq(src, SourceCloneFlags{
+			name:            "staging",
+			sharedSecret:    "shared",
+			sharedSecretEnv: "STAGING_SHARED_SECRET",
+		}, lookupEnv)
+		assert.Error(t, err)
+	})
+
+	t.Run("rejects invalid names", func(t *testing.T) {
+		_, err := newSourceCloneReq(src, SourceCloneFlags{name: " "}, lookupEnv)
+		assert.Error(t, err)
+
+		_, err = newSourceCloneReq(src, SourceCloneFlags{name: "prod"}, lookupEnv)
+		assert.Error(t, err)
+	})
+}
+
+func TestSourceCreate(t *testing.T) {
+	env := map[string]string{
+		"PROD_PASSWORD":      "secret",
+		"PROD_SHARED_SECRET": "shared",
+	}
+	lookupEnv := func(k string) (string, bool) {
+		v, ok := env[k]
+		return v, ok
+	}
+
+	t.Run("resolves secrets from the environment", func(t *testing.T) {
+		src, err := newSourceCreateReq(SourceCreateFlags{
+			name:            "prod",
+			typ:             platform.V1SourceType,
+			url:             "http://prod.example.com:8086",
+			orgID:           platform.ID(2).String(),
+			username:        "admin",
+			passwordEnv:     "PROD_PASSWORD",
+			sharedSecretEnv: "PROD_SHARED_SECRET",
+			telegraf:        "telegraf",
+		}, lookupEnv)
+		require.NoError(t, err)
+
+		expected := &platform.Source{
+			OrganizationID: platform.ID(2),
+			Name:           "prod",
+			Type:           platform.V1SourceType,
+			URL:            "http://prod.example.com:8086",
+			Telegraf:       "telegraf",
+			V1SourceFields: platform.V1SourceFields{
+				Username:     "admin",
+				Password:     "secret",
+				SharedSecret: "shared",
+			},
+		}
+		assert.Equal(t, expected, src)
+	})
+
+	t.Run("errors on an unset environment variable", func(t *testing.T) {
+		_, err := newSourceCreateReq(SourceCreateFlags{
+			name:        "prod",
+			typ:         platform.V1SourceType,
+			orgID:       platform.ID(2).String(),
+			passwordEnv: "MISSING_PASSWORD",
+		}, lookupEnv)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "MISSING_PASSWORD")
+	})
+
+	t.Run("rejects invalid types", func(t *testing.T) {
+		_, err := newSourceCreateReq(SourceCreateFlags{
+			name:  "prod",
+			typ:   "v3",
+			orgID: platform.ID(2).String(),
+		}, lookupEnv)
+		assert.Error(t, err)
+	})
+}
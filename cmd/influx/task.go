@@ -2,15 +2,21 @@ package main
 
 import (
 	"context"
+	"encoding/csv"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/influxdata/flux/repl"
 	platform "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/cmd/influx/internal"
 	"github.com/influxdata/influxdb/http"
+	"github.com/influxdata/influxdb/task/options"
 	"github.com/spf13/cobra"
+	cron "gopkg.in/robfig/cron.v2"
 )
 
 // task Command
@@ -29,6 +35,14 @@ func taskF(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func newTaskService(f Flags) *http.TaskService {
+	return &http.TaskService{
+		Addr:               f.host,
+		Token:              f.token,
+		InsecureSkipVerify: f.skipVerify,
+	}
+}
+
 var logCmd = &cobra.Command{
 	Use:   "log",
 	Short: "Log related commands",
@@ -56,8 +70,12 @@ func init() {
 
 // TaskCreateFlags define the Create Command
 type TaskCreateFlags struct {
-	org   string
-	orgID string
+	org    string
+	orgID  string
+	every  string
+	cron   string
+	offset string
+	json   bool
 }
 
 var taskCreateFlags TaskCreateFlags
@@ -66,12 +84,19 @@ func init() {
 	taskCreateCmd := &cobra.Command{
 		Use:   "create [query literal or @/path/to/query.flux]",
 		Short: "Create task",
-		Args:  cobra.ExactArgs(1),
-		RunE:  wrapCheckSetup(taskCreateF),
+		Long: `Create a task from a flux script.
+The schedule flags set the options of the script's task option,
+overriding the schedule it defines.`,
+		Args: cobra.ExactArgs(1),
+		RunE: wrapCheckSetup(taskCreateF),
 	}
 
 	taskCreateCmd.Flags().StringVarP(&taskCreateFlags.org, "org", "", "", "organization name")
 	taskCreateCmd.Flags().StringVarP(&taskCreateFlags.orgID, "org-id", "", "", "id of the organization that owns the task")
+	taskCreateCmd.Flags().StringVarP(&taskCreateFlags.every, "every", "", "", "run the task at the provided interval, replaces any cron schedule of the script")
+	taskCreateCmd.Flags().StringVarP(&taskCreateFlags.cron, "cron", "", "", "run the task on the provided cron schedule, replaces any every interval of the script")
+	taskCreateCmd.Flags().StringVarP(&taskCreateFlags.offset, "offset", "", "", "the task offset, 0 removes the offset of the script")
+	taskCreateCmd.Flags().BoolVar(&taskCreateFlags.json, "json", false, "output the created task as JSON")
 	taskCreateCmd.MarkFlagRequired("flux")
 
 	taskCmd.AddCommand(taskCreateCmd)
@@ -82,16 +107,18 @@ func taskCreateF(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("must specify exactly one of org or org-id")
 	}
 
-	s := &http.TaskService{
-		Addr:  flags.host,
-		Token: flags.token,
-	}
+	s := newTaskService(flags)
 
 	flux, err := repl.LoadQuery(args[0])
 	if err != nil {
 		return fmt.Errorf("error parsing flux script: %s", err)
 	}
 
+	flux, err = createTaskSchedule(flux, taskCreateFlags)
+	if err != nil {
+		return err
+	}
+
 	tc := platform.TaskCreate{
 		Flux:         flux,
 		Organization: taskCreateFlags.org,
@@ -109,29 +136,32 @@ func taskCreateF(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	w := internal.NewTabWriter(os.Stdout)
-	w.WriteHeaders(
-		"ID",
-		"Name",
-		"OrganizationID",
-		"Organization",
-		"AuthorizationID",
-		"Status",
-		"Every",
-		"Cron",
-	)
-	w.Write(map[string]interface{}{
-		"ID":             t.ID.String(),
-		"Name":           t.Name,
-		"OrganizationID": t.OrganizationID.String(),
-		"Organization":   t.Organization,
-		"Status":         t.Status,
-		"Every":          t.Every,
-		"Cron":           t.Cron,
-	})
-	w.Flush()
+	return writeTasks(os.Stdout, []http.Task{*t}, taskCreateFlags.json)
+}
 
-	return nil
+// createTaskSchedule sets the schedule options provided by the flags in the
+// task option of the flux script. The flux is returned unchanged when no
+// schedule flags are provided.
+func createTaskSchedule(flux string, f TaskCreateFlags) (string, error) {
+	scheduleFlags := TaskUpdateFlags{
+		every:  f.every,
+		cron:   f.cron,
+		offset: f.offset,
+	}
+	if !scheduleFlags.hasSchedule() {
+		return flux, nil
+	}
+
+	var update platform.TaskUpdate
+	if err := updateTaskSchedule(&update, scheduleFlags, flux); err != nil {
+		return "", err
+	}
+
+	// the edited script must still provide valid task options
+	if _, err := options.FromScript(*update.Flux); err != nil {
+		return "", fmt.Errorf("invalid task options: %v", err)
+	}
+	return *update.Flux, nil
 }
 
 // taskFindFlags define the Find Command
@@ -141,6 +171,13 @@ type TaskFindFlags struct {
 	org   string
 	orgID string
 	limit int
+
+	overdue      bool
+	overdueGrace time.Duration
+	schedule     string
+
+	json bool
+	csv  bool
 }
 
 var taskFindFlags TaskFindFlags
@@ -157,16 +194,28 @@ func init() {
 	taskFindCmd.Flags().StringVarP(&taskFindFlags.org, "org", "", "", "task organization name")
 	taskFindCmd.Flags().StringVarP(&taskFindFlags.orgID, "org-id", "", "", "task organization ID")
 	taskFindCmd.Flags().IntVarP(&taskFindFlags.limit, "limit", "", platform.TaskDefaultPageSize, "the number of tasks to find")
+	taskFindCmd.Flags().BoolVar(&taskFindFlags.overdue, "overdue", false, "only list active tasks whose next run is overdue")
+	taskFindCmd.Flags().DurationVar(&taskFindFlags.overdueGrace, "overdue-grace", time.Minute, "how long past its due time a task must be to be overdue")
+	taskFindCmd.Flags().StringVar(&taskFindFlags.schedule, "schedule", "", "only list tasks with the schedule type; every or cron")
+	taskFindCmd.Flags().BoolVar(&taskFindFlags.json, "json", false, "output the tasks as JSON")
+	taskFindCmd.Flags().BoolVar(&taskFindFlags.csv, "csv", false, "output the tasks as CSV, with the columns of the table")
 
 	taskCmd.AddCommand(taskFindCmd)
 }
 
 func taskFindF(cmd *cobra.Command, args []string) error {
-	s := &http.TaskService{
-		Addr:  flags.host,
-		Token: flags.token,
+	if taskFindFlags.json && taskFindFlags.csv {
+		return fmt.Errorf("json and csv flags are mutually exclusive")
 	}
 
+	switch taskFindFlags.schedule {
+	case "", taskScheduleEvery, taskScheduleCron:
+	default:
+		return fmt.Errorf("schedule must be one of %s or %s", taskScheduleEvery, taskScheduleCron)
+	}
+
+	s := newTaskService(flags)
+
 	filter := platform.TaskFilter{}
 	if taskFindFlags.user != "" {
 		id, err := platform.IDFromString(taskFindFlags.user)
@@ -214,60 +263,225 @@ func taskFindF(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	w := internal.NewTabWriter(os.Stdout)
-	w.WriteHeaders(
-		"ID",
-		"Name",
-		"OrganizationID",
-		"Organization",
-		"AuthorizationID",
-		"Status",
-		"Every",
-		"Cron",
-	)
-	for _, t := range tasks {
-		w.Write(map[string]interface{}{
-			"ID":             t.ID.String(),
-			"Name":           t.Name,
-			"OrganizationID": t.OrganizationID.String(),
-			"Organization":   t.Organization,
-			"Status":         t.Status,
-			"Every":          t.Every,
-			"Cron":           t.Cron,
-		})
+	if taskFindFlags.overdue {
+		tasks, err = overdueTasks(tasks, time.Now().UTC(), taskFindFlags.overdueGrace)
+		if err != nil {
+			return err
+		}
+	}
+
+	if taskFindFlags.schedule != "" {
+		tasks = scheduledTasks(tasks, taskFindFlags.schedule)
 	}
-	w.Flush()
 
+	if taskFindFlags.csv {
+		return writeTasksCSV(os.Stdout, tasks)
+	}
+	return writeTasks(os.Stdout, tasks, taskFindFlags.json)
+}
+
+var taskFindHeaders = []string{
+	"ID",
+	"Name",
+	"OrganizationID",
+	"Organization",
+	"AuthorizationID",
+	"Status",
+	"Every",
+	"Cron",
+}
+
+func taskFindRow(t http.Task) map[string]interface{} {
+	return map[string]interface{}{
+		"ID":             t.ID.String(),
+		"Name":           t.Name,
+		"OrganizationID": t.OrganizationID.String(),
+		"Organization":   t.Organization,
+		"Status":         t.Status,
+		"Every":          t.Every,
+		"Cron":           t.Cron,
+	}
+}
+
+// writeTasks writes the tasks as a table, or as JSON when asJSON is set. The
+// JSON includes the fields the table omits, with the schedule of each task
+// filled from its flux.
+func writeTasks(w io.Writer, tasks []http.Task, asJSON bool) error {
+	if asJSON {
+		out := make([]http.Task, 0, len(tasks))
+		for _, t := range tasks {
+			out = append(out, taskWithFluxSchedule(t))
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "\t")
+		return enc.Encode(out)
+	}
+
+	tw := internal.NewTabWriter(w)
+	tw.WriteHeaders(taskFindHeaders...)
+	for _, t := range tasks {
+		tw.Write(taskFindRow(t))
+	}
+	tw.Flush()
 	return nil
 }
 
+// writeTasksCSV writes the tasks as CSV with the columns of the task table,
+// a header row followed by a row per task.
+func writeTasksCSV(w io.Writer, tasks []http.Task) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(taskFindHeaders); err != nil {
+		return err
+	}
+
+	for _, t := range tasks {
+		row := taskFindRow(t)
+		rec := make([]string, len(taskFindHeaders))
+		for i, h := range taskFindHeaders {
+			if v, ok := row[h]; ok {
+				rec[i] = fmt.Sprint(v)
+			}
+		}
+		if err := cw.Write(rec); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// taskWithFluxSchedule fills the every, cron and offset of the task that are
+// not set with the task options of its flux. The task is returned unchanged
+// when its flux does not parse.
+func taskWithFluxSchedule(t http.Task) http.Task {
+	hasSchedule := t.Every != "" || t.Cron != ""
+	if hasSchedule && t.Offset != "" {
+		return t
+	}
+
+	opts, err := options.FromScript(t.Flux)
+	if err != nil {
+		return t
+	}
+
+	if !hasSchedule {
+		if opts.Cron != "" {
+			t.Cron = opts.Cron
+		} else if !opts.Every.IsZero() {
+			t.Every = opts.Every.String()
+		}
+	}
+	if t.Offset == "" && opts.Offset != nil && !opts.Offset.IsZero() {
+		t.Offset = opts.Offset.String()
+	}
+	return t
+}
+
+const (
+	taskScheduleEvery = "every"
+	taskScheduleCron  = "cron"
+)
+
+// scheduledTasks returns the tasks whose effective options, including those of
+// their flux, schedule them by the schedule type, every or cron.
+func scheduledTasks(tasks []http.Task, schedule string) []http.Task {
+	var scheduled []http.Task
+	for _, t := range tasks {
+		effective := taskWithFluxSchedule(t)
+		switch {
+		case schedule == taskScheduleCron && effective.Cron != "",
+			schedule == taskScheduleEvery && effective.Cron == "" && effective.Every != "":
+			scheduled = append(scheduled, t)
+		}
+	}
+	return scheduled
+}
+
+// overdueTasks returns the active tasks whose next run was due more than the
+// grace period before now.
+func overdueTasks(tasks []http.Task, now time.Time, grace time.Duration) ([]http.Task, error) {
+	var overdue []http.Task
+	for _, t := range tasks {
+		if t.Status != platform.TaskStatusActive {
+			continue
+		}
+
+		nextDue, err := taskNextDue(t)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute next run of task %s: %v", t.ID, err)
+		}
+		if nextDue.Add(grace).Before(now) {
+			overdue = append(overdue, t)
+		}
+	}
+	return overdue, nil
+}
+
+// taskNextDue returns when the task's next run is due, the first scheduled
+// time after its latest completed run delayed by its offset.
+func taskNextDue(t http.Task) (time.Time, error) {
+	pt := platform.Task{
+		Every:           t.Every,
+		Cron:            t.Cron,
+		Offset:          t.Offset,
+		LatestCompleted: t.LatestCompleted,
+		CreatedAt:       t.CreatedAt,
+	}
+
+	sch, err := cron.Parse(pt.EffectiveCron())
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	latestCompleted, err := pt.LatestCompletedTime()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	offset, err := pt.OffsetDuration()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return sch.Next(latestCompleted).Add(offset), nil
+}
+
 // taskUpdateFlags define the Update Command
 type TaskUpdateFlags struct {
 	id     string
 	status string
+	every  string
+	cron   string
+	offset string
+	json   bool
 }
 
 var taskUpdateFlags TaskUpdateFlags
 
 func init() {
 	taskUpdateCmd := &cobra.Command{
-		Use:   "update",
+		Use:   "update [flux script or @/path/to/script.flux]",
 		Short: "Update task",
-		RunE:  wrapCheckSetup(taskUpdateF),
+		Long: `Update the status, schedule or flux script of a task.
+The schedule flags edit the options of the task's existing flux script,
+the remainder of the script is left as is.`,
+		RunE: wrapCheckSetup(taskUpdateF),
 	}
 
 	taskUpdateCmd.Flags().StringVarP(&taskUpdateFlags.id, "id", "i", "", "task ID (required)")
 	taskUpdateCmd.Flags().StringVarP(&taskUpdateFlags.status, "status", "", "", "update task status")
+	taskUpdateCmd.Flags().StringVarP(&taskUpdateFlags.every, "every", "", "", "update the task to run at the provided interval, replaces any cron schedule")
+	taskUpdateCmd.Flags().StringVarP(&taskUpdateFlags.cron, "cron", "", "", "update the task to run on the provided cron schedule, replaces any every interval")
+	taskUpdateCmd.Flags().StringVarP(&taskUpdateFlags.offset, "offset", "", "", "update the task offset, 0 removes the offset")
+	taskUpdateCmd.Flags().BoolVar(&taskUpdateFlags.json, "json", false, "output the updated task as JSON")
 	taskUpdateCmd.MarkFlagRequired("id")
 
 	taskCmd.AddCommand(taskUpdateCmd)
 }
 
 func taskUpdateF(cmd *cobra.Command, args []string) error {
-	s := &http.TaskService{
-		Addr:  flags.host,
-		Token: flags.token,
-	}
+	s := newTaskService(flags)
 
 	var id platform.ID
 	if err := id.DecodeFromString(taskUpdateFlags.id); err != nil {
@@ -287,39 +501,72 @@ func taskUpdateF(cmd *cobra.Command, args []string) error {
 		update.Flux = &flux
 	}
 
+	if taskUpdateFlags.hasSchedule() {
+		var currentFlux string
+		if update.Flux == nil {
+			t, err := s.FindTaskByID(context.Background(), id)
+			if err != nil {
+				return fmt.Errorf("failed to retrieve task %q: %v", id, err)
+			}
+			currentFlux = t.Flux
+		}
+
+		if err := updateTaskSchedule(&update, taskUpdateFlags, currentFlux); err != nil {
+			return err
+		}
+	}
+
 	t, err := s.UpdateTask(context.Background(), id, update)
 	if err != nil {
 		return err
 	}
 
-	w := internal.NewTabWriter(os.Stdout)
-	w.WriteHeaders(
-		"ID",
-		"Name",
-		"OrganizationID",
-		"Organization",
-		"AuthorizationID",
-		"Status",
-		"Every",
-		"Cron",
-	)
-	w.Write(map[string]interface{}{
-		"ID":             t.ID.String(),
-		"Name":           t.Name,
-		"OrganizationID": t.OrganizationID.String(),
-		"Organization":   t.Organization,
-		"Status":         t.Status,
-		"Every":          t.Every,
-		"Cron":           t.Cron,
-	})
-	w.Flush()
+	return writeTasks(os.Stdout, []http.Task{*t}, taskUpdateFlags.json)
+}
+
+func (f TaskUpdateFlags) hasSchedule() bool {
+	return f.every != "" || f.cron != "" || f.offset != ""
+}
+
+// updateTaskSchedule sets the schedule options provided by the flags on the
+// update and rewrites them into the flux script. The script of the update is
+// edited when provided, otherwise the current flux of the task is.
+func updateTaskSchedule(update *platform.TaskUpdate, f TaskUpdateFlags, currentFlux string) error {
+	if f.every != "" && f.cron != "" {
+		return fmt.Errorf("must specify only one of every or cron")
+	}
+
+	if f.every != "" {
+		if err := update.Options.Every.Parse(f.every); err != nil {
+			return fmt.Errorf("failed to parse every %q: %v", f.every, err)
+		}
+	}
 
+	update.Options.Cron = f.cron
+
+	switch f.offset {
+	case "":
+	case "0":
+		// a zero offset removes the offset from the task options
+		update.Options.Offset = &options.Duration{}
+	default:
+		offset := new(options.Duration)
+		if err := offset.Parse(f.offset); err != nil {
+			return fmt.Errorf("failed to parse offset %q: %v", f.offset, err)
+		}
+		update.Options.Offset = offset
+	}
+
+	if err := update.UpdateFlux(currentFlux); err != nil {
+		return fmt.Errorf("failed to update task options: %v", err)
+	}
 	return nil
 }
 
 // taskDeleteFlags define the Delete command
 type TaskDeleteFlags struct {
-	id string
+	id   string
+	json bool
 }
 
 var taskDeleteFlags TaskDeleteFlags
@@ -332,16 +579,14 @@ func init() {
 	}
 
 	taskDeleteCmd.Flags().StringVarP(&taskDeleteFlags.id, "id", "i", "", "task id (required)")
+	taskDeleteCmd.Flags().BoolVar(&taskDeleteFlags.json, "json", false, "output the deleted task as JSON")
 	taskDeleteCmd.MarkFlagRequired("id")
 
 	taskCmd.AddCommand(taskDeleteCmd)
 }
 
 func taskDeleteF(cmd *cobra.Command, args []string) error {
-	s := &http.TaskService{
-		Addr:  flags.host,
-		Token: flags.token,
-	}
+	s := newTaskService(flags)
 
 	var id platform.ID
 	err := id.DecodeFromString(taskDeleteFlags.id)
@@ -359,35 +604,15 @@ func taskDeleteF(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	w := internal.NewTabWriter(os.Stdout)
-	w.WriteHeaders(
-		"ID",
-		"Name",
-		"OrganizationID",
-		"Organization",
-		"AuthorizationID",
-		"Status",
-		"Every",
-		"Cron",
-	)
-	w.Write(map[string]interface{}{
-		"ID":             t.ID.String(),
-		"Name":           t.Name,
-		"OrganizationID": t.OrganizationID.String(),
-		"Organization":   t.Organization,
-		"Status":         t.Status,
-		"Every":          t.Every,
-		"Cron":           t.Cron,
-	})
-	w.Flush()
-
-	return nil
+	return writeTasks(os.Stdout, []http.Task{*t}, taskDeleteFlags.json)
 }
 
 // taskLogFindFlags define the Delete command
 type TaskLogFindFlags struct {
 	taskID string
 	runID  string
+	since  string
+	until  string
 }
 
 var taskLogFindFlags TaskLogFindFlags
@@ -401,17 +626,21 @@ func init() {
 
 	taskLogFindCmd.Flags().StringVarP(&taskLogFindFlags.taskID, "task-id", "", "", "task id (required)")
 	taskLogFindCmd.Flags().StringVarP(&taskLogFindFlags.runID, "run-id", "", "", "run id")
+	taskLogFindCmd.Flags().StringVar(&taskLogFindFlags.since, "since", "", "only list logs at or after the time, RFC3339 format")
+	taskLogFindCmd.Flags().StringVar(&taskLogFindFlags.until, "until", "", "only list logs at or before the time, RFC3339 format")
 	taskLogFindCmd.MarkFlagRequired("task-id")
 
 	logCmd.AddCommand(taskLogFindCmd)
 }
 
 func taskLogFindF(cmd *cobra.Command, args []string) error {
-	s := &http.TaskService{
-		Addr:  flags.host,
-		Token: flags.token,
+	since, until, err := parseLogWindow(taskLogFindFlags.since, taskLogFindFlags.until)
+	if err != nil {
+		return err
 	}
 
+	s := newTaskService(flags)
+
 	var filter platform.LogFilter
 	id, err := platform.IDFromString(taskLogFindFlags.taskID)
 	if err != nil {
@@ -433,6 +662,11 @@ func taskLogFindF(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	logs, err = filterLogsByWindow(logs, since, until)
+	if err != nil {
+		return err
+	}
+
 	w := internal.NewTabWriter(os.Stdout)
 	w.WriteHeaders(
 		"RunID",
@@ -451,6 +685,54 @@ func taskLogFindF(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// parseLogWindow parses the since and until times of the log window. Either
+// may be empty to leave that side of the window open.
+func parseLogWindow(since, until string) (time.Time, time.Time, error) {
+	var sinceTime, untilTime time.Time
+	if since != "" {
+		t, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid since time %q; must be RFC3339: %v", since, err)
+		}
+		sinceTime = t
+	}
+	if until != "" {
+		t, err := time.Parse(time.RFC3339, until)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid until time %q; must be RFC3339: %v", until, err)
+		}
+		untilTime = t
+	}
+	if !sinceTime.IsZero() && !untilTime.IsZero() && untilTime.Before(sinceTime) {
+		return time.Time{}, time.Time{}, fmt.Errorf("until time %s must not be before since time %s", until, since)
+	}
+	return sinceTime, untilTime, nil
+}
+
+// filterLogsByWindow returns the logs within the since and until times, inclusive.
+// The log filter has no time window, so the logs are filtered client side.
+func filterLogsByWindow(logs []*platform.Log, since, until time.Time) ([]*platform.Log, error) {
+	if since.IsZero() && until.IsZero() {
+		return logs, nil
+	}
+
+	var filtered []*platform.Log
+	for _, l := range logs {
+		t, err := time.Parse(time.RFC3339Nano, l.Time)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time %q for log of run %s: %v", l.Time, l.RunID, err)
+		}
+		if !since.IsZero() && t.Before(since) {
+			continue
+		}
+		if !until.IsZero() && t.After(until) {
+			continue
+		}
+		filtered = append(filtered, l)
+	}
+	return filtered, nil
+}
+
 // taskLogFindFlags define the Delete command
 type TaskRunFindFlags struct {
 	runID      string
@@ -458,10 +740,14 @@ type TaskRunFindFlags struct {
 	afterTime  string
 	beforeTime string
 	limit      int
+	status     string
 }
 
 var taskRunFindFlags TaskRunFindFlags
 
+// taskRunStatuses are the statuses runs may be filtered by.
+var taskRunStatuses = []string{"scheduled", "started", "success", "failed", "canceled"}
+
 func init() {
 	taskRunFindCmd := &cobra.Command{
 		Use:   "find",
@@ -474,6 +760,7 @@ func init() {
 	taskRunFindCmd.Flags().StringVarP(&taskRunFindFlags.afterTime, "after", "", "", "after time for filtering")
 	taskRunFindCmd.Flags().StringVarP(&taskRunFindFlags.beforeTime, "before", "", "", "before time for filtering")
 	taskRunFindCmd.Flags().IntVarP(&taskRunFindFlags.limit, "limit", "", 0, "limit the results")
+	taskRunFindCmd.Flags().StringVar(&taskRunFindFlags.status, "status", "", fmt.Sprintf("only list runs with the status, one of: %s; the client filters the runs and pages through them until --limit runs match", strings.Join(taskRunStatuses, ", ")))
 
 	taskRunFindCmd.MarkFlagRequired("task-id")
 
@@ -481,11 +768,12 @@ func init() {
 }
 
 func taskRunFindF(cmd *cobra.Command, args []string) error {
-	s := &http.TaskService{
-		Addr:  flags.host,
-		Token: flags.token,
+	if err := validateRunStatus(taskRunFindFlags.status); err != nil {
+		return err
 	}
 
+	s := newTaskService(flags)
+
 	filter := platform.RunFilter{
 		Limit:      taskRunFindFlags.limit,
 		AfterTime:  taskRunFindFlags.afterTime,
@@ -508,6 +796,12 @@ func taskRunFindF(cmd *cobra.Command, args []string) error {
 			return err
 		}
 		runs = append(runs, run)
+		runs = filterRunsByStatus(runs, taskRunFindFlags.status)
+	} else if taskRunFindFlags.status != "" {
+		runs, err = findRunsByStatus(context.Background(), s.FindRuns, filter, taskRunFindFlags.status)
+		if err != nil {
+			return err
+		}
 	} else {
 		runs, _, err = s.FindRuns(context.Background(), filter)
 		if err != nil {
@@ -547,6 +841,78 @@ func taskRunFindF(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func validateRunStatus(status string) error {
+	if status == "" {
+		return nil
+	}
+	for _, s := range taskRunStatuses {
+		if s == status {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid run status %q; must be one of: %s", status, strings.Join(taskRunStatuses, ", "))
+}
+
+// findRunsByStatus returns up to the limit of the filter runs with the status.
+// The server does not filter runs by status, so pages of the most runs the
+// server returns are filtered until the limit is reached, or a page has no
+// runs that were not seen before.
+func findRunsByStatus(ctx context.Context, findRuns func(context.Context, platform.RunFilter) ([]*platform.Run, int, error), filter platform.RunFilter, status string) ([]*platform.Run, error) {
+	limit := filter.Limit
+	if limit == 0 {
+		limit = platform.TaskDefaultPageSize
+	}
+	filter.Limit = platform.TaskMaxPageSize
+
+	var filtered []*platform.Run
+	seen := make(map[platform.ID]bool)
+	for {
+		page, _, err := findRuns(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+
+		var unseen []*platform.Run
+		for _, r := range page {
+			if seen[r.ID] {
+				continue
+			}
+			seen[r.ID] = true
+			unseen = append(unseen, r)
+		}
+		if len(unseen) == 0 {
+			return filtered, nil
+		}
+
+		filtered = append(filtered, filterRunsByStatus(unseen, status)...)
+		if len(filtered) >= limit {
+			return filtered[:limit], nil
+		}
+		if len(page) < filter.Limit {
+			return filtered, nil
+		}
+
+		after := page[len(page)-1].ID
+		filter.After = &after
+	}
+}
+
+// filterRunsByStatus returns the runs with the status. The runs are filtered
+// client side, after the time window is applied by the server.
+func filterRunsByStatus(runs []*platform.Run, status string) []*platform.Run {
+	if status == "" {
+		return runs
+	}
+
+	var filtered []*platform.Run
+	for _, r := range runs {
+		if r.Status == status {
+			filtered = append(filtered, r)
+		}
+	}
+	return filtered
+}
+
 type RunRetryFlags struct {
 	taskID, runID string
 }
@@ -569,10 +935,7 @@ func init() {
 }
 
 func runRetryF(cmd *cobra.Command, args []string) error {
-	s := &http.TaskService{
-		Addr:  flags.host,
-		Token: flags.token,
-	}
+	s := newTaskService(flags)
 
 	var taskID, runID platform.ID
 	if err := taskID.DecodeFromString(runRetryFlags.taskID); err != nil {
@@ -592,3 +955,63 @@ func runRetryF(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+type RunCancelFlags struct {
+	taskID, runID string
+}
+
+var runCancelFlags RunCancelFlags
+
+func init() {
+	cmd := &cobra.Command{
+		Use:   "cancel",
+		Short: "cancel a run in progress",
+		RunE:  wrapCheckSetup(runCancelF),
+	}
+
+	cmd.Flags().StringVarP(&runCancelFlags.taskID, "task-id", "i", "", "task id (required)")
+	cmd.Flags().StringVarP(&runCancelFlags.runID, "run-id", "r", "", "run id (required)")
+	cmd.MarkFlagRequired("task-id")
+	cmd.MarkFlagRequired("run-id")
+
+	runCmd.AddCommand(cmd)
+}
+
+func runCancelF(cmd *cobra.Command, args []string) error {
+	s := newTaskService(flags)
+
+	var taskID, runID platform.ID
+	if err := taskID.DecodeFromString(runCancelFlags.taskID); err != nil {
+		return err
+	}
+	if err := runID.DecodeFromString(runCancelFlags.runID); err != nil {
+		return err
+	}
+
+	ctx := context.TODO()
+	run, err := s.FindRunByID(ctx, taskID, runID)
+	if err != nil {
+		return err
+	}
+	if isRunFinished(run.Status) {
+		return fmt.Errorf("task %s's run %s already finished with status %s", taskID, runID, run.Status)
+	}
+
+	if err := s.CancelRun(ctx, taskID, runID); err != nil {
+		return err
+	}
+
+	fmt.Printf("Task %s's run %s canceled.\n", taskID, runID)
+
+	return nil
+}
+
+// isRunFinished reports whether a run with the status has completed, it can
+// no longer be canceled.
+func isRunFinished(status string) bool {
+	switch status {
+	case "success", "failed", "canceled":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,485 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/http"
+	_ "github.com/influxdata/influxdb/query/builtin"
+	"github.com/influxdata/influxdb/task/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskUpdateSchedule(t *testing.T) {
+	const (
+		everyFlux = `option task = {every: 20s, name: "foo"} from(bucket:"x") |> range(start:-1h)`
+		cronFlux  = `option task = {cron: "* * * * *", name: "foo", offset: 10s} from(bucket:"x") |> range(start:-1h)`
+	)
+
+	tests := []struct {
+		name     string
+		flags    TaskUpdateFlags
+		flux     string
+		expected string
+	}{
+		{
+			name:  "every to cron",
+			flags: TaskUpdateFlags{cron: "0 * * * *"},
+			flux:  everyFlux,
+			expected: `option task = {cron: "0 * * * *", name: "foo"}
+
+from(bucket: "x")
+	|> range(start: -1h)`,
+		},
+		{
+			name:  "cron to every",
+			flags: TaskUpdateFlags{every: "1m"},
+			flux:  cronFlux,
+			expected: `option task = {every: 1m, name: "foo", offset: 10s}
+
+from(bucket: "x")
+	|> range(start: -1h)`,
+		},
+		{
+			name:  "set offset",
+			flags: TaskUpdateFlags{offset: "30s"},
+			flux:  everyFlux,
+			expected: `option task = {every: 20s, name: "foo", offset: 30s}
+
+from(bucket: "x")
+	|> range(start: -1h)`,
+		},
+		{
+			name:  "remove offset",
+			flags: TaskUpdateFlags{offset: "0"},
+			flux:  cronFlux,
+			expected: `option task = {cron: "* * * * *", name: "foo"}
+
+from(bucket: "x")
+	|> range(start: -1h)`,
+		},
+	}
+
+	for _, tt := range tests {
+		fn := func(t *testing.T) {
+			var update platform.TaskUpdate
+			require.NoError(t, updateTaskSchedule(&update, tt.flags, tt.flux))
+
+			require.NotNil(t, update.Flux)
+			assert.Equal(t, tt.expected, *update.Flux)
+		}
+		t.Run(tt.name, fn)
+	}
+
+	t.Run("edits the provided flux over the current flux", func(t *testing.T) {
+		flux := `option task = {every: 1h, name: "bar"} from(bucket:"y") |> range(start:-1h)`
+		update := platform.TaskUpdate{Flux: &flux}
+		require.NoError(t, updateTaskSchedule(&update, TaskUpdateFlags{every: "2h"}, everyFlux))
+
+		expected := `option task = {every: 2h, name: "bar"}
+
+from(bucket: "y")
+	|> range(start: -1h)`
+		require.NotNil(t, update.Flux)
+		assert.Equal(t, expected, *update.Flux)
+	})
+
+	t.Run("rejects every and cron together", func(t *testing.T) {
+		var update platform.TaskUpdate
+		err := updateTaskSchedule(&update, TaskUpdateFlags{every: "1m", cron: "* * * * *"}, everyFlux)
+		require.Error(t, err)
+	})
+
+	t.Run("rejects invalid every", func(t *testing.T) {
+		var update platform.TaskUpdate
+		err := updateTaskSchedule(&update, TaskUpdateFlags{every: "not a duration"}, everyFlux)
+		require.Error(t, err)
+	})
+}
+
+func TestTaskCreateSchedule(t *testing.T) {
+	const flux = `option task = {every: 20s, name: "foo"} from(bucket:"x") |> range(start:-1h)`
+
+	tests := []struct {
+		name      string
+		flags     TaskCreateFlags
+		wantEvery string
+		wantCron  string
+		wantOff   string
+	}{
+		{
+			name:      "no schedule flags",
+			wantEvery: "20s",
+		},
+		{
+			name:      "override every",
+			flags:     TaskCreateFlags{every: "1h"},
+			wantEvery: "1h",
+		},
+		{
+			name:     "cron replaces every",
+			flags:    TaskCreateFlags{cron: "0 * * * *"},
+			wantCron: "0 * * * *",
+		},
+		{
+			name:      "inject offset",
+			flags:     TaskCreateFlags{offset: "5m"},
+			wantEvery: "20s",
+			wantOff:   "5m",
+		},
+	}
+
+	for _, tt := range tests {
+		fn := func(t *testing.T) {
+			got, err := createTaskSchedule(flux, tt.flags)
+			require.NoError(t, err)
+
+			opts, err := options.FromScript(got)
+			require.NoError(t, err)
+
+			assert.Equal(t, "foo", opts.Name)
+			assert.Equal(t, tt.wantCron, opts.Cron)
+			if tt.wantEvery != "" {
+				assert.Equal(t, tt.wantEvery, opts.Every.String())
+			} else {
+				assert.True(t, opts.Every.IsZero())
+			}
+			if tt.wantOff != "" {
+				require.NotNil(t, opts.Offset)
+				assert.Equal(t, tt.wantOff, opts.Offset.String())
+			} else {
+				assert.Nil(t, opts.Offset)
+			}
+		}
+		t.Run(tt.name, fn)
+	}
+
+	t.Run("rejects every and cron together", func(t *testing.T) {
+		_, err := createTaskSchedule(flux, TaskCreateFlags{every: "1m", cron: "* * * * *"})
+		require.Error(t, err)
+	})
+}
+
+func TestTaskRunFilterByStatus(t *testing.T) {
+	runs := []*platform.Run{
+		{ID: 1, Status: "success"},
+		{ID: 2, Status: "failed"},
+		{ID: 3, Status: "canceled"},
+		{ID: 4, Status: "failed"},
+		{ID: 5, Status: "scheduled"},
+	}
+
+	t.Run("filters to failed runs", func(t *testing.T) {
+		require.NoError(t, validateRunStatus("failed"))
+
+		filtered := filterRunsByStatus(runs, "failed")
+		require.Len(t, filtered, 2)
+		assert.Equal(t, platform.ID(2), filtered[0].ID)
+		assert.Equal(t, platform.ID(4), filtered[1].ID)
+	})
+
+	t.Run("no status returns all runs", func(t *testing.T) {
+		assert.Equal(t, runs, filterRunsByStatus(runs, ""))
+	})
+
+	t.Run("rejects unknown statuses", func(t *testing.T) {
+		assert.Error(t, validateRunStatus("exploded"))
+	})
+
+	t.Run("pages until the limit is reached after filtering", func(t *testing.T) {
+		var filters []platform.RunFilter
+		findRuns := func(_ context.Context, f platform.RunFilter) ([]*platform.Run, int, error) {
+			filters = append(filters, f)
+
+			start := platform.ID(1)
+			if f.After != nil {
+				start = *f.After + 1
+			}
+			var page []*platform.Run
+			for id := start; id < start+platform.ID(f.Limit); id++ {
+				status := "success"
+				if id%200 == 0 {
+					status = "failed"
+				}
+				page = append(page, &platform.Run{ID: id, Status: status})
+			}
+			return page, len(page), nil
+		}
+
+		filter := platform.RunFilter{Task: platform.ID(1), Limit: 3}
+		filtered, err := findRunsByStatus(context.Background(), findRuns, filter, "failed")
+		require.NoError(t, err)
+
+		require.Len(t, filtered, 3)
+		assert.Equal(t, platform.ID(200), filtered[0].ID)
+		assert.Equal(t, platform.ID(400), filtered[1].ID)
+		assert.Equal(t, platform.ID(600), filtered[2].ID)
+
+		require.Len(t, filters, 2)
+		assert.Equal(t, platform.TaskMaxPageSize, filters[0].Limit)
+		require.NotNil(t, filters[1].After)
+		assert.Equal(t, platform.ID(500), *filters[1].After)
+	})
+
+	t.Run("stops paging when no new runs are returned", func(t *testing.T) {
+		var calls int
+		findRuns := func(_ context.Context, f platform.RunFilter) ([]*platform.Run, int, error) {
+			calls++
+			page := make([]*platform.Run, platform.TaskMaxPageSize)
+			for i := range page {
+				page[i] = &platform.Run{ID: platform.ID(i + 1), Status: "success"}
+			}
+			page[0].Status = "failed"
+			return page, len(page), nil
+		}
+
+		filtered, err := findRunsByStatus(context.Background(), findRuns, platform.RunFilter{Task: platform.ID(1)}, "failed")
+		require.NoError(t, err)
+
+		require.Len(t, filtered, 1)
+		assert.Equal(t, 2, calls)
+	})
+}
+
+func TestTaskLogWindow(t *testing.T) {
+	logs := []*platform.Log{
+		{RunID: 1, Time: "2019-12-01T10:00:00Z", Message: "started"},
+		{RunID: 1, Time: "2019-12-01T11:00:00.5Z", Message: "querying"},
+		{RunID: 2, Time: "2019-12-01T12:00:00Z", Message: "failed"},
+		{RunID: 2, Time: "2019-12-01T13:00:00Z", Message: "retried"},
+	}
+
+	t.Run("filters logs to the window", func(t *testing.T) {
+		since, until, err := parseLogWindow("2019-12-01T11:00:00Z", "2019-12-01T12:00:00Z")
+		require.NoError(t, err)
+
+		filtered, err := filterLogsByWindow(logs, since, until)
+		require.NoError(t, err)
+
+		require.Len(t, filtered, 2)
+		assert.Equal(t, "querying", filtered[0].Message)
+		assert.Equal(t, "failed", filtered[1].Message)
+	})
+
+	t.Run("open ended window", func(t *testing.T) {
+		since, until, err := parseLogWindow("2019-12-01T12:00:00Z", "")
+		require.NoError(t, err)
+
+		filtered, err := filterLogsByWindow(logs, since, until)
+		require.NoError(t, err)
+
+		require.Len(t, filtered, 2)
+		assert.Equal(t, "failed", filtered[0].Message)
+		assert.Equal(t, "retried", filtered[1].Message)
+	})
+
+	t.Run("no window returns all logs", func(t *testing.T) {
+		filtered, err := filterLogsByWindow(logs, time.Time{}, time.Time{})
+		require.NoError(t, err)
+		assert.Equal(t, logs, filtered)
+	})
+
+	t.Run("rejects invalid windows", func(t *testing.T) {
+		_, _, err := parseLogWindow("yesterday", "")
+		assert.Error(t, err)
+
+		_, _, err = parseLogWindow("2019-12-01T12:00:00Z", "2019-12-01T11:00:00Z")
+		assert.Error(t, err)
+	})
+}
+
+func TestTaskOverdue(t *testing.T) {
+	now := time.Date(2019, 12, 1, 12, 0, 0, 0, time.UTC)
+
+	tasks := []http.Task{
+		{
+			ID:              1,
+			Status:          platform.TaskStatusActive,
+			Every:           "10m",
+			LatestCompleted: now.Add(-time.Hour).Format(time.RFC3339),
+		},
+		{
+			ID:              2,
+			Status:          platform.TaskStatusActive,
+			Every:           "10m",
+			LatestCompleted: now.Add(-5 * time.Minute).Format(time.RFC3339),
+		},
+		{
+			ID:              3,
+			Status:          platform.TaskStatusInactive,
+			Every:           "10m",
+			LatestCompleted: now.Add(-time.Hour).Format(time.RFC3339),
+		},
+		{
+			ID:              4,
+			Status:          platform.TaskStatusActive,
+			Cron:            "*/30 * * * *",
+			LatestCompleted: now.Add(-40 * time.Minute).Format(time.RFC3339),
+		},
+	}
+
+	t.Run("identifies tasks with a stale latest completed", func(t *testing.T) {
+		overdue, err := overdueTasks(tasks, now, time.Minute)
+		require.NoError(t, err)
+
+		require.Len(t, overdue, 2)
+		assert.Equal(t, platform.ID(1), overdue[0].ID)
+		assert.Equal(t, platform.ID(4), overdue[1].ID)
+	})
+
+	t.Run("grace period excludes recently due tasks", func(t *testing.T) {
+		overdue, err := overdueTasks(tasks, now, 45*time.Minute)
+		require.NoError(t, err)
+
+		require.Len(t, overdue, 1)
+		assert.Equal(t, platform.ID(1), overdue[0].ID)
+	})
+}
+
+func TestTaskFindSchedule(t *testing.T) {
+	tasks := []http.Task{
+		{ID: 1, Every: "10m"},
+		{ID: 2, Cron: "*/30 * * * *"},
+		// schedule only provided by the flux
+		{ID: 3, Flux: `option task = {name: "foo", every: 1h} from(bucket:"x") |> range(start:-1h)`},
+		{ID: 4, Flux: `option task = {name: "foo", cron: "0 * * * *"} from(bucket:"x") |> range(start:-1h)`},
+	}
+
+	t.Run("every", func(t *testing.T) {
+		got := scheduledTasks(tasks, taskScheduleEvery)
+		require.Len(t, got, 2)
+		assert.Equal(t, platform.ID(1), got[0].ID)
+		assert.Equal(t, platform.ID(3), got[1].ID)
+	})
+
+	t.Run("cron", func(t *testing.T) {
+		got := scheduledTasks(tasks, taskScheduleCron)
+		require.Len(t, got, 2)
+		assert.Equal(t, platform.ID(2), got[0].ID)
+		assert.Equal(t, platform.ID(4), got[1].ID)
+	})
+}
+
+func TestTaskFindCSV(t *testing.T) {
+	tasks := []http.Task{
+		{
+			ID:             platform.ID(1),
+			OrganizationID: platform.ID(2),
+			Organization:   "org_1",
+			Name:           "task, the first",
+			Status:         string(platform.TaskStatusActive),
+			Every:          "1h",
+		},
+	}
+
+	var buf strings.Builder
+	require.NoError(t, writeTasksCSV(&buf, tasks))
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	require.Len(t, lines, 2)
+	assert.Equal(t, "ID,Name,OrganizationID,Organization,AuthorizationID,Status,Every,Cron", lines[0])
+	assert.Equal(t, `0000000000000001,"task, the first",0000000000000002,org_1,,active,1h,`, lines[1])
+}
+
+func TestTaskFindJSONSchedule(t *testing.T) {
+	tests := []struct {
+		name     string
+		task     http.Task
+		expected map[string]string
+	}{
+		{
+			name: "derives every and offset from the flux",
+			task: http.Task{
+				Flux: `option task = {name: "t1", every: 1h, offset: 10m} from(bucket: "b") |> range(start: -1h)`,
+			},
+			expected: map[string]string{"every": "1h", "offset": "10m"},
+		},
+		{
+			name: "derives cron from the flux",
+			task: http.Task{
+				Flux: `option task = {name: "t1", cron: "0 * * * *"} from(bucket: "b") |> range(start: -1h)`,
+			},
+			expected: map[string]string{"cron": "0 * * * *"},
+		},
+		{
+			name: "keeps the top level fields",
+			task: http.Task{
+				Every: "5m",
+				Flux:  `option task = {name: "t1", every: 1h, offset: 10m} from(bucket: "b") |> range(start: -1h)`,
+			},
+			expected: map[string]string{"every": "5m", "offset": "10m"},
+		},
+		{
+			name:     "leaves the task as is when the flux does not parse",
+			task:     http.Task{Flux: `option task = {`},
+			expected: map[string]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := json.Marshal(taskWithFluxSchedule(tt.task))
+			require.NoError(t, err)
+
+			var got map[string]interface{}
+			require.NoError(t, json.Unmarshal(b, &got))
+
+			for _, field := range []string{"every", "cron", "offset"} {
+				want, ok := tt.expected[field]
+				if !ok {
+					assert.NotContains(t, got, field)
+					continue
+				}
+				assert.Equal(t, want, got[field])
+			}
+		})
+	}
+}
+
+func TestTaskWriteTasks(t *testing.T) {
+	tasks := []http.Task{
+		{
+			ID:              1,
+			Name:            "t1",
+			Status:          platform.TaskStatusActive,
+			Flux:            `option task = {name: "t1", every: 1h, offset: 10m} from(bucket: "b") |> range(start: -1h)`,
+			LatestCompleted: "2019-12-01T12:00:00Z",
+		},
+	}
+
+	t.Run("table", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, writeTasks(&buf, tasks, false))
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		require.Len(t, lines, 2)
+		assert.Equal(t, taskFindHeaders, strings.Fields(lines[0]))
+		assert.Contains(t, lines[1], "t1")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, writeTasks(&buf, tasks, true))
+
+		var got []map[string]interface{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+		require.Len(t, got, 1)
+
+		assert.Equal(t, "0000000000000001", got[0]["id"])
+		assert.Equal(t, "1h", got[0]["every"])
+		assert.Equal(t, "10m", got[0]["offset"])
+		assert.Equal(t, "2019-12-01T12:00:00Z", got[0]["latestCompleted"])
+	})
+
+	t.Run("json without tasks is an empty list", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, writeTasks(&buf, nil, true))
+		assert.Equal(t, "[]\n", buf.String())
+	})
+}
@@ -45,8 +45,9 @@ func newUserService(f Flags) (platform.UserService, error) {
 		return newLocalKVService()
 	}
 	return &http.UserService{
-		Addr:  flags.host,
-		Token: flags.token,
+		Addr:               flags.host,
+		Token:              flags.token,
+		InsecureSkipVerify: flags.skipVerify,
 	}, nil
 }
 
@@ -55,8 +56,9 @@ func newUserResourceMappingService(f Flags) (platform.UserResourceMappingService
 		return newLocalKVService()
 	}
 	return &http.UserResourceMappingService{
-		Addr:  flags.host,
-		Token: flags.token,
+		Addr:               flags.host,
+		Token:              flags.token,
+		InsecureSkipVerify: flags.skipVerify,
 	}, nil
 }
 
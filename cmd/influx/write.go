@@ -1,11 +1,16 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
 	"strings"
+	"sync"
+	"time"
 
 	platform "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/http"
@@ -26,11 +31,16 @@ or add an entire file specified with an @ prefix.`,
 }
 
 var writeFlags struct {
-	OrgID     string
-	Org       string
-	BucketID  string
-	Bucket    string
-	Precision string
+	OrgID         string
+	Org           string
+	BucketID      string
+	Bucket        string
+	Precision     string
+	BatchSize     int
+	FlushInterval time.Duration
+	MaxRetries    int
+	BucketMap     string
+	DeadLetter    string
 }
 
 func init() {
@@ -63,6 +73,12 @@ func init() {
 	if p := viper.GetString("PRECISION"); p != "" {
 		writeFlags.Precision = p
 	}
+
+	writeCmd.PersistentFlags().IntVar(&writeFlags.BatchSize, "batch-size", write.DefaultMaxBytes, "The maximum number of bytes to buffer before writing a batch")
+	writeCmd.PersistentFlags().DurationVar(&writeFlags.FlushInterval, "flush-interval", write.DefaultInterval, "The maximum amount of time to buffer lines before writing a batch")
+	writeCmd.PersistentFlags().IntVar(&writeFlags.MaxRetries, "max-retries", 3, "The number of times a failed batch is retried before the write fails")
+	writeCmd.PersistentFlags().StringVar(&writeFlags.DeadLetter, "dead-letter", "", "Path to a file the lines rejected as invalid are written to, along with their errors, so the rest of the lines are still written")
+	writeCmd.PersistentFlags().StringVar(&writeFlags.BucketMap, "bucket-map", "", "Path to a JSON file mapping measurements to bucket names, lines are written to the bucket of their measurement and unmapped measurements to the provided bucket")
 }
 
 func fluxWriteF(cmd *cobra.Command, args []string) error {
@@ -83,12 +99,40 @@ func fluxWriteF(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("invalid precision")
 	}
 
+	if writeFlags.BatchSize <= 0 {
+		cmd.Usage()
+		return fmt.Errorf("batch-size must be greater than 0")
+	}
+
+	if writeFlags.FlushInterval <= 0 {
+		cmd.Usage()
+		return fmt.Errorf("flush-interval must be greater than 0")
+	}
+
+	if writeFlags.MaxRetries < 0 {
+		cmd.Usage()
+		return fmt.Errorf("max-retries must not be negative")
+	}
+
 	bs := &http.BucketService{
-		Addr:  flags.host,
-		Token: flags.token,
+		Addr:               flags.host,
+		Token:              flags.token,
+		InsecureSkipVerify: flags.skipVerify,
+	}
+
+	dl, err := newDeadLetterWriter(writeFlags.DeadLetter)
+	if err != nil {
+		return err
+	}
+	defer dl.Close()
+
+	if writeFlags.BucketMap != "" {
+		if err := writeBucketMap(ctx, bs, dl, args[0]); err != nil {
+			return err
+		}
+		return dl.check()
 	}
 
-	var err error
 	filter := platform.BucketFilter{}
 
 	if writeFlags.BucketID != "" {
@@ -128,32 +172,212 @@ func fluxWriteF(cmd *cobra.Command, args []string) error {
 
 	bucketID, orgID := buckets[0].ID, buckets[0].OrgID
 
-	var r io.Reader
-	if args[0] == "-" {
-		r = os.Stdin
-	} else if len(args[0]) > 0 && args[0][0] == '@' {
-		f, err := os.Open(args[0][1:])
+	r, err := openWriteInput(args[0])
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	s := newWriteBatcher(dl)
+
+	ctx = signals.WithStandardSignals(ctx)
+	if err := s.Write(ctx, orgID, bucketID, r); err != nil && err != context.Canceled {
+		return fmt.Errorf("failed to write data: %v", err)
+	}
+
+	return dl.check()
+}
+
+// openWriteInput opens the line protocol to write, read from stdin, a file
+// prefixed with an @ sign or the literal argument.
+func openWriteInput(arg string) (io.ReadCloser, error) {
+	if arg == "-" {
+		return ioutil.NopCloser(os.Stdin), nil
+	}
+	if len(arg) > 0 && arg[0] == '@' {
+		f, err := os.Open(arg[1:])
 		if err != nil {
-			return fmt.Errorf("failed to open %q: %v", args[0][1:], err)
+			return nil, fmt.Errorf("failed to open %q: %v", arg[1:], err)
 		}
-		defer f.Close()
-		r = f
-	} else {
-		r = strings.NewReader(args[0])
+		return f, nil
 	}
+	return ioutil.NopCloser(strings.NewReader(arg)), nil
+}
 
-	s := write.Batcher{
+func newWriteBatcher(dl *deadLetterWriter) *write.Batcher {
+	b := &write.Batcher{
+		MaxFlushBytes:    writeFlags.BatchSize,
+		MaxFlushInterval: writeFlags.FlushInterval,
+		MaxRetries:       writeFlags.MaxRetries,
 		Service: &http.WriteService{
-			Addr:      flags.host,
-			Token:     flags.token,
-			Precision: writeFlags.Precision,
+			Addr:               flags.host,
+			Token:              flags.token,
+			Precision:          writeFlags.Precision,
+			InsecureSkipVerify: flags.skipVerify,
+			Partial:            dl != nil,
 		},
 	}
+	if dl != nil {
+		b.DeadLetter = dl.deadLetter
+	}
+	return b
+}
+
+// deadLetterWriter writes the lines rejected by the write service to a file,
+// each preceded by a comment holding the error it was rejected with. The file
+// remains line protocol, it can be written once its lines are fixed.
+type deadLetterWriter struct {
+	path string
+	f    *os.File
+
+	mu    sync.Mutex
+	count int
+}
+
+// newDeadLetterWriter creates the dead letter file at path. No file is
+// created and nil is returned when the path is empty.
+func newDeadLetterWriter(path string) (*deadLetterWriter, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dead letter file %q: %v", path, err)
+	}
+	return &deadLetterWriter{path: path, f: f}, nil
+}
+
+func (d *deadLetterWriter) deadLetter(line []byte, lineErr error) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	msg := strings.Replace(lineErr.Error(), "\n", " ", -1)
+	if _, err := fmt.Fprintf(d.f, "# error: %s\n", msg); err != nil {
+		return fmt.Errorf("failed to write dead letter: %v", err)
+	}
+	if _, err := d.f.Write(line); err != nil {
+		return fmt.Errorf("failed to write dead letter: %v", err)
+	}
+	if !bytes.HasSuffix(line, []byte("\n")) {
+		if _, err := d.f.Write([]byte("\n")); err != nil {
+			return fmt.Errorf("failed to write dead letter: %v", err)
+		}
+	}
+	d.count++
+	return nil
+}
+
+// check returns an error when any lines were dead lettered, the write did not
+// write every line.
+func (d *deadLetterWriter) check() error {
+	if d == nil || d.count == 0 {
+		return nil
+	}
+	return fmt.Errorf("%d lines failed to write, they were written to %s", d.count, d.path)
+}
+
+// Close closes the dead letter file.
+func (d *deadLetterWriter) Close() error {
+	if d == nil {
+		return nil
+	}
+	return d.f.Close()
+}
+
+// writeBucketMap writes each line to the bucket mapped to its measurement.
+// Lines of unmapped measurements are written to the bucket provided by the
+// bucket flags, when no bucket is provided they fail the write.
+func writeBucketMap(ctx context.Context, bs platform.BucketService, dl *deadLetterWriter, arg string) error {
+	if writeFlags.Org == "" && writeFlags.OrgID == "" {
+		return fmt.Errorf("please specify one of org or org-id when writing with a bucket map")
+	}
+
+	bucketMap, err := readBucketMap(writeFlags.BucketMap)
+	if err != nil {
+		return err
+	}
+
+	var orgID platform.ID
+	findBucket := func(filter platform.BucketFilter) (*platform.Bucket, error) {
+		if writeFlags.OrgID != "" {
+			id, err := platform.IDFromString(writeFlags.OrgID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode org-id id: %v", err)
+			}
+			filter.OrganizationID = id
+		}
+		if writeFlags.Org != "" {
+			filter.Org = &writeFlags.Org
+		}
+
+		b, err := bs.FindBucket(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		orgID = b.OrgID
+		return b, nil
+	}
+
+	rt := &write.Router{
+		Buckets: make(map[string]platform.ID, len(bucketMap)),
+		Service: newWriteBatcher(dl),
+	}
+	for m, name := range bucketMap {
+		name := name
+		b, err := findBucket(platform.BucketFilter{Name: &name})
+		if err != nil {
+			return fmt.Errorf("failed to find bucket %q for measurement %q: %v", name, m, err)
+		}
+		rt.Buckets[m] = b.ID
+	}
+
+	if writeFlags.BucketID != "" {
+		id, err := platform.IDFromString(writeFlags.BucketID)
+		if err != nil {
+			return fmt.Errorf("failed to decode bucket-id: %v", err)
+		}
+		b, err := findBucket(platform.BucketFilter{ID: id})
+		if err != nil {
+			return fmt.Errorf("bucket with id %q does not exist", writeFlags.BucketID)
+		}
+		rt.DefaultBucket = b.ID
+	}
+	if writeFlags.Bucket != "" {
+		b, err := findBucket(platform.BucketFilter{Name: &writeFlags.Bucket})
+		if err != nil {
+			return fmt.Errorf("bucket %q was not found", writeFlags.Bucket)
+		}
+		rt.DefaultBucket = b.ID
+	}
+
+	r, err := openWriteInput(arg)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
 
 	ctx = signals.WithStandardSignals(ctx)
-	if err := s.Write(ctx, orgID, bucketID, r); err != nil && err != context.Canceled {
+	if err := rt.Write(ctx, orgID, r); err != nil && err != context.Canceled {
 		return fmt.Errorf("failed to write data: %v", err)
 	}
 
 	return nil
 }
+
+// readBucketMap reads the JSON object mapping measurements to bucket names.
+func readBucketMap(path string) (map[string]string, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bucket map %q: %v", path, err)
+	}
+
+	var bucketMap map[string]string
+	if err := json.Unmarshal(b, &bucketMap); err != nil {
+		return nil, fmt.Errorf("failed to decode bucket map %q: %v", path, err)
+	}
+	if len(bucketMap) == 0 {
+		return nil, fmt.Errorf("bucket map %q must map at least one measurement", path)
+	}
+	return bucketMap, nil
+}
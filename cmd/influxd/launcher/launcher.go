@@ -196,6 +196,29 @@ func buildLauncherCommand(l *Launcher, cmd *cobra.Command) {
 			Default: false,
 			Desc:    "disables automatically extending session ttl on request",
 		},
+		{
+			DestP:   &l.maxBucketRetention,
+			Flag:    "bucket-max-retention",
+			Default: time.Duration(0),
+			Desc:    "maximum retention period allowed for buckets created or updated over the API, 0 means no maximum",
+		},
+		{
+			DestP:   &l.authCacheSize,
+			Flag:    "auth-cache-size",
+			Default: 0,
+			Desc:    "number of authorizations found by token to cache in memory, 0 disables the cache",
+		},
+		{
+			DestP:   &l.authCacheTTL,
+			Flag:    "auth-cache-ttl",
+			Default: time.Minute,
+			Desc:    "how long an authorization found by token is cached before it is looked up again",
+		},
+		{
+			DestP: &l.authTokenHeader,
+			Flag:  "auth-token-header",
+			Desc:  "header, i.e. X-Influx-Token, read for the token of requests with neither an Authorization header nor a session cookie",
+		},
 		{
 			DestP: &vaultConfig.Address,
 			Flag:  "vault-addr",
@@ -282,6 +305,10 @@ type Launcher struct {
 	testing              bool
 	sessionLength        int // in minutes
 	sessionRenewDisabled bool
+	maxBucketRetention   time.Duration
+	authCacheSize        int
+	authCacheTTL         time.Duration
+	authTokenHeader      string
 
 	logLevel          string
 	tracingType       string
@@ -793,15 +820,19 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 	}
 
 	m.apibackend = &http.APIBackend{
-		AssetsPath:           m.assetsPath,
-		HTTPErrorHandler:     http.ErrorHandler(0),
-		Logger:               m.logger,
-		SessionRenewDisabled: m.sessionRenewDisabled,
-		NewBucketService:     source.NewBucketService,
-		NewQueryService:      source.NewQueryService,
-		PointsWriter:         pointsWriter,
-		DeleteService:        deleteService,
-		AuthorizationService: authSvc,
+		AssetsPath:             m.assetsPath,
+		HTTPErrorHandler:       http.ErrorHandler(0),
+		Logger:                 m.logger,
+		SessionRenewDisabled:   m.sessionRenewDisabled,
+		MaxBucketRetention:     m.maxBucketRetention,
+		AuthorizationCacheSize: m.authCacheSize,
+		AuthorizationCacheTTL:  m.authCacheTTL,
+		TokenHeader:            m.authTokenHeader,
+		NewBucketService:       source.NewBucketService,
+		NewQueryService:        source.NewQueryService,
+		PointsWriter:           pointsWriter,
+		DeleteService:          deleteService,
+		AuthorizationService:   authSvc,
 		// Wrap the BucketService in a storage backed one that will ensure deleted buckets are removed from the storage engine.
 		BucketService:                   storage.NewBucketService(bucketSvc, m.engine),
 		SessionService:                  sessionSvc,
@@ -843,8 +874,13 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		pkgSVC = pkger.NewService(
 			pkger.WithLogger(m.logger.With(zap.String("service", "pkger"))),
 			pkger.WithBucketSVC(b.BucketService),
+			pkger.WithCheckSVC(b.CheckService),
 			pkger.WithDashboardSVC(b.DashboardService),
 			pkger.WithLabelSVC(b.LabelService),
+			pkger.WithNotificationEndpointSVC(b.NotificationEndpointService),
+			pkger.WithNotificationRuleSVC(b.NotificationRuleStore),
+			pkger.WithTaskSVC(b.TaskService),
+			pkger.WithTelegrafSVC(b.TelegrafService),
 			pkger.WithVariableSVC(b.VariableService),
 		)
 	}
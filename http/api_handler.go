@@ -3,6 +3,7 @@ package http
 import (
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/go-chi/chi"
 	"github.com/influxdata/influxdb"
@@ -53,6 +54,11 @@ type APIBackend struct {
 	Logger     *zap.Logger
 	influxdb.HTTPErrorHandler
 	SessionRenewDisabled bool
+	MaxBucketRetention   time.Duration // zero means bucket retention is not capped
+
+	AuthorizationCacheSize int           // zero disables caching the authorizations found by token
+	AuthorizationCacheTTL  time.Duration // how long an authorization found by token is cached
+	TokenHeader            string        // names a header carrying the token of requests without an Authorization header or session cookie
 
 	NewBucketService func(*influxdb.Source) (influxdb.BucketService, error)
 	NewQueryService  func(*influxdb.Source) (query.ProxyQueryService, error)
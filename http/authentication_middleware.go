@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	platform "github.com/influxdata/influxdb"
@@ -24,6 +25,19 @@ type AuthenticationHandler struct {
 	TokenParser          *jsonweb.TokenParser
 	SessionRenewDisabled bool
 
+	// SessionRenewDuration is how long a session is renewed for on each
+	// request, when zero sessions are renewed for platform.RenewSessionTime.
+	SessionRenewDuration time.Duration
+
+	// TokenHeader names a header, i.e. X-Influx-Token, that carries the token
+	// of clients unable to set the Authorization header. It is only consulted
+	// when the request has neither an Authorization header nor a session cookie.
+	TokenHeader string
+
+	// AuthorizationCache caches the authorizations found by token, when it is
+	// nil every request authorized by a token looks up its authorization.
+	AuthorizationCache *AuthorizationCache
+
 	// This is only really used for it's lookup method the specific http
 	// handler used to register routes does not matter.
 	noAuthRouter *httprouter.Router
@@ -54,8 +68,10 @@ const (
 )
 
 // ProbeAuthScheme probes the http request for the requests for token or cookie session.
-func ProbeAuthScheme(r *http.Request) (string, error) {
-	_, tokenErr := GetToken(r)
+// The tokenHeaders name headers that carry the token, they are consulted in order
+// when the request has neither an Authorization header nor a session cookie.
+func ProbeAuthScheme(r *http.Request, tokenHeaders ...string) (string, error) {
+	_, tokenErr := getToken(r, tokenHeaders...)
 	_, sessErr := decodeCookieSession(r.Context(), r)
 
 	if tokenErr != nil && sessErr != nil {
@@ -69,6 +85,41 @@ func ProbeAuthScheme(r *http.Request) (string, error) {
 	return sessionAuthScheme, nil
 }
 
+// getToken returns the token of the Authorization header. When the request has
+// neither an Authorization header nor a session cookie the token is taken from
+// the first of the tokenHeaders that is set.
+func getToken(r *http.Request, tokenHeaders ...string) (string, error) {
+	t, err := GetToken(r)
+	if err != ErrAuthHeaderMissing {
+		return t, err
+	}
+
+	if _, sessErr := decodeCookieSession(r.Context(), r); sessErr == nil {
+		return "", err
+	}
+
+	for _, header := range tokenHeaders {
+		if header == "" {
+			continue
+		}
+		if t := strings.TrimSpace(r.Header.Get(header)); t != "" {
+			return t, nil
+		}
+	}
+	return "", err
+}
+
+var (
+	errTokenExpired = &platform.Error{
+		Code: platform.EUnauthorized,
+		Msg:  "token expired",
+	}
+	errTokenNotValidYet = &platform.Error{
+		Code: platform.EUnauthorized,
+		Msg:  "token not valid yet",
+	}
+)
+
 func (h *AuthenticationHandler) unauthorized(ctx context.Context, w http.ResponseWriter, err error) {
 	h.Logger.Info("unauthorized", zap.Error(err))
 	UnauthorizedError(ctx, h, w)
@@ -82,7 +133,7 @@ func (h *AuthenticationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 	}
 
 	ctx := r.Context()
-	scheme, err := ProbeAuthScheme(r)
+	scheme, err := ProbeAuthScheme(r, h.TokenHeader)
 	if err != nil {
 		h.unauthorized(ctx, w, err)
 		return
@@ -93,6 +144,11 @@ func (h *AuthenticationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 	switch scheme {
 	case tokenAuthScheme:
 		auth, err = h.extractAuthorization(ctx, r)
+		if err == errTokenExpired || err == errTokenNotValidYet {
+			h.Logger.Info("unauthorized", zap.Error(err))
+			h.HandleHTTPError(ctx, err, w)
+			return
+		}
 		if err != nil {
 			h.unauthorized(ctx, w, err)
 			return
@@ -113,6 +169,9 @@ func (h *AuthenticationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 	// disregard the user active check
 	if auth.GetUserID().Valid() {
 		if err = h.isUserActive(ctx, auth); err != nil {
+			if scheme == tokenAuthScheme {
+				h.evictAuthorization(r)
+			}
 			InactiveUserError(ctx, h, w)
 			return
 		}
@@ -137,7 +196,7 @@ func (h *AuthenticationHandler) isUserActive(ctx context.Context, auth platform.
 }
 
 func (h *AuthenticationHandler) extractAuthorization(ctx context.Context, r *http.Request) (platform.Authorizer, error) {
-	t, err := GetToken(r)
+	t, err := getToken(r, h.TokenHeader)
 	if err != nil {
 		return nil, err
 	}
@@ -147,6 +206,16 @@ func (h *AuthenticationHandler) extractAuthorization(ctx context.Context, r *htt
 		return token, nil
 	}
 
+	// a well formed JWT outside of its validity window is
+	// reported as such, rather than as any other unauthorized
+	// access, so clients know to acquire a new token
+	if jsonweb.IsExpiredError(err) {
+		return nil, errTokenExpired
+	}
+	if jsonweb.IsNotValidYetError(err) {
+		return nil, errTokenNotValidYet
+	}
+
 	// if the error returned signifies ths token is
 	// not a well formed JWT then use it as a lookup
 	// key for its associated authorization
@@ -155,7 +224,39 @@ func (h *AuthenticationHandler) extractAuthorization(ctx context.Context, r *htt
 		return nil, err
 	}
 
-	return h.AuthorizationService.FindAuthorizationByToken(ctx, t)
+	if h.AuthorizationCache != nil {
+		if auth, ok := h.AuthorizationCache.Get(t); ok {
+			return auth, nil
+		}
+	}
+
+	auth, err := h.AuthorizationService.FindAuthorizationByToken(ctx, t)
+	if err != nil {
+		return nil, err
+	}
+
+	if h.AuthorizationCache != nil {
+		h.AuthorizationCache.Set(t, auth)
+	}
+	return auth, nil
+}
+
+// evictAuthorization evicts the authorization of the request token from the
+// cache, the next request with the token looks up its authorization again.
+func (h *AuthenticationHandler) evictAuthorization(r *http.Request) {
+	if h.AuthorizationCache == nil {
+		return
+	}
+	if t, err := getToken(r, h.TokenHeader); err == nil {
+		h.AuthorizationCache.Delete(t)
+	}
+}
+
+func (h *AuthenticationHandler) sessionRenewDuration() time.Duration {
+	if h.SessionRenewDuration > 0 {
+		return h.SessionRenewDuration
+	}
+	return platform.RenewSessionTime
 }
 
 func (h *AuthenticationHandler) extractSession(ctx context.Context, r *http.Request) (*platform.Session, error) {
@@ -171,7 +272,7 @@ func (h *AuthenticationHandler) extractSession(ctx context.Context, r *http.Requ
 
 	if !h.SessionRenewDisabled {
 		// if the session is not expired, renew the session
-		err = h.SessionService.RenewSession(ctx, s, time.Now().Add(platform.RenewSessionTime))
+		err = h.SessionService.RenewSession(ctx, s, time.Now().Add(h.sessionRenewDuration()))
 		if err != nil {
 			return nil, err
 		}
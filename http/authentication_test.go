@@ -6,9 +6,11 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/dgrijalva/jwt-go"
 	influxdb "github.com/influxdata/influxdb"
 	platform "github.com/influxdata/influxdb"
 	platformhttp "github.com/influxdata/influxdb/http"
@@ -33,8 +35,19 @@ func TestAuthenticationHandler(t *testing.T) {
 	}
 	type wants struct {
 		code int
+		msg  string
 	}
 
+	signToken := func(claims jwt.StandardClaims) string {
+		v, err := jsonweb.Sign(&jsonweb.Token{StandardClaims: claims, KeyID: "some-key"}, []byte("correct-key"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+	expiredToken := signToken(jwt.StandardClaims{ExpiresAt: time.Now().Add(-time.Hour).Unix()})
+	notValidYetToken := signToken(jwt.StandardClaims{NotBefore: time.Now().Add(time.Hour).Unix()})
+
 	tests := []struct {
 		name   string
 		fields fields
@@ -200,6 +213,48 @@ func TestAuthenticationHandler(t *testing.T) {
 				code: http.StatusUnauthorized,
 			},
 		},
+		{
+			name: "jwt provided - expired",
+			fields: fields{
+				AuthorizationService: &mock.AuthorizationService{
+					FindAuthorizationByTokenFn: func(ctx context.Context, token string) (*platform.Authorization, error) {
+						panic("token lookup attempted")
+					},
+				},
+				SessionService: mock.NewSessionService(),
+				TokenParser: jsonweb.NewTokenParser(jsonweb.KeyStoreFunc(func(string) ([]byte, error) {
+					return []byte("correct-key"), nil
+				})),
+			},
+			args: args{
+				token: expiredToken,
+			},
+			wants: wants{
+				code: http.StatusUnauthorized,
+				msg:  "token expired",
+			},
+		},
+		{
+			name: "jwt provided - not valid yet",
+			fields: fields{
+				AuthorizationService: &mock.AuthorizationService{
+					FindAuthorizationByTokenFn: func(ctx context.Context, token string) (*platform.Authorization, error) {
+						panic("token lookup attempted")
+					},
+				},
+				SessionService: mock.NewSessionService(),
+				TokenParser: jsonweb.NewTokenParser(jsonweb.KeyStoreFunc(func(string) ([]byte, error) {
+					return []byte("correct-key"), nil
+				})),
+			},
+			args: args{
+				token: notValidYetToken,
+			},
+			wants: wants{
+				code: http.StatusUnauthorized,
+				msg:  "token not valid yet",
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -243,14 +298,266 @@ func TestAuthenticationHandler(t *testing.T) {
 			if got, want := w.Code, tt.wants.code; got != want {
 				t.Errorf("expected status code to be %d got %d", want, got)
 			}
+
+			if tt.wants.msg != "" && !strings.Contains(w.Body.String(), tt.wants.msg) {
+				t.Errorf("expected body to contain %q got %q", tt.wants.msg, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestAuthenticationHandler_AuthorizationCache(t *testing.T) {
+	newHandler := func(calls *int) *platformhttp.AuthenticationHandler {
+		h := platformhttp.NewAuthenticationHandler(platformhttp.ErrorHandler(0))
+		h.AuthorizationService = &mock.AuthorizationService{
+			FindAuthorizationByTokenFn: func(ctx context.Context, token string) (*platform.Authorization, error) {
+				*calls++
+				return &platform.Authorization{UserID: one}, nil
+			},
+		}
+		h.UserService = &mock.UserService{
+			FindUserByIDFn: func(ctx context.Context, id platform.ID) (*platform.User, error) {
+				return &platform.User{}, nil
+			},
+		}
+		h.AuthorizationCache = platformhttp.NewAuthorizationCache(10, time.Minute)
+		h.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})
+		return h
+	}
+
+	serve := func(t *testing.T, h http.Handler, token string) int {
+		t.Helper()
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("POST", "http://any.url", nil)
+		platformhttp.SetToken(token, r)
+		h.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	t.Run("second request with the same token is served from the cache", func(t *testing.T) {
+		var calls int
+		h := newHandler(&calls)
+
+		for i := 0; i < 2; i++ {
+			if code := serve(t, h, "abc123"); code != http.StatusOK {
+				t.Fatalf("expected status code to be %d got %d", http.StatusOK, code)
+			}
+		}
+		if calls != 1 {
+			t.Errorf("expected the authorization service to be called once, got %d", calls)
+		}
+	})
+
+	t.Run("inactive user evicts the token", func(t *testing.T) {
+		var calls int
+		h := newHandler(&calls)
+		h.UserService = &mock.UserService{
+			FindUserByIDFn: func(ctx context.Context, id platform.ID) (*platform.User, error) {
+				return &platform.User{Status: "inactive"}, nil
+			},
+		}
+
+		for i := 0; i < 2; i++ {
+			if code := serve(t, h, "abc123"); code != http.StatusForbidden {
+				t.Fatalf("expected status code to be %d got %d", http.StatusForbidden, code)
+			}
+		}
+		if calls != 2 {
+			t.Errorf("expected the authorization service to be called twice, got %d", calls)
+		}
+	})
+
+	t.Run("jwt bypasses the cache", func(t *testing.T) {
+		var calls int
+		h := newHandler(&calls)
+		h.TokenParser = jsonweb.NewTokenParser(jsonweb.KeyStoreFunc(func(string) ([]byte, error) {
+			return []byte("correct-key"), nil
+		}))
+
+		for i := 0; i < 2; i++ {
+			if code := serve(t, h, token); code != http.StatusOK {
+				t.Fatalf("expected status code to be %d got %d", http.StatusOK, code)
+			}
+		}
+		if calls != 0 {
+			t.Errorf("expected the authorization service not to be called, got %d", calls)
+		}
+	})
+}
+
+func TestAuthenticationHandler_TokenHeader(t *testing.T) {
+	const tokenHeader = "X-Influx-Token"
+
+	tests := []struct {
+		name        string
+		token       string
+		headerToken string
+		wantCode    int
+		wantToken   string
+	}{
+		{
+			name:        "token header alone",
+			headerToken: "header123",
+			wantCode:    http.StatusOK,
+			wantToken:   "header123",
+		},
+		{
+			name:      "authorization header alone",
+			token:     "abc123",
+			wantCode:  http.StatusOK,
+			wantToken: "abc123",
+		},
+		{
+			name:        "authorization header takes precedence over the token header",
+			token:       "abc123",
+			headerToken: "header123",
+			wantCode:    http.StatusOK,
+			wantToken:   "abc123",
+		},
+		{
+			name:     "neither header",
+			wantCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotToken string
+			h := platformhttp.NewAuthenticationHandler(platformhttp.ErrorHandler(0))
+			h.AuthorizationService = &mock.AuthorizationService{
+				FindAuthorizationByTokenFn: func(ctx context.Context, token string) (*platform.Authorization, error) {
+					gotToken = token
+					return &platform.Authorization{}, nil
+				},
+			}
+			h.SessionService = mock.NewSessionService()
+			h.TokenHeader = tokenHeader
+			h.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("POST", "http://any.url", nil)
+			if tt.token != "" {
+				platformhttp.SetToken(tt.token, r)
+			}
+			if tt.headerToken != "" {
+				r.Header.Set(tokenHeader, tt.headerToken)
+			}
+
+			h.ServeHTTP(w, r)
+
+			if got, want := w.Code, tt.wantCode; got != want {
+				t.Errorf("expected status code to be %d got %d", want, got)
+			}
+			if got, want := gotToken, tt.wantToken; got != want {
+				t.Errorf("expected authorization to be found by token %q got %q", want, got)
+			}
+		})
+	}
+}
+
+func TestAuthenticationHandler_SessionRenewDuration(t *testing.T) {
+	tests := []struct {
+		name          string
+		renewDuration time.Duration
+		want          time.Duration
+	}{
+		{
+			name:          "custom duration",
+			renewDuration: 5 * time.Minute,
+			want:          5 * time.Minute,
+		},
+		{
+			name: "zero falls back to the default",
+			want: platform.RenewSessionTime,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var expiredAt time.Time
+			h := platformhttp.NewAuthenticationHandler(platformhttp.ErrorHandler(0))
+			h.AuthorizationService = mock.NewAuthorizationService()
+			h.SessionService = &mock.SessionService{
+				FindSessionFn: func(ctx context.Context, key string) (*platform.Session, error) {
+					return &platform.Session{}, nil
+				},
+				RenewSessionFn: func(ctx context.Context, session *platform.Session, newExpiration time.Time) error {
+					expiredAt = newExpiration
+					return nil
+				},
+			}
+			h.SessionRenewDuration = tt.renewDuration
+			h.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("POST", "http://any.url", nil)
+			platformhttp.SetCookieSession("abc123", r)
+
+			before := time.Now()
+			h.ServeHTTP(w, r)
+			after := time.Now()
+
+			if got, want := w.Code, http.StatusOK; got != want {
+				t.Fatalf("expected status code to be %d got %d", want, got)
+			}
+			if expiredAt.Before(before.Add(tt.want)) || expiredAt.After(after.Add(tt.want)) {
+				t.Errorf("expected session to be renewed for %s, got expiration %s", tt.want, expiredAt)
+			}
+		})
+	}
+}
+
+func BenchmarkAuthenticationHandler_AuthorizationCache(b *testing.B) {
+	newHandler := func(cache *platformhttp.AuthorizationCache) http.Handler {
+		h := platformhttp.NewAuthenticationHandler(platformhttp.ErrorHandler(0))
+		h.AuthorizationService = &mock.AuthorizationService{
+			FindAuthorizationByTokenFn: func(ctx context.Context, token string) (*platform.Authorization, error) {
+				// stands in for the kv read of the authorization
+				time.Sleep(10 * time.Microsecond)
+				return &platform.Authorization{}, nil
+			},
+		}
+		h.AuthorizationCache = cache
+		h.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})
+		return h
+	}
+
+	benchmarks := []struct {
+		name  string
+		cache *platformhttp.AuthorizationCache
+	}{
+		{name: "uncached"},
+		{name: "cached", cache: platformhttp.NewAuthorizationCache(100, time.Minute)},
+	}
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			h := newHandler(bm.cache)
+			r := httptest.NewRequest("POST", "http://any.url", nil)
+			platformhttp.SetToken("abc123", r)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				h.ServeHTTP(httptest.NewRecorder(), r)
+			}
 		})
 	}
 }
 
 func TestProbeAuthScheme(t *testing.T) {
 	type args struct {
-		token   string
-		session string
+		token       string
+		session     string
+		headerToken string
 	}
 	type wants struct {
 		scheme string
@@ -280,6 +587,25 @@ func TestProbeAuthScheme(t *testing.T) {
 				scheme: "token",
 			},
 		},
+		{
+			name: "token header provided",
+			args: args{
+				headerToken: "abc123",
+			},
+			wants: wants{
+				scheme: "token",
+			},
+		},
+		{
+			name: "session and token header provided",
+			args: args{
+				session:     "abc123",
+				headerToken: "abc123",
+			},
+			wants: wants{
+				scheme: "session",
+			},
+		},
 		{
 			name: "no auth provided",
 			args: args{},
@@ -301,7 +627,11 @@ func TestProbeAuthScheme(t *testing.T) {
 				platformhttp.SetToken(tt.args.token, r)
 			}
 
-			scheme, err := platformhttp.ProbeAuthScheme(r)
+			if tt.args.headerToken != "" {
+				r.Header.Set("X-Influx-Token", tt.args.headerToken)
+			}
+
+			scheme, err := platformhttp.ProbeAuthScheme(r, "X-Influx-Token")
 			if (err != nil) != (tt.wants.err != nil) {
 				t.Errorf("unexpected error got %v want %v", err, tt.wants.err)
 				return
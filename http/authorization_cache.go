@@ -0,0 +1,99 @@
+package http
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+// AuthorizationCache is an LRU cache of the authorizers resolved from tokens.
+// When more than size tokens are cached, the least recently used token is
+// evicted. A cached authorizer expires ttl after it is cached, so changes to
+// the authorization, i.e. it being deactivated, are seen within the ttl.
+type AuthorizationCache struct {
+	mu      sync.Mutex
+	size    int
+	ttl     time.Duration
+	now     func() time.Time
+	cache   map[string]*list.Element
+	evictor *list.List
+}
+
+type authorizationCacheEntry struct {
+	token     string
+	auth      platform.Authorizer
+	expiresAt time.Time
+}
+
+// NewAuthorizationCache returns an AuthorizationCache of at most size tokens,
+// each cached for the ttl.
+func NewAuthorizationCache(size int, ttl time.Duration) *AuthorizationCache {
+	return &AuthorizationCache{
+		size:    size,
+		ttl:     ttl,
+		now:     time.Now,
+		cache:   make(map[string]*list.Element),
+		evictor: list.New(),
+	}
+}
+
+// Get returns the authorizer cached for the token. An expired authorizer is
+// evicted and not returned.
+func (c *AuthorizationCache) Get(token string) (platform.Authorizer, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ele, ok := c.cache[token]
+	if !ok {
+		return nil, false
+	}
+
+	entry := ele.Value.(*authorizationCacheEntry)
+	if !c.now().Before(entry.expiresAt) {
+		c.remove(ele)
+		return nil, false
+	}
+
+	c.evictor.MoveToFront(ele)
+	return entry.auth, true
+}
+
+// Set caches the authorizer of the token, evicting the least recently used
+// token when the cache is full.
+func (c *AuthorizationCache) Set(token string, auth platform.Authorizer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := &authorizationCacheEntry{
+		token:     token,
+		auth:      auth,
+		expiresAt: c.now().Add(c.ttl),
+	}
+	if ele, ok := c.cache[token]; ok {
+		ele.Value = entry
+		c.evictor.MoveToFront(ele)
+		return
+	}
+
+	c.cache[token] = c.evictor.PushFront(entry)
+	for c.evictor.Len() > c.size {
+		c.remove(c.evictor.Back())
+	}
+}
+
+// Delete evicts the token from the cache.
+func (c *AuthorizationCache) Delete(token string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if ele, ok := c.cache[token]; ok {
+		c.remove(ele)
+	}
+}
+
+func (c *AuthorizationCache) remove(ele *list.Element) {
+	c.evictor.Remove(ele)
+	delete(c.cache, ele.Value.(*authorizationCacheEntry).token)
+}
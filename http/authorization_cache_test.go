@@ -0,0 +1,75 @@
+package http
+
+import (
+	"testing"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+func TestAuthorizationCache(t *testing.T) {
+	auth := func(id platform.ID) *platform.Authorization {
+		return &platform.Authorization{ID: id}
+	}
+
+	t.Run("returns the cached authorizer", func(t *testing.T) {
+		c := NewAuthorizationCache(2, time.Minute)
+		c.Set("token1", auth(1))
+
+		got, ok := c.Get("token1")
+		if !ok {
+			t.Fatal("expected token1 to be cached")
+		}
+		if got.Identifier() != platform.ID(1) {
+			t.Errorf("expected authorization 1, got %s", got.Identifier())
+		}
+
+		if _, ok := c.Get("token2"); ok {
+			t.Error("expected token2 not to be cached")
+		}
+	})
+
+	t.Run("evicts the least recently used token", func(t *testing.T) {
+		c := NewAuthorizationCache(2, time.Minute)
+		c.Set("token1", auth(1))
+		c.Set("token2", auth(2))
+		c.Get("token1")
+		c.Set("token3", auth(3))
+
+		if _, ok := c.Get("token2"); ok {
+			t.Error("expected token2 to be evicted")
+		}
+		for _, token := range []string{"token1", "token3"} {
+			if _, ok := c.Get(token); !ok {
+				t.Errorf("expected %s to be cached", token)
+			}
+		}
+	})
+
+	t.Run("expires tokens after the ttl", func(t *testing.T) {
+		now := time.Now()
+		c := NewAuthorizationCache(2, time.Minute)
+		c.now = func() time.Time { return now }
+		c.Set("token1", auth(1))
+
+		now = now.Add(59 * time.Second)
+		if _, ok := c.Get("token1"); !ok {
+			t.Fatal("expected token1 to be cached within the ttl")
+		}
+
+		now = now.Add(time.Second)
+		if _, ok := c.Get("token1"); ok {
+			t.Error("expected token1 to expire")
+		}
+	})
+
+	t.Run("deletes a token", func(t *testing.T) {
+		c := NewAuthorizationCache(2, time.Minute)
+		c.Set("token1", auth(1))
+		c.Delete("token1")
+
+		if _, ok := c.Get("token1"); ok {
+			t.Error("expected token1 to be deleted")
+		}
+	})
+}
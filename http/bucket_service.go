@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/http"
 	"path"
+	"strings"
 	"time"
 
 	"github.com/julienschmidt/httprouter"
@@ -28,6 +29,10 @@ type BucketBackend struct {
 	LabelService               influxdb.LabelService
 	UserService                influxdb.UserService
 	OrganizationService        influxdb.OrganizationService
+
+	// MaxRetention caps the retention period of created and updated buckets,
+	// zero disables the cap.
+	MaxRetention time.Duration
 }
 
 // NewBucketBackend returns a new instance of BucketBackend.
@@ -42,6 +47,8 @@ func NewBucketBackend(b *APIBackend) *BucketBackend {
 		LabelService:               b.LabelService,
 		UserService:                b.UserService,
 		OrganizationService:        b.OrganizationService,
+
+		MaxRetention: b.MaxBucketRetention,
 	}
 }
 
@@ -57,6 +64,8 @@ type BucketHandler struct {
 	LabelService               influxdb.LabelService
 	UserService                influxdb.UserService
 	OrganizationService        influxdb.OrganizationService
+
+	MaxRetention time.Duration
 }
 
 const (
@@ -84,6 +93,8 @@ func NewBucketHandler(b *BucketBackend) *BucketHandler {
 		LabelService:               b.LabelService,
 		UserService:                b.UserService,
 		OrganizationService:        b.OrganizationService,
+
+		MaxRetention: b.MaxRetention,
 	}
 
 	h.HandlerFunc("POST", bucketsPath, h.handlePostBucket)
@@ -327,6 +338,11 @@ func (h *BucketHandler) handlePostBucket(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	if err := h.validateRetention(bucket.RetentionPeriod); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
 	if err := h.BucketService.CreateBucket(ctx, bucket); err != nil {
 		h.HandleHTTPError(ctx, err, w)
 		return
@@ -339,6 +355,21 @@ func (h *BucketHandler) handlePostBucket(w http.ResponseWriter, r *http.Request)
 	}
 }
 
+// validateRetention checks the retention period against the configured maximum.
+// An infinite retention period exceeds any maximum.
+func (h *BucketHandler) validateRetention(d time.Duration) error {
+	if h.MaxRetention <= 0 {
+		return nil
+	}
+	if d == 0 || d > h.MaxRetention {
+		return &influxdb.Error{
+			Code: influxdb.EUnprocessableEntity,
+			Msg:  fmt.Sprintf("retention period must not exceed the maximum of %s", h.MaxRetention),
+		}
+	}
+	return nil
+}
+
 type postBucketRequest struct {
 	OrgID               influxdb.ID     `json:"orgID,omitempty"`
 	Name                string          `json:"name"`
@@ -581,15 +612,84 @@ func (h *BucketHandler) handleGetBuckets(w http.ResponseWriter, r *http.Request)
 	}
 	h.Logger.Debug("buckets retrieved", zap.String("buckets", fmt.Sprint(bs)))
 
-	if err := encodeResponse(ctx, w, http.StatusOK, newBucketsResponse(ctx, req.opts, req.filter, bs, h.LabelService)); err != nil {
+	// the labels filter the page of buckets found, the paging links continue
+	// from the buckets found before they are filtered.
+	found := len(bs)
+	bs, err = filterBucketsByLabels(ctx, h.LabelService, bs, req.labels)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	res := newBucketsResponse(ctx, req.opts, req.filter, bs, h.LabelService)
+	res.Links = newPagingLinks(bucketsPath, req.opts, req.pagingFilter(), found)
+	if err := encodeResponse(ctx, w, http.StatusOK, res); err != nil {
 		logEncodingError(h.Logger, r, err)
 		return
 	}
 }
 
+// filterBucketsByLabels returns the buckets that have every one of the named labels.
+func filterBucketsByLabels(ctx context.Context, labelService influxdb.LabelService, bs []*influxdb.Bucket, names []string) ([]*influxdb.Bucket, error) {
+	if len(names) == 0 {
+		return bs, nil
+	}
+
+	filtered := make([]*influxdb.Bucket, 0, len(bs))
+	for _, b := range bs {
+		labels, err := labelService.FindResourceLabels(ctx, influxdb.LabelMappingFilter{
+			ResourceID:   b.ID,
+			ResourceType: influxdb.BucketsResourceType,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		has := make(map[string]bool, len(labels))
+		for _, l := range labels {
+			has[l.Name] = true
+		}
+
+		hasAll := true
+		for _, name := range names {
+			if !has[name] {
+				hasAll = false
+				break
+			}
+		}
+		if hasAll {
+			filtered = append(filtered, b)
+		}
+	}
+	return filtered, nil
+}
+
 type getBucketsRequest struct {
 	filter influxdb.BucketFilter
 	opts   influxdb.FindOptions
+	labels []string
+}
+
+// pagingFilter returns the filter of the request, including its labels, to
+// be carried into the paging links.
+func (r *getBucketsRequest) pagingFilter() influxdb.PagingFilter {
+	return bucketsPagingFilter{
+		BucketFilter: r.filter,
+		labels:       r.labels,
+	}
+}
+
+type bucketsPagingFilter struct {
+	influxdb.BucketFilter
+	labels []string
+}
+
+func (f bucketsPagingFilter) QueryParams() map[string][]string {
+	qp := f.BucketFilter.QueryParams()
+	if len(f.labels) > 0 {
+		qp["label"] = f.labels
+	}
+	return qp
 }
 
 func decodeGetBucketsRequest(ctx context.Context, r *http.Request) (*getBucketsRequest, error) {
@@ -602,6 +702,9 @@ func decodeGetBucketsRequest(ctx context.Context, r *http.Request) (*getBucketsR
 	}
 
 	req.opts = *opts
+	if err := validBucketSortBy(req.opts.SortBy); err != nil {
+		return nil, err
+	}
 
 	if orgID := qp.Get("orgID"); orgID != "" {
 		id, err := influxdb.IDFromString(orgID)
@@ -627,9 +730,30 @@ func decodeGetBucketsRequest(ctx context.Context, r *http.Request) (*getBucketsR
 		req.filter.ID = id
 	}
 
+	for _, label := range qp["label"] {
+		if label != "" {
+			req.labels = append(req.labels, label)
+		}
+	}
+
 	return req, nil
 }
 
+func validBucketSortBy(sortBy string) error {
+	if sortBy == "" {
+		return nil
+	}
+	for _, f := range influxdb.BucketSortByFields {
+		if f == sortBy {
+			return nil
+		}
+	}
+	return &influxdb.Error{
+		Code: influxdb.EInvalid,
+		Msg:  fmt.Sprintf("sortBy must be one of: %s", strings.Join(influxdb.BucketSortByFields, ", ")),
+	}
+}
+
 // handlePatchBucket is the HTTP handler for the PATCH /api/v2/buckets route.
 func (h *BucketHandler) handlePatchBucket(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -639,6 +763,13 @@ func (h *BucketHandler) handlePatchBucket(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	if req.Update.RetentionPeriod != nil {
+		if err := h.validateRetention(*req.Update.RetentionPeriod); err != nil {
+			h.HandleHTTPError(ctx, err, w)
+			return
+		}
+	}
+
 	b, err := h.BucketService.UpdateBucket(ctx, req.BucketID, req.Update)
 	if err != nil {
 		h.HandleHTTPError(ctx, err, w)
@@ -188,6 +188,196 @@ func TestService_handleGetBuckets(t *testing.T) {
     "self": "/api/v2/buckets?descending=false&limit=1&offset=0"
   },
   "buckets": []
+}`,
+			},
+		},
+		{
+			name: "get buckets by a name that does not exist",
+			fields: fields{
+				&mock.BucketService{
+					FindBucketsFn: func(ctx context.Context, filter platform.BucketFilter, opts ...platform.FindOptions) ([]*platform.Bucket, int, error) {
+						if filter.Name == nil || *filter.Name != "hello" {
+							return []*platform.Bucket{}, 0, nil
+						}
+						return []*platform.Bucket{
+							&platform.Bucket{
+								ID:              platformtesting.MustIDBase16("0b501e7e557ab1ed"),
+								Name:            "hello",
+								OrgID:           platformtesting.MustIDBase16("50f7ba1150f7ba11"),
+								RetentionPeriod: 2 * time.Second,
+							},
+						}, 1, nil
+					},
+				},
+				&mock.LabelService{},
+			},
+			args: args{
+				map[string][]string{
+					"name":  {"nope"},
+					"limit": {"1"},
+				},
+			},
+			wants: wants{
+				statusCode:  http.StatusOK,
+				contentType: "application/json; charset=utf-8",
+				body: `
+{
+  "links": {
+    "self": "/api/v2/buckets?descending=false&limit=1&name=nope&offset=0"
+  },
+  "buckets": []
+}`,
+			},
+		},
+		{
+			name: "get buckets with a label",
+			fields: fields{
+				&mock.BucketService{
+					FindBucketsFn: func(ctx context.Context, filter platform.BucketFilter, opts ...platform.FindOptions) ([]*platform.Bucket, int, error) {
+						return []*platform.Bucket{
+							&platform.Bucket{
+								ID:              platformtesting.MustIDBase16("0b501e7e557ab1ed"),
+								Name:            "hello",
+								OrgID:           platformtesting.MustIDBase16("50f7ba1150f7ba11"),
+								RetentionPeriod: 2 * time.Second,
+							},
+							{
+								ID:              platformtesting.MustIDBase16("c0175f0077a77005"),
+								Name:            "example",
+								OrgID:           platformtesting.MustIDBase16("7e55e118dbabb1ed"),
+								RetentionPeriod: 24 * time.Hour,
+							},
+						}, 2, nil
+					},
+				},
+				&mock.LabelService{
+					FindResourceLabelsFn: func(ctx context.Context, f platform.LabelMappingFilter) ([]*platform.Label, error) {
+						if f.ResourceID == platformtesting.MustIDBase16("0b501e7e557ab1ed") {
+							return []*platform.Label{
+								{ID: platformtesting.MustIDBase16("fc3dc670a4be9b9a"), Name: "prod"},
+								{ID: platformtesting.MustIDBase16("fc3dc670a4be9b9b"), Name: "team_a"},
+							}, nil
+						}
+						return []*platform.Label{
+							{ID: platformtesting.MustIDBase16("fc3dc670a4be9b9c"), Name: "dev"},
+						}, nil
+					},
+				},
+			},
+			args: args{
+				map[string][]string{
+					"label": {"prod"},
+					"limit": {"2"},
+				},
+			},
+			wants: wants{
+				statusCode:  http.StatusOK,
+				contentType: "application/json; charset=utf-8",
+				body: `
+{
+  "links": {
+    "self": "/api/v2/buckets?descending=false&label=prod&limit=2&offset=0",
+    "next": "/api/v2/buckets?descending=false&label=prod&limit=2&offset=2"
+  },
+  "buckets": [
+    {
+      "links": {
+        "org": "/api/v2/orgs/50f7ba1150f7ba11",
+        "self": "/api/v2/buckets/0b501e7e557ab1ed",
+        "logs": "/api/v2/buckets/0b501e7e557ab1ed/logs",
+        "labels": "/api/v2/buckets/0b501e7e557ab1ed/labels",
+        "owners": "/api/v2/buckets/0b501e7e557ab1ed/owners",
+        "members": "/api/v2/buckets/0b501e7e557ab1ed/members",
+        "write": "/api/v2/write?org=50f7ba1150f7ba11&bucket=0b501e7e557ab1ed"
+      },
+      "createdAt": "0001-01-01T00:00:00Z",
+      "updatedAt": "0001-01-01T00:00:00Z",
+      "id": "0b501e7e557ab1ed",
+      "orgID": "50f7ba1150f7ba11",
+      "type": "user",
+      "name": "hello",
+      "retentionRules": [{"type": "expire", "everySeconds": 2}],
+      "labels": [
+        {"id": "fc3dc670a4be9b9a", "name": "prod"},
+        {"id": "fc3dc670a4be9b9b", "name": "team_a"}
+      ]
+    }
+  ]
+}`,
+			},
+		},
+		{
+			name: "get buckets by name and labels",
+			fields: fields{
+				&mock.BucketService{
+					FindBucketsFn: func(ctx context.Context, filter platform.BucketFilter, opts ...platform.FindOptions) ([]*platform.Bucket, int, error) {
+						if filter.Name == nil || *filter.Name != "hello" {
+							return []*platform.Bucket{}, 0, nil
+						}
+						return []*platform.Bucket{
+							&platform.Bucket{
+								ID:              platformtesting.MustIDBase16("0b501e7e557ab1ed"),
+								Name:            "hello",
+								OrgID:           platformtesting.MustIDBase16("50f7ba1150f7ba11"),
+								RetentionPeriod: 2 * time.Second,
+							},
+						}, 1, nil
+					},
+				},
+				&mock.LabelService{
+					FindResourceLabelsFn: func(ctx context.Context, f platform.LabelMappingFilter) ([]*platform.Label, error) {
+						if f.ResourceID == platformtesting.MustIDBase16("0b501e7e557ab1ed") {
+							return []*platform.Label{
+								{ID: platformtesting.MustIDBase16("fc3dc670a4be9b9a"), Name: "prod"},
+								{ID: platformtesting.MustIDBase16("fc3dc670a4be9b9b"), Name: "team_a"},
+							}, nil
+						}
+						return []*platform.Label{
+							{ID: platformtesting.MustIDBase16("fc3dc670a4be9b9c"), Name: "dev"},
+						}, nil
+					},
+				},
+			},
+			args: args{
+				map[string][]string{
+					"name":  {"hello"},
+					"label": {"prod", "team_a"},
+					"limit": {"1"},
+				},
+			},
+			wants: wants{
+				statusCode:  http.StatusOK,
+				contentType: "application/json; charset=utf-8",
+				body: `
+{
+  "links": {
+    "self": "/api/v2/buckets?descending=false&label=prod&label=team_a&limit=1&name=hello&offset=0",
+    "next": "/api/v2/buckets?descending=false&label=prod&label=team_a&limit=1&name=hello&offset=1"
+  },
+  "buckets": [
+    {
+      "links": {
+        "org": "/api/v2/orgs/50f7ba1150f7ba11",
+        "self": "/api/v2/buckets/0b501e7e557ab1ed",
+        "logs": "/api/v2/buckets/0b501e7e557ab1ed/logs",
+        "labels": "/api/v2/buckets/0b501e7e557ab1ed/labels",
+        "owners": "/api/v2/buckets/0b501e7e557ab1ed/owners",
+        "members": "/api/v2/buckets/0b501e7e557ab1ed/members",
+        "write": "/api/v2/write?org=50f7ba1150f7ba11&bucket=0b501e7e557ab1ed"
+      },
+      "createdAt": "0001-01-01T00:00:00Z",
+      "updatedAt": "0001-01-01T00:00:00Z",
+      "id": "0b501e7e557ab1ed",
+      "orgID": "50f7ba1150f7ba11",
+      "type": "user",
+      "name": "hello",
+      "retentionRules": [{"type": "expire", "everySeconds": 2}],
+      "labels": [
+        {"id": "fc3dc670a4be9b9a", "name": "prod"},
+        {"id": "fc3dc670a4be9b9b", "name": "team_a"}
+      ]
+    }
+  ]
 }`,
 			},
 		},
@@ -367,6 +557,7 @@ func TestService_handlePostBucket(t *testing.T) {
 	type fields struct {
 		BucketService       platform.BucketService
 		OrganizationService platform.OrganizationService
+		MaxRetention        time.Duration
 	}
 	type args struct {
 		bucket *platform.Bucket
@@ -430,6 +621,106 @@ func TestService_handlePostBucket(t *testing.T) {
 `,
 			},
 		},
+		{
+			name: "create a bucket within the maximum retention",
+			fields: fields{
+				BucketService: &mock.BucketService{
+					CreateBucketFn: func(ctx context.Context, c *platform.Bucket) error {
+						c.ID = platformtesting.MustIDBase16("020f755c3c082000")
+						return nil
+					},
+				},
+				OrganizationService: &mock.OrganizationService{
+					FindOrganizationF: func(ctx context.Context, f platform.OrganizationFilter) (*platform.Organization, error) {
+						return &platform.Organization{ID: platformtesting.MustIDBase16("6f626f7274697320")}, nil
+					},
+				},
+				MaxRetention: 24 * time.Hour,
+			},
+			args: args{
+				bucket: &platform.Bucket{
+					Name:            "hello",
+					OrgID:           platformtesting.MustIDBase16("6f626f7274697320"),
+					RetentionPeriod: time.Hour,
+				},
+			},
+			wants: wants{
+				statusCode:  http.StatusCreated,
+				contentType: "application/json; charset=utf-8",
+				body: `
+{
+  "links": {
+    "org": "/api/v2/orgs/6f626f7274697320",
+    "self": "/api/v2/buckets/020f755c3c082000",
+    "logs": "/api/v2/buckets/020f755c3c082000/logs",
+    "labels": "/api/v2/buckets/020f755c3c082000/labels",
+    "members": "/api/v2/buckets/020f755c3c082000/members",
+    "owners": "/api/v2/buckets/020f755c3c082000/owners",
+    "write": "/api/v2/write?org=6f626f7274697320&bucket=020f755c3c082000"
+  },
+  "createdAt": "0001-01-01T00:00:00Z",
+  "updatedAt": "0001-01-01T00:00:00Z",
+  "id": "020f755c3c082000",
+  "orgID": "6f626f7274697320",
+  "type": "user",
+  "name": "hello",
+  "retentionRules": [{"type": "expire", "everySeconds": 3600}],
+  "labels": []
+}
+`,
+			},
+		},
+		{
+			name: "reject a bucket exceeding the maximum retention",
+			fields: fields{
+				BucketService: &mock.BucketService{
+					CreateBucketFn: func(ctx context.Context, c *platform.Bucket) error {
+						return fmt.Errorf("should not create a bucket exceeding the maximum retention")
+					},
+				},
+				OrganizationService: &mock.OrganizationService{
+					FindOrganizationF: func(ctx context.Context, f platform.OrganizationFilter) (*platform.Organization, error) {
+						return &platform.Organization{ID: platformtesting.MustIDBase16("6f626f7274697320")}, nil
+					},
+				},
+				MaxRetention: 24 * time.Hour,
+			},
+			args: args{
+				bucket: &platform.Bucket{
+					Name:            "hello",
+					OrgID:           platformtesting.MustIDBase16("6f626f7274697320"),
+					RetentionPeriod: 48 * time.Hour,
+				},
+			},
+			wants: wants{
+				statusCode: http.StatusUnprocessableEntity,
+			},
+		},
+		{
+			name: "reject an infinite retention when a maximum is set",
+			fields: fields{
+				BucketService: &mock.BucketService{
+					CreateBucketFn: func(ctx context.Context, c *platform.Bucket) error {
+						return fmt.Errorf("should not create a bucket exceeding the maximum retention")
+					},
+				},
+				OrganizationService: &mock.OrganizationService{
+					FindOrganizationF: func(ctx context.Context, f platform.OrganizationFilter) (*platform.Organization, error) {
+						return &platform.Organization{ID: platformtesting.MustIDBase16("6f626f7274697320")}, nil
+					},
+				},
+				MaxRetention: 24 * time.Hour,
+			},
+			args: args{
+				bucket: &platform.Bucket{
+					Name:  "hello",
+					OrgID: platformtesting.MustIDBase16("6f626f7274697320"),
+				},
+			},
+			wants: wants{
+				statusCode: http.StatusUnprocessableEntity,
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -437,6 +728,7 @@ func TestService_handlePostBucket(t *testing.T) {
 			bucketBackend := NewMockBucketBackend()
 			bucketBackend.BucketService = tt.fields.BucketService
 			bucketBackend.OrganizationService = tt.fields.OrganizationService
+			bucketBackend.MaxRetention = tt.fields.MaxRetention
 			h := NewBucketHandler(bucketBackend)
 
 			b, err := json.Marshal(newBucket(tt.args.bucket))
@@ -6,9 +6,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"path"
 
 	"github.com/influxdata/influxdb"
 	pctx "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/kit/tracing"
 	"github.com/influxdata/influxdb/notification/check"
 	"github.com/julienschmidt/httprouter"
 	"go.uber.org/zap"
@@ -665,3 +667,245 @@ func (h *CheckHandler) handleDeleteCheck(w http.ResponseWriter, r *http.Request)
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// CheckService connects to Influx via HTTP using tokens to manage checks.
+type CheckService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+
+	*UserResourceMappingService
+	*OrganizationService
+}
+
+var _ influxdb.CheckService = (*CheckService)(nil)
+
+// NewCheckService returns a client for the check handlers, with the user resource mapping
+// and organization services the influxdb.CheckService requires.
+func NewCheckService(addr, token string, insecureSkipVerify bool) *CheckService {
+	return &CheckService{
+		Addr:               addr,
+		Token:              token,
+		InsecureSkipVerify: insecureSkipVerify,
+		UserResourceMappingService: &UserResourceMappingService{
+			Addr:               addr,
+			Token:              token,
+			InsecureSkipVerify: insecureSkipVerify,
+		},
+		OrganizationService: &OrganizationService{
+			Addr:               addr,
+			Token:              token,
+			InsecureSkipVerify: insecureSkipVerify,
+		},
+	}
+}
+
+func checkIDPath(id influxdb.ID) string {
+	return path.Join(checksPath, id.String())
+}
+
+// FindCheckByID returns a single check by ID.
+func (s *CheckService) FindCheckByID(ctx context.Context, id influxdb.ID) (influxdb.Check, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	u, err := NewURL(s.Addr, checkIDPath(id))
+	if err != nil {
+		return nil, err
+	}
+
+	var raw json.RawMessage
+	if err := doJSONRequest(ctx, "GET", u, s.Token, s.InsecureSkipVerify, nil, &raw); err != nil {
+		return nil, err
+	}
+	return check.UnmarshalJSON(raw)
+}
+
+// FindCheck returns the first check that matches filter.
+func (s *CheckService) FindCheck(ctx context.Context, filter influxdb.CheckFilter) (influxdb.Check, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if filter.ID != nil {
+		return s.FindCheckByID(ctx, *filter.ID)
+	}
+
+	checks, n, err := s.FindChecks(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Op:   influxdb.OpFindCheck,
+			Msg:  "check not found",
+		}
+	}
+	return checks[0], nil
+}
+
+// FindChecks returns a list of checks that match filter and the total count of matching checks.
+// Additional options provide pagination & sorting. Without them every page of checks is read.
+func (s *CheckService) FindChecks(ctx context.Context, filter influxdb.CheckFilter, opt ...influxdb.FindOptions) ([]influxdb.Check, int, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if len(opt) > 0 {
+		checks, err := s.findChecks(ctx, filter, opt[0])
+		if err != nil {
+			return nil, 0, err
+		}
+		return checks, len(checks), nil
+	}
+
+	var checks []influxdb.Check
+	for offset := 0; ; offset += influxdb.MaxPageSize {
+		page, err := s.findChecks(ctx, filter, influxdb.FindOptions{
+			Limit:  influxdb.MaxPageSize,
+			Offset: offset,
+		})
+		if err != nil {
+			return nil, 0, err
+		}
+		checks = append(checks, page...)
+		if len(page) < influxdb.MaxPageSize {
+			break
+		}
+	}
+	return checks, len(checks), nil
+}
+
+func (s *CheckService) findChecks(ctx context.Context, filter influxdb.CheckFilter, opt influxdb.FindOptions) ([]influxdb.Check, error) {
+	u, err := NewURL(s.Addr, checksPath)
+	if err != nil {
+		return nil, err
+	}
+
+	query := u.Query()
+	if filter.OrgID != nil {
+		query.Add("orgID", filter.OrgID.String())
+	}
+	if filter.Org != nil {
+		query.Add("org", *filter.Org)
+	}
+	for k, vs := range opt.QueryParams() {
+		for _, v := range vs {
+			query.Add(k, v)
+		}
+	}
+	u.RawQuery = query.Encode()
+
+	var resp struct {
+		Checks []json.RawMessage `json:"checks"`
+	}
+	if err := doJSONRequest(ctx, "GET", u, s.Token, s.InsecureSkipVerify, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	// the handler does not filter by id or name, so that is done here.
+	checks := make([]influxdb.Check, 0, len(resp.Checks))
+	for _, raw := range resp.Checks {
+		c, err := check.UnmarshalJSON(raw)
+		if err != nil {
+			return nil, err
+		}
+		if filter.ID != nil && c.GetID() != *filter.ID {
+			continue
+		}
+		if filter.Name != nil && c.GetName() != *filter.Name {
+			continue
+		}
+		checks = append(checks, c)
+	}
+	return checks, nil
+}
+
+// CreateCheck creates a new check and sets c.ID with the new identifier.
+// The user creating the check is the user the token belongs to, the userID is
+// ignored.
+func (s *CheckService) CreateCheck(ctx context.Context, c influxdb.CheckCreate, userID influxdb.ID) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	u, err := NewURL(s.Addr, checksPath)
+	if err != nil {
+		return err
+	}
+
+	octets, err := mergeJSON(c.Check, decodeStatus{Status: c.Status})
+	if err != nil {
+		return err
+	}
+
+	var raw json.RawMessage
+	if err := doJSONRequest(ctx, "POST", u, s.Token, s.InsecureSkipVerify, octets, &raw); err != nil {
+		return err
+	}
+
+	created, err := check.UnmarshalJSON(raw)
+	if err != nil {
+		return err
+	}
+	c.SetID(created.GetID())
+	c.SetOwnerID(created.GetOwnerID())
+	c.SetTaskID(created.GetTaskID())
+	return nil
+}
+
+// UpdateCheck updates the whole check.
+// Returns the new check state after update.
+func (s *CheckService) UpdateCheck(ctx context.Context, id influxdb.ID, c influxdb.CheckCreate) (influxdb.Check, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	u, err := NewURL(s.Addr, checkIDPath(id))
+	if err != nil {
+		return nil, err
+	}
+
+	octets, err := mergeJSON(c.Check, decodeStatus{Status: c.Status})
+	if err != nil {
+		return nil, err
+	}
+
+	var raw json.RawMessage
+	if err := doJSONRequest(ctx, "PUT", u, s.Token, s.InsecureSkipVerify, octets, &raw); err != nil {
+		return nil, err
+	}
+	return check.UnmarshalJSON(raw)
+}
+
+// PatchCheck updates a single check with changeset.
+// Returns the new check state after update.
+func (s *CheckService) PatchCheck(ctx context.Context, id influxdb.ID, upd influxdb.CheckUpdate) (influxdb.Check, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	u, err := NewURL(s.Addr, checkIDPath(id))
+	if err != nil {
+		return nil, err
+	}
+
+	octets, err := json.Marshal(upd)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw json.RawMessage
+	if err := doJSONRequest(ctx, "PATCH", u, s.Token, s.InsecureSkipVerify, octets, &raw); err != nil {
+		return nil, err
+	}
+	return check.UnmarshalJSON(raw)
+}
+
+// DeleteCheck will delete the check by id.
+func (s *CheckService) DeleteCheck(ctx context.Context, id influxdb.ID) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	u, err := NewURL(s.Addr, checkIDPath(id))
+	if err != nil {
+		return err
+	}
+	return doJSONRequest(ctx, "DELETE", u, s.Token, s.InsecureSkipVerify, nil, nil)
+}
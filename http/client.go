@@ -1,8 +1,14 @@
 package http
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
 	"net/url"
+	"sync"
 
 	"github.com/influxdata/influxdb/kit/tracing"
 )
@@ -64,6 +70,77 @@ func NewURL(addr, path string) (*url.URL, error) {
 	return u, nil
 }
 
+// doJSONRequest sends the request to the url, with body as its json payload
+// when provided, and decodes the json response into v when v is not nil.
+func doJSONRequest(ctx context.Context, method string, u *url.URL, token string, insecureSkipVerify bool, body []byte, v interface{}) error {
+	var r io.Reader
+	if body != nil {
+		r = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequest(method, u.String(), r)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	SetToken(token, req)
+
+	hc := NewClient(u.Scheme, insecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return err
+	}
+
+	if v == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// mergeJSON marshals each of the values into a json object and merges their
+// fields into a single object, the way the handlers merge a resource with its
+// status, labels and links.
+func mergeJSON(values ...interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for _, v := range values {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		b = bytes.TrimSpace(b)
+		if len(b) < 2 || b[0] != '{' || b[len(b)-1] != '}' {
+			return nil, fmt.Errorf("unable to merge non object json %s", b)
+		}
+
+		fields := bytes.TrimSpace(b[1 : len(b)-1])
+		if len(fields) == 0 {
+			continue
+		}
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		buf.Write(fields)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// InsecureSkipVerifyWarning is the warning for a client created with
+// InsecureSkipVerify enabled. NewClient does not write it, the caller that
+// enables InsecureSkipVerify logs it once through its own logger.
+const InsecureSkipVerifyWarning = "WARNING: an HTTP client was created with InsecureSkipVerify enabled, " +
+	"TLS certificates will not be verified. This should never be enabled in production."
+
 // NewClient returns an http.Client that pools connections and injects a span.
 func NewClient(scheme string, insecure bool) *traceClient {
 	hc := &traceClient{
@@ -81,6 +158,9 @@ func NewClient(scheme string, insecure bool) *traceClient {
 // traceClient always injects any opentracing trace into the client requests.
 type traceClient struct {
 	http.Client
+
+	mu            sync.Mutex
+	serverVersion *ServerVersion
 }
 
 // Do injects the trace and then performs the request.
@@ -88,5 +168,77 @@ func (c *traceClient) Do(r *http.Request) (*http.Response, error) {
 	span, _ := tracing.StartSpanFromContext(r.Context())
 	defer span.Finish()
 	tracing.InjectToHTTPRequest(span, r)
-	return c.Client.Do(r)
+
+	resp, err := c.Client.Do(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if v, ok := serverVersionFromHeader(resp.Header); ok {
+		c.mu.Lock()
+		c.serverVersion = &v
+		c.mu.Unlock()
+		setLastServerVersion(v)
+	}
+	return resp, nil
+}
+
+// ServerVersion returns the server version reported by the last response
+// received by the client that reported one.
+func (c *traceClient) ServerVersion() (ServerVersion, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.serverVersion == nil {
+		return ServerVersion{}, false
+	}
+	return *c.serverVersion, true
+}
+
+const (
+	// VersionHeader is the response header the server reports its version in.
+	VersionHeader = "X-Influxdb-Version"
+	// BuildHeader is the response header the server reports the commit it was built from in.
+	BuildHeader = "X-Influxdb-Build"
+)
+
+// ServerVersion is the version and build of a server, as reported by the
+// headers of its responses.
+type ServerVersion struct {
+	Version string
+	Build   string
+}
+
+func (v ServerVersion) String() string {
+	if v.Build == "" {
+		return v.Version
+	}
+	return fmt.Sprintf("%s (build %s)", v.Version, v.Build)
+}
+
+func serverVersionFromHeader(h http.Header) (ServerVersion, bool) {
+	v := ServerVersion{
+		Version: h.Get(VersionHeader),
+		Build:   h.Get(BuildHeader),
+	}
+	return v, v.Version != "" || v.Build != ""
+}
+
+var lastServerVersion struct {
+	sync.Mutex
+	v  ServerVersion
+	ok bool
+}
+
+func setLastServerVersion(v ServerVersion) {
+	lastServerVersion.Lock()
+	defer lastServerVersion.Unlock()
+	lastServerVersion.v, lastServerVersion.ok = v, true
+}
+
+// LastServerVersion returns the server version reported by the last response
+// received by any client created with NewClient that reported one.
+func LastServerVersion() (ServerVersion, bool) {
+	lastServerVersion.Lock()
+	defer lastServerVersion.Unlock()
+	return lastServerVersion.v, lastServerVersion.ok
 }
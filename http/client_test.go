@@ -0,0 +1,62 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewClient_ServerVersion(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/versioned" {
+			w.Header().Set(VersionHeader, "2.0.0-beta.1")
+			w.Header().Set(BuildHeader, "abc123")
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer svr.Close()
+
+	do := func(t *testing.T, hc *traceClient, path string) {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodGet, svr.URL+path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := hc.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+
+	t.Run("reports the version from the response headers", func(t *testing.T) {
+		hc := NewClient("http", false)
+		if _, ok := hc.ServerVersion(); ok {
+			t.Fatal("expected no server version before a request")
+		}
+
+		do(t, hc, "/versioned")
+
+		expected := ServerVersion{Version: "2.0.0-beta.1", Build: "abc123"}
+		v, ok := hc.ServerVersion()
+		if !ok || v != expected {
+			t.Errorf("expected server version %+v, got %+v", expected, v)
+		}
+
+		v, ok = LastServerVersion()
+		if !ok || v != expected {
+			t.Errorf("expected last server version %+v, got %+v", expected, v)
+		}
+	})
+
+	t.Run("keeps the last version when a response reports none", func(t *testing.T) {
+		hc := NewClient("http", false)
+		do(t, hc, "/versioned")
+		do(t, hc, "/unversioned")
+
+		v, ok := hc.ServerVersion()
+		if !ok || v.Version != "2.0.0-beta.1" {
+			t.Errorf("expected server version to be kept, got %+v", v)
+		}
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	http "net/http"
+	"strconv"
 	"time"
 
 	"github.com/influxdata/influxdb"
@@ -90,6 +91,10 @@ func (h *DeleteHandler) handleDelete(w http.ResponseWriter, r *http.Request) {
 		h.BucketService,
 	)
 	if err != nil {
+		if pErr, ok := predicateParseError(err); ok {
+			h.handlePredicateError(w, r, err, pErr.Position)
+			return
+		}
 		h.HandleHTTPError(ctx, err, w)
 		return
 	}
@@ -114,6 +119,15 @@ func (h *DeleteHandler) handleDelete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if dr.Predicate == nil && !confirmFullDelete(r) {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Op:   "http/handleDelete",
+			Msg:  "deleting all data in the time range requires confirmation; set confirmFullDelete=true to delete without a predicate",
+		}, w)
+		return
+	}
+
 	// send delete points request to storage
 	err = h.DeleteService.DeleteBucketRangePredicate(ctx,
 		dr.Org.ID,
@@ -134,6 +148,51 @@ func (h *DeleteHandler) handleDelete(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// confirmFullDelete reports whether the request confirms the deletion of all
+// data in the time range, which is the case for a delete without a predicate.
+func confirmFullDelete(r *http.Request) bool {
+	confirm, _ := strconv.ParseBool(r.URL.Query().Get("confirmFullDelete"))
+	return confirm
+}
+
+// predicateErrorResponse is the body of an error response for a predicate that
+// fails to parse. Position identifies the offending character in the predicate.
+type predicateErrorResponse struct {
+	Code     string `json:"code"`
+	Message  string `json:"message"`
+	Position int    `json:"position"`
+}
+
+// handlePredicateError writes the predicate parse error along with the position
+// in the predicate at which parsing failed, allowing tooling to highlight it.
+func (h *DeleteHandler) handlePredicateError(w http.ResponseWriter, r *http.Request, err error, pos int) {
+	code := influxdb.ErrorCode(err)
+	w.Header().Set(PlatformErrorCodeHeader, code)
+	res := predicateErrorResponse{
+		Code:     code,
+		Message:  err.Error(),
+		Position: pos,
+	}
+	if err := encodeResponse(r.Context(), w, http.StatusBadRequest, res); err != nil {
+		logEncodingError(h.Logger, r, err)
+	}
+}
+
+// predicateParseError returns the predicate parse error wrapped by err, if any.
+func predicateParseError(err error) (*predicate.ParseError, bool) {
+	for err != nil {
+		switch e := err.(type) {
+		case *predicate.ParseError:
+			return e, true
+		case *influxdb.Error:
+			err = e.Err
+		default:
+			return nil, false
+		}
+	}
+	return nil, false
+}
+
 func decodeDeleteRequest(ctx context.Context, r *http.Request, orgSvc influxdb.OrganizationService, bucketSvc influxdb.BucketService) (*deleteRequest, error) {
 	dr := new(deleteRequest)
 	err := json.NewDecoder(r.Body).Decode(dr)
@@ -177,6 +236,9 @@ type DeleteRequest struct {
 	Start     string `json:"start"`
 	Stop      string `json:"stop"`
 	Predicate string `json:"predicate"`
+	// ConfirmFullDelete confirms the deletion of all data in the time
+	// range, a delete without a predicate is rejected without it.
+	ConfirmFullDelete bool `json:"-"`
 }
 
 func (dr *deleteRequest) UnmarshalJSON(b []byte) error {
@@ -253,6 +315,10 @@ func (s *DeleteService) DeleteBucketRangePredicate(ctx context.Context, dr Delet
 	} else if dr.Bucket != "" {
 		params.Set("bucket", dr.Bucket)
 	}
+
+	if dr.ConfirmFullDelete {
+		params.Set("confirmFullDelete", "true")
+	}
 	req.URL.RawQuery = params.Encode()
 
 	hc := NewClient(u.Scheme, s.InsecureSkipVerify)
@@ -183,7 +183,7 @@ func TestDelete(t *testing.T) {
 			},
 		},
 		{
-			name: "no predicate delete",
+			name: "unconfirmed no predicate delete",
 			args: args{
 				queryParams: map[string][]string{
 					"org":    []string{"org1"},
@@ -224,13 +224,65 @@ func TestDelete(t *testing.T) {
 					},
 				},
 			},
+			wants: wants{
+				statusCode:  http.StatusBadRequest,
+				contentType: "application/json; charset=utf-8",
+				body: `{
+					"code": "invalid",
+					"message": "deleting all data in the time range requires confirmation; set confirmFullDelete=true to delete without a predicate"
+				  }`,
+			},
+		},
+		{
+			name: "confirmed no predicate delete",
+			args: args{
+				queryParams: map[string][]string{
+					"org":               []string{"org1"},
+					"bucket":            []string{"buck1"},
+					"confirmFullDelete": []string{"true"},
+				},
+				body: []byte(`{"start":"2009-01-01T23:00:00Z","stop":"2019-11-10T01:00:00Z"}`),
+				authorizer: &influxdb.Authorization{
+					UserID: user1ID,
+					Status: influxdb.Active,
+					Permissions: []influxdb.Permission{
+						{
+							Action: influxdb.WriteAction,
+							Resource: influxdb.Resource{
+								Type:  influxdb.BucketsResourceType,
+								ID:    influxtesting.IDPtr(influxdb.ID(2)),
+								OrgID: influxtesting.IDPtr(influxdb.ID(1)),
+							},
+						},
+					},
+				},
+			},
+			fields: fields{
+				DeleteService: mock.NewDeleteService(),
+				BucketService: &mock.BucketService{
+					FindBucketFn: func(ctx context.Context, f influxdb.BucketFilter) (*influxdb.Bucket, error) {
+						return &influxdb.Bucket{
+							ID:   influxdb.ID(2),
+							Name: "bucket1",
+						}, nil
+					},
+				},
+				OrganizationService: &mock.OrganizationService{
+					FindOrganizationF: func(ctx context.Context, f influxdb.OrganizationFilter) (*influxdb.Organization, error) {
+						return &influxdb.Organization{
+							ID:   influxdb.ID(1),
+							Name: "org1",
+						}, nil
+					},
+				},
+			},
 			wants: wants{
 				statusCode: http.StatusNoContent,
 				body:       ``,
 			},
 		},
 		{
-			name: "unsupported delete",
+			name: "delete with or",
 			args: args{
 				queryParams: map[string][]string{
 					"org":    []string{"org1"},
@@ -276,11 +328,8 @@ func TestDelete(t *testing.T) {
 				},
 			},
 			wants: wants{
-				statusCode: http.StatusBadRequest,
-				body: `{
-					"code": "invalid",
-					"message": "invalid request; error parsing request json: the logical operator OR is not supported yet at position 25"
-				  }`,
+				statusCode: http.StatusNoContent,
+				body:       ``,
 			},
 		},
 		{
@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/kit/prom"
 	"github.com/influxdata/influxdb/kit/tracing"
 	"github.com/opentracing/opentracing-go"
@@ -116,6 +117,8 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		}).Observe(duration.Seconds())
 	}(time.Now())
 
+	setServerVersionHeaders(w.Header())
+
 	switch {
 	case r.URL.Path == MetricsPath:
 		h.MetricsHandler.ServeHTTP(w, r)
@@ -130,6 +133,18 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// setServerVersionHeaders reports the version and build of the server to
+// clients for compatibility diagnostics.
+func setServerVersionHeaders(h http.Header) {
+	info := influxdb.GetBuildInfo()
+	if info.Version != "" {
+		h.Set(VersionHeader, info.Version)
+	}
+	if info.Commit != "" {
+		h.Set(BuildHeader, info.Commit)
+	}
+}
+
 func encodeResponse(ctx context.Context, w http.ResponseWriter, code int, res interface{}) error {
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(code)
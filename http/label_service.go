@@ -25,8 +25,9 @@ type LabelHandler struct {
 }
 
 const (
-	labelsPath   = "/api/v2/labels"
-	labelsIDPath = "/api/v2/labels/:id"
+	labelsPath         = "/api/v2/labels"
+	labelsIDPath       = "/api/v2/labels/:id"
+	labelsMappingsPath = "/api/v2/labels/mappings"
 )
 
 // NewLabelHandler returns a new instance of LabelHandler
@@ -41,6 +42,8 @@ func NewLabelHandler(s influxdb.LabelService, he influxdb.HTTPErrorHandler) *Lab
 	h.HandlerFunc("POST", labelsPath, h.handlePostLabel)
 	h.HandlerFunc("GET", labelsPath, h.handleGetLabels)
 
+	h.HandlerFunc("POST", labelsMappingsPath, h.handlePostLabelMappings)
+
 	h.HandlerFunc("GET", labelsIDPath, h.handleGetLabel)
 	h.HandlerFunc("PATCH", labelsIDPath, h.handlePatchLabel)
 	h.HandlerFunc("DELETE", labelsIDPath, h.handleDeleteLabel)
@@ -106,6 +109,115 @@ func decodePostLabelRequest(ctx context.Context, r *http.Request) (*postLabelReq
 	return req, req.Validate()
 }
 
+// handlePostLabelMappings is the HTTP handler for the POST /api/v2/labels/mappings route.
+// Each mapping is created independently, a failed mapping does not prevent the
+// remaining mappings in the batch from being created.
+func (h *LabelHandler) handlePostLabelMappings(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req, err := decodePostLabelMappingsRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	results := make([]labelMappingResult, 0, len(req.Mappings))
+	for _, item := range req.Mappings {
+		res := labelMappingResult{labelMappingItem: item}
+
+		m, err := item.toLabelMapping()
+		if err == nil {
+			err = h.LabelService.CreateLabelMapping(ctx, &m)
+		}
+		if err != nil {
+			res.Error = &influxdb.Error{
+				Code: influxdb.ErrorCode(err),
+				Msg:  influxdb.ErrorMessage(err),
+			}
+		}
+		results = append(results, res)
+	}
+	h.Logger.Debug("label mappings created", zap.Int("mappings", len(results)))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, labelMappingsResponse{Results: results}); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+type postLabelMappingsRequest struct {
+	Mappings []labelMappingItem
+}
+
+func decodePostLabelMappingsRequest(ctx context.Context, r *http.Request) (*postLabelMappingsRequest, error) {
+	var mappings []labelMappingItem
+	if err := json.NewDecoder(r.Body).Decode(&mappings); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "unable to decode label mappings request",
+			Err:  err,
+		}
+	}
+
+	if len(mappings) == 0 {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "at least 1 label mapping must be provided",
+		}
+	}
+
+	return &postLabelMappingsRequest{
+		Mappings: mappings,
+	}, nil
+}
+
+// labelMappingItem is a single mapping of a batch. The IDs are kept as
+// strings so an invalid ID fails its own item rather than the whole batch.
+type labelMappingItem struct {
+	LabelID      string                `json:"labelID"`
+	ResourceID   string                `json:"resourceID"`
+	ResourceType influxdb.ResourceType `json:"resourceType"`
+}
+
+func newLabelMappingItem(m influxdb.LabelMapping) labelMappingItem {
+	return labelMappingItem{
+		LabelID:      m.LabelID.String(),
+		ResourceID:   m.ResourceID.String(),
+		ResourceType: m.ResourceType,
+	}
+}
+
+func (l labelMappingItem) toLabelMapping() (influxdb.LabelMapping, error) {
+	m := influxdb.LabelMapping{ResourceType: l.ResourceType}
+	if l.LabelID != "" {
+		if err := m.LabelID.DecodeFromString(l.LabelID); err != nil {
+			return m, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "invalid label id",
+				Err:  err,
+			}
+		}
+	}
+	if l.ResourceID != "" {
+		if err := m.ResourceID.DecodeFromString(l.ResourceID); err != nil {
+			return m, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "invalid resource id",
+				Err:  err,
+			}
+		}
+	}
+	return m, m.Validate()
+}
+
+type labelMappingResult struct {
+	labelMappingItem
+	Error *influxdb.Error `json:"error,omitempty"`
+}
+
+type labelMappingsResponse struct {
+	Results []labelMappingResult `json:"results"`
+}
+
 // handleGetLabels is the HTTP handler for the GET /api/v2/labels route.
 func (h *LabelHandler) handleGetLabels(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -725,6 +837,59 @@ func (s *LabelService) CreateLabelMapping(ctx context.Context, m *influxdb.Label
 	return nil
 }
 
+// CreateLabelMappings creates a batch of label mappings in a single request. The
+// returned errors are index aligned with the provided mappings, a nil entry
+// indicates the mapping was created.
+func (s *LabelService) CreateLabelMappings(ctx context.Context, mappings []influxdb.LabelMapping) ([]error, error) {
+	u, err := NewURL(s.Addr, labelsMappingsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]labelMappingItem, 0, len(mappings))
+	for _, m := range mappings {
+		items = append(items, newLabelMappingItem(m))
+	}
+
+	octets, err := json.Marshal(items)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", u.String(), bytes.NewReader(octets))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	SetToken(s.Token, req)
+
+	hc := NewClient(u.Scheme, s.InsecureSkipVerify)
+
+	resp, err := hc.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return nil, err
+	}
+
+	var r labelMappingsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return nil, err
+	}
+
+	errs := make([]error, len(r.Results))
+	for i, res := range r.Results {
+		if res.Error != nil {
+			errs[i] = res.Error
+		}
+	}
+	return errs, nil
+}
+
 // UpdateLabel updates a label and returns the updated label.
 func (s *LabelService) UpdateLabel(ctx context.Context, id influxdb.ID, upd influxdb.LabelUpdate) (*influxdb.Label, error) {
 	u, err := NewURL(s.Addr, labelIDPath(id))
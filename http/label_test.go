@@ -8,6 +8,7 @@ import (
 	"io/ioutil"
 	http "net/http"
 	"net/http/httptest"
+	"reflect"
 	"testing"
 
 	platform "github.com/influxdata/influxdb"
@@ -340,6 +341,162 @@ func TestService_handlePostLabel(t *testing.T) {
 	}
 }
 
+func TestService_handlePostLabelMappings(t *testing.T) {
+	type fields struct {
+		LabelService platform.LabelService
+	}
+	type args struct {
+		mappings []labelMappingItem
+	}
+	type wants struct {
+		statusCode  int
+		contentType string
+		body        string
+		created     []platform.LabelMapping
+	}
+
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		wants  wants
+	}{
+		{
+			name: "mixed batch reports per item errors",
+			args: args{
+				mappings: []labelMappingItem{
+					{
+						LabelID:      "020f755c3c082000",
+						ResourceID:   "020f755c3c082001",
+						ResourceType: platform.BucketsResourceType,
+					},
+					{
+						ResourceID:   "020f755c3c082001",
+						ResourceType: platform.BucketsResourceType,
+					},
+					{
+						LabelID:      "020f755c3c082002",
+						ResourceID:   "020f755c3c082003",
+						ResourceType: platform.DashboardsResourceType,
+					},
+					{
+						LabelID:      "not an id",
+						ResourceID:   "020f755c3c082003",
+						ResourceType: platform.DashboardsResourceType,
+					},
+				},
+			},
+			wants: wants{
+				statusCode:  http.StatusOK,
+				contentType: "application/json; charset=utf-8",
+				body: `
+{
+  "results": [
+    {
+      "labelID": "020f755c3c082000",
+      "resourceID": "020f755c3c082001",
+      "resourceType": "buckets"
+    },
+    {
+      "labelID": "",
+      "resourceID": "020f755c3c082001",
+      "resourceType": "buckets",
+      "error": {
+        "code": "invalid",
+        "message": "label id is required"
+      }
+    },
+    {
+      "labelID": "020f755c3c082002",
+      "resourceID": "020f755c3c082003",
+      "resourceType": "dashboards",
+      "error": {
+        "code": "not found",
+        "message": "label not found"
+      }
+    },
+    {
+      "labelID": "not an id",
+      "resourceID": "020f755c3c082003",
+      "resourceType": "dashboards",
+      "error": {
+        "code": "invalid",
+        "message": "invalid label id"
+      }
+    }
+  ]
+}
+`,
+				created: []platform.LabelMapping{
+					{
+						LabelID:      platformtesting.MustIDBase16("020f755c3c082000"),
+						ResourceID:   platformtesting.MustIDBase16("020f755c3c082001"),
+						ResourceType: platform.BucketsResourceType,
+					},
+				},
+			},
+		},
+		{
+			name: "empty batch is invalid",
+			args: args{
+				mappings: []labelMappingItem{},
+			},
+			wants: wants{
+				statusCode:  http.StatusBadRequest,
+				contentType: "application/json; charset=utf-8",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var created []platform.LabelMapping
+			svc := &mock.LabelService{
+				CreateLabelMappingFn: func(ctx context.Context, m *platform.LabelMapping) error {
+					if m.ResourceType == platform.DashboardsResourceType {
+						return &platform.Error{
+							Code: platform.ENotFound,
+							Msg:  "label not found",
+						}
+					}
+					created = append(created, *m)
+					return nil
+				},
+			}
+			h := NewLabelHandler(svc, ErrorHandler(0))
+
+			b, err := json.Marshal(tt.args.mappings)
+			if err != nil {
+				t.Fatalf("failed to marshal label mappings: %v", err)
+			}
+
+			r := httptest.NewRequest("POST", "http://any.url/api/v2/labels/mappings", bytes.NewReader(b))
+			w := httptest.NewRecorder()
+
+			h.ServeHTTP(w, r)
+
+			res := w.Result()
+			content := res.Header.Get("Content-Type")
+			body, _ := ioutil.ReadAll(res.Body)
+
+			if res.StatusCode != tt.wants.statusCode {
+				t.Errorf("%q. handlePostLabelMappings() = %v, want %v", tt.name, res.StatusCode, tt.wants.statusCode)
+			}
+			if tt.wants.contentType != "" && content != tt.wants.contentType {
+				t.Errorf("%q. handlePostLabelMappings() = %v, want %v", tt.name, content, tt.wants.contentType)
+			}
+			if tt.wants.body != "" {
+				if eq, diff, err := jsonEqual(string(body), tt.wants.body); err != nil || !eq {
+					t.Errorf("%q. handlePostLabelMappings() = ***%v***", tt.name, diff)
+				}
+			}
+			if !reflect.DeepEqual(created, tt.wants.created) {
+				t.Errorf("%q. handlePostLabelMappings() created %v, want %v", tt.name, created, tt.wants.created)
+			}
+		})
+	}
+}
+
 func TestService_handleDeleteLabel(t *testing.T) {
 	type fields struct {
 		LabelService platform.LabelService
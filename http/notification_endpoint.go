@@ -6,9 +6,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"path"
 
 	"github.com/influxdata/influxdb"
 	pctx "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/kit/tracing"
 	"github.com/influxdata/influxdb/notification/endpoint"
 	"github.com/julienschmidt/httprouter"
 	"go.uber.org/zap"
@@ -588,3 +590,253 @@ func (h *NotificationEndpointHandler) handleDeleteNotificationEndpoint(w http.Re
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// NotificationEndpointService connects to Influx via HTTP using tokens to manage notification endpoints.
+type NotificationEndpointService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+
+	*UserResourceMappingService
+	*OrganizationService
+}
+
+var _ influxdb.NotificationEndpointService = (*NotificationEndpointService)(nil)
+
+// NewNotificationEndpointService returns a client for the notification endpoint handlers, with
+// the user resource mapping and organization services the influxdb.NotificationEndpointService requires.
+func NewNotificationEndpointService(addr, token string, insecureSkipVerify bool) *NotificationEndpointService {
+	return &NotificationEndpointService{
+		Addr:               addr,
+		Token:              token,
+		InsecureSkipVerify: insecureSkipVerify,
+		UserResourceMappingService: &UserResourceMappingService{
+			Addr:               addr,
+			Token:              token,
+			InsecureSkipVerify: insecureSkipVerify,
+		},
+		OrganizationService: &OrganizationService{
+			Addr:               addr,
+			Token:              token,
+			InsecureSkipVerify: insecureSkipVerify,
+		},
+	}
+}
+
+func notificationEndpointIDPath(id influxdb.ID) string {
+	return path.Join(notificationEndpointsPath, id.String())
+}
+
+// FindNotificationEndpointByID returns a single notification endpoint by ID.
+func (s *NotificationEndpointService) FindNotificationEndpointByID(ctx context.Context, id influxdb.ID) (influxdb.NotificationEndpoint, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	u, err := NewURL(s.Addr, notificationEndpointIDPath(id))
+	if err != nil {
+		return nil, err
+	}
+
+	var raw json.RawMessage
+	if err := doJSONRequest(ctx, "GET", u, s.Token, s.InsecureSkipVerify, nil, &raw); err != nil {
+		return nil, err
+	}
+	return endpoint.UnmarshalJSON(raw)
+}
+
+// FindNotificationEndpoints returns a list of notification endpoints that match filter and the total count of matching notification endpoints.
+// Additional options provide pagination & sorting. Without them every page of notification endpoints is read.
+func (s *NotificationEndpointService) FindNotificationEndpoints(ctx context.Context, filter influxdb.NotificationEndpointFilter, opt ...influxdb.FindOptions) ([]influxdb.NotificationEndpoint, int, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if len(opt) > 0 {
+		edps, err := s.findNotificationEndpoints(ctx, filter, opt[0])
+		if err != nil {
+			return nil, 0, err
+		}
+		return edps, len(edps), nil
+	}
+
+	var edps []influxdb.NotificationEndpoint
+	for offset := 0; ; offset += influxdb.MaxPageSize {
+		page, err := s.findNotificationEndpoints(ctx, filter, influxdb.FindOptions{
+			Limit:  influxdb.MaxPageSize,
+			Offset: offset,
+		})
+		if err != nil {
+			return nil, 0, err
+		}
+		edps = append(edps, page...)
+		if len(page) < influxdb.MaxPageSize {
+			break
+		}
+	}
+	return edps, len(edps), nil
+}
+
+func (s *NotificationEndpointService) findNotificationEndpoints(ctx context.Context, filter influxdb.NotificationEndpointFilter, opt influxdb.FindOptions) ([]influxdb.NotificationEndpoint, error) {
+	u, err := NewURL(s.Addr, notificationEndpointsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	query := u.Query()
+	if filter.OrgID != nil {
+		query.Add("orgID", filter.OrgID.String())
+	}
+	if filter.Org != nil {
+		query.Add("org", *filter.Org)
+	}
+	for k, vs := range opt.QueryParams() {
+		for _, v := range vs {
+			query.Add(k, v)
+		}
+	}
+	u.RawQuery = query.Encode()
+
+	var resp struct {
+		NotificationEndpoints []json.RawMessage `json:"notificationEndpoints"`
+	}
+	if err := doJSONRequest(ctx, "GET", u, s.Token, s.InsecureSkipVerify, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	edps := make([]influxdb.NotificationEndpoint, 0, len(resp.NotificationEndpoints))
+	for _, raw := range resp.NotificationEndpoints {
+		edp, err := endpoint.UnmarshalJSON(raw)
+		if err != nil {
+			return nil, err
+		}
+		edps = append(edps, edp)
+	}
+	return edps, nil
+}
+
+// CreateNotificationEndpoint creates a new notification endpoint and sets edp.ID with the new identifier.
+// The user creating the notification endpoint is the user the token belongs to, the userID is ignored.
+func (s *NotificationEndpointService) CreateNotificationEndpoint(ctx context.Context, edp influxdb.NotificationEndpoint, userID influxdb.ID) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	u, err := NewURL(s.Addr, notificationEndpointsPath)
+	if err != nil {
+		return err
+	}
+
+	octets, err := encodeNotificationEndpoint(edp)
+	if err != nil {
+		return err
+	}
+
+	var raw json.RawMessage
+	if err := doJSONRequest(ctx, "POST", u, s.Token, s.InsecureSkipVerify, octets, &raw); err != nil {
+		return err
+	}
+
+	created, err := endpoint.UnmarshalJSON(raw)
+	if err != nil {
+		return err
+	}
+	edp.SetID(created.GetID())
+	edp.BackfillSecretKeys()
+	return nil
+}
+
+// UpdateNotificationEndpoint updates a single notification endpoint.
+// Returns the new notification endpoint after update.
+func (s *NotificationEndpointService) UpdateNotificationEndpoint(ctx context.Context, id influxdb.ID, edp influxdb.NotificationEndpoint, userID influxdb.ID) (influxdb.NotificationEndpoint, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	u, err := NewURL(s.Addr, notificationEndpointIDPath(id))
+	if err != nil {
+		return nil, err
+	}
+
+	octets, err := encodeNotificationEndpoint(edp)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw json.RawMessage
+	if err := doJSONRequest(ctx, "PUT", u, s.Token, s.InsecureSkipVerify, octets, &raw); err != nil {
+		return nil, err
+	}
+	return endpoint.UnmarshalJSON(raw)
+}
+
+// PatchNotificationEndpoint updates a single notification endpoint with changeset.
+// Returns the new notification endpoint state after update.
+func (s *NotificationEndpointService) PatchNotificationEndpoint(ctx context.Context, id influxdb.ID, upd influxdb.NotificationEndpointUpdate) (influxdb.NotificationEndpoint, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	u, err := NewURL(s.Addr, notificationEndpointIDPath(id))
+	if err != nil {
+		return nil, err
+	}
+
+	octets, err := json.Marshal(upd)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw json.RawMessage
+	if err := doJSONRequest(ctx, "PATCH", u, s.Token, s.InsecureSkipVerify, octets, &raw); err != nil {
+		return nil, err
+	}
+	return endpoint.UnmarshalJSON(raw)
+}
+
+// DeleteNotificationEndpoint removes a notification endpoint by ID. The handler deletes
+// the secrets of the notification endpoint, so no secret fields are returned for further deletion.
+func (s *NotificationEndpointService) DeleteNotificationEndpoint(ctx context.Context, id influxdb.ID) ([]influxdb.SecretField, influxdb.ID, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	u, err := NewURL(s.Addr, notificationEndpointIDPath(id))
+	if err != nil {
+		return nil, 0, err
+	}
+	return nil, 0, doJSONRequest(ctx, "DELETE", u, s.Token, s.InsecureSkipVerify, nil, nil)
+}
+
+// encodeNotificationEndpoint encodes the notification endpoint for the handlers. A secret
+// field encodes only its key, the values of the secret fields without a key yet are
+// encoded in their place so the handler is able to store them as secrets.
+func encodeNotificationEndpoint(edp influxdb.NotificationEndpoint) ([]byte, error) {
+	values := make(map[string]influxdb.SecretField)
+	switch e := edp.(type) {
+	case *endpoint.HTTP:
+		values["token"] = e.Token
+		values["username"] = e.Username
+		values["password"] = e.Password
+	case *endpoint.PagerDuty:
+		values["routingKey"] = e.RoutingKey
+	case *endpoint.Slack:
+		values["token"] = e.Token
+	}
+
+	b, err := json.Marshal(edp)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+
+	for k, fld := range values {
+		if fld.Key != "" || fld.Value == nil {
+			continue
+		}
+		v, err := json.Marshal(*fld.Value)
+		if err != nil {
+			return nil, err
+		}
+		fields[k] = v
+	}
+	return json.Marshal(fields)
+}
@@ -1174,3 +1174,57 @@ func TestService_handlePostNotificationEndpointOwner(t *testing.T) {
 		})
 	}
 }
+
+func TestNotificationEndpointService_CreateNotificationEndpoint(t *testing.T) {
+	secrets := make(map[string]string)
+	backend := NewMockNotificationEndpointBackend()
+	backend.NotificationEndpointService = &mock.NotificationEndpointService{
+		CreateNotificationEndpointF: func(ctx context.Context, edp influxdb.NotificationEndpoint, userID influxdb.ID) error {
+			edp.SetID(influxTesting.MustIDBase16("020f755c3c082000"))
+			edp.BackfillSecretKeys()
+			return nil
+		},
+	}
+	backend.SecretService = &mock.SecretService{
+		PutSecretFn: func(ctx context.Context, orgID influxdb.ID, k string, v string) error {
+			secrets[orgID.String()+"-"+k] = v
+			return nil
+		},
+	}
+	h := NewNotificationEndpointHandler(backend)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &influxdb.Session{UserID: user1ID}))
+		h.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	svc := NewNotificationEndpointService(server.URL, "tok", false)
+
+	token := "slack token"
+	edp := &endpoint.Slack{
+		Base: endpoint.Base{
+			Name:   "slack",
+			OrgID:  influxTesting.MustIDBase16("6f626f7274697320"),
+			Status: influxdb.Active,
+		},
+		URL:   "https://hooks.slack.com",
+		Token: influxdb.SecretField{Value: &token},
+	}
+	if err := svc.CreateNotificationEndpoint(context.Background(), edp, user1ID); err != nil {
+		t.Fatalf("unexpected error creating notification endpoint: %v", err)
+	}
+
+	if edp.ID != influxTesting.MustIDBase16("020f755c3c082000") {
+		t.Errorf("expected the id of the created endpoint to be set, got %s", edp.ID)
+	}
+	if edp.Token.Key != "020f755c3c082000-token" {
+		t.Errorf("expected the token secret key to be set, got %q", edp.Token.Key)
+	}
+
+	expected := map[string]string{
+		"6f626f7274697320-020f755c3c082000-token": "slack token",
+	}
+	if diff := cmp.Diff(expected, secrets); diff != "" {
+		t.Errorf("secrets are different ***%s***", diff)
+	}
+}
@@ -6,9 +6,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"path"
 
 	"github.com/influxdata/influxdb"
 	pctx "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/kit/tracing"
 	"github.com/influxdata/influxdb/notification/rule"
 	"github.com/julienschmidt/httprouter"
 	"go.uber.org/zap"
@@ -683,3 +685,212 @@ func (h *NotificationRuleHandler) handleDeleteNotificationRule(w http.ResponseWr
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// NotificationRuleService connects to Influx via HTTP using tokens to manage notification rules.
+type NotificationRuleService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+
+	*UserResourceMappingService
+	*OrganizationService
+}
+
+var _ influxdb.NotificationRuleStore = (*NotificationRuleService)(nil)
+
+// NewNotificationRuleService returns a client for the notification rule handlers, with the
+// user resource mapping and organization services the influxdb.NotificationRuleStore requires.
+func NewNotificationRuleService(addr, token string, insecureSkipVerify bool) *NotificationRuleService {
+	return &NotificationRuleService{
+		Addr:               addr,
+		Token:              token,
+		InsecureSkipVerify: insecureSkipVerify,
+		UserResourceMappingService: &UserResourceMappingService{
+			Addr:               addr,
+			Token:              token,
+			InsecureSkipVerify: insecureSkipVerify,
+		},
+		OrganizationService: &OrganizationService{
+			Addr:               addr,
+			Token:              token,
+			InsecureSkipVerify: insecureSkipVerify,
+		},
+	}
+}
+
+func notificationRuleIDPath(id influxdb.ID) string {
+	return path.Join(notificationRulesPath, id.String())
+}
+
+// FindNotificationRuleByID returns a single notification rule by ID.
+func (s *NotificationRuleService) FindNotificationRuleByID(ctx context.Context, id influxdb.ID) (influxdb.NotificationRule, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	u, err := NewURL(s.Addr, notificationRuleIDPath(id))
+	if err != nil {
+		return nil, err
+	}
+
+	var raw json.RawMessage
+	if err := doJSONRequest(ctx, "GET", u, s.Token, s.InsecureSkipVerify, nil, &raw); err != nil {
+		return nil, err
+	}
+	return rule.UnmarshalJSON(raw)
+}
+
+// FindNotificationRules returns a list of notification rules that match filter and the total count of matching notification rules.
+// Additional options provide pagination & sorting. Without them every page of notification rules is read.
+func (s *NotificationRuleService) FindNotificationRules(ctx context.Context, filter influxdb.NotificationRuleFilter, opt ...influxdb.FindOptions) ([]influxdb.NotificationRule, int, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if len(opt) > 0 {
+		nrs, err := s.findNotificationRules(ctx, filter, opt[0])
+		if err != nil {
+			return nil, 0, err
+		}
+		return nrs, len(nrs), nil
+	}
+
+	var nrs []influxdb.NotificationRule
+	for offset := 0; ; offset += influxdb.MaxPageSize {
+		page, err := s.findNotificationRules(ctx, filter, influxdb.FindOptions{
+			Limit:  influxdb.MaxPageSize,
+			Offset: offset,
+		})
+		if err != nil {
+			return nil, 0, err
+		}
+		nrs = append(nrs, page...)
+		if len(page) < influxdb.MaxPageSize {
+			break
+		}
+	}
+	return nrs, len(nrs), nil
+}
+
+func (s *NotificationRuleService) findNotificationRules(ctx context.Context, filter influxdb.NotificationRuleFilter, opt influxdb.FindOptions) ([]influxdb.NotificationRule, error) {
+	u, err := NewURL(s.Addr, notificationRulesPath)
+	if err != nil {
+		return nil, err
+	}
+
+	query := u.Query()
+	for k, vs := range filter.QueryParams() {
+		for _, v := range vs {
+			query.Add(k, v)
+		}
+	}
+	for k, vs := range opt.QueryParams() {
+		for _, v := range vs {
+			query.Add(k, v)
+		}
+	}
+	u.RawQuery = query.Encode()
+
+	var resp struct {
+		NotificationRules []json.RawMessage `json:"notificationRules"`
+	}
+	if err := doJSONRequest(ctx, "GET", u, s.Token, s.InsecureSkipVerify, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	nrs := make([]influxdb.NotificationRule, 0, len(resp.NotificationRules))
+	for _, raw := range resp.NotificationRules {
+		nr, err := rule.UnmarshalJSON(raw)
+		if err != nil {
+			return nil, err
+		}
+		nrs = append(nrs, nr)
+	}
+	return nrs, nil
+}
+
+// CreateNotificationRule creates a new notification rule and sets nr.ID with the new identifier.
+// The user creating the notification rule is the user the token belongs to, the userID is ignored.
+func (s *NotificationRuleService) CreateNotificationRule(ctx context.Context, nr influxdb.NotificationRuleCreate, userID influxdb.ID) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	u, err := NewURL(s.Addr, notificationRulesPath)
+	if err != nil {
+		return err
+	}
+
+	octets, err := mergeJSON(nr.NotificationRule, statusDecode{Status: &nr.Status})
+	if err != nil {
+		return err
+	}
+
+	var raw json.RawMessage
+	if err := doJSONRequest(ctx, "POST", u, s.Token, s.InsecureSkipVerify, octets, &raw); err != nil {
+		return err
+	}
+
+	created, err := rule.UnmarshalJSON(raw)
+	if err != nil {
+		return err
+	}
+	nr.SetID(created.GetID())
+	nr.SetOwnerID(created.GetOwnerID())
+	return nil
+}
+
+// UpdateNotificationRule updates a single notification rule.
+// Returns the new notification rule after update.
+func (s *NotificationRuleService) UpdateNotificationRule(ctx context.Context, id influxdb.ID, nr influxdb.NotificationRuleCreate, userID influxdb.ID) (influxdb.NotificationRule, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	u, err := NewURL(s.Addr, notificationRuleIDPath(id))
+	if err != nil {
+		return nil, err
+	}
+
+	octets, err := mergeJSON(nr.NotificationRule, statusDecode{Status: &nr.Status})
+	if err != nil {
+		return nil, err
+	}
+
+	var raw json.RawMessage
+	if err := doJSONRequest(ctx, "PUT", u, s.Token, s.InsecureSkipVerify, octets, &raw); err != nil {
+		return nil, err
+	}
+	return rule.UnmarshalJSON(raw)
+}
+
+// PatchNotificationRule updates a single notification rule with changeset.
+// Returns the new notification rule state after update.
+func (s *NotificationRuleService) PatchNotificationRule(ctx context.Context, id influxdb.ID, upd influxdb.NotificationRuleUpdate) (influxdb.NotificationRule, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	u, err := NewURL(s.Addr, notificationRuleIDPath(id))
+	if err != nil {
+		return nil, err
+	}
+
+	octets, err := json.Marshal(upd)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw json.RawMessage
+	if err := doJSONRequest(ctx, "PATCH", u, s.Token, s.InsecureSkipVerify, octets, &raw); err != nil {
+		return nil, err
+	}
+	return rule.UnmarshalJSON(raw)
+}
+
+// DeleteNotificationRule removes a notification rule by ID.
+func (s *NotificationRuleService) DeleteNotificationRule(ctx context.Context, id influxdb.ID) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	u, err := NewURL(s.Addr, notificationRuleIDPath(id))
+	if err != nil {
+		return err
+	}
+	return doJSONRequest(ctx, "DELETE", u, s.Token, s.InsecureSkipVerify, nil, nil)
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"mime"
 	"net/http"
+	"strings"
 
 	"github.com/go-chi/chi"
 	"github.com/go-chi/chi/middleware"
@@ -144,18 +146,16 @@ func (s *HandlerPkg) applyPkg(w http.ResponseWriter, r *http.Request) {
 }
 
 func decodeApplyReq(r *http.Request) (ReqApplyPkg, error) {
-	var (
-		reqBody  ReqApplyPkg
-		encoding pkger.Encoding
-		err      error
-	)
+	encoding, err := pkgEncodingFromContentType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return ReqApplyPkg{}, err
+	}
 
-	switch contentType := r.Header.Get("Content-Type"); contentType {
-	case "text/yml", "application/x-yaml":
-		encoding = pkger.EncodingYAML
+	var reqBody ReqApplyPkg
+	switch encoding {
+	case pkger.EncodingYAML:
 		err = yaml.NewDecoder(r.Body).Decode(&reqBody)
 	default:
-		encoding = pkger.EncodingJSON
 		err = json.NewDecoder(r.Body).Decode(&reqBody)
 	}
 	if err != nil {
@@ -165,6 +165,41 @@ func decodeApplyReq(r *http.Request) (ReqApplyPkg, error) {
 	return reqBody, nil
 }
 
+// pkgYAMLContentTypes are the media types a yaml request body may be sent with.
+var pkgYAMLContentTypes = []string{"application/x-yaml", "application/yaml", "text/yaml", "text/x-yaml", "text/yml"}
+
+// pkgEncodingFromContentType infers the encoding of a request body from its
+// Content-Type. A request without a Content-Type is treated as json.
+func pkgEncodingFromContentType(contentType string) (pkger.Encoding, error) {
+	if contentType == "" {
+		return pkger.EncodingJSON, nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return pkger.EncodingUnknown, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("invalid Content-Type %q", contentType),
+			Err:  err,
+		}
+	}
+
+	if mediaType == "application/json" {
+		return pkger.EncodingJSON, nil
+	}
+	for _, t := range pkgYAMLContentTypes {
+		if mediaType == t {
+			return pkger.EncodingYAML, nil
+		}
+	}
+
+	return pkger.EncodingUnknown, &influxdb.Error{
+		Code: influxdb.EInvalid,
+		Msg: fmt.Sprintf("unsupported Content-Type %q; must be application/json or one of: %s",
+			mediaType, strings.Join(pkgYAMLContentTypes, ", ")),
+	}
+}
+
 func (s *HandlerPkg) encResp(ctx context.Context, w http.ResponseWriter, code int, res interface{}) {
 	w.WriteHeader(code)
 	enc := json.NewEncoder(w)
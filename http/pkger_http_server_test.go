@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"testing"
@@ -80,6 +81,10 @@ func TestPkgerHTTPServer(t *testing.T) {
 					name:        "app json",
 					contentType: "application/json",
 				},
+				{
+					name:        "app json with charset",
+					contentType: "application/json; charset=utf-8",
+				},
 				{
 					name: "defaults json when no content type",
 				},
@@ -142,6 +147,14 @@ func TestPkgerHTTPServer(t *testing.T) {
 					name:        "text yml",
 					contentType: "text/yml",
 				},
+				{
+					name:        "text yaml with charset",
+					contentType: "text/yaml; charset=utf-8",
+				},
+				{
+					name:        "app yaml",
+					contentType: "application/yaml",
+				},
 			}
 
 			for _, tt := range tests {
@@ -183,6 +196,24 @@ func TestPkgerHTTPServer(t *testing.T) {
 				t.Run(tt.name, fn)
 			}
 		})
+
+		t.Run("rejects unsupported content type", func(t *testing.T) {
+			svc := &fakeSVC{
+				DryRunFn: func(ctx context.Context, orgID influxdb.ID, pkg *pkger.Pkg) (pkger.Summary, pkger.Diff, error) {
+					return pkger.Summary{}, pkger.Diff{}, errors.New("should not be called with an unsupported content type")
+				},
+			}
+
+			pkgHandler := fluxTTP.NewHandlerPkg(fluxTTP.ErrorHandler(0), svc)
+			svr := newMountedHandler(pkgHandler)
+
+			body := newReqApplyYMLBody(t, influxdb.ID(9000), true)
+
+			testttp.Post("/api/v2/packages/apply", body).
+				Headers("Content-Type", "text/plain").
+				Do(svr).
+				ExpectStatus(t, http.StatusBadRequest)
+		})
 	})
 
 	t.Run("apply a pkg", func(t *testing.T) {
@@ -316,7 +347,7 @@ func (f *fakeSVC) CreatePkg(ctx context.Context, setters ...pkger.CreatePkgSetFn
 	panic("not implemented")
 }
 
-func (f *fakeSVC) DryRun(ctx context.Context, orgID influxdb.ID, pkg *pkger.Pkg) (pkger.Summary, pkger.Diff, error) {
+func (f *fakeSVC) DryRun(ctx context.Context, orgID influxdb.ID, pkg *pkger.Pkg, opts ...pkger.ApplyOptFn) (pkger.Summary, pkger.Diff, error) {
 	if f.DryRunFn == nil {
 		panic("not implemented")
 	}
@@ -324,7 +355,7 @@ func (f *fakeSVC) DryRun(ctx context.Context, orgID influxdb.ID, pkg *pkger.Pkg)
 	return f.DryRunFn(ctx, orgID, pkg)
 }
 
-func (f *fakeSVC) Apply(ctx context.Context, orgID influxdb.ID, pkg *pkger.Pkg) (pkger.Summary, error) {
+func (f *fakeSVC) Apply(ctx context.Context, orgID influxdb.ID, pkg *pkger.Pkg, opts ...pkger.ApplyOptFn) (pkger.Summary, error) {
 	if f.ApplyFn == nil {
 		panic("not implemented")
 	}
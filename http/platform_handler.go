@@ -30,6 +30,10 @@ func NewPlatformHandler(b *APIBackend, opts ...APIHandlerOptFn) *PlatformHandler
 	h.SessionService = b.SessionService
 	h.SessionRenewDisabled = b.SessionRenewDisabled
 	h.UserService = b.UserService
+	h.TokenHeader = b.TokenHeader
+	if b.AuthorizationCacheSize > 0 {
+		h.AuthorizationCache = NewAuthorizationCache(b.AuthorizationCacheSize, b.AuthorizationCacheTTL)
+	}
 
 	h.RegisterNoAuthRoute("GET", "/api/v2")
 	h.RegisterNoAuthRoute("POST", "/api/v2/signin")
@@ -5,14 +5,18 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"path"
+	"time"
 
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/csv"
+	"github.com/influxdata/flux/iocounter"
 	"github.com/influxdata/flux/lang"
 	"github.com/influxdata/flux/repl"
 	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/check"
 	"github.com/influxdata/influxdb/query"
 	"github.com/influxdata/influxdb/query/influxql"
 	"github.com/julienschmidt/httprouter"
@@ -112,8 +116,15 @@ type SourceHandler struct {
 	// TODO(desa): this was done so in order to remove an import cycle and to allow
 	// for http mocking.
 	NewQueryService func(s *platform.Source) (query.ProxyQueryService, error)
+
+	// healthTimeout bounds the health check of an external source.
+	healthTimeout time.Duration
 }
 
+// defaultSourceHealthTimeout is how long the health check of an external
+// source waits for its query service before the source is reported unhealthy.
+const defaultSourceHealthTimeout = 5 * time.Second
+
 // NewSourceHandler returns a new instance of SourceHandler.
 func NewSourceHandler(b *SourceBackend) *SourceHandler {
 	h := &SourceHandler{
@@ -125,6 +136,8 @@ func NewSourceHandler(b *SourceBackend) *SourceHandler {
 		LabelService:    b.LabelService,
 		BucketService:   b.BucketService,
 		NewQueryService: b.NewQueryService,
+
+		healthTimeout: defaultSourceHealthTimeout,
 	}
 
 	h.HandlerFunc("POST", "/api/v2/sources", h.handlePostSource)
@@ -143,15 +156,14 @@ func NewSourceHandler(b *SourceBackend) *SourceHandler {
 func decodeSourceQueryRequest(r *http.Request) (*query.ProxyRequest, error) {
 	// starts here
 	request := struct {
-		Spec           *flux.Spec  `json:"spec"`
-		Query          string      `json:"query"`
-		Type           string      `json:"type"`
-		DB             string      `json:"db"`
-		RP             string      `json:"rp"`
-		Cluster        string      `json:"cluster"`
-		OrganizationID platform.ID `json:"organizationID"`
-		// TODO(desa): support influxql dialect
-		Dialect csv.Dialect `json:"dialect"`
+		Spec           *flux.Spec      `json:"spec"`
+		Query          string          `json:"query"`
+		Type           string          `json:"type"`
+		DB             string          `json:"db"`
+		RP             string          `json:"rp"`
+		Cluster        string          `json:"cluster"`
+		OrganizationID platform.ID     `json:"organizationID"`
+		Dialect        json.RawMessage `json:"dialect"`
 	}{}
 
 	err := json.NewDecoder(r.Body).Decode(&request)
@@ -159,8 +171,13 @@ func decodeSourceQueryRequest(r *http.Request) (*query.ProxyRequest, error) {
 		return nil, err
 	}
 
+	dialect, err := decodeSourceQueryDialect(request.Type, request.Dialect)
+	if err != nil {
+		return nil, err
+	}
+
 	req := &query.ProxyRequest{}
-	req.Dialect = request.Dialect
+	req.Dialect = dialect
 
 	req.Request.OrganizationID = request.OrganizationID
 
@@ -184,9 +201,78 @@ func decodeSourceQueryRequest(r *http.Request) (*query.ProxyRequest, error) {
 		return nil, fmt.Errorf("compiler type not supported")
 	}
 
+	if err := validateSourceQueryDialect(request.Type, dialect); err != nil {
+		return nil, err
+	}
+
 	return req, nil
 }
 
+// decodeSourceQueryDialect decodes the dialect of a source query by its type.
+// When no type is provided the dialect defaults to the one of the compiler,
+// the influxql dialect for influxql queries and the flux csv dialect otherwise.
+func decodeSourceQueryDialect(compilerType string, raw json.RawMessage) (flux.Dialect, error) {
+	if len(raw) == 0 {
+		if compilerType == influxql.CompilerType {
+			return &influxql.Dialect{Encoding: influxql.JSON}, nil
+		}
+		return csv.Dialect{}, nil
+	}
+
+	var typ struct {
+		Type flux.DialectType `json:"type"`
+	}
+	if err := json.Unmarshal(raw, &typ); err != nil {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "invalid dialect",
+			Err:  err,
+		}
+	}
+
+	if typ.Type == "" && compilerType == influxql.CompilerType {
+		typ.Type = influxql.DialectType
+	}
+
+	switch typ.Type {
+	case "", csv.DialectType:
+		var d csv.Dialect
+		if err := json.Unmarshal(raw, &d); err != nil {
+			return nil, &platform.Error{
+				Code: platform.EInvalid,
+				Msg:  "invalid csv dialect",
+				Err:  err,
+			}
+		}
+		return d, nil
+	case influxql.DialectType:
+		return &influxql.Dialect{Encoding: influxql.JSON}, nil
+	default:
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  fmt.Sprintf("dialect type %q not supported; must be one of %q or %q", typ.Type, csv.DialectType, influxql.DialectType),
+		}
+	}
+}
+
+// validateSourceQueryDialect ensures the dialect can encode the results of
+// the compiler type. InfluxQL results are only encoded by the influxql dialect
+// and flux results only by the csv dialect.
+func validateSourceQueryDialect(compilerType string, d flux.Dialect) error {
+	want := flux.DialectType(csv.DialectType)
+	if compilerType == influxql.CompilerType {
+		want = influxql.DialectType
+	}
+
+	if d.DialectType() != want {
+		return &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  fmt.Sprintf("%s compiler requires the %s dialect; got %s", compilerType, want, d.DialectType()),
+		}
+	}
+	return nil
+}
+
 // handlePostSourceQuery is the HTTP handler for POST /api/v2/sources/:id/query
 func (h *SourceHandler) handlePostSourceQuery(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -214,11 +300,83 @@ func (h *SourceHandler) handlePostSourceQuery(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	_, err = querySvc.Query(ctx, w, req)
+	if hd, ok := req.Dialect.(HTTPDialect); ok {
+		hd.SetHeaders(w)
+	}
+
+	fw := newFlushingWriter(ctx, w, sourceQueryFlushInterval)
+	cw := iocounter.Writer{Writer: fw}
+	if _, err := querySvc.Query(ctx, &cw, req); err != nil {
+		if ctx.Err() != nil {
+			h.Logger.Info("Source query canceled by client",
+				zap.String("handler", "source"),
+				zap.Stringer("source_id", s.ID),
+				zap.Error(ctx.Err()),
+			)
+			return
+		}
+		if cw.Count() == 0 {
+			// Only record the error headers IFF nothing has been written to w.
+			h.HandleHTTPError(ctx, err, w)
+			return
+		}
+		h.Logger.Info("Error writing response to client",
+			zap.String("handler", "source"),
+			zap.Error(err),
+		)
+	}
+	fw.Flush()
+}
+
+// sourceQueryFlushInterval is the longest the results of a source query are
+// held in the response buffer before they are flushed to the client.
+const sourceQueryFlushInterval = 100 * time.Millisecond
+
+// flushingWriter streams writes to the client, flushing them at most once per
+// interval. Writes fail once the context is done, so an encoder stops writing
+// results for a client that has gone away.
+type flushingWriter struct {
+	ctx       context.Context
+	w         io.Writer
+	flusher   http.Flusher
+	interval  time.Duration
+	lastFlush time.Time
+}
+
+func newFlushingWriter(ctx context.Context, w http.ResponseWriter, interval time.Duration) *flushingWriter {
+	flusher, _ := w.(http.Flusher)
+	return &flushingWriter{
+		ctx:       ctx,
+		w:         w,
+		flusher:   flusher,
+		interval:  interval,
+		lastFlush: time.Now(),
+	}
+}
+
+func (fw *flushingWriter) Write(p []byte) (int, error) {
+	if err := fw.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	n, err := fw.w.Write(p)
 	if err != nil {
-		h.HandleHTTPError(ctx, err, w)
+		return n, err
+	}
+
+	if time.Since(fw.lastFlush) >= fw.interval {
+		fw.Flush()
+	}
+	return n, nil
+}
+
+// Flush flushes the buffered writes to the client.
+func (fw *flushingWriter) Flush() {
+	if fw.flusher == nil || fw.ctx.Err() != nil {
 		return
 	}
+	fw.flusher.Flush()
+	fw.lastFlush = time.Now()
 }
 
 // handleGetSourcesBuckets is the HTTP handler for the GET /api/v2/sources/:id/buckets route.
@@ -334,24 +492,97 @@ func (h *SourceHandler) handleGetSource(w http.ResponseWriter, r *http.Request)
 func (h *SourceHandler) handleGetSourceHealth(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	msg := `{"name":"sources","message":"source is %shealthy","status":"%s","checks":[]}`
-	w.Header().Set("Content-Type", "application/json; charset=utf-8")
-
 	req, err := decodeGetSourceRequest(ctx, r)
 	if err != nil {
 		h.HandleHTTPError(ctx, err, w)
 		return
 	}
-	if _, err := h.SourceService.FindSourceByID(ctx, req.SourceID); err != nil {
+	s, err := h.SourceService.FindSourceByID(ctx, req.SourceID)
+	if err != nil {
 		h.HandleHTTPError(ctx, err, w)
 		return
 	}
-	// todo(leodido) > check source is actually healthy and reply with 503 if not
-	// w.WriteHeader(http.StatusServiceUnavailable)
-	// fmt.Fprintln(w, fmt.Sprintf(msg, "not ", "fail"))
 
-	w.WriteHeader(http.StatusOK)
-	fmt.Fprintln(w, fmt.Sprintf(msg, "", "pass"))
+	res := sourceHealthResponse{
+		Name:    "sources",
+		Message: "source is healthy",
+		Status:  check.StatusPass,
+		Checks:  h.checkSource(ctx, s),
+	}
+	code := http.StatusOK
+	for _, c := range res.Checks {
+		if c.Status != check.StatusPass {
+			res.Message = "source is not healthy"
+			res.Status = check.StatusFail
+			code = http.StatusServiceUnavailable
+			break
+		}
+	}
+
+	if err := encodeResponse(ctx, w, code, res); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// sourceHealthResponse is the body of the source health response. Unlike
+// check.Response, the checks are always present.
+type sourceHealthResponse struct {
+	Name    string          `json:"name"`
+	Message string          `json:"message"`
+	Status  check.Status    `json:"status"`
+	Checks  check.Responses `json:"checks"`
+}
+
+// checkSource returns the failed checks of the source. A self source is checked
+// through the services backing it, an external source by the health of its query
+// service. A query service that does not respond within the health timeout
+// fails the check.
+func (h *SourceHandler) checkSource(ctx context.Context, s *platform.Source) check.Responses {
+	checks := check.Responses{}
+	if s.Type == platform.SelfSourceType {
+		_, _, err := h.BucketService.FindBuckets(ctx, platform.BucketFilter{OrganizationID: &s.OrganizationID}, platform.FindOptions{Limit: 1})
+		if err != nil {
+			checks = append(checks, check.Response{
+				Name:    "buckets",
+				Status:  check.StatusFail,
+				Message: err.Error(),
+			})
+		}
+		return checks
+	}
+
+	querySvc, err := h.NewQueryService(s)
+	if err != nil {
+		return append(checks, check.Response{
+			Name:    "query service",
+			Status:  check.StatusFail,
+			Message: err.Error(),
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, h.healthTimeout)
+	defer cancel()
+
+	resc := make(chan check.Response, 1)
+	go func() {
+		resc <- querySvc.Check(ctx)
+	}()
+
+	var res check.Response
+	select {
+	case res = <-resc:
+	case <-ctx.Done():
+		res = check.Response{
+			Name:    "query service",
+			Status:  check.StatusFail,
+			Message: fmt.Sprintf("source did not respond within %s", h.healthTimeout),
+		}
+	}
+	if res.Status != check.StatusPass {
+		checks = append(checks, res)
+	}
+	return checks
 }
 
 type getSourceRequest struct {
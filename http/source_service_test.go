@@ -1,10 +1,27 @@
 package http
 
 import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/csv"
 	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/check"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/query/influxql"
+	querymock "github.com/influxdata/influxdb/query/mock"
+	"go.uber.org/zap"
 )
 
 func Test_newSourceResponse(t *testing.T) {
@@ -72,3 +89,348 @@ func Test_newSourceResponse(t *testing.T) {
 		})
 	}
 }
+
+func TestSourceHandler_handleGetSourceHealth(t *testing.T) {
+	sourceID := platform.ID(1)
+
+	type fields struct {
+		source          *platform.Source
+		BucketService   platform.BucketService
+		NewQueryService func(s *platform.Source) (query.ProxyQueryService, error)
+		healthTimeout   time.Duration
+	}
+	type wants struct {
+		statusCode int
+		body       string
+	}
+
+	tests := []struct {
+		name   string
+		fields fields
+		wants  wants
+	}{
+		{
+			name: "external source passes when its query service is healthy",
+			fields: fields{
+				source: &platform.Source{ID: sourceID, Type: platform.V2SourceType, URL: "http://example.com"},
+				NewQueryService: func(s *platform.Source) (query.ProxyQueryService, error) {
+					return &querymock.ProxyQueryService{}, nil
+				},
+			},
+			wants: wants{
+				statusCode: http.StatusOK,
+				body:       `{"name":"sources","message":"source is healthy","status":"pass","checks":[]}`,
+			},
+		},
+		{
+			name: "external source fails when its query service is unreachable",
+			fields: fields{
+				source: &platform.Source{ID: sourceID, Type: platform.V1SourceType, URL: "http://example.com"},
+				NewQueryService: func(s *platform.Source) (query.ProxyQueryService, error) {
+					return nil, errors.New("connection refused")
+				},
+			},
+			wants: wants{
+				statusCode: http.StatusServiceUnavailable,
+				body: `
+{
+  "name": "sources",
+  "message": "source is not healthy",
+  "status": "fail",
+  "checks": [
+    {"name": "query service", "status": "fail", "message": "connection refused"}
+  ]
+}`,
+			},
+		},
+		{
+			name: "external source fails when its query service does not respond in time",
+			fields: fields{
+				source: &platform.Source{ID: sourceID, Type: platform.V2SourceType, URL: "http://example.com"},
+				NewQueryService: func(s *platform.Source) (query.ProxyQueryService, error) {
+					return &querymock.ProxyQueryService{
+						CheckF: func(ctx context.Context) check.Response {
+							<-ctx.Done()
+							return check.Response{Name: "query service", Status: check.StatusFail, Message: ctx.Err().Error()}
+						},
+					}, nil
+				},
+				healthTimeout: 10 * time.Millisecond,
+			},
+			wants: wants{
+				statusCode: http.StatusServiceUnavailable,
+				body: `
+{
+  "name": "sources",
+  "message": "source is not healthy",
+  "status": "fail",
+  "checks": [
+    {"name": "query service", "status": "fail", "message": "source did not respond within 10ms"}
+  ]
+}`,
+			},
+		},
+		{
+			name: "self source fails when its buckets cannot be found",
+			fields: fields{
+				source: &platform.Source{ID: sourceID, OrganizationID: platform.ID(2), Type: platform.SelfSourceType},
+				BucketService: &mock.BucketService{
+					FindBucketsFn: func(ctx context.Context, filter platform.BucketFilter, opts ...platform.FindOptions) ([]*platform.Bucket, int, error) {
+						return nil, 0, errors.New("store unavailable")
+					},
+				},
+			},
+			wants: wants{
+				statusCode: http.StatusServiceUnavailable,
+				body: `
+{
+  "name": "sources",
+  "message": "source is not healthy",
+  "status": "fail",
+  "checks": [
+    {"name": "buckets", "status": "fail", "message": "store unavailable"}
+  ]
+}`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sourceSVC := mock.NewSourceService()
+			sourceSVC.FindSourceByIDFn = func(ctx context.Context, id platform.ID) (*platform.Source, error) {
+				return tt.fields.source, nil
+			}
+
+			h := NewSourceHandler(&SourceBackend{
+				HTTPErrorHandler: ErrorHandler(0),
+				Logger:           zap.NewNop(),
+				SourceService:    sourceSVC,
+				BucketService:    tt.fields.BucketService,
+				NewQueryService:  tt.fields.NewQueryService,
+			})
+			if tt.fields.healthTimeout != 0 {
+				h.healthTimeout = tt.fields.healthTimeout
+			}
+
+			r := httptest.NewRequest("GET", "http://any.url/api/v2/sources/"+sourceID.String()+"/health", nil)
+			w := httptest.NewRecorder()
+
+			h.ServeHTTP(w, r)
+
+			res := w.Result()
+			body, _ := ioutil.ReadAll(res.Body)
+
+			if res.StatusCode != tt.wants.statusCode {
+				t.Errorf("handleGetSourceHealth() = %v, want %v", res.StatusCode, tt.wants.statusCode)
+			}
+			if eq, diff, err := jsonEqual(string(body), tt.wants.body); err != nil || !eq {
+				t.Errorf("handleGetSourceHealth() = ***%v***", diff)
+			}
+		})
+	}
+}
+
+func Test_decodeSourceQueryRequest(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		wantDialect flux.Dialect
+		wantErrCode string
+	}{
+		{
+			name:        "defaults to the csv dialect",
+			body:        `{"type":"flux","query":"buckets()"}`,
+			wantDialect: csv.Dialect{},
+		},
+		{
+			name: "csv dialect",
+			body: `{"type":"flux","query":"buckets()","dialect":{"type":"csv","delimiter":";","header":false}}`,
+			wantDialect: csv.Dialect{
+				ResultEncoderConfig: csv.ResultEncoderConfig{
+					Delimiter: ';',
+					NoHeader:  true,
+				},
+			},
+		},
+		{
+			name:        "influxql dialect",
+			body:        `{"type":"influxql","query":"SHOW DATABASES","db":"db0","dialect":{"type":"influxql"}}`,
+			wantDialect: &influxql.Dialect{Encoding: influxql.JSON},
+		},
+		{
+			name:        "influxql compiler defaults to the influxql dialect",
+			body:        `{"type":"influxql","query":"SHOW DATABASES","db":"db0"}`,
+			wantDialect: &influxql.Dialect{Encoding: influxql.JSON},
+		},
+		{
+			name:        "influxql compiler with untyped dialect",
+			body:        `{"type":"influxql","query":"SHOW DATABASES","db":"db0","dialect":{}}`,
+			wantDialect: &influxql.Dialect{Encoding: influxql.JSON},
+		},
+		{
+			name:        "influxql compiler with csv dialect",
+			body:        `{"type":"influxql","query":"SHOW DATABASES","db":"db0","dialect":{"type":"csv"}}`,
+			wantErrCode: platform.EInvalid,
+		},
+		{
+			name:        "flux compiler with influxql dialect",
+			body:        `{"type":"flux","query":"buckets()","dialect":{"type":"influxql"}}`,
+			wantErrCode: platform.EInvalid,
+		},
+		{
+			name:        "unknown dialect type",
+			body:        `{"type":"flux","query":"buckets()","dialect":{"type":"xml"}}`,
+			wantErrCode: platform.EInvalid,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "http://any.url/api/v2/sources/0000000000000001/query", strings.NewReader(tt.body))
+
+			req, err := decodeSourceQueryRequest(r)
+			if tt.wantErrCode != "" {
+				if code := platform.ErrorCode(err); code != tt.wantErrCode {
+					t.Fatalf("decodeSourceQueryRequest() error code = %q, want %q: %v", code, tt.wantErrCode, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decodeSourceQueryRequest() unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(req.Dialect, tt.wantDialect) {
+				t.Errorf("decodeSourceQueryRequest() dialect = %#v, want %#v", req.Dialect, tt.wantDialect)
+			}
+		})
+	}
+}
+
+func TestSourceHandler_handlePostSourceQuery(t *testing.T) {
+	sourceID := platform.ID(1)
+
+	t.Run("streams results until the request is canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var queryCtxErr error
+		querySVC := &querymock.ProxyQueryService{
+			QueryF: func(ctx context.Context, w io.Writer, req *query.ProxyRequest) (flux.Statistics, error) {
+				if _, err := w.Write([]byte("first,chunk\r\n")); err != nil {
+					return flux.Statistics{}, err
+				}
+
+				// the client goes away partway through the results
+				cancel()
+				<-ctx.Done()
+				queryCtxErr = ctx.Err()
+
+				_, err := w.Write([]byte("second,chunk\r\n"))
+				return flux.Statistics{}, err
+			},
+		}
+
+		sourceSVC := mock.NewSourceService()
+		sourceSVC.FindSourceByIDFn = func(ctx context.Context, id platform.ID) (*platform.Source, error) {
+			return &platform.Source{ID: id, Type: platform.V2SourceType}, nil
+		}
+
+		h := NewSourceHandler(&SourceBackend{
+			HTTPErrorHandler: ErrorHandler(0),
+			Logger:           zap.NewNop(),
+			SourceService:    sourceSVC,
+			NewQueryService: func(s *platform.Source) (query.ProxyQueryService, error) {
+				return querySVC, nil
+			},
+		})
+
+		body := strings.NewReader(`{"type":"flux","query":"buckets()"}`)
+		r := httptest.NewRequest("POST", "http://any.url/api/v2/sources/"+sourceID.String()+"/query", body)
+		r = r.WithContext(ctx)
+		w := httptest.NewRecorder()
+
+		h.ServeHTTP(w, r)
+
+		if queryCtxErr != context.Canceled {
+			t.Fatalf("query service context error = %v, want %v", queryCtxErr, context.Canceled)
+		}
+
+		res := w.Result()
+		if res.StatusCode != http.StatusOK {
+			t.Errorf("handlePostSourceQuery() status = %v, want %v", res.StatusCode, http.StatusOK)
+		}
+		got, _ := ioutil.ReadAll(res.Body)
+		if string(got) != "first,chunk\r\n" {
+			t.Errorf("handlePostSourceQuery() body = %q, want only the results written before the cancellation", got)
+		}
+	})
+
+	t.Run("flushes results through the handler chain", func(t *testing.T) {
+		received := make(chan struct{})
+		flushed := make(chan bool, 1)
+		querySVC := &querymock.ProxyQueryService{
+			QueryF: func(ctx context.Context, w io.Writer, req *query.ProxyRequest) (flux.Statistics, error) {
+				// the first write is due a flush once the interval has passed
+				time.Sleep(sourceQueryFlushInterval)
+				if _, err := w.Write([]byte("first,chunk\r\n")); err != nil {
+					return flux.Statistics{}, err
+				}
+
+				// the client can only read the first chunk before the query
+				// ends when it was flushed
+				select {
+				case <-received:
+					flushed <- true
+				case <-time.After(5 * time.Second):
+					flushed <- false
+				}
+
+				_, err := w.Write([]byte("second,chunk\r\n"))
+				return flux.Statistics{}, err
+			},
+		}
+
+		sourceSVC := mock.NewSourceService()
+		sourceSVC.FindSourceByIDFn = func(ctx context.Context, id platform.ID) (*platform.Source, error) {
+			return &platform.Source{ID: id, Type: platform.V2SourceType}, nil
+		}
+
+		h := NewHandler("test")
+		h.Handler = NewSourceHandler(&SourceBackend{
+			HTTPErrorHandler: ErrorHandler(0),
+			Logger:           zap.NewNop(),
+			SourceService:    sourceSVC,
+			NewQueryService: func(s *platform.Source) (query.ProxyQueryService, error) {
+				return querySVC, nil
+			},
+		})
+		server := httptest.NewServer(h)
+		defer server.Close()
+
+		body := strings.NewReader(`{"type":"flux","query":"buckets()"}`)
+		res, err := http.Post(server.URL+"/api/v2/sources/"+sourceID.String()+"/query", "application/json", body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer res.Body.Close()
+
+		reader := bufio.NewReader(res.Body)
+		first, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("failed to read first chunk: %v", err)
+		}
+		close(received)
+		if first != "first,chunk\r\n" {
+			t.Errorf("handlePostSourceQuery() first chunk = %q, want %q", first, "first,chunk\r\n")
+		}
+
+		if !<-flushed {
+			t.Fatal("handlePostSourceQuery() did not flush the first chunk to the client")
+		}
+
+		rest, _ := ioutil.ReadAll(reader)
+		if string(rest) != "second,chunk\r\n" {
+			t.Errorf("handlePostSourceQuery() rest of body = %q, want %q", rest, "second,chunk\r\n")
+		}
+	})
+}
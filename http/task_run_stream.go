@@ -0,0 +1,223 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+// RunEvent is a run state transition of a task, or the error that interrupted
+// following the task's runs when Err is set.
+type RunEvent struct {
+	Run influxdb.Run
+	Err error
+}
+
+// TaskRunEventSource provides a stream of run state transitions for a task.
+// Implementations must close the returned channel once the provided context
+// is done.
+type TaskRunEventSource interface {
+	RunEvents(ctx context.Context, taskID influxdb.ID) (<-chan RunEvent, error)
+}
+
+// pollingRunEventSource is a TaskRunEventSource that polls the task service
+// for a task's runs and emits a run every time its status changes.
+type pollingRunEventSource struct {
+	TaskService influxdb.TaskService
+	Interval    time.Duration
+}
+
+// RunEvents polls the runs for the task until the context is done. A failure
+// to find the runs is sent as an event and polling carries on.
+func (p *pollingRunEventSource) RunEvents(ctx context.Context, taskID influxdb.ID) (<-chan RunEvent, error) {
+	if _, err := p.TaskService.FindTaskByID(ctx, taskID); err != nil {
+		return nil, err
+	}
+
+	events := make(chan RunEvent)
+	go func() {
+		defer close(events)
+
+		ticker := time.NewTicker(p.Interval)
+		defer ticker.Stop()
+
+		send := func(ev RunEvent) bool {
+			select {
+			case events <- ev:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		// lastStatuses holds the status of the runs returned by the latest
+		// poll, a run no longer returned is forgotten so the map is bounded
+		// by the runs a single poll returns.
+		lastStatuses := make(map[influxdb.ID]string)
+		for {
+			runs, _, err := p.TaskService.FindRuns(ctx, influxdb.RunFilter{Task: taskID})
+			switch {
+			case err != nil && err != influxdb.ErrNoRunsFound:
+				if !send(RunEvent{Err: err}) {
+					return
+				}
+			default:
+				statuses := make(map[influxdb.ID]string, len(runs))
+				for _, run := range runs {
+					statuses[run.ID] = run.Status
+					if lastStatuses[run.ID] == run.Status {
+						continue
+					}
+					if !send(RunEvent{Run: *run}) {
+						return
+					}
+				}
+				lastStatuses = statuses
+			}
+
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+// handleStreamRuns is the HTTP handler for the GET /api/v2/tasks/:id/runstream route.
+// Each run state transition is written as a server-sent event, the stream
+// ends when the client disconnects or the event source is exhausted.
+func (h *TaskHandler) handleStreamRuns(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	taskID, err := decodeStreamRunsRequest(ctx)
+	if err != nil {
+		err = &influxdb.Error{
+			Err:  err,
+			Code: influxdb.EInvalid,
+			Msg:  "failed to decode request",
+		}
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	auth, err := pcontext.GetAuthorizer(ctx)
+	if err != nil {
+		err = &influxdb.Error{
+			Err:  err,
+			Code: influxdb.EUnauthorized,
+			Msg:  "failed to get authorizer",
+		}
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if k := auth.Kind(); k != influxdb.AuthorizationKind {
+		// Get the authorization for the task, if allowed.
+		authz, err := h.getAuthorizationForTask(ctx, auth, taskID)
+		if err != nil {
+			h.HandleHTTPError(ctx, err, w)
+			return
+		}
+
+		// We were able to access the authorizer for the task, so reassign that on the context for the rest of this call.
+		ctx = pcontext.SetAuthorizer(ctx, authz)
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Msg:  "streaming is not supported",
+		}, w)
+		return
+	}
+
+	events, err := h.RunEventSource.RunEvents(ctx, taskID)
+	if err != nil {
+		err := &influxdb.Error{
+			Err: err,
+			Msg: "failed to stream runs",
+		}
+		if err.Err == influxdb.ErrTaskNotFound {
+			err.Code = influxdb.ENotFound
+		}
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-ctx.Done():
+			h.logger.Debug("run stream closed by client", zap.String("taskID", taskID.String()))
+			return
+		case ev, ok := <-events:
+			if !ok {
+				return
+			}
+			if err := writeRunEvent(w, ev); err != nil {
+				logEncodingError(h.logger, r, err)
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+func decodeStreamRunsRequest(ctx context.Context) (influxdb.ID, error) {
+	params := httprouter.ParamsFromContext(ctx)
+	id := params.ByName("id")
+	if id == "" {
+		return 0, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "you must provide a task ID",
+		}
+	}
+
+	var taskID influxdb.ID
+	if err := taskID.DecodeFromString(id); err != nil {
+		return 0, err
+	}
+	return taskID, nil
+}
+
+// writeRunEvent writes the event as a single server-sent event frame. A run is
+// written as a run event, an error as an error event holding the error.
+func writeRunEvent(w io.Writer, ev RunEvent) error {
+	if ev.Err != nil {
+		b, err := json.Marshal(&influxdb.Error{
+			Code: influxdb.ErrorCode(ev.Err),
+			Msg:  influxdb.ErrorMessage(ev.Err),
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintf(w, "event: error\ndata: %s\n\n", b)
+		return err
+	}
+
+	b, err := json.Marshal(newRunResponse(ev.Run))
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "id: %s\nevent: run\ndata: %s\n\n", ev.Run.ID, b)
+	return err
+}
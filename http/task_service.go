@@ -35,6 +35,7 @@ type TaskBackend struct {
 	LabelService               influxdb.LabelService
 	UserService                influxdb.UserService
 	BucketService              influxdb.BucketService
+	RunEventSource             TaskRunEventSource
 }
 
 // NewTaskBackend returns a new instance of TaskBackend.
@@ -65,6 +66,7 @@ type TaskHandler struct {
 	LabelService               influxdb.LabelService
 	UserService                influxdb.UserService
 	BucketService              influxdb.BucketService
+	RunEventSource             TaskRunEventSource
 }
 
 const (
@@ -79,6 +81,7 @@ const (
 	tasksIDRunsIDPath      = "/api/v2/tasks/:id/runs/:rid"
 	tasksIDRunsIDLogsPath  = "/api/v2/tasks/:id/runs/:rid/logs"
 	tasksIDRunsIDRetryPath = "/api/v2/tasks/:id/runs/:rid/retry"
+	tasksIDRunStreamPath   = "/api/v2/tasks/:id/runstream"
 	tasksIDLabelsPath      = "/api/v2/tasks/:id/labels"
 	tasksIDLabelsIDPath    = "/api/v2/tasks/:id/labels/:lid"
 )
@@ -97,6 +100,13 @@ func NewTaskHandler(b *TaskBackend) *TaskHandler {
 		LabelService:               b.LabelService,
 		UserService:                b.UserService,
 		BucketService:              b.BucketService,
+		RunEventSource:             b.RunEventSource,
+	}
+	if h.RunEventSource == nil {
+		h.RunEventSource = &pollingRunEventSource{
+			TaskService: b.TaskService,
+			Interval:    time.Second,
+		}
 	}
 
 	h.HandlerFunc("GET", tasksPath, h.handleGetTasks)
@@ -136,6 +146,7 @@ func NewTaskHandler(b *TaskBackend) *TaskHandler {
 	h.HandlerFunc("GET", tasksIDRunsPath, h.handleGetRuns)
 	h.HandlerFunc("POST", tasksIDRunsPath, h.handleForceRun)
 	h.HandlerFunc("GET", tasksIDRunsIDPath, h.handleGetRun)
+	h.HandlerFunc("GET", tasksIDRunStreamPath, h.handleStreamRuns)
 	h.HandlerFunc("POST", tasksIDRunsIDRetryPath, h.handleRetryRun)
 	h.HandlerFunc("DELETE", tasksIDRunsIDPath, h.handleCancelRun)
 
@@ -1813,16 +1824,12 @@ func (t TaskService) ForceRun(ctx context.Context, taskID influxdb.ID, scheduled
 	return convertRun(rs.httpRun), nil
 }
 
-func cancelPath(taskID, runID influxdb.ID) string {
-	return path.Join(taskID.String(), runID.String())
-}
-
 // CancelRun stops a longer running run.
 func (t TaskService) CancelRun(ctx context.Context, taskID, runID influxdb.ID) error {
 	span, _ := tracing.StartSpanFromContext(ctx)
 	defer span.Finish()
 
-	u, err := NewURL(t.Addr, cancelPath(taskID, runID))
+	u, err := NewURL(t.Addr, taskIDRunIDPath(taskID, runID))
 	if err != nil {
 		return err
 	}
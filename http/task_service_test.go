@@ -1,6 +1,7 @@
 package http
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
@@ -817,6 +818,184 @@ func TestTaskHandler_handleGetRuns(t *testing.T) {
 	}
 }
 
+// fakeRunEventSource sends its events, then holds the stream open until the
+// client goes away.
+type fakeRunEventSource struct {
+	events []RunEvent
+}
+
+func (f *fakeRunEventSource) RunEvents(ctx context.Context, taskID platform.ID) (<-chan RunEvent, error) {
+	events := make(chan RunEvent)
+	go func() {
+		defer close(events)
+		for _, ev := range f.events {
+			ev.Run.TaskID = taskID
+			select {
+			case events <- ev:
+			case <-ctx.Done():
+				return
+			}
+		}
+		<-ctx.Done()
+	}()
+	return events, nil
+}
+
+func TestTaskHandler_handleStreamRuns(t *testing.T) {
+	scheduledFor, _ := time.Parse(time.RFC3339, "2018-12-01T17:00:13Z")
+	taskBackend := NewMockTaskBackend(t)
+	taskBackend.HTTPErrorHandler = ErrorHandler(0)
+	taskBackend.RunEventSource = &fakeRunEventSource{
+		events: []RunEvent{
+			{Run: platform.Run{ID: platform.ID(2), Status: "started", ScheduledFor: scheduledFor}},
+			{Err: &platform.Error{Code: platform.EInternal, Msg: "failed to find runs"}},
+			{Run: platform.Run{ID: platform.ID(2), Status: "success", ScheduledFor: scheduledFor}},
+		},
+	}
+	taskHandler := NewTaskHandler(taskBackend)
+
+	// served through the same handler chain as the API, its response writer
+	// wraps the one of the server.
+	h := NewHandler("test")
+	h.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := pcontext.SetAuthorizer(r.Context(), &platform.Authorization{Permissions: platform.OperPermissions()})
+		taskHandler.ServeHTTP(w, r.WithContext(ctx))
+	})
+	server := httptest.NewServer(h)
+	defer server.Close()
+
+	// the stream is held open after the events, they are only read within the
+	// timeout when each is flushed to the client as it is written.
+	client := &http.Client{Timeout: 5 * time.Second}
+	res, err := client.Get(server.URL + "/api/v2/tasks/0000000000000001/runstream")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("handleStreamRuns() = %v, want %v", res.StatusCode, http.StatusOK)
+	}
+	if content := res.Header.Get("Content-Type"); content != "text/event-stream" {
+		t.Errorf("handleStreamRuns() content type = %v, want %v", content, "text/event-stream")
+	}
+
+	reader := bufio.NewReader(res.Body)
+	readFrame := func(t *testing.T) []string {
+		t.Helper()
+
+		var lines []string
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				t.Fatalf("failed to read event: %v", err)
+			}
+			line = strings.TrimSuffix(line, "\n")
+			if line == "" {
+				return lines
+			}
+			lines = append(lines, line)
+		}
+	}
+
+	assertRunFrame := func(t *testing.T, lines []string, status string) {
+		t.Helper()
+
+		if len(lines) != 3 {
+			t.Fatalf("expected 3 lines in run event; got %q", lines)
+		}
+		if lines[0] != "id: 0000000000000002" {
+			t.Errorf("unexpected id line: %q", lines[0])
+		}
+		if lines[1] != "event: run" {
+			t.Errorf("unexpected event line: %q", lines[1])
+		}
+
+		var run httpRun
+		if err := json.Unmarshal([]byte(strings.TrimPrefix(lines[2], "data: ")), &run); err != nil {
+			t.Fatalf("failed to decode event data: %v", err)
+		}
+		if run.Status != status {
+			t.Errorf("event status = %q, want %q", run.Status, status)
+		}
+		if run.TaskID != platform.ID(1) {
+			t.Errorf("event taskID = %s, want %s", run.TaskID, platform.ID(1))
+		}
+	}
+
+	assertRunFrame(t, readFrame(t), "started")
+
+	errLines := readFrame(t)
+	if len(errLines) != 2 || errLines[0] != "event: error" {
+		t.Fatalf("expected an error event; got %q", errLines)
+	}
+	var pe platform.Error
+	if err := json.Unmarshal([]byte(strings.TrimPrefix(errLines[1], "data: ")), &pe); err != nil {
+		t.Fatalf("failed to decode error event data: %v", err)
+	}
+	if pe.Code != platform.EInternal || pe.Msg != "failed to find runs" {
+		t.Errorf("unexpected error event: %+v", pe)
+	}
+
+	assertRunFrame(t, readFrame(t), "success")
+}
+
+func TestPollingRunEventSource(t *testing.T) {
+	var polls int
+	svc := &mock.TaskService{
+		FindTaskByIDFn: func(ctx context.Context, id platform.ID) (*platform.Task, error) {
+			return &platform.Task{ID: id}, nil
+		},
+		FindRunsFn: func(ctx context.Context, f platform.RunFilter) ([]*platform.Run, int, error) {
+			polls++
+			switch polls {
+			case 1:
+				return []*platform.Run{{ID: 1, Status: "started"}}, 1, nil
+			case 2:
+				return nil, 0, errors.New("storage unavailable")
+			default:
+				return []*platform.Run{{ID: 1, Status: "success"}}, 1, nil
+			}
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	src := &pollingRunEventSource{TaskService: svc, Interval: time.Millisecond}
+	events, err := src.RunEvents(ctx, platform.ID(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	next := func() RunEvent {
+		select {
+		case ev := <-events:
+			return ev
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for a run event")
+		}
+		return RunEvent{}
+	}
+
+	if ev := next(); ev.Err != nil || ev.Run.Status != "started" {
+		t.Fatalf("expected the started run, got %+v", ev)
+	}
+	if ev := next(); ev.Err == nil {
+		t.Fatalf("expected the find runs error, got %+v", ev)
+	}
+	// the error does not end the stream, the next poll finds the run again
+	if ev := next(); ev.Err != nil || ev.Run.Status != "success" {
+		t.Fatalf("expected the finished run, got %+v", ev)
+	}
+
+	select {
+	case ev := <-events:
+		t.Fatalf("expected an unchanged run not to be sent again, got %+v", ev)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
 func TestTaskHandler_NotFoundStatus(t *testing.T) {
 	// Ensure that the HTTP handlers return 404s for missing resources, and OKs for matching.
 
@@ -1737,3 +1916,46 @@ func TestTaskHandler_Sessions(t *testing.T) {
 		}
 	})
 }
+
+func TestTaskService_CancelRun(t *testing.T) {
+	const (
+		taskID = platform.ID(1)
+		runID  = platform.ID(2)
+	)
+
+	var gotTaskID, gotRunID platform.ID
+	taskBackend := NewMockTaskBackend(t)
+	taskBackend.HTTPErrorHandler = ErrorHandler(0)
+	taskBackend.TaskService = &mock.TaskService{
+		CancelRunFn: func(_ context.Context, tid, rid platform.ID) error {
+			gotTaskID, gotRunID = tid, rid
+			if rid != runID {
+				return platform.ErrRunNotFound
+			}
+			return nil
+		},
+	}
+	server := httptest.NewServer(NewTaskHandler(taskBackend))
+	defer server.Close()
+
+	client := TaskService{Addr: server.URL}
+
+	t.Run("cancels the run", func(t *testing.T) {
+		if err := client.CancelRun(context.Background(), taskID, runID); err != nil {
+			t.Fatalf("unexpected error canceling run: %v", err)
+		}
+		if gotTaskID != taskID || gotRunID != runID {
+			t.Errorf("expected run %s of task %s to be canceled, got run %s of task %s", runID, taskID, gotRunID, gotTaskID)
+		}
+	})
+
+	t.Run("run not found", func(t *testing.T) {
+		err := client.CancelRun(context.Background(), taskID, platform.ID(3))
+		if err == nil {
+			t.Fatal("expected an error canceling an unknown run")
+		}
+		if code := platform.ErrorCode(err); code != platform.ENotFound {
+			t.Errorf("expected error code %q, got %q", platform.ENotFound, code)
+		}
+	})
+}
@@ -5,11 +5,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"path"
 	"strings"
 
 	"github.com/golang/gddo/httputil"
 	platform "github.com/influxdata/influxdb"
 	pctx "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/kit/tracing"
 	"github.com/influxdata/influxdb/telegraf/plugins"
 	"github.com/julienschmidt/httprouter"
 	"go.uber.org/zap"
@@ -424,3 +426,159 @@ func (h *TelegrafHandler) handleDeleteTelegraf(w http.ResponseWriter, r *http.Re
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// TelegrafService connects to Influx via HTTP using tokens to manage telegraf configs.
+type TelegrafService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+
+	*UserResourceMappingService
+}
+
+var _ platform.TelegrafConfigStore = (*TelegrafService)(nil)
+
+// NewTelegrafService returns a client for the telegraf handlers, with the user resource
+// mapping service the platform.TelegrafConfigStore requires.
+func NewTelegrafService(addr, token string, insecureSkipVerify bool) *TelegrafService {
+	return &TelegrafService{
+		Addr:               addr,
+		Token:              token,
+		InsecureSkipVerify: insecureSkipVerify,
+		UserResourceMappingService: &UserResourceMappingService{
+			Addr:               addr,
+			Token:              token,
+			InsecureSkipVerify: insecureSkipVerify,
+		},
+	}
+}
+
+func telegrafIDPath(id platform.ID) string {
+	return path.Join(telegrafsPath, id.String())
+}
+
+// FindTelegrafConfigByID returns a single telegraf config by ID.
+func (s *TelegrafService) FindTelegrafConfigByID(ctx context.Context, id platform.ID) (*platform.TelegrafConfig, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	u, err := NewURL(s.Addr, telegrafIDPath(id))
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	// the handler responds with toml unless json is asked for.
+	req.Header.Set("Accept", "application/json")
+	SetToken(s.Token, req)
+
+	hc := NewClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return nil, err
+	}
+
+	tc := new(platform.TelegrafConfig)
+	if err := json.NewDecoder(resp.Body).Decode(tc); err != nil {
+		return nil, err
+	}
+	return tc, nil
+}
+
+// FindTelegrafConfigs returns a list of telegraf configs that match filter and the total count of matching telegraf configs.
+// The handler returns every matching telegraf config, the options are not applied.
+func (s *TelegrafService) FindTelegrafConfigs(ctx context.Context, filter platform.TelegrafConfigFilter, opt ...platform.FindOptions) ([]*platform.TelegrafConfig, int, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	u, err := NewURL(s.Addr, telegrafsPath)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := u.Query()
+	if filter.OrgID != nil {
+		query.Add("orgID", filter.OrgID.String())
+	}
+	if filter.Organization != nil {
+		query.Add("org", *filter.Organization)
+	}
+	u.RawQuery = query.Encode()
+
+	var resp struct {
+		TelegrafConfigs []*platform.TelegrafConfig `json:"configurations"`
+	}
+	if err := doJSONRequest(ctx, "GET", u, s.Token, s.InsecureSkipVerify, nil, &resp); err != nil {
+		return nil, 0, err
+	}
+	return resp.TelegrafConfigs, len(resp.TelegrafConfigs), nil
+}
+
+// CreateTelegrafConfig creates a new telegraf config and sets tc.ID with the new identifier.
+// The user creating the config is the user the token belongs to, the userID is
+// ignored.
+func (s *TelegrafService) CreateTelegrafConfig(ctx context.Context, tc *platform.TelegrafConfig, userID platform.ID) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	u, err := NewURL(s.Addr, telegrafsPath)
+	if err != nil {
+		return err
+	}
+
+	octets, err := json.Marshal(tc)
+	if err != nil {
+		return err
+	}
+
+	created := new(platform.TelegrafConfig)
+	if err := doJSONRequest(ctx, "POST", u, s.Token, s.InsecureSkipVerify, octets, created); err != nil {
+		return err
+	}
+	*tc = *created
+	return nil
+}
+
+// UpdateTelegrafConfig updates a single telegraf config.
+// Returns the new telegraf config after update.
+func (s *TelegrafService) UpdateTelegrafConfig(ctx context.Context, id platform.ID, tc *platform.TelegrafConfig, userID platform.ID) (*platform.TelegrafConfig, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	u, err := NewURL(s.Addr, telegrafIDPath(id))
+	if err != nil {
+		return nil, err
+	}
+
+	octets, err := json.Marshal(tc)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := new(platform.TelegrafConfig)
+	if err := doJSONRequest(ctx, "PUT", u, s.Token, s.InsecureSkipVerify, octets, updated); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// DeleteTelegrafConfig removes a telegraf config by ID.
+func (s *TelegrafService) DeleteTelegrafConfig(ctx context.Context, id platform.ID) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	u, err := NewURL(s.Addr, telegrafIDPath(id))
+	if err != nil {
+		return err
+	}
+	return doJSONRequest(ctx, "DELETE", u, s.Token, s.InsecureSkipVerify, nil, nil)
+}
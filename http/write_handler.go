@@ -1,12 +1,15 @@
 package http
 
 import (
+	"bytes"
 	"compress/gzip"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/influxdata/influxdb/http/metric"
@@ -135,6 +138,9 @@ func (h *WriteHandler) handleWrite(w http.ResponseWriter, r *http.Request) {
 	}
 
 	logger := h.Logger.With(zap.String("org", req.Org), zap.String("bucket", req.Bucket))
+	for _, param := range req.Ignored {
+		logger.Warn("Ignoring unsupported write parameter", zap.String("param", param))
+	}
 
 	var org *influxdb.Organization
 	org, err = queryOrganization(ctx, r, h.OrganizationService)
@@ -146,6 +152,18 @@ func (h *WriteHandler) handleWrite(w http.ResponseWriter, r *http.Request) {
 
 	orgID = org.ID
 
+	if req.Bucket == "" && req.Database != "" {
+		// there is no dbrp mapping to find the bucket of the db and rp by.
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Op:   "http/handleWrite",
+			Msg:  "writing by db and rp is not supported, a bucket is required",
+		}, w)
+		return
+	} else if req.RetentionPolicy != "" {
+		logger.Warn("Ignoring rp, the bucket is written to", zap.String("rp", req.RetentionPolicy))
+	}
+
 	var bucket *influxdb.Bucket
 	if id, err := influxdb.IDFromString(req.Bucket); err == nil {
 		// Decoded ID successfully. Make sure it's a real bucket.
@@ -221,36 +239,133 @@ func (h *WriteHandler) handleWrite(w http.ResponseWriter, r *http.Request) {
 
 	encoded := tsdb.EncodeName(org.ID, bucket.ID)
 	mm := models.EscapeMeasurement(encoded[:])
-	points, err := models.ParsePointsWithPrecision(data, mm, time.Now(), req.Precision)
-	if err != nil {
-		logger.Error("Error parsing points", zap.Error(err))
-		h.HandleHTTPError(ctx, &influxdb.Error{
-			Code: influxdb.EInvalid,
-			Msg:  err.Error(),
-		}, w)
-		return
+
+	var (
+		points   []models.Point
+		lineErrs []writeLineError
+	)
+	if req.Partial {
+		points, lineErrs = parsePointsByLine(data, mm, time.Now(), req.Precision)
+	} else {
+		points, err = models.ParsePointsWithPrecision(data, mm, time.Now(), req.Precision)
+		if err != nil {
+			logger.Error("Error parsing points", zap.Error(err))
+			h.HandleHTTPError(ctx, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  err.Error(),
+			}, w)
+			return
+		}
 	}
 
-	if err := h.PointsWriter.WritePoints(ctx, points); err != nil {
-		logger.Error("Error writing points", zap.Error(err))
-		h.HandleHTTPError(ctx, &influxdb.Error{
-			Code: influxdb.EInternal,
-			Op:   "http/handleWrite",
-			Msg:  "unexpected error writing points to database",
-			Err:  err,
-		}, w)
+	if !req.Partial || len(points) > 0 {
+		if err := h.PointsWriter.WritePoints(ctx, points); err != nil {
+			logger.Error("Error writing points", zap.Error(err))
+			h.HandleHTTPError(ctx, &influxdb.Error{
+				Code: influxdb.EInternal,
+				Op:   "http/handleWrite",
+				Msg:  "unexpected error writing points to database",
+				Err:  err,
+			}, w)
+			return
+		}
+	}
+
+	if len(lineErrs) > 0 {
+		logger.Info("Partial write with unparsable lines", zap.Int("lines", len(lineErrs)), zap.Int("points", len(points)))
+		res := partialWriteResponse{
+			Code:    influxdb.EInvalid,
+			Message: fmt.Sprintf("partial write: %d lines could not be parsed, %d points were written", len(lineErrs), len(points)),
+			Lines:   lineErrs,
+		}
+		if err := encodeResponse(ctx, w, http.StatusBadRequest, res); err != nil {
+			logEncodingError(logger, r, err)
+		}
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// writeLineError is a line of a partial write that could not be parsed.
+type writeLineError struct {
+	Line  int    `json:"line"`
+	Error string `json:"error"`
+}
+
+// partialWriteResponse reports the lines of a partial write that could not be
+// parsed, the points of every other line are written.
+type partialWriteResponse struct {
+	Code    string           `json:"code"`
+	Message string           `json:"message"`
+	Lines   []writeLineError `json:"lines"`
+}
+
+// parsePointsByLine parses every line of data on its own, so the points of the
+// valid lines are kept when other lines can not be parsed. Lines are scanned
+// as models.ParsePoints scans them, a quoted string field value may span
+// several lines. Lines are numbered from 1, a point is numbered by its first
+// line, and blank lines and comments are skipped.
+func parsePointsByLine(data, mm []byte, now time.Time, precision string) ([]models.Point, []writeLineError) {
+	var (
+		points   []models.Point
+		lineErrs []writeLineError
+	)
+	lineNum := 1
+	for pos := 0; pos < len(data); pos++ {
+		var line []byte
+		pos, line = models.ScanLine(data, pos)
+
+		n := lineNum
+		lineNum += bytes.Count(line, []byte("\n")) + 1
+
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 || line[0] == '#' {
+			continue
+		}
+
+		pts, err := models.ParsePointsWithPrecision(line, mm, now, precision)
+		if err != nil {
+			lineErrs = append(lineErrs, writeLineError{Line: n, Error: err.Error()})
+			continue
+		}
+		points = append(points, pts...)
+	}
+	return points, lineErrs
+}
+
+// v1Precisions maps the precisions of 1.x writes to their 2.x equivalent, the
+// ms and s precisions are the same in both.
+var v1Precisions = map[string]string{
+	"n": "ns",
+	"u": "us",
+}
+
+// unsupportedV1Precisions are 1.x precisions that have no 2.x equivalent.
+var unsupportedV1Precisions = map[string]bool{
+	"m": true,
+	"h": true,
+}
+
+// ignoredWriteParams are 1.x write parameters that have no 2.x equivalent.
+var ignoredWriteParams = []string{"consistency"}
+
 func decodeWriteRequest(ctx context.Context, r *http.Request) (*postWriteRequest, error) {
 	qp := r.URL.Query()
 	p := qp.Get("precision")
 	if p == "" {
 		p = "ns"
 	}
+	if v2, ok := v1Precisions[p]; ok {
+		p = v2
+	}
+	if unsupportedV1Precisions[p] {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Op:   "http/decodeWriteRequest",
+			Msg:  fmt.Sprintf("precision %q is not supported; convert the timestamps to s, ms, us or ns", p),
+		}
+	}
 
 	if !models.ValidPrecision(p) {
 		return nil, &influxdb.Error{
@@ -260,10 +375,34 @@ func decodeWriteRequest(ctx context.Context, r *http.Request) (*postWriteRequest
 		}
 	}
 
+	var partial bool
+	if v := qp.Get("partial"); v != "" {
+		var err error
+		partial, err = strconv.ParseBool(v)
+		if err != nil {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Op:   "http/decodeWriteRequest",
+				Msg:  "invalid partial; must be true or false",
+			}
+		}
+	}
+
+	var ignored []string
+	for _, param := range ignoredWriteParams {
+		if qp.Get(param) != "" {
+			ignored = append(ignored, param)
+		}
+	}
+
 	return &postWriteRequest{
-		Bucket:    qp.Get("bucket"),
-		Org:       qp.Get("org"),
-		Precision: p,
+		Bucket:          qp.Get("bucket"),
+		Org:             qp.Get("org"),
+		Precision:       p,
+		Partial:         partial,
+		Database:        qp.Get("db"),
+		RetentionPolicy: qp.Get("rp"),
+		Ignored:         ignored,
 	}, nil
 }
 
@@ -271,6 +410,18 @@ type postWriteRequest struct {
 	Org       string
 	Bucket    string
 	Precision string
+
+	// Partial writes the points of every line that parses, rather than
+	// rejecting the write when any line can not be parsed.
+	Partial bool
+
+	// Database and RetentionPolicy are the 1.x location of the write. A
+	// bucket is still required, the rp is ignored when it is provided.
+	Database        string
+	RetentionPolicy string
+
+	// Ignored lists the provided parameters that are not supported.
+	Ignored []string
 }
 
 // WriteService sends data over HTTP to influxdb via line protocol.
@@ -279,6 +430,13 @@ type WriteService struct {
 	Token              string
 	Precision          string
 	InsecureSkipVerify bool
+
+	// RetentionPolicy is passed along as the rp of 1.x compatible writes.
+	RetentionPolicy string
+
+	// Partial writes the valid lines of each write, the lines that can not
+	// be parsed are reported by a *PartialWriteError.
+	Partial bool
 }
 
 var _ influxdb.WriteService = (*WriteService)(nil)
@@ -330,6 +488,12 @@ func (s *WriteService) Write(ctx context.Context, orgID, bucketID influxdb.ID, r
 	params.Set("org", string(org))
 	params.Set("bucket", string(bucket))
 	params.Set("precision", string(precision))
+	if s.RetentionPolicy != "" {
+		params.Set("rp", s.RetentionPolicy)
+	}
+	if s.Partial {
+		params.Set("partial", "true")
+	}
 	req.URL.RawQuery = params.Encode()
 
 	hc := NewClient(u.Scheme, s.InsecureSkipVerify)
@@ -340,7 +504,90 @@ func (s *WriteService) Write(ctx context.Context, orgID, bucketID influxdb.ID, r
 	}
 	defer resp.Body.Close()
 
-	return CheckError(resp)
+	if s.Partial && resp.StatusCode == http.StatusBadRequest {
+		return checkPartialWriteError(resp)
+	}
+	if err := CheckError(resp); err != nil {
+		return withStatusErrorCode(resp.StatusCode, err)
+	}
+	return nil
+}
+
+// withStatusErrorCode gives an error response that was not encoded as a
+// platform error the code of its HTTP status, so callers can tell a rejected
+// write from one that failed on the server.
+func withStatusErrorCode(status int, err error) error {
+	if _, ok := err.(*influxdb.Error); ok {
+		return err
+	}
+
+	code := influxdb.EInternal
+	switch {
+	case status == http.StatusTooManyRequests:
+		code = influxdb.ETooManyRequests
+	case status == http.StatusServiceUnavailable:
+		code = influxdb.EUnavailable
+	case status == http.StatusUnauthorized:
+		code = influxdb.EUnauthorized
+	case status == http.StatusForbidden:
+		code = influxdb.EForbidden
+	case status == http.StatusNotFound:
+		code = influxdb.ENotFound
+	case status/100 == 4:
+		code = influxdb.EInvalid
+	}
+	return &influxdb.Error{
+		Code: code,
+		Err:  err,
+	}
+}
+
+// PartialWriteError is returned by partial writes that wrote the points of
+// the valid lines and rejected the lines that could not be parsed.
+type PartialWriteError struct {
+	Msg   string
+	Lines map[int]error
+}
+
+// Error returns the message of the partial write.
+func (e *PartialWriteError) Error() string {
+	return e.Msg
+}
+
+// LineErrors returns the error of each rejected line by its number.
+func (e *PartialWriteError) LineErrors() map[int]error {
+	return e.Lines
+}
+
+// checkPartialWriteError decodes the lines rejected by a partial write. Any
+// other bad request is returned as the error it was encoded as.
+func checkPartialWriteError(resp *http.Response) error {
+	var res partialWriteResponse
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "unable to decode write error",
+			Err:  err,
+		}
+	}
+	if len(res.Lines) == 0 {
+		return &influxdb.Error{
+			Code: res.Code,
+			Msg:  res.Message,
+		}
+	}
+
+	lines := make(map[int]error, len(res.Lines))
+	for _, l := range res.Lines {
+		lines[l.Line] = &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  l.Error,
+		}
+	}
+	return &PartialWriteError{
+		Msg:   res.Message,
+		Lines: lines,
+	}
 }
 
 func compressWithGzip(data io.Reader) (io.Reader, error) {
@@ -76,6 +76,128 @@ func TestWriteService_Write(t *testing.T) {
 	}
 }
 
+func TestWriteService_WriteRetentionPolicy(t *testing.T) {
+	var rp string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rp = r.URL.Query().Get("rp")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	s := &WriteService{
+		Addr:            ts.URL,
+		RetentionPolicy: "autogen",
+	}
+	if err := s.Write(context.Background(), 1, 2, strings.NewReader("m,t1=v1 f1=2")); err != nil {
+		t.Fatalf("WriteService.Write() error = %v", err)
+	}
+	if got, want := rp, "autogen"; got != want {
+		t.Errorf("WriteService.Write() rp = %v, want %v", got, want)
+	}
+}
+
+func TestWriteService_WritePartial(t *testing.T) {
+	var partial string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		partial = r.URL.Query().Get("partial")
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"code":"invalid","message":"partial write: 1 lines could not be parsed, 1 points were written","lines":[{"line":2,"error":"unable to parse 'm2': missing fields"}]}`))
+	}))
+	defer ts.Close()
+
+	s := &WriteService{
+		Addr:    ts.URL,
+		Partial: true,
+	}
+	err := s.Write(context.Background(), 1, 2, strings.NewReader("m1,t1=v1 f1=1\nm2"))
+	if partial != "true" {
+		t.Errorf("WriteService.Write() partial = %q, want %q", partial, "true")
+	}
+
+	perr, ok := err.(*PartialWriteError)
+	if !ok {
+		t.Fatalf("WriteService.Write() error = %v, want a *PartialWriteError", err)
+	}
+	lines := perr.LineErrors()
+	if len(lines) != 1 || lines[2] == nil {
+		t.Fatalf("WriteService.Write() line errors = %v, want an error for line 2", lines)
+	}
+	if got, want := lines[2].Error(), "unable to parse 'm2': missing fields"; got != want {
+		t.Errorf("WriteService.Write() line 2 error = %q, want %q", got, want)
+	}
+}
+
+func TestWriteService_WriteError(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		contentType string
+		body        string
+		want        string
+		wantCode    string
+	}{
+		{
+			name:   "unparsable line protocol",
+			status: http.StatusBadRequest,
+			body:   `{"code":"invalid","message":"unable to parse 'm,t1=v1': missing fields"}`,
+			want:   "unable to parse 'm,t1=v1': missing fields",
+		},
+		{
+			name:   "server error",
+			status: http.StatusInternalServerError,
+			body:   `{"code":"internal error","message":"unexpected error writing points to database"}`,
+			want:   "unexpected error writing points to database",
+		},
+		{
+			name:        "plain text client error",
+			status:      http.StatusBadRequest,
+			contentType: "text/plain",
+			body:        "bad request",
+			want:        "bad request",
+			wantCode:    influxdb.EInvalid,
+		},
+		{
+			name:        "plain text rate limit",
+			status:      http.StatusTooManyRequests,
+			contentType: "text/plain",
+			body:        "slow down",
+			want:        "slow down",
+			wantCode:    influxdb.ETooManyRequests,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				contentType := tt.contentType
+				if contentType == "" {
+					contentType = "application/json; charset=utf-8"
+				}
+				w.Header().Set("Content-Type", contentType)
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer ts.Close()
+
+			s := &WriteService{
+				Addr: ts.URL,
+			}
+			err := s.Write(context.Background(), 1, 2, strings.NewReader("m,t1=v1"))
+			if err == nil {
+				t.Fatal("WriteService.Write() expected error")
+			}
+			if got := err.Error(); !strings.Contains(got, tt.want) {
+				t.Errorf("WriteService.Write() error = %q, want it to contain %q", got, tt.want)
+			}
+			if tt.wantCode != "" {
+				if got := influxdb.ErrorCode(err); got != tt.wantCode {
+					t.Errorf("WriteService.Write() error code = %q, want %q", got, tt.wantCode)
+				}
+			}
+		})
+	}
+}
+
 func TestWriteHandler_handleWrite(t *testing.T) {
 	// state is the internal state of org and bucket services
 	type state struct {
@@ -88,16 +210,22 @@ func TestWriteHandler_handleWrite(t *testing.T) {
 
 	// want is the expected output of the HTTP endpoint
 	type wants struct {
-		body string
-		code int
+		body   string
+		code   int
+		time   int64 // unix nanosecond time of the written point, unchecked when zero
+		points int   // number of points written, unchecked when zero
 	}
 
 	// request is sent to the HTTP endpoint
 	type request struct {
-		auth   influxdb.Authorizer
-		org    string
-		bucket string
-		body   string
+		auth      influxdb.Authorizer
+		org       string
+		bucket    string
+		db        string
+		rp        string
+		precision string
+		partial   string
+		body      string
 	}
 
 	tests := []struct {
@@ -122,6 +250,151 @@ func TestWriteHandler_handleWrite(t *testing.T) {
 				code: 204,
 			},
 		},
+		{
+			name: "1.x write with a bucket ignores the rp",
+			request: request{
+				org:       "043e0780ee2b1000",
+				bucket:    "04504b356e23b000",
+				db:        "telegraf",
+				rp:        "autogen",
+				precision: "u",
+				body:      "m1,t1=v1 f1=1 1000000",
+				auth:      bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			state: state{
+				org:    testOrg("043e0780ee2b1000"),
+				bucket: testBucket("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			wants: wants{
+				code: 204,
+				time: 1000000000,
+			},
+		},
+		{
+			name: "ns precision timestamp is scaled to nanoseconds",
+			request: request{
+				org:       "043e0780ee2b1000",
+				bucket:    "04504b356e23b000",
+				precision: "ns",
+				body:      "m1,t1=v1 f1=1 1000000000",
+				auth:      bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			state: state{
+				org:    testOrg("043e0780ee2b1000"),
+				bucket: testBucket("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			wants: wants{
+				code: 204,
+				time: 1000000000,
+			},
+		},
+		{
+			name: "us precision timestamp is scaled to nanoseconds",
+			request: request{
+				org:       "043e0780ee2b1000",
+				bucket:    "04504b356e23b000",
+				precision: "us",
+				body:      "m1,t1=v1 f1=1 1000000",
+				auth:      bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			state: state{
+				org:    testOrg("043e0780ee2b1000"),
+				bucket: testBucket("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			wants: wants{
+				code: 204,
+				time: 1000000000,
+			},
+		},
+		{
+			name: "ms precision timestamp is scaled to nanoseconds",
+			request: request{
+				org:       "043e0780ee2b1000",
+				bucket:    "04504b356e23b000",
+				precision: "ms",
+				body:      "m1,t1=v1 f1=1 1000",
+				auth:      bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			state: state{
+				org:    testOrg("043e0780ee2b1000"),
+				bucket: testBucket("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			wants: wants{
+				code: 204,
+				time: 1000000000,
+			},
+		},
+		{
+			name: "s precision timestamp is scaled to nanoseconds",
+			request: request{
+				org:       "043e0780ee2b1000",
+				bucket:    "04504b356e23b000",
+				precision: "s",
+				body:      "m1,t1=v1 f1=1 1",
+				auth:      bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			state: state{
+				org:    testOrg("043e0780ee2b1000"),
+				bucket: testBucket("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			wants: wants{
+				code: 204,
+				time: 1000000000,
+			},
+		},
+		{
+			name: "1.x hour precision returns 400",
+			request: request{
+				org:       "043e0780ee2b1000",
+				bucket:    "04504b356e23b000",
+				precision: "h",
+				body:      "m1,t1=v1 f1=1 1",
+				auth:      bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			state: state{
+				org:    testOrg("043e0780ee2b1000"),
+				bucket: testBucket("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			wants: wants{
+				code: 400,
+				body: `{"code":"invalid","message":"precision \"h\" is not supported; convert the timestamps to s, ms, us or ns"}`,
+			},
+		},
+		{
+			name: "invalid precision returns 400",
+			request: request{
+				org:       "043e0780ee2b1000",
+				bucket:    "04504b356e23b000",
+				precision: "d",
+				body:      "m1,t1=v1 f1=1 1",
+				auth:      bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			state: state{
+				org:    testOrg("043e0780ee2b1000"),
+				bucket: testBucket("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			wants: wants{
+				code: 400,
+				body: `{"code":"invalid","message":"invalid precision; valid precision units are ns, us, ms, and s"}`,
+			},
+		},
+		{
+			name: "1.x write without a bucket returns 400",
+			request: request{
+				org:  "043e0780ee2b1000",
+				db:   "telegraf",
+				body: "m1,t1=v1 f1=1",
+				auth: bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			state: state{
+				org:    testOrg("043e0780ee2b1000"),
+				bucket: testBucket("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			wants: wants{
+				code: 400,
+				body: `{"code":"invalid","message":"writing by db and rp is not supported, a bucket is required"}`,
+			},
+		},
 		{
 			name: "points writer error is an internal error",
 			request: request{
@@ -222,6 +495,99 @@ func TestWriteHandler_handleWrite(t *testing.T) {
 				body: `{"code":"invalid","message":"unable to parse 'invalid': missing fields"}`,
 			},
 		},
+		{
+			name: "partial write writes the valid lines and reports the invalid ones",
+			request: request{
+				org:     "043e0780ee2b1000",
+				bucket:  "04504b356e23b000",
+				partial: "true",
+				auth:    bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+				body:    "m1,t1=v1 f1=1\ninvalid\n\nm1,t1=v2 f1=2\nbad\n",
+			},
+			state: state{
+				org:    testOrg("043e0780ee2b1000"),
+				bucket: testBucket("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			wants: wants{
+				code:   400,
+				points: 2,
+				body: `{"code":"invalid","message":"partial write: 2 lines could not be parsed, 2 points were written","lines":[{"line":2,"error":"unable to parse 'invalid': missing fields"},{"line":5,"error":"unable to parse 'bad': missing fields"}]}
+`,
+			},
+		},
+		{
+			name: "partial write of valid lines is accepted",
+			request: request{
+				org:     "043e0780ee2b1000",
+				bucket:  "04504b356e23b000",
+				partial: "true",
+				auth:    bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+				body:    "m1,t1=v1 f1=1\nm1,t1=v2 f1=2",
+			},
+			state: state{
+				org:    testOrg("043e0780ee2b1000"),
+				bucket: testBucket("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			wants: wants{
+				code:   204,
+				points: 2,
+			},
+		},
+		{
+			name: "partial write keeps string fields that span lines",
+			request: request{
+				org:     "043e0780ee2b1000",
+				bucket:  "04504b356e23b000",
+				partial: "true",
+				auth:    bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+				body:    "m1,t1=v1 s=\"line one\nline two\"\ninvalid\nm1,t1=v2 f1=2",
+			},
+			state: state{
+				org:    testOrg("043e0780ee2b1000"),
+				bucket: testBucket("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			wants: wants{
+				code:   400,
+				points: 2,
+				body: `{"code":"invalid","message":"partial write: 1 lines could not be parsed, 2 points were written","lines":[{"line":3,"error":"unable to parse 'invalid': missing fields"}]}
+`,
+			},
+		},
+		{
+			name: "strict write rejects a body with any invalid line",
+			request: request{
+				org:    "043e0780ee2b1000",
+				bucket: "04504b356e23b000",
+				auth:   bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+				body:   "m1,t1=v1 f1=1\ninvalid",
+			},
+			state: state{
+				org:    testOrg("043e0780ee2b1000"),
+				bucket: testBucket("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			wants: wants{
+				code: 400,
+				body: `{"code":"invalid","message":"unable to parse 'invalid': missing fields"}`,
+			},
+		},
+		{
+			name: "invalid partial returns 400",
+			request: request{
+				org:     "043e0780ee2b1000",
+				bucket:  "04504b356e23b000",
+				partial: "sometimes",
+				auth:    bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+				body:    "m1,t1=v1 f1=1",
+			},
+			state: state{
+				org:    testOrg("043e0780ee2b1000"),
+				bucket: testBucket("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			wants: wants{
+				code: 400,
+				body: `{"code":"invalid","message":"invalid partial; must be true or false"}`,
+			},
+		},
 		{
 			name: "forbidden to write with insufficient permission",
 			request: request{
@@ -267,12 +633,13 @@ func TestWriteHandler_handleWrite(t *testing.T) {
 				return tt.state.bucket, tt.state.bucketErr
 			}
 
+			pw := &mock.PointsWriter{Err: tt.state.writeErr}
 			b := &APIBackend{
 				HTTPErrorHandler:    DefaultErrorHandler,
 				Logger:              zaptest.NewLogger(t),
 				OrganizationService: orgs,
 				BucketService:       buckets,
-				PointsWriter:        &mock.PointsWriter{Err: tt.state.writeErr},
+				PointsWriter:        pw,
 				WriteEventRecorder:  &metric.NopEventRecorder{},
 			}
 			writeHandler := NewWriteHandler(NewWriteBackend(b))
@@ -287,6 +654,11 @@ func TestWriteHandler_handleWrite(t *testing.T) {
 			params := r.URL.Query()
 			params.Set("org", tt.request.org)
 			params.Set("bucket", tt.request.bucket)
+			for k, v := range map[string]string{"db": tt.request.db, "rp": tt.request.rp, "precision": tt.request.precision, "partial": tt.request.partial} {
+				if v != "" {
+					params.Set(k, v)
+				}
+			}
 			r.URL.RawQuery = params.Encode()
 
 			w := httptest.NewRecorder()
@@ -298,6 +670,19 @@ func TestWriteHandler_handleWrite(t *testing.T) {
 			if got, want := w.Body.String(), tt.wants.body; got != want {
 				t.Errorf("unexpected body: got %s want %s", got, want)
 			}
+
+			if tt.wants.points != 0 && len(pw.Points) != tt.wants.points {
+				t.Errorf("unexpected number of points written: got %d want %d", len(pw.Points), tt.wants.points)
+			}
+
+			if tt.wants.time != 0 {
+				if len(pw.Points) != 1 {
+					t.Fatalf("unexpected number of points written: got %d want 1", len(pw.Points))
+				}
+				if got, want := pw.Points[0].Time().UnixNano(), tt.wants.time; got != want {
+					t.Errorf("unexpected point time: got %d want %d", got, want)
+				}
+			}
 		})
 	}
 }
@@ -84,6 +84,16 @@ func (s *Service) forEachBucket(ctx context.Context, descending bool, fn func(b
 }
 
 func (s *Service) filterBuckets(ctx context.Context, fn func(b *platform.Bucket) bool, opts ...platform.FindOptions) ([]*platform.Bucket, error) {
+	if len(opts) > 0 && opts[0].SortBy != "" {
+		// a sorted page can only be taken once every matching bucket is sorted
+		bs, err := s.filterBuckets(ctx, fn)
+		if err != nil {
+			return nil, err
+		}
+		platform.SortBuckets(opts[0], bs)
+		return platform.PageBuckets(opts[0], bs), nil
+	}
+
 	var offset, limit, count int
 	var descending bool
 	if len(opts) > 0 {
@@ -279,6 +289,10 @@ func (s *Service) UpdateBucket(ctx context.Context, id platform.ID, upd platform
 		b.RetentionPeriod = *upd.RetentionPeriod
 	}
 
+	if upd.ShardGroupDuration != nil {
+		b.ShardGroupDuration = *upd.ShardGroupDuration
+	}
+
 	if upd.Description != nil {
 		b.Description = *upd.Description
 	}
@@ -1,7 +1,9 @@
 package jsonweb
 
 import (
+	"crypto/rsa"
 	"errors"
+	"fmt"
 
 	"github.com/dgrijalva/jwt-go"
 	"github.com/influxdata/influxdb"
@@ -14,6 +16,10 @@ var (
 	// a key cannot be located for the provided key ID
 	ErrKeyNotFound = errors.New("key not found")
 
+	// ErrAudienceMismatch is returned when a token's audience does not
+	// match the audience expected by the TokenParser
+	ErrAudienceMismatch = errors.New("token audience does not match expected audience")
+
 	// EmptyKeyStore is a KeyStore implementation which contains no keys
 	EmptyKeyStore = KeyStoreFunc(func(string) ([]byte, error) {
 		return nil, ErrKeyNotFound
@@ -32,21 +38,95 @@ type KeyStoreFunc func(string) ([]byte, error)
 // Key delegates to the receiver KeyStoreFunc
 func (k KeyStoreFunc) Key(v string) ([]byte, error) { return k(v) }
 
+// VerifyKey is a key along with the signing method of the tokens
+// it verifies
+type VerifyKey struct {
+	Method jwt.SigningMethod
+	// Key is a []byte for HS256 and a *rsa.PublicKey for RS256
+	Key interface{}
+}
+
+// HS256Key returns a VerifyKey for tokens signed with the symmetric key
+func HS256Key(key []byte) VerifyKey {
+	return VerifyKey{Method: jwt.SigningMethodHS256, Key: key}
+}
+
+// RS256Key returns a VerifyKey for tokens signed with the private
+// key of the RSA public key
+func RS256Key(key *rsa.PublicKey) VerifyKey {
+	return VerifyKey{Method: jwt.SigningMethodRS256, Key: key}
+}
+
+// VerifyKeyStore is a type which holds a set of keys, aware of the
+// signing method they verify, accessed via an id
+type VerifyKeyStore interface {
+	VerifyKey(string) (VerifyKey, error)
+}
+
+// VerifyKeyStoreFunc is a function which can be used as a VerifyKeyStore
+type VerifyKeyStoreFunc func(string) (VerifyKey, error)
+
+// VerifyKey delegates to the receiver VerifyKeyStoreFunc
+func (k VerifyKeyStoreFunc) VerifyKey(v string) (VerifyKey, error) { return k(v) }
+
+// hs256KeyStore is a VerifyKeyStore of the symmetric keys of a KeyStore
+type hs256KeyStore struct {
+	KeyStore
+}
+
+func (k hs256KeyStore) VerifyKey(v string) (VerifyKey, error) {
+	key, err := k.Key(v)
+	if err != nil {
+		return VerifyKey{}, err
+	}
+	return HS256Key(key), nil
+}
+
 // TokenParser is a type which can parse and validate tokens
 type TokenParser struct {
-	keyStore KeyStore
+	keyStore VerifyKeyStore
 	parser   *jwt.Parser
+	audience string
+}
+
+// TokenParserOption is a functional option for configuring a TokenParser
+type TokenParserOption func(*TokenParser)
+
+// WithExpectedAudience configures the parser to reject any token
+// whose audience ("aud") claim does not match the provided audience
+func WithExpectedAudience(aud string) TokenParserOption {
+	return func(t *TokenParser) {
+		t.audience = aud
+	}
 }
 
 // NewTokenParser returns a configured token parser used to
-// parse Token types from strings
-func NewTokenParser(keyStore KeyStore) *TokenParser {
-	return &TokenParser{
+// parse Token types from strings. The keys of the key store
+// verify tokens signed with HS256.
+func NewTokenParser(keyStore KeyStore, opts ...TokenParserOption) *TokenParser {
+	return NewVerifyKeyTokenParser(hs256KeyStore{keyStore}, opts...)
+}
+
+// NewVerifyKeyTokenParser returns a configured token parser used to
+// parse Token types from strings. Tokens signed with either HS256
+// or RS256 are verified, a token is only verified by a key of the
+// signing method it is signed with.
+func NewVerifyKeyTokenParser(keyStore VerifyKeyStore, opts ...TokenParserOption) *TokenParser {
+	t := &TokenParser{
 		keyStore: keyStore,
 		parser: &jwt.Parser{
-			ValidMethods: []string{jwt.SigningMethodHS256.Alg()},
+			ValidMethods: []string{
+				jwt.SigningMethodHS256.Alg(),
+				jwt.SigningMethodRS256.Alg(),
+			},
 		},
 	}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	return t
 }
 
 // Parse takes a string then parses and validates it as a jwt based on
@@ -59,7 +139,18 @@ func (t *TokenParser) Parse(v string) (*Token, error) {
 		}
 
 		// fetch key for "kid" from key store
-		return t.keyStore.Key(token.KeyID)
+		key, err := t.keyStore.VerifyKey(token.KeyID)
+		if err != nil {
+			return nil, err
+		}
+
+		// a token must not be verified by a key of another signing method,
+		// i.e. an RSA public key used as the secret of a HS256 token
+		if key.Method == nil || key.Method.Alg() != jwt.Method.Alg() {
+			return nil, fmt.Errorf("key %q does not verify signing method %s", token.KeyID, jwt.Method.Alg())
+		}
+
+		return key.Key, nil
 	})
 
 	if err != nil {
@@ -71,9 +162,38 @@ func (t *TokenParser) Parse(v string) (*Token, error) {
 		return nil, errors.New("token is unexpected type")
 	}
 
+	if t.audience != "" && !token.VerifyAudience(t.audience, true) {
+		return nil, ErrAudienceMismatch
+	}
+
 	return token, nil
 }
 
+// Decode decodes the claims of the token without verifying its signature. The
+// claims of a decoded token can not be trusted, it is only useful to inspect
+// a token.
+func Decode(v string) (*Token, error) {
+	var token Token
+	if _, _, err := new(jwt.Parser).ParseUnverified(v, &token); err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// Sign signs the token with the key using HS256. The KeyID of the token
+// identifies the key a TokenParser verifies the signature with.
+func Sign(token *Token, key []byte) (string, error) {
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, token).SignedString(key)
+}
+
+// SignRS256 signs the token with the RSA private key using RS256. The
+// KeyID of the token identifies the public key a TokenParser verifies
+// the signature with.
+func SignRS256(token *Token, key *rsa.PrivateKey) (string, error) {
+	return jwt.NewWithClaims(jwt.SigningMethodRS256, token).SignedString(key)
+}
+
 // IsMalformedError returns true if the error returned represents
 // a jwt malformed token error
 func IsMalformedError(err error) bool {
@@ -81,6 +201,28 @@ func IsMalformedError(err error) bool {
 	return ok && verr.Errors&jwt.ValidationErrorMalformed > 0
 }
 
+// unverifiedErrors are the validation errors of a token whose
+// signature could not be verified, its claims can not be trusted
+const unverifiedErrors = jwt.ValidationErrorMalformed |
+	jwt.ValidationErrorUnverifiable |
+	jwt.ValidationErrorSignatureInvalid
+
+// IsExpiredError returns true if the error returned represents
+// a jwt expired token error. A token whose signature is not
+// verified is never reported as expired.
+func IsExpiredError(err error) bool {
+	verr, ok := err.(*jwt.ValidationError)
+	return ok && verr.Errors&jwt.ValidationErrorExpired > 0 && verr.Errors&unverifiedErrors == 0
+}
+
+// IsNotValidYetError returns true if the error returned represents
+// a jwt token used before its not before time. A token whose
+// signature is not verified is never reported as not valid yet.
+func IsNotValidYetError(err error) bool {
+	verr, ok := err.(*jwt.ValidationError)
+	return ok && verr.Errors&jwt.ValidationErrorNotValidYet > 0 && verr.Errors&unverifiedErrors == 0
+}
+
 // Token is a structure which is serialized as a json web token
 // It contains the necessary claims required to authorize
 type Token struct {
@@ -1,8 +1,11 @@
 package jsonweb
 
 import (
+	"crypto/rand"
+	"crypto/rsa"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/dgrijalva/jwt-go"
 	"github.com/google/go-cmp/cmp"
@@ -83,3 +86,225 @@ func Test_TokenParser(t *testing.T) {
 		})
 	}
 }
+
+func Test_TokenParser_ExpectedAudience(t *testing.T) {
+	sign := func(t *testing.T, aud string) string {
+		t.Helper()
+
+		v, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &Token{
+			StandardClaims: jwt.StandardClaims{
+				Issuer:   "cloud2.influxdata.com",
+				Audience: aud,
+				IssuedAt: 1568628980,
+			},
+			KeyID: "some-key",
+		}).SignedString([]byte("correct-key"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return v
+	}
+
+	for _, test := range []struct {
+		name     string
+		audience string
+		opts     []TokenParserOption
+		// expectations
+		err error
+	}{
+		{
+			name:     "no expected audience",
+			audience: "gateway.influxdata.com",
+		},
+		{
+			name:     "matching audience",
+			audience: "gateway.influxdata.com",
+			opts:     []TokenParserOption{WithExpectedAudience("gateway.influxdata.com")},
+		},
+		{
+			name:     "mismatching audience",
+			audience: "other.influxdata.com",
+			opts:     []TokenParserOption{WithExpectedAudience("gateway.influxdata.com")},
+			err:      ErrAudienceMismatch,
+		},
+		{
+			name: "absent audience",
+			opts: []TokenParserOption{WithExpectedAudience("gateway.influxdata.com")},
+			err:  ErrAudienceMismatch,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			parser := NewTokenParser(keyStore, test.opts...)
+
+			token, err := parser.Parse(sign(t, test.audience))
+			if !reflect.DeepEqual(test.err, err) {
+				t.Errorf("expected %[1]s (%#[1]v), got %[2]s (%#[2]v)", test.err, err)
+			}
+
+			if test.err != nil {
+				if token != nil {
+					t.Errorf("expected nil token, got %v", token)
+				}
+				return
+			}
+
+			if token.Audience != test.audience {
+				t.Errorf("expected audience %q, got %q", test.audience, token.Audience)
+			}
+		})
+	}
+}
+
+func Test_TokenParser_VerifyKey(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otherRSAKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyStore := VerifyKeyStoreFunc(func(kid string) (VerifyKey, error) {
+		switch kid {
+		case "hs-key":
+			return HS256Key([]byte("correct-key")), nil
+		case "rs-key":
+			return RS256Key(&rsaKey.PublicKey), nil
+		default:
+			return VerifyKey{}, ErrKeyNotFound
+		}
+	})
+
+	newToken := func(kid string) *Token {
+		return &Token{
+			StandardClaims: jwt.StandardClaims{
+				Issuer:   "cloud2.influxdata.com",
+				IssuedAt: 1568628980,
+			},
+			KeyID: kid,
+		}
+	}
+
+	for _, test := range []struct {
+		name  string
+		sign  func() (string, error)
+		valid bool
+	}{
+		{
+			name: "symmetric key",
+			sign: func() (string, error) {
+				return Sign(newToken("hs-key"), []byte("correct-key"))
+			},
+			valid: true,
+		},
+		{
+			name: "rsa key",
+			sign: func() (string, error) {
+				return SignRS256(newToken("rs-key"), rsaKey)
+			},
+			valid: true,
+		},
+		{
+			name: "unknown rsa key",
+			sign: func() (string, error) {
+				return SignRS256(newToken("rs-key"), otherRSAKey)
+			},
+		},
+		{
+			name: "unknown key id",
+			sign: func() (string, error) {
+				return SignRS256(newToken("some-other-key"), rsaKey)
+			},
+		},
+		{
+			name: "signing method of another key",
+			sign: func() (string, error) {
+				return Sign(newToken("rs-key"), []byte("correct-key"))
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			v, err := test.sign()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			token, err := NewVerifyKeyTokenParser(keyStore).Parse(v)
+			if !test.valid {
+				if err == nil {
+					t.Errorf("expected error, got token %v", token)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if diff := cmp.Diff(newToken(token.KeyID), token); diff != "" {
+				t.Errorf("unexpected token:\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_TokenParser_ValidityWindow(t *testing.T) {
+	var (
+		past   = time.Now().Add(-time.Hour).Unix()
+		future = time.Now().Add(time.Hour).Unix()
+	)
+
+	for _, test := range []struct {
+		name        string
+		claims      jwt.StandardClaims
+		key         []byte
+		expired     bool
+		notValidYet bool
+	}{
+		{
+			name:   "within the validity window",
+			claims: jwt.StandardClaims{ExpiresAt: future, NotBefore: past},
+			key:    []byte("correct-key"),
+		},
+		{
+			name:    "expired",
+			claims:  jwt.StandardClaims{ExpiresAt: past},
+			key:     []byte("correct-key"),
+			expired: true,
+		},
+		{
+			name:        "not valid yet",
+			claims:      jwt.StandardClaims{NotBefore: future},
+			key:         []byte("correct-key"),
+			notValidYet: true,
+		},
+		{
+			name:   "expired with invalid signature",
+			claims: jwt.StandardClaims{ExpiresAt: past},
+			key:    []byte("incorrect-key"),
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			v, err := Sign(&Token{StandardClaims: test.claims, KeyID: "some-key"}, test.key)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = NewTokenParser(keyStore).Parse(v)
+			if got := IsExpiredError(err); got != test.expired {
+				t.Errorf("expected IsExpiredError %v, got %v (%v)", test.expired, got, err)
+			}
+
+			if got := IsNotValidYetError(err); got != test.notValidYet {
+				t.Errorf("expected IsNotValidYetError %v, got %v (%v)", test.notValidYet, got, err)
+			}
+
+			if got := IsMalformedError(err); got {
+				t.Errorf("expected token not to be malformed (%v)", err)
+			}
+		})
+	}
+}
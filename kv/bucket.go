@@ -397,6 +397,16 @@ func (s *Service) findBuckets(ctx context.Context, tx Tx, filter influxdb.Bucket
 		filter.OrganizationID = &o.ID
 	}
 
+	if len(opts) > 0 && opts[0].SortBy != "" {
+		// a sorted page can only be taken once every matching bucket is sorted
+		bs, err := s.findBuckets(ctx, tx, filter)
+		if err != nil {
+			return nil, err
+		}
+		influxdb.SortBuckets(opts[0], bs)
+		return influxdb.PageBuckets(opts[0], bs), nil
+	}
+
 	var offset, limit, count int
 	var descending bool
 	if len(opts) > 0 {
@@ -688,6 +698,10 @@ func (s *Service) updateBucket(ctx context.Context, tx Tx, id influxdb.ID, upd i
 		b.RetentionPeriod = *upd.RetentionPeriod
 	}
 
+	if upd.ShardGroupDuration != nil {
+		b.ShardGroupDuration = *upd.ShardGroupDuration
+	}
+
 	if upd.Description != nil {
 		b.Description = *upd.Description
 	}
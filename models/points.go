@@ -1177,6 +1177,13 @@ func skipWhitespace(buf []byte, i int) int {
 	return i
 }
 
+// ScanLine returns the end position in buf and the next line found within
+// buf, starting at i. A newline in a quoted string field value does not end
+// the line.
+func ScanLine(buf []byte, i int) (int, []byte) {
+	return scanLine(buf, i)
+}
+
 // scanLine returns the end position in buf and the next line found within
 // buf.
 func scanLine(buf []byte, i int) (int, []byte) {
@@ -3,6 +3,7 @@ package pkger
 import (
 	"errors"
 	"sort"
+	"strings"
 
 	"github.com/influxdata/influxdb"
 )
@@ -25,16 +26,42 @@ func (r ResourceToClone) OK() error {
 	return nil
 }
 
+type cloneKey struct {
+	kind Kind
+	id   influxdb.ID
+}
+
+func newCloneKey(r ResourceToClone) cloneKey {
+	return cloneKey{
+		kind: Kind(strings.TrimSpace(strings.ToLower(string(r.Kind)))),
+		id:   r.ID,
+	}
+}
+
+// cloneDependency is a resource that must be cloned alongside the resource
+// found at resIdx in the pkg resources.
+type cloneDependency struct {
+	resIdx int
+	dep    ResourceToClone
+}
+
 func bucketToResource(bkt influxdb.Bucket, name string) Resource {
 	if name == "" {
 		name = bkt.Name
 	}
-	return Resource{
+	r := Resource{
 		fieldKind:                  KindBucket.String(),
 		fieldName:                  name,
 		fieldDescription:           bkt.Description,
 		fieldBucketRetentionPeriod: bkt.RetentionPeriod.String(),
 	}
+	if bkt.SchemaType != "" {
+		r[fieldBucketSchemaType] = string(bkt.SchemaType)
+	}
+	if bkt.ShardGroupDuration > 0 {
+		r[fieldBucketShardGroupDuration] = bkt.ShardGroupDuration.String()
+	}
+	return r
 }
 
 type cellView struct {
@@ -1,37 +1,61 @@
 package pkger
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/influxdata/flux/parser"
 	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/notification"
+	icheck "github.com/influxdata/influxdb/notification/check"
+	"github.com/influxdata/influxdb/notification/endpoint"
+	"github.com/influxdata/influxdb/notification/rule"
 )
 
 // Package kinds.
 const (
-	KindUnknown   Kind = ""
-	KindBucket    Kind = "bucket"
-	KindDashboard Kind = "dashboard"
-	KindLabel     Kind = "label"
-	KindPackage   Kind = "package"
-	KindVariable  Kind = "variable"
+	KindUnknown              Kind = ""
+	KindBucket               Kind = "bucket"
+	KindCheck                Kind = "check"
+	KindDashboard            Kind = "dashboard"
+	KindLabel                Kind = "label"
+	KindNotificationEndpoint Kind = "notificationendpoint"
+	KindNotificationRule     Kind = "notificationrule"
+	KindPackage              Kind = "package"
+	KindTelegraf             Kind = "telegraf"
+	KindVariable             Kind = "variable"
 )
 
 var kinds = map[Kind]bool{
-	KindBucket:    true,
-	KindDashboard: true,
-	KindLabel:     true,
-	KindPackage:   true,
-	KindVariable:  true,
+	KindBucket:               true,
+	KindCheck:                true,
+	KindDashboard:            true,
+	KindLabel:                true,
+	KindNotificationEndpoint: true,
+	KindNotificationRule:     true,
+	KindPackage:              true,
+	KindTelegraf:             true,
+	KindVariable:             true,
 }
 
 // Kind is a resource kind.
 type Kind string
 
+// newKind normalizes the kind. The plural form of a kind, i.e. Buckets, is
+// tolerated and provided as its singular kind.
 func newKind(s string) Kind {
-	return Kind(strings.TrimSpace(strings.ToLower(s)))
+	k := Kind(strings.TrimSpace(strings.ToLower(s)))
+	if singular := Kind(strings.TrimSuffix(string(k), "s")); !kinds[k] && kinds[singular] {
+		return singular
+	}
+	return k
 }
 
 // String provides the kind in human readable form.
@@ -47,19 +71,33 @@ func (k Kind) String() string {
 
 // OK validates the kind is valid.
 func (k Kind) OK() error {
-	newKind := Kind(strings.ToLower(string(k)))
-	if newKind == KindUnknown {
+	normed := newKind(string(k))
+	if normed == KindUnknown {
 		return errors.New("invalid kind")
 	}
-	if !kinds[newKind] {
-		return errors.New("unsupported kind provided")
+	if !kinds[normed] {
+		return fmt.Errorf("unsupported kind provided: %q; must be one of %s", string(k), supportedKinds())
 	}
 	return nil
 }
 
+// Normed returns the kind in its normalized form, i.e. the Buckets kind is
+// returned as bucket.
+func (k Kind) Normed() Kind {
+	return newKind(string(k))
+}
+
 func (k Kind) is(comp Kind) bool {
-	normed := Kind(strings.TrimSpace(strings.ToLower(string(k))))
-	return normed == comp
+	return newKind(string(k)) == comp
+}
+
+func supportedKinds() string {
+	var out []string
+	for k := range kinds {
+		out = append(out, string(k))
+	}
+	sort.Strings(out)
+	return strings.Join(out, ", ")
 }
 
 // SafeID is an equivalent influxdb.ID that encodes safely with
@@ -89,11 +127,66 @@ type Metadata struct {
 // Diff is the result of a service DryRun call. The diff outlines
 // what is new and or updated from the current state of the platform.
 type Diff struct {
-	Buckets       []DiffBucket       `json:"buckets"`
-	Dashboards    []DiffDashboard    `json:"dashboards"`
-	Labels        []DiffLabel        `json:"labels"`
-	LabelMappings []DiffLabelMapping `json:"labelMappings"`
-	Variables     []DiffVariable     `json:"variables"`
+	Buckets               []DiffBucket               `json:"buckets"`
+	Checks                []DiffCheck                `json:"checks"`
+	Dashboards            []DiffDashboard            `json:"dashboards"`
+	Labels                []DiffLabel                `json:"labels"`
+	LabelMappings         []DiffLabelMapping         `json:"labelMappings"`
+	NotificationEndpoints []DiffNotificationEndpoint `json:"notificationEndpoints"`
+	NotificationRules     []DiffNotificationRule     `json:"notificationRules"`
+	Telegrafs             []DiffTelegraf             `json:"telegrafConfigs"`
+	Variables             []DiffVariable             `json:"variables"`
+	Deletions             []DiffDeletion             `json:"deletions"`
+	Prunes                []DiffDeletion             `json:"prunes"`
+}
+
+// HasChanges indicates whether applying the pkg would create or update
+// any resources. A diff without changes indicates the pkg is already
+// fully applied.
+func (d Diff) HasChanges() bool {
+	for _, b := range d.Buckets {
+		if b.HasChanges() {
+			return true
+		}
+	}
+	for _, c := range d.Checks {
+		if c.HasChanges() {
+			return true
+		}
+	}
+	for _, l := range d.Labels {
+		if l.HasChanges() {
+			return true
+		}
+	}
+	for _, v := range d.Variables {
+		if v.HasChanges() {
+			return true
+		}
+	}
+	for _, m := range d.LabelMappings {
+		if m.IsNew {
+			return true
+		}
+	}
+	if len(d.Deletions) > 0 || len(d.Prunes) > 0 {
+		return true
+	}
+	// dashboards, notification endpoints, notification rules and telegraf
+	// configs are always created new
+	return len(d.Dashboards) > 0 ||
+		len(d.NotificationEndpoints) > 0 ||
+		len(d.NotificationRules) > 0 ||
+		len(d.Telegrafs) > 0
+}
+
+// DiffDeletion is an existing resource that is deleted when the pkg is applied
+// with replace, as it is not present in the pkg. When applied with prune, it
+// is an existing resource that belongs to the pkg but is no longer present in it.
+type DiffDeletion struct {
+	Kind Kind   `json:"kind"`
+	ID   SafeID `json:"id"`
+	Name string `json:"name"`
 }
 
 // DiffBucket is a diff of an individual bucket.
@@ -104,6 +197,11 @@ type DiffBucket struct {
 	NewDesc      string        `json:"newDescription"`
 	OldRetention time.Duration `json:"oldRP"`
 	NewRetention time.Duration `json:"newRP"`
+	OldShardDur  time.Duration `json:"oldShardGroupDuration"`
+	NewShardDur  time.Duration `json:"newShardGroupDuration"`
+
+	OldSchemaType influxdb.SchemaType `json:"oldSchemaType"`
+	NewSchemaType influxdb.SchemaType `json:"newSchemaType"`
 }
 
 // IsNew indicates whether a pkg bucket is going to be new to the platform.
@@ -111,15 +209,88 @@ func (d DiffBucket) IsNew() bool {
 	return d.ID == SafeID(0)
 }
 
+// HasChanges indicates whether the bucket will be created or updated.
+func (d DiffBucket) HasChanges() bool {
+	return d.IsNew() ||
+		d.OldDesc != d.NewDesc ||
+		d.OldRetention != d.NewRetention ||
+		d.OldShardDur != d.NewShardDur ||
+		d.OldSchemaType != d.NewSchemaType
+}
+
 func newDiffBucket(b *bucket, i influxdb.Bucket) DiffBucket {
+	oldSchemaType := i.SchemaType
+	if i.ID.Valid() && oldSchemaType == "" {
+		// buckets created before schema types existed are implicit
+		oldSchemaType = influxdb.SchemaTypeImplicit
+	}
 	return DiffBucket{
-		ID:           SafeID(i.ID),
-		Name:         b.Name,
-		OldDesc:      i.Description,
-		NewDesc:      b.Description,
-		OldRetention: i.RetentionPeriod,
-		NewRetention: b.RetentionPeriod,
+		ID:            SafeID(i.ID),
+		Name:          b.Name,
+		OldDesc:       i.Description,
+		NewDesc:       b.Description,
+		OldRetention:  i.RetentionPeriod,
+		NewRetention:  b.RetentionPeriod,
+		OldShardDur:   i.ShardGroupDuration,
+		NewShardDur:   b.shardGroupDuration(i.ShardGroupDuration),
+		OldSchemaType: oldSchemaType,
+		NewSchemaType: b.SchemaType,
+	}
+}
+
+// DiffCheck is a diff of an individual check.
+type DiffCheck struct {
+	ID   SafeID         `json:"id"`
+	Name string         `json:"name"`
+	Old  influxdb.Check `json:"old"`
+	New  influxdb.Check `json:"new"`
+}
+
+// IsNew indicates whether a pkg check is going to be new to the platform.
+func (d DiffCheck) IsNew() bool {
+	return d.ID == SafeID(0)
+}
+
+// HasChanges indicates whether the check will be created or updated.
+func (d DiffCheck) HasChanges() bool {
+	return d.IsNew() || !reflect.DeepEqual(d.Old, d.New)
+}
+
+// UnmarshalJSON decodes the old and new checks by their check type.
+func (d *DiffCheck) UnmarshalJSON(b []byte) error {
+	var out struct {
+		ID   SafeID          `json:"id"`
+		Name string          `json:"name"`
+		Old  json.RawMessage `json:"old"`
+		New  json.RawMessage `json:"new"`
+	}
+	if err := json.Unmarshal(b, &out); err != nil {
+		return err
+	}
+
+	oldCheck, err := unmarshalCheck(out.Old)
+	if err != nil {
+		return err
+	}
+	newCheck, err := unmarshalCheck(out.New)
+	if err != nil {
+		return err
 	}
+
+	d.ID, d.Name, d.Old, d.New = out.ID, out.Name, oldCheck, newCheck
+	return nil
+}
+
+func newDiffCheck(c *check, existing influxdb.Check) DiffCheck {
+	diff := DiffCheck{
+		Name: c.Name,
+		New:  c.influxCheck(),
+	}
+	if existing != nil {
+		diff.ID = SafeID(existing.GetID())
+		diff.Old = existing
+	}
+	return diff
 }
 
 // DiffDashboard is a diff of an individual dashboard.
@@ -165,6 +336,11 @@ func (d DiffLabel) IsNew() bool {
 	return d.ID == SafeID(0)
 }
 
+// HasChanges indicates whether the label will be created or updated.
+func (d DiffLabel) HasChanges() bool {
+	return d.IsNew() || d.OldColor != d.NewColor || d.OldDesc != d.NewDesc
+}
+
 func newDiffLabel(l *label, i influxdb.Label) DiffLabel {
 	return DiffLabel{
 		ID:       SafeID(i.ID),
@@ -190,6 +366,64 @@ type DiffLabelMapping struct {
 	LabelName string `json:"labelName"`
 }
 
+// DiffNotificationEndpoint is a diff of an individual notification endpoint.
+// Since all notification endpoints are new right now, only the new endpoint
+// is provided.
+type DiffNotificationEndpoint struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	Desc string `json:"description"`
+}
+
+func newDiffNotificationEndpoint(e *notificationEndpoint) DiffNotificationEndpoint {
+	return DiffNotificationEndpoint{
+		Name: e.Name,
+		Type: e.Type,
+		Desc: e.Description,
+	}
+}
+
+// DiffNotificationRule is a diff of an individual notification rule. Since
+// all notification rules are new right now, only the new rule is provided.
+type DiffNotificationRule struct {
+	Name         string `json:"name"`
+	Desc         string `json:"description"`
+	CheckName    string `json:"checkName,omitempty"`
+	EndpointName string `json:"endpointName"`
+	EndpointType string `json:"endpointType"`
+	Every        string `json:"every"`
+	Offset       string `json:"offset"`
+}
+
+func newDiffNotificationRule(r *notificationRule) DiffNotificationRule {
+	diff := DiffNotificationRule{
+		Name:         r.Name,
+		Desc:         r.Description,
+		EndpointName: r.endpoint.Name,
+		EndpointType: r.endpoint.Type,
+		Every:        r.Every,
+		Offset:       r.Offset,
+	}
+	if r.check != nil {
+		diff.CheckName = r.check.Name
+	}
+	return diff
+}
+
+// DiffTelegraf is a diff of an individual telegraf config. Since all
+// telegraf configs are new right now, only the new config is provided.
+type DiffTelegraf struct {
+	Name string `json:"name"`
+	Desc string `json:"description"`
+}
+
+func newDiffTelegraf(t *telegraf) DiffTelegraf {
+	return DiffTelegraf{
+		Name: t.Name(),
+		Desc: t.config.Description,
+	}
+}
+
 // DiffVariable is a diff of an individual variable.
 type DiffVariable struct {
 	ID      SafeID `json:"id"`
@@ -217,14 +451,108 @@ func (d DiffVariable) IsNew() bool {
 	return d.ID == SafeID(0)
 }
 
+// HasChanges indicates whether the variable will be created or updated.
+func (d DiffVariable) HasChanges() bool {
+	return d.IsNew() || d.OldDesc != d.NewDesc || !reflect.DeepEqual(d.OldArgs, d.NewArgs)
+}
+
 // Summary is a definition of all the resources that have or
 // will be created from a pkg.
 type Summary struct {
-	Buckets       []SummaryBucket       `json:"buckets"`
-	Dashboards    []SummaryDashboard    `json:"dashboards"`
-	Labels        []SummaryLabel        `json:"labels"`
-	LabelMappings []SummaryLabelMapping `json:"labelMappings"`
-	Variables     []SummaryVariable     `json:"variables"`
+	Buckets               []SummaryBucket               `json:"buckets"`
+	Checks                []SummaryCheck                `json:"checks"`
+	Dashboards            []SummaryDashboard            `json:"dashboards"`
+	Labels                []SummaryLabel                `json:"labels"`
+	LabelMappings         []SummaryLabelMapping         `json:"labelMappings"`
+	NotificationEndpoints []SummaryNotificationEndpoint `json:"notificationEndpoints"`
+	NotificationRules     []SummaryNotificationRule     `json:"notificationRules"`
+	TelegrafConfigs       []SummaryTelegraf             `json:"telegrafConfigs"`
+	Variables             []SummaryVariable             `json:"variables"`
+	Breakdown             []SummaryBreakdown            `json:"breakdown"`
+}
+
+// BreakdownOf returns the breakdown of the resources of the provided kind.
+// The breakdown is empty when the summary contains no resources of the kind.
+func (s Summary) BreakdownOf(k Kind) SummaryBreakdown {
+	for _, b := range s.Breakdown {
+		if b.Kind.is(k) {
+			return b
+		}
+	}
+	return SummaryBreakdown{Kind: k}
+}
+
+// SummaryBreakdown counts the resources of a kind by what applying the pkg
+// does to them. Created resources are new to the platform, updated resources
+// exist and differ from the pkg, unchanged resources exist and match the pkg.
+// Skipped resources are left as they exist even though they differ from the pkg.
+type SummaryBreakdown struct {
+	Kind      Kind `json:"kind"`
+	Created   int  `json:"created"`
+	Updated   int  `json:"updated"`
+	Unchanged int  `json:"unchanged"`
+	Skipped   int  `json:"skipped"`
+}
+
+// Total is the number of resources of the kind in the pkg.
+func (b SummaryBreakdown) Total() int {
+	return b.Created + b.Updated + b.Unchanged + b.Skipped
+}
+
+// newSummaryBreakdown counts the resources of the diff by kind. Dashboards,
+// notification endpoints, notification rules and telegraf configs are always
+// created new.
+func newSummaryBreakdown(diff Diff) []SummaryBreakdown {
+	counts := make(map[Kind]*SummaryBreakdown)
+	count := func(k Kind, isNew, hasChanges bool) {
+		b, ok := counts[k]
+		if !ok {
+			b = &SummaryBreakdown{Kind: k}
+			counts[k] = b
+		}
+		switch {
+		case isNew:
+			b.Created++
+		case hasChanges:
+			b.Updated++
+		default:
+			b.Unchanged++
+		}
+	}
+
+	for _, b := range diff.Buckets {
+		count(KindBucket, b.IsNew(), b.HasChanges())
+	}
+	for _, c := range diff.Checks {
+		count(KindCheck, c.IsNew(), c.HasChanges())
+	}
+	for range diff.Dashboards {
+		count(KindDashboard, true, true)
+	}
+	for _, l := range diff.Labels {
+		count(KindLabel, l.IsNew(), l.HasChanges())
+	}
+	for range diff.NotificationEndpoints {
+		count(KindNotificationEndpoint, true, true)
+	}
+	for range diff.NotificationRules {
+		count(KindNotificationRule, true, true)
+	}
+	for range diff.Telegrafs {
+		count(KindTelegraf, true, true)
+	}
+	for _, v := range diff.Variables {
+		count(KindVariable, v.IsNew(), v.HasChanges())
+	}
+
+	breakdown := make([]SummaryBreakdown, 0, len(counts))
+	for _, b := range counts {
+		breakdown = append(breakdown, *b)
+	}
+	sort.Slice(breakdown, func(i, j int) bool {
+		return breakdown[i].Kind < breakdown[j].Kind
+	})
+	return breakdown
 }
 
 // SummaryBucket provides a summary of a pkg bucket.
@@ -233,6 +561,38 @@ type SummaryBucket struct {
 	LabelAssociations []influxdb.Label `json:"labelAssociations"`
 }
 
+// SummaryCheck provides a summary of a pkg check.
+type SummaryCheck struct {
+	Check             influxdb.Check   `json:"check"`
+	LabelAssociations []influxdb.Label `json:"labelAssociations"`
+}
+
+// UnmarshalJSON decodes the check by its check type.
+func (s *SummaryCheck) UnmarshalJSON(b []byte) error {
+	var out struct {
+		Check             json.RawMessage  `json:"check"`
+		LabelAssociations []influxdb.Label `json:"labelAssociations"`
+	}
+	if err := json.Unmarshal(b, &out); err != nil {
+		return err
+	}
+
+	c, err := unmarshalCheck(out.Check)
+	if err != nil {
+		return err
+	}
+
+	s.Check, s.LabelAssociations = c, out.LabelAssociations
+	return nil
+}
+
+func unmarshalCheck(b json.RawMessage) (influxdb.Check, error) {
+	if len(b) == 0 || string(b) == "null" {
+		return nil, nil
+	}
+	return icheck.UnmarshalJSON(b)
+}
+
 // SummaryDashboard provides a summary of a pkg dashboard.
 type SummaryDashboard struct {
 	ID          SafeID         `json:"id"`
@@ -253,15 +613,21 @@ type chartKind string
 const (
 	chartKindUnknown            chartKind = ""
 	chartKindGauge              chartKind = "gauge"
+	chartKindHeatMap            chartKind = "heatmap"
+	chartKindHistogram          chartKind = "histogram"
+	chartKindMarkdown           chartKind = "markdown"
+	chartKindScatter            chartKind = "scatter"
 	chartKindSingleStat         chartKind = "single_stat"
 	chartKindSingleStatPlusLine chartKind = "single_stat_plus_line"
+	chartKindTable              chartKind = "table"
 	chartKindXY                 chartKind = "xy"
 )
 
 func (c chartKind) ok() bool {
 	switch c {
 	case chartKindSingleStat, chartKindSingleStatPlusLine, chartKindXY,
-		chartKindGauge:
+		chartKindGauge, chartKindHeatMap, chartKindHistogram,
+		chartKindMarkdown, chartKindScatter, chartKindTable:
 		return true
 	default:
 		return false
@@ -291,6 +657,74 @@ type SummaryLabelMapping struct {
 	influxdb.LabelMapping
 }
 
+// SummaryNotificationEndpoint provides a summary of a pkg notification endpoint.
+type SummaryNotificationEndpoint struct {
+	NotificationEndpoint influxdb.NotificationEndpoint `json:"notificationEndpoint"`
+	LabelAssociations    []influxdb.Label              `json:"labelAssociations"`
+}
+
+// UnmarshalJSON decodes the notification endpoint by its endpoint type.
+func (s *SummaryNotificationEndpoint) UnmarshalJSON(b []byte) error {
+	var out struct {
+		NotificationEndpoint json.RawMessage  `json:"notificationEndpoint"`
+		LabelAssociations    []influxdb.Label `json:"labelAssociations"`
+	}
+	if err := json.Unmarshal(b, &out); err != nil {
+		return err
+	}
+
+	s.LabelAssociations = out.LabelAssociations
+	if len(out.NotificationEndpoint) == 0 || string(out.NotificationEndpoint) == "null" {
+		return nil
+	}
+
+	e, err := endpoint.UnmarshalJSON(out.NotificationEndpoint)
+	if err != nil {
+		return err
+	}
+	s.NotificationEndpoint = e
+	return nil
+}
+
+// SummaryNotificationRule provides a summary of a pkg notification rule.
+type SummaryNotificationRule struct {
+	NotificationRule  influxdb.NotificationRule `json:"notificationRule"`
+	CheckName         string                    `json:"checkName,omitempty"`
+	EndpointName      string                    `json:"endpointName"`
+	LabelAssociations []influxdb.Label          `json:"labelAssociations"`
+}
+
+// UnmarshalJSON decodes the notification rule by its rule type.
+func (s *SummaryNotificationRule) UnmarshalJSON(b []byte) error {
+	var out struct {
+		NotificationRule  json.RawMessage  `json:"notificationRule"`
+		CheckName         string           `json:"checkName"`
+		EndpointName      string           `json:"endpointName"`
+		LabelAssociations []influxdb.Label `json:"labelAssociations"`
+	}
+	if err := json.Unmarshal(b, &out); err != nil {
+		return err
+	}
+
+	s.CheckName, s.EndpointName, s.LabelAssociations = out.CheckName, out.EndpointName, out.LabelAssociations
+	if len(out.NotificationRule) == 0 || string(out.NotificationRule) == "null" {
+		return nil
+	}
+
+	r, err := rule.UnmarshalJSON(out.NotificationRule)
+	if err != nil {
+		return err
+	}
+	s.NotificationRule = r
+	return nil
+}
+
+// SummaryTelegraf provides a summary of a pkg telegraf config.
+type SummaryTelegraf struct {
+	TelegrafConfig    influxdb.TelegrafConfig `json:"telegrafConfig"`
+	LabelAssociations []influxdb.Label        `json:"labelAssociations"`
+}
+
 // SummaryVariable provides a summary of a pkg variable.
 type SummaryVariable struct {
 	influxdb.Variable
@@ -298,10 +732,19 @@ type SummaryVariable struct {
 }
 
 const (
+	// fieldAnnotations holds the documentation of a resource as a map of
+	// strings. Annotations are carried through formatting untouched, unlike
+	// comments, and are ignored when the pkg is applied.
+	fieldAnnotations  = "annotations"
 	fieldAssociations = "associations"
+	fieldDependency   = "dependency"
+	fieldDependsOn    = "dependsOn"
 	fieldDescription  = "description"
+	fieldEvery        = "every"
+	fieldID           = "id"
 	fieldKind         = "kind"
 	fieldName         = "name"
+	fieldOffset       = "offset"
 	fieldPrefix       = "prefix"
 	fieldQuery        = "query"
 	fieldSuffix       = "suffix"
@@ -311,16 +754,23 @@ const (
 )
 
 const (
-	fieldBucketRetentionPeriod = "retention_period"
+	fieldBucketRetentionPeriod    = "retention_period"
+	fieldBucketSchemaType         = "schemaType"
+	fieldBucketShardGroupDuration = "shard_group_duration"
 )
 
 type bucket struct {
-	id              influxdb.ID
-	OrgID           influxdb.ID
-	Description     string
-	Name            string
-	RetentionPeriod time.Duration
-	labels          []*label
+	id                 influxdb.ID
+	OrgID              influxdb.ID
+	Description        string
+	Name               string
+	RetentionPeriod    time.Duration
+	SchemaType         influxdb.SchemaType
+	ShardGroupDuration time.Duration
+	labels             []*label
+
+	// lookupID is the id the pkg provides for the bucket.
+	lookupID influxdb.ID
 
 	// existing provides context for a resource that already
 	// exists in the platform. If a resource already exists
@@ -346,21 +796,251 @@ func (b *bucket) Exists() bool {
 func (b *bucket) summarize() SummaryBucket {
 	return SummaryBucket{
 		Bucket: influxdb.Bucket{
-			ID:              b.ID(),
-			OrgID:           b.OrgID,
-			Name:            b.Name,
-			Description:     b.Description,
-			RetentionPeriod: b.RetentionPeriod,
+			ID:                 b.ID(),
+			OrgID:              b.OrgID,
+			Name:               b.Name,
+			Description:        b.Description,
+			RetentionPeriod:    b.RetentionPeriod,
+			SchemaType:         b.SchemaType,
+			ShardGroupDuration: b.ShardGroupDuration,
 		},
 		LabelAssociations: toInfluxLabels(b.labels...),
 	}
 }
 
+// shardGroupDuration returns the shard group duration the bucket will have
+// once applied. An unset duration leaves the current one in place.
+func (b *bucket) shardGroupDuration(current time.Duration) time.Duration {
+	if b.ShardGroupDuration == 0 {
+		return current
+	}
+	return b.ShardGroupDuration
+}
+
 func (b *bucket) shouldApply() bool {
 	return b.existing == nil ||
 		b.Description != b.existing.Description ||
 		b.Name != b.existing.Name ||
-		b.RetentionPeriod != b.existing.RetentionPeriod
+		b.RetentionPeriod != b.existing.RetentionPeriod ||
+		b.shardGroupDuration(b.existing.ShardGroupDuration) != b.existing.ShardGroupDuration
+}
+
+const (
+	fieldCheckAllValues             = "allValues"
+	fieldCheckLevel                 = "level"
+	fieldCheckMax                   = "max"
+	fieldCheckMin                   = "min"
+	fieldCheckStatusMessageTemplate = "statusMessageTemplate"
+	fieldCheckThresholds            = "thresholds"
+)
+
+type check struct {
+	id            influxdb.ID
+	OrgID         influxdb.ID
+	Name          string
+	Description   string
+	Query         string
+	StatusMessage string
+	Every         string
+	Offset        string
+	Thresholds    []threshold
+
+	labels []*label
+
+	// lookupID is the id the pkg provides for the check.
+	lookupID influxdb.ID
+
+	existing influxdb.Check
+	// existingStatus is the status of the existing check before the
+	// pkg is applied, it is restored on rollback.
+	existingStatus influxdb.Status
+}
+
+func (c *check) ID() influxdb.ID {
+	if c.existing != nil {
+		return c.existing.GetID()
+	}
+	return c.id
+}
+
+func (c *check) ResourceType() influxdb.ResourceType {
+	return influxdb.ChecksResourceType
+}
+
+func (c *check) Exists() bool {
+	return c.existing != nil
+}
+
+func (c *check) shouldApply() bool {
+	return c.existing == nil || !reflect.DeepEqual(c.existing, c.influxCheck())
+}
+
+func (c *check) summarize() SummaryCheck {
+	return SummaryCheck{
+		Check:             c.influxCheck(),
+		LabelAssociations: toInfluxLabels(c.labels...),
+	}
+}
+
+func (c *check) influxCheck() influxdb.Check {
+	base := icheck.Base{
+		ID:                    c.ID(),
+		Name:                  c.Name,
+		Description:           c.Description,
+		OrgID:                 c.OrgID,
+		Query:                 influxdb.DashboardQuery{Text: c.Query},
+		StatusMessageTemplate: c.StatusMessage,
+		Every:                 toNotificationDuration(c.Every),
+		Offset:                toNotificationDuration(c.Offset),
+	}
+	if c.existing != nil {
+		base.OwnerID = c.existing.GetOwnerID()
+		base.TaskID = c.existing.GetTaskID()
+		base.CRUDLog = c.existing.GetCRUDLog()
+	}
+
+	iThresholds := make([]icheck.ThresholdConfig, 0, len(c.Thresholds))
+	for _, th := range c.Thresholds {
+		iThresholds = append(iThresholds, th.influxThreshold())
+	}
+
+	return &icheck.Threshold{
+		Base:       base,
+		Thresholds: iThresholds,
+	}
+}
+
+func (c *check) valid() []failure {
+	var failures []failure
+	if strings.TrimSpace(c.Query) == "" {
+		failures = append(failures, failure{
+			Field: fieldQuery,
+			Msg:   "must provide a query",
+		})
+	}
+
+	failures = append(failures, validSchedule(c.Every, c.Offset)...)
+
+	if len(c.Thresholds) == 0 {
+		failures = append(failures, failure{
+			Field: fieldCheckThresholds,
+			Msg:   "at least 1 threshold must be provided",
+		})
+	}
+	for i, th := range c.Thresholds {
+		for _, f := range th.valid() {
+			failures = append(failures, failure{
+				Field: fmt.Sprintf("%s[%d].%s", fieldCheckThresholds, i, f.Field),
+				Msg:   f.Msg,
+			})
+		}
+	}
+
+	return failures
+}
+
+// validSchedule validates the every and offset flux durations of a task
+// backed resource. The every duration is required.
+func validSchedule(every, offset string) []failure {
+	var failures []failure
+	if every == "" {
+		failures = append(failures, failure{
+			Field: fieldEvery,
+			Msg:   "must provide a schedule duration",
+		})
+	} else if _, err := parser.ParseDuration(every); err != nil {
+		failures = append(failures, failure{
+			Field: fieldEvery,
+			Msg:   "invalid duration provided: " + err.Error(),
+		})
+	}
+
+	if offset != "" {
+		if _, err := parser.ParseDuration(offset); err != nil {
+			failures = append(failures, failure{
+				Field: fieldOffset,
+				Msg:   "invalid duration provided: " + err.Error(),
+			})
+		}
+	}
+	return failures
+}
+
+func toNotificationDuration(s string) *notification.Duration {
+	if s == "" {
+		return nil
+	}
+	dur, err := parser.ParseDuration(s)
+	if err != nil {
+		return nil
+	}
+	d := notification.Duration(*dur)
+	return &d
+}
+
+const (
+	thresholdTypeGreater      = "greater"
+	thresholdTypeLesser       = "lesser"
+	thresholdTypeInsideRange  = "inside_range"
+	thresholdTypeOutsideRange = "outside_range"
+)
+
+type threshold struct {
+	Type      string
+	Level     string
+	AllValues bool
+	Val       float64
+	Min       float64
+	Max       float64
+}
+
+func (t threshold) influxThreshold() icheck.ThresholdConfig {
+	base := icheck.ThresholdConfigBase{
+		AllValues: t.AllValues,
+		Level:     notification.ParseCheckLevel(t.Level),
+	}
+	switch t.Type {
+	case thresholdTypeGreater:
+		return &icheck.Greater{ThresholdConfigBase: base, Value: t.Val}
+	case thresholdTypeLesser:
+		return &icheck.Lesser{ThresholdConfigBase: base, Value: t.Val}
+	default:
+		return &icheck.Range{
+			ThresholdConfigBase: base,
+			Min:                 t.Min,
+			Max:                 t.Max,
+			Within:              t.Type == thresholdTypeInsideRange,
+		}
+	}
+}
+
+func (t threshold) valid() []failure {
+	var failures []failure
+	if !validCheckLevel(t.Level) {
+		failures = append(failures, failure{
+			Field: fieldCheckLevel,
+			Msg:   fmt.Sprintf("must be 1 in [CRIT, WARN, INFO, OK]; got=%q", t.Level),
+		})
+	}
+
+	switch t.Type {
+	case thresholdTypeGreater, thresholdTypeLesser:
+	case thresholdTypeInsideRange, thresholdTypeOutsideRange:
+		if t.Min > t.Max {
+			failures = append(failures, failure{
+				Field: fieldCheckMin,
+				Msg:   "min must be less than or equal to max",
+			})
+		}
+	default:
+		const msgFmt = "must be 1 in [%s, %s, %s, %s]; got=%q"
+		failures = append(failures, failure{
+			Field: fieldType,
+			Msg:   fmt.Sprintf(msgFmt, thresholdTypeGreater, thresholdTypeLesser, thresholdTypeInsideRange, thresholdTypeOutsideRange, t.Type),
+		})
+	}
+
+	return failures
 }
 
 type assocMapKey struct {
@@ -381,6 +1061,14 @@ func (l assocMapVal) bucket() (*bucket, bool) {
 	return b, ok
 }
 
+func (l assocMapVal) check() (*check, bool) {
+	if l.v == nil {
+		return nil, false
+	}
+	c, ok := l.v.(*check)
+	return c, ok
+}
+
 func (l assocMapVal) dashboard() (*dashboard, bool) {
 	if l.v == nil {
 		return nil, false
@@ -389,6 +1077,30 @@ func (l assocMapVal) dashboard() (*dashboard, bool) {
 	return d, ok
 }
 
+func (l assocMapVal) notificationEndpoint() (*notificationEndpoint, bool) {
+	if l.v == nil {
+		return nil, false
+	}
+	e, ok := l.v.(*notificationEndpoint)
+	return e, ok
+}
+
+func (l assocMapVal) notificationRule() (*notificationRule, bool) {
+	if l.v == nil {
+		return nil, false
+	}
+	r, ok := l.v.(*notificationRule)
+	return r, ok
+}
+
+func (l assocMapVal) telegraf() (*telegraf, bool) {
+	if l.v == nil {
+		return nil, false
+	}
+	t, ok := l.v.(*telegraf)
+	return t, ok
+}
+
 func (l assocMapVal) variable() (*variable, bool) {
 	if l.v == nil {
 		return nil, false
@@ -423,6 +1135,18 @@ func (l *associationMapping) setBucketMapping(b *bucket, exists bool) {
 	l.setMapping(key, val)
 }
 
+func (l *associationMapping) setCheckMapping(c *check, exists bool) {
+	key := assocMapKey{
+		resType: c.ResourceType(),
+		name:    c.Name,
+	}
+	val := assocMapVal{
+		exists: exists,
+		v:      c,
+	}
+	l.setMapping(key, val)
+}
+
 func (l *associationMapping) setDashboardMapping(d *dashboard) {
 	key := assocMapKey{
 		resType: d.ResourceType(),
@@ -432,6 +1156,33 @@ func (l *associationMapping) setDashboardMapping(d *dashboard) {
 	l.setMapping(key, val)
 }
 
+func (l *associationMapping) setNotificationEndpointMapping(e *notificationEndpoint) {
+	key := assocMapKey{
+		resType: e.ResourceType(),
+		name:    e.Name,
+	}
+	val := assocMapVal{v: e}
+	l.setMapping(key, val)
+}
+
+func (l *associationMapping) setNotificationRuleMapping(r *notificationRule) {
+	key := assocMapKey{
+		resType: r.ResourceType(),
+		name:    r.Name,
+	}
+	val := assocMapVal{v: r}
+	l.setMapping(key, val)
+}
+
+func (l *associationMapping) setTelegrafMapping(t *telegraf) {
+	key := assocMapKey{
+		resType: t.ResourceType(),
+		name:    t.Name(),
+	}
+	val := assocMapVal{v: t}
+	l.setMapping(key, val)
+}
+
 func (l *associationMapping) setVariableMapping(v *variable, exists bool) {
 	key := assocMapKey{
 		resType: v.ResourceType(),
@@ -448,102 +1199,555 @@ const (
 	fieldLabelColor = "color"
 )
 
-type label struct {
-	id          influxdb.ID
-	OrgID       influxdb.ID
-	Name        string
-	Color       string
-	Description string
-	associationMapping
+type label struct {
+	id          influxdb.ID
+	OrgID       influxdb.ID
+	Name        string
+	Color       string
+	Description string
+	associationMapping
+
+	// lookupID is the id the pkg provides for the label.
+	lookupID influxdb.ID
+
+	// exists provides context for a resource that already
+	// exists in the platform. If a resource already exists(exists=true)
+	// then the ID should be populated.
+	existing *influxdb.Label
+}
+
+func (l *label) shouldApply() bool {
+	return l.existing == nil ||
+		l.Description != l.existing.Properties["description"] ||
+		l.Name != l.existing.Name ||
+		l.Color != l.existing.Properties["color"]
+}
+
+func (l *label) ID() influxdb.ID {
+	if l.existing != nil {
+		return l.existing.ID
+	}
+	return l.id
+}
+
+func (l *label) summarize() SummaryLabel {
+	return SummaryLabel{
+		Label: influxdb.Label{
+			ID:         l.ID(),
+			OrgID:      l.OrgID,
+			Name:       l.Name,
+			Properties: l.properties(),
+		},
+	}
+}
+
+func (l *label) mappingSummary() []SummaryLabelMapping {
+	var mappings []SummaryLabelMapping
+	for res, lm := range l.mappings {
+		mappings = append(mappings, SummaryLabelMapping{
+			exists:       lm.exists,
+			ResourceName: res.name,
+			LabelName:    l.Name,
+			LabelMapping: influxdb.LabelMapping{
+				LabelID:      l.ID(),
+				ResourceID:   l.getMappedResourceID(res),
+				ResourceType: res.resType,
+			},
+		})
+	}
+
+	return mappings
+}
+
+func (l *label) getMappedResourceID(k assocMapKey) influxdb.ID {
+	switch k.resType {
+	case influxdb.BucketsResourceType:
+		b, ok := l.mappings[k].bucket()
+		if ok {
+			return b.ID()
+		}
+	case influxdb.ChecksResourceType:
+		c, ok := l.mappings[k].check()
+		if ok {
+			return c.ID()
+		}
+	case influxdb.DashboardsResourceType:
+		d, ok := l.mappings[k].dashboard()
+		if ok {
+			return d.ID()
+		}
+	case influxdb.NotificationEndpointResourceType:
+		e, ok := l.mappings[k].notificationEndpoint()
+		if ok {
+			return e.ID()
+		}
+	case influxdb.NotificationRuleResourceType:
+		r, ok := l.mappings[k].notificationRule()
+		if ok {
+			return r.ID()
+		}
+	case influxdb.TelegrafsResourceType:
+		t, ok := l.mappings[k].telegraf()
+		if ok {
+			return t.ID()
+		}
+	case influxdb.VariablesResourceType:
+		v, ok := l.mappings[k].variable()
+		if ok {
+			return v.ID()
+		}
+	}
+	return 0
+}
+
+func (l *label) properties() map[string]string {
+	return map[string]string{
+		"color":       l.Color,
+		"description": l.Description,
+	}
+}
+
+// labelColorNames maps the named colors a label may be given to their hex color.
+var labelColorNames = map[string]string{
+	"aqua":    "#00ffff",
+	"black":   "#000000",
+	"blue":    "#0000ff",
+	"cyan":    "#00ffff",
+	"fuchsia": "#ff00ff",
+	"gray":    "#808080",
+	"green":   "#008000",
+	"grey":    "#808080",
+	"lime":    "#00ff00",
+	"magenta": "#ff00ff",
+	"maroon":  "#800000",
+	"navy":    "#000080",
+	"olive":   "#808000",
+	"orange":  "#ffa500",
+	"purple":  "#800080",
+	"red":     "#ff0000",
+	"silver":  "#c0c0c0",
+	"teal":    "#008080",
+	"white":   "#ffffff",
+	"yellow":  "#ffff00",
+}
+
+// normalizeLabelColor converts a named or short form hex color, i.e. red or #abc,
+// to its 6 digit hex color. An empty color is left unset.
+func normalizeLabelColor(color string) (string, error) {
+	if color == "" {
+		return "", nil
+	}
+
+	if !strings.HasPrefix(color, "#") {
+		hex, ok := labelColorNames[strings.ToLower(color)]
+		if !ok {
+			return "", fmt.Errorf("unknown label color %q; must be a hex color or 1 of the named colors", color)
+		}
+		return hex, nil
+	}
+
+	digits := color[1:]
+	for _, r := range digits {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", r) {
+			return "", fmt.Errorf("invalid label color %q; must be a hex color of 3 or 6 digits", color)
+		}
+	}
+
+	switch len(digits) {
+	case 3:
+		return "#" + string([]byte{
+			digits[0], digits[0],
+			digits[1], digits[1],
+			digits[2], digits[2],
+		}), nil
+	case 6:
+		return color, nil
+	default:
+		return "", fmt.Errorf("invalid label color %q; must be a hex color of 3 or 6 digits", color)
+	}
+}
+
+func toInfluxLabels(labels ...*label) []influxdb.Label {
+	var iLabels []influxdb.Label
+	for _, l := range labels {
+		iLabels = append(iLabels, influxdb.Label{
+			ID:         l.ID(),
+			OrgID:      l.OrgID,
+			Name:       l.Name,
+			Properties: l.properties(),
+		})
+	}
+	return iLabels
+}
+
+const (
+	fieldNotificationEndpointAuthMethod = "authMethod"
+	fieldNotificationEndpointClientURL  = "clientURL"
+	fieldNotificationEndpointMethod     = "method"
+	fieldNotificationEndpointPassword   = "password"
+	fieldNotificationEndpointRoutingKey = "routingKey"
+	fieldNotificationEndpointStatus     = "status"
+	fieldNotificationEndpointToken      = "token"
+	fieldNotificationEndpointURL        = "url"
+	fieldNotificationEndpointUsername   = "username"
+)
+
+type notificationEndpoint struct {
+	id          influxdb.ID
+	OrgID       influxdb.ID
+	Name        string
+	Description string
+	Type        string
+	Status      influxdb.Status
+
+	URL        string
+	ClientURL  string
+	RoutingKey string
+	Token      string
+	Method     string
+	AuthMethod string
+	Username   string
+	Password   string
+
+	labels []*label
+}
+
+func (e *notificationEndpoint) ID() influxdb.ID {
+	return e.id
+}
+
+func (e *notificationEndpoint) ResourceType() influxdb.ResourceType {
+	return influxdb.NotificationEndpointResourceType
+}
+
+func (e *notificationEndpoint) Exists() bool {
+	return false
+}
+
+func (e *notificationEndpoint) summarize() SummaryNotificationEndpoint {
+	return SummaryNotificationEndpoint{
+		NotificationEndpoint: e.influxEndpoint(),
+		LabelAssociations:    toInfluxLabels(e.labels...),
+	}
+}
+
+func (e *notificationEndpoint) influxEndpoint() influxdb.NotificationEndpoint {
+	base := endpoint.Base{
+		ID:          e.ID(),
+		Name:        e.Name,
+		Description: e.Description,
+		OrgID:       e.OrgID,
+		Status:      e.Status,
+	}
+
+	switch e.Type {
+	case endpoint.HTTPType:
+		return &endpoint.HTTP{
+			Base:       base,
+			URL:        e.URL,
+			Method:     e.Method,
+			AuthMethod: e.AuthMethod,
+			Token:      toSecretField(e.Token),
+			Username:   toSecretField(e.Username),
+			Password:   toSecretField(e.Password),
+		}
+	case endpoint.PagerDutyType:
+		return &endpoint.PagerDuty{
+			Base:       base,
+			ClientURL:  e.ClientURL,
+			RoutingKey: toSecretField(e.RoutingKey),
+		}
+	case endpoint.SlackType:
+		return &endpoint.Slack{
+			Base:  base,
+			URL:   e.URL,
+			Token: toSecretField(e.Token),
+		}
+	}
+	return nil
+}
+
+func (e *notificationEndpoint) valid() []failure {
+	var failures []failure
+	switch e.Type {
+	case endpoint.HTTPType:
+		failures = append(failures, validURL(fieldNotificationEndpointURL, e.URL)...)
+		switch e.Method {
+		case "GET", "POST", "PUT":
+		default:
+			failures = append(failures, failure{
+				Field: fieldNotificationEndpointMethod,
+				Msg:   fmt.Sprintf("must be 1 in [GET, POST, PUT]; got=%q", e.Method),
+			})
+		}
+		switch e.AuthMethod {
+		case "none":
+		case "basic":
+			if e.Username == "" || e.Password == "" {
+				failures = append(failures, failure{
+					Field: fieldNotificationEndpointAuthMethod,
+					Msg:   "basic auth requires a username and password",
+				})
+			}
+		case "bearer":
+			if e.Token == "" {
+				failures = append(failures, failure{
+					Field: fieldNotificationEndpointToken,
+					Msg:   "bearer auth requires a token",
+				})
+			}
+		default:
+			failures = append(failures, failure{
+				Field: fieldNotificationEndpointAuthMethod,
+				Msg:   fmt.Sprintf("must be 1 in [none, basic, bearer]; got=%q", e.AuthMethod),
+			})
+		}
+	case endpoint.PagerDutyType:
+		if e.RoutingKey == "" {
+			failures = append(failures, failure{
+				Field: fieldNotificationEndpointRoutingKey,
+				Msg:   "must provide a routing key",
+			})
+		}
+	case endpoint.SlackType:
+		if e.URL == "" && e.Token == "" {
+			failures = append(failures, failure{
+				Field: fieldNotificationEndpointURL,
+				Msg:   "must provide a url or token",
+			})
+		} else if e.URL != "" {
+			failures = append(failures, validURL(fieldNotificationEndpointURL, e.URL)...)
+		}
+	default:
+		const msgFmt = "must be 1 in [%s, %s, %s]; got=%q"
+		failures = append(failures, failure{
+			Field: fieldType,
+			Msg:   fmt.Sprintf(msgFmt, endpoint.HTTPType, endpoint.PagerDutyType, endpoint.SlackType, e.Type),
+		})
+	}
+
+	switch e.Status {
+	case influxdb.Active, influxdb.Inactive:
+	default:
+		failures = append(failures, failure{
+			Field: fieldNotificationEndpointStatus,
+			Msg:   fmt.Sprintf("must be 1 in [%s, %s]; got=%q", influxdb.Active, influxdb.Inactive, e.Status),
+		})
+	}
+
+	return failures
+}
+
+func validURL(field, u string) []failure {
+	if u == "" {
+		return []failure{{
+			Field: field,
+			Msg:   "must provide a url",
+		}}
+	}
+	if _, err := url.Parse(u); err != nil {
+		return []failure{{
+			Field: field,
+			Msg:   "invalid url provided: " + err.Error(),
+		}}
+	}
+	return nil
+}
+
+func toSecretField(v string) influxdb.SecretField {
+	if v == "" {
+		return influxdb.SecretField{}
+	}
+	return influxdb.SecretField{Value: &v}
+}
+
+const (
+	fieldNotificationRuleChannel         = "channel"
+	fieldNotificationRuleCheckName       = "checkName"
+	fieldNotificationRuleCurrentLevel    = "currentLevel"
+	fieldNotificationRuleEndpointName    = "endpointName"
+	fieldNotificationRuleMessageTemplate = "messageTemplate"
+	fieldNotificationRulePreviousLevel   = "previousLevel"
+	fieldNotificationRuleStatusRules     = "statusRules"
+)
+
+type notificationRule struct {
+	id              influxdb.ID
+	OrgID           influxdb.ID
+	Name            string
+	Description     string
+	Every           string
+	Offset          string
+	Channel         string
+	MessageTemplate string
+	StatusRules     []statusRule
+
+	check    *check
+	endpoint *notificationEndpoint
+	labels   []*label
+}
+
+func (r *notificationRule) ID() influxdb.ID {
+	return r.id
+}
+
+func (r *notificationRule) ResourceType() influxdb.ResourceType {
+	return influxdb.NotificationRuleResourceType
+}
+
+func (r *notificationRule) Exists() bool {
+	return false
+}
+
+func (r *notificationRule) summarize() SummaryNotificationRule {
+	sum := SummaryNotificationRule{
+		NotificationRule:  r.influxRule(),
+		EndpointName:      r.endpoint.Name,
+		LabelAssociations: toInfluxLabels(r.labels...),
+	}
+	if r.check != nil {
+		sum.CheckName = r.check.Name
+	}
+	return sum
+}
+
+func (r *notificationRule) influxRule() influxdb.NotificationRule {
+	base := rule.Base{
+		ID:          r.ID(),
+		Name:        r.Name,
+		Description: r.Description,
+		EndpointID:  r.endpoint.ID(),
+		OrgID:       r.OrgID,
+		Every:       toNotificationDuration(r.Every),
+		Offset:      toNotificationDuration(r.Offset),
+	}
+	for _, sr := range r.StatusRules {
+		base.StatusRules = append(base.StatusRules, sr.influxStatusRule())
+	}
+	if r.check != nil {
+		// the statuses of a check are tagged with its name, the rule is
+		// limited to the statuses of the check it references.
+		base.TagRules = append(base.TagRules, notification.TagRule{
+			Tag:      influxdb.Tag{Key: "_check_name", Value: r.check.Name},
+			Operator: influxdb.Equal,
+		})
+	}
+
+	switch r.endpoint.Type {
+	case endpoint.HTTPType:
+		return &rule.HTTP{Base: base}
+	case endpoint.PagerDutyType:
+		return &rule.PagerDuty{
+			Base:            base,
+			MessageTemplate: r.MessageTemplate,
+		}
+	case endpoint.SlackType:
+		return &rule.Slack{
+			Base:            base,
+			Channel:         r.Channel,
+			MessageTemplate: r.MessageTemplate,
+		}
+	}
+	return nil
+}
+
+func (r *notificationRule) valid() []failure {
+	var failures []failure
+	failures = append(failures, validSchedule(r.Every, r.Offset)...)
+	if every, offset := toNotificationDuration(r.Every), toNotificationDuration(r.Offset); every != nil && offset != nil {
+		if offset.TimeDuration() >= every.TimeDuration() {
+			failures = append(failures, failure{
+				Field: fieldOffset,
+				Msg:   fmt.Sprintf("must be less than every; got=%s every=%s", r.Offset, r.Every),
+			})
+		}
+	}
+
+	for i, sr := range r.StatusRules {
+		for _, f := range sr.valid() {
+			failures = append(failures, failure{
+				Field: fmt.Sprintf("%s[%d].%s", fieldNotificationRuleStatusRules, i, f.Field),
+				Msg:   f.Msg,
+			})
+		}
+	}
 
-	// exists provides context for a resource that already
-	// exists in the platform. If a resource already exists(exists=true)
-	// then the ID should be populated.
-	existing *influxdb.Label
+	return failures
 }
 
-func (l *label) shouldApply() bool {
-	return l.existing == nil ||
-		l.Description != l.existing.Properties["description"] ||
-		l.Name != l.existing.Name ||
-		l.Color != l.existing.Properties["color"]
+type statusRule struct {
+	CurrentLevel  string
+	PreviousLevel string
 }
 
-func (l *label) ID() influxdb.ID {
-	if l.existing != nil {
-		return l.existing.ID
+func (s statusRule) influxStatusRule() notification.StatusRule {
+	sr := notification.StatusRule{
+		CurrentLevel: notification.ParseCheckLevel(s.CurrentLevel),
 	}
-	return l.id
+	if s.PreviousLevel != "" {
+		prev := notification.ParseCheckLevel(s.PreviousLevel)
+		sr.PreviousLevel = &prev
+	}
+	return sr
 }
 
-func (l *label) summarize() SummaryLabel {
-	return SummaryLabel{
-		Label: influxdb.Label{
-			ID:         l.ID(),
-			OrgID:      l.OrgID,
-			Name:       l.Name,
-			Properties: l.properties(),
-		},
+func (s statusRule) valid() []failure {
+	var failures []failure
+	if !validCheckLevel(s.CurrentLevel) {
+		failures = append(failures, failure{
+			Field: fieldNotificationRuleCurrentLevel,
+			Msg:   fmt.Sprintf("must be 1 in [CRIT, WARN, INFO, OK]; got=%q", s.CurrentLevel),
+		})
 	}
+	if s.PreviousLevel != "" && !validCheckLevel(s.PreviousLevel) {
+		failures = append(failures, failure{
+			Field: fieldNotificationRulePreviousLevel,
+			Msg:   fmt.Sprintf("must be 1 in [CRIT, WARN, INFO, OK]; got=%q", s.PreviousLevel),
+		})
+	}
+	return failures
 }
 
-func (l *label) mappingSummary() []SummaryLabelMapping {
-	var mappings []SummaryLabelMapping
-	for res, lm := range l.mappings {
-		mappings = append(mappings, SummaryLabelMapping{
-			exists:       lm.exists,
-			ResourceName: res.name,
-			LabelName:    l.Name,
-			LabelMapping: influxdb.LabelMapping{
-				LabelID:      l.ID(),
-				ResourceID:   l.getMappedResourceID(res),
-				ResourceType: res.resType,
-			},
-		})
+func validCheckLevel(level string) bool {
+	switch notification.ParseCheckLevel(level) {
+	case notification.Critical, notification.Warn, notification.Info, notification.Ok:
+		return true
+	default:
+		return false
 	}
+}
 
-	return mappings
+const (
+	fieldTelegrafConfig = "config"
+)
+
+type telegraf struct {
+	config influxdb.TelegrafConfig
+
+	labels []*label
 }
 
-func (l *label) getMappedResourceID(k assocMapKey) influxdb.ID {
-	switch k.resType {
-	case influxdb.BucketsResourceType:
-		b, ok := l.mappings[k].bucket()
-		if ok {
-			return b.ID()
-		}
-	case influxdb.DashboardsResourceType:
-		d, ok := l.mappings[k].dashboard()
-		if ok {
-			return d.ID()
-		}
-	case influxdb.VariablesResourceType:
-		v, ok := l.mappings[k].variable()
-		if ok {
-			return v.ID()
-		}
-	}
-	return 0
+func (t *telegraf) ID() influxdb.ID {
+	return t.config.ID
 }
 
-func (l *label) properties() map[string]string {
-	return map[string]string{
-		"color":       l.Color,
-		"description": l.Description,
-	}
+func (t *telegraf) Name() string {
+	return t.config.Name
 }
 
-func toInfluxLabels(labels ...*label) []influxdb.Label {
-	var iLabels []influxdb.Label
-	for _, l := range labels {
-		iLabels = append(iLabels, influxdb.Label{
-			ID:         l.ID(),
-			OrgID:      l.OrgID,
-			Name:       l.Name,
-			Properties: l.properties(),
-		})
+func (t *telegraf) ResourceType() influxdb.ResourceType {
+	return influxdb.TelegrafsResourceType
+}
+
+func (t *telegraf) Exists() bool {
+	return false
+}
+
+func (t *telegraf) summarize() SummaryTelegraf {
+	return SummaryTelegraf{
+		TelegrafConfig:    t.config,
+		LabelAssociations: toInfluxLabels(t.labels...),
 	}
-	return iLabels
 }
 
 const (
@@ -551,6 +1755,7 @@ const (
 	fieldArgTypeMap      = "map"
 	fieldArgTypeQuery    = "query"
 	fieldVarLanguage     = "language"
+	fieldVarValuesFile   = "valuesFile"
 )
 
 type variable struct {
@@ -566,6 +1771,9 @@ type variable struct {
 
 	labels []*label
 
+	// lookupID is the id the pkg provides for the variable.
+	lookupID influxdb.ID
+
 	existing *influxdb.Variable
 }
 
@@ -586,6 +1794,7 @@ func (v *variable) ResourceType() influxdb.ResourceType {
 
 func (v *variable) shouldApply() bool {
 	return v.existing == nil ||
+		v.existing.Name != v.Name ||
 		v.existing.Description != v.Description ||
 		v.existing.Arguments == nil ||
 		v.existing.Arguments.Type != v.Type
@@ -658,7 +1867,17 @@ func (v *variable) valid() []failure {
 }
 
 const (
-	fieldDashCharts = "charts"
+	fieldDashCharts        = "charts"
+	fieldDashLayout        = "layout"
+	fieldDashLayoutColumns = "layoutColumns"
+)
+
+// dashboard layouts
+const (
+	dashLayoutAuto = "auto"
+
+	// dashLayoutDefaultColumns is the number of columns of the dashboard grid in the UI.
+	dashLayoutDefaultColumns = 12
 )
 
 type dashboard struct {
@@ -705,15 +1924,23 @@ func (d *dashboard) summarize() SummaryDashboard {
 
 const (
 	fieldChartAxes          = "axes"
+	fieldChartBinCount      = "binCount"
+	fieldChartBinSize       = "binSize"
 	fieldChartColors        = "colors"
 	fieldChartDecimalPlaces = "decimalPlaces"
+	fieldChartFieldOptions  = "fieldOptions"
+	fieldChartFillColumns   = "fillColumns"
 	fieldChartGeom          = "geom"
 	fieldChartHeight        = "height"
 	fieldChartLegend        = "legend"
 	fieldChartNote          = "note"
 	fieldChartNoteOnEmpty   = "noteOnEmpty"
+	fieldChartPosition      = "position"
 	fieldChartQueries       = "queries"
 	fieldChartShade         = "shade"
+	fieldChartSymbolColumns = "symbolColumns"
+	fieldChartTableOptions  = "tableOptions"
+	fieldChartTimeFormat    = "timeFormat"
 	fieldChartWidth         = "width"
 	fieldChartXCol          = "xCol"
 	fieldChartXPos          = "xPos"
@@ -736,6 +1963,14 @@ type chart struct {
 	Queries         queries
 	Axes            axes
 	Geom            string
+	BinCount        int
+	BinSize         int
+	FillColumns     []string
+	SymbolColumns   []string
+	Position        string
+	TableOptions    tableOptions
+	FieldOptions    fieldOptions
+	TimeFormat      string
 
 	XCol, YCol    string
 	XPos, YPos    int
@@ -758,6 +1993,67 @@ func (c chart) properties() influxdb.ViewProperties {
 			Note:              c.Note,
 			ShowNoteWhenEmpty: c.NoteOnEmpty,
 		}
+	case chartKindHeatMap:
+		xAxis, yAxis := c.Axes.get("x"), c.Axes.get("y")
+		return influxdb.HeatmapViewProperties{
+			Type:              influxdb.ViewPropertyTypeHeatMap,
+			Queries:           c.Queries.influxDashQueries(),
+			ViewColors:        c.Colors.hexes(),
+			BinSize:           int32(c.BinSize),
+			XColumn:           c.XCol,
+			YColumn:           c.YCol,
+			XDomain:           xAxis.Domain,
+			YDomain:           yAxis.Domain,
+			XAxisLabel:        xAxis.Label,
+			YAxisLabel:        yAxis.Label,
+			XPrefix:           xAxis.Prefix,
+			XSuffix:           xAxis.Suffix,
+			YPrefix:           yAxis.Prefix,
+			YSuffix:           yAxis.Suffix,
+			Note:              c.Note,
+			ShowNoteWhenEmpty: c.NoteOnEmpty,
+		}
+	case chartKindHistogram:
+		xAxis := c.Axes.get("x")
+		return influxdb.HistogramViewProperties{
+			Type:              influxdb.ViewPropertyTypeHistogram,
+			Queries:           c.Queries.influxDashQueries(),
+			ViewColors:        c.Colors.influxViewColors(),
+			XColumn:           c.XCol,
+			FillColumns:       c.FillColumns,
+			XDomain:           xAxis.Domain,
+			XAxisLabel:        xAxis.Label,
+			Position:          c.Position,
+			BinCount:          c.BinCount,
+			Note:              c.Note,
+			ShowNoteWhenEmpty: c.NoteOnEmpty,
+		}
+	case chartKindMarkdown:
+		return influxdb.MarkdownViewProperties{
+			Type: influxdb.ViewPropertyTypeMarkdown,
+			Note: c.Note,
+		}
+	case chartKindScatter:
+		xAxis, yAxis := c.Axes.get("x"), c.Axes.get("y")
+		return influxdb.ScatterViewProperties{
+			Type:              influxdb.ViewPropertyTypeScatter,
+			Queries:           c.Queries.influxDashQueries(),
+			ViewColors:        c.Colors.hexes(),
+			XColumn:           c.XCol,
+			YColumn:           c.YCol,
+			FillColumns:       c.FillColumns,
+			SymbolColumns:     c.SymbolColumns,
+			XDomain:           xAxis.Domain,
+			YDomain:           yAxis.Domain,
+			XAxisLabel:        xAxis.Label,
+			YAxisLabel:        yAxis.Label,
+			XPrefix:           xAxis.Prefix,
+			XSuffix:           xAxis.Suffix,
+			YPrefix:           yAxis.Prefix,
+			YSuffix:           yAxis.Suffix,
+			Note:              c.Note,
+			ShowNoteWhenEmpty: c.NoteOnEmpty,
+		}
 	case chartKindSingleStat:
 		return influxdb.SingleStatViewProperties{
 			Type:   influxdb.ViewPropertyTypeSingleStat,
@@ -791,6 +2087,26 @@ func (c chart) properties() influxdb.ViewProperties {
 			ViewColors:        c.Colors.influxViewColors(),
 			Axes:              c.Axes.influxAxes(),
 		}
+	case chartKindTable:
+		return influxdb.TableViewProperties{
+			Type:       influxdb.ViewPropertyTypeTable,
+			Queries:    c.Queries.influxDashQueries(),
+			ViewColors: c.Colors.influxViewColors(),
+			TableOptions: influxdb.TableOptions{
+				VerticalTimeAxis: c.TableOptions.VerticalTimeAxis,
+				SortBy:           c.FieldOptions.renamableField(c.TableOptions.SortBy),
+				Wrapping:         c.TableOptions.Wrapping,
+				FixFirstColumn:   c.TableOptions.FixFirstColumn,
+			},
+			FieldOptions: c.FieldOptions.influxFieldOptions(),
+			TimeFormat:   c.TimeFormat,
+			DecimalPlaces: influxdb.DecimalPlaces{
+				IsEnforced: c.EnforceDecimals,
+				Digits:     int32(c.DecimalPlaces),
+			},
+			Note:              c.Note,
+			ShowNoteWhenEmpty: c.NoteOnEmpty,
+		}
 	case chartKindXY:
 		return influxdb.XYViewProperties{
 			Type:              influxdb.ViewPropertyTypeXY,
@@ -811,6 +2127,11 @@ func (c chart) properties() influxdb.ViewProperties {
 }
 
 func (c chart) validProperties() []failure {
+	if c.Kind == chartKindMarkdown {
+		// a markdown chart only renders its note, it has no queries or colors
+		return append(c.validBaseProps(), c.validNote()...)
+	}
+
 	var fails []failure
 
 	validatorFns := []func() []failure{
@@ -826,19 +2147,106 @@ func (c chart) validProperties() []failure {
 	switch c.Kind {
 	case chartKindGauge:
 		fails = append(fails, c.Colors.hasTypes(colorTypeMin, colorTypeThreshold, colorTypeMax)...)
+	case chartKindHeatMap:
+		fails = append(fails, c.Colors.hasTypes(colorTypeScale)...)
+		fails = append(fails, c.validColumns(fieldChartXCol, fieldChartYCol)...)
+		fails = append(fails, validPositiveInt(fieldChartBinSize, c.BinSize)...)
+		fails = append(fails, c.Axes.hasAxes("x", "y")...)
+		fails = append(fails, c.Axes.validDomains()...)
+	case chartKindHistogram:
+		fails = append(fails, c.Colors.hasTypes(colorTypeScale)...)
+		fails = append(fails, c.validColumns(fieldChartXCol)...)
+		fails = append(fails, validPositiveInt(fieldChartBinCount, c.BinCount)...)
+		fails = append(fails, validHistogramPosition(c.Position)...)
+		fails = append(fails, c.Axes.validDomains()...)
+	case chartKindScatter:
+		fails = append(fails, c.Colors.hasTypes(colorTypeScale)...)
+		fails = append(fails, c.validColumns(fieldChartXCol, fieldChartYCol)...)
+		fails = append(fails, c.validScatterGeom()...)
+		fails = append(fails, c.Axes.hasAxes("x", "y")...)
+		fails = append(fails, c.Axes.validDomains()...)
 	case chartKindSingleStat:
 		fails = append(fails, c.Colors.hasTypes(colorTypeText)...)
 	case chartKindSingleStatPlusLine:
 		fails = append(fails, c.Colors.hasTypes(colorTypeText)...)
-		fails = append(fails, c.Axes.hasAxes("x", "y")...)
+		fails = append(fails, c.Axes.hasExactAxes("x", "y")...)
+	case chartKindTable:
+		fails = append(fails, c.FieldOptions.valid()...)
+		fails = append(fails, c.TableOptions.valid()...)
 	case chartKindXY:
 		fails = append(fails, validGeometry(c.Geom)...)
-		fails = append(fails, c.Axes.hasAxes("x", "y")...)
+		fails = append(fails, c.Axes.hasExactAxes("x", "y")...)
+	}
+
+	return fails
+}
+
+func (c chart) validNote() []failure {
+	if strings.TrimSpace(c.Note) == "" {
+		return []failure{{
+			Field: fieldChartNote,
+			Msg:   "a note must be provided",
+		}}
+	}
+	return nil
+}
+
+// validScatterGeom verifies no geom is provided, a scatter chart plots each
+// point by its x and y columns.
+func (c chart) validScatterGeom() []failure {
+	if c.Geom != "" {
+		return []failure{{
+			Field: fieldChartGeom,
+			Msg:   fmt.Sprintf("geom is not supported by scatter charts: %q", c.Geom),
+		}}
+	}
+	return nil
+}
+
+// validColumns verifies the column fields are provided.
+func (c chart) validColumns(fields ...string) []failure {
+	cols := map[string]string{
+		fieldChartXCol: c.XCol,
+		fieldChartYCol: c.YCol,
 	}
 
+	var fails []failure
+	for _, f := range fields {
+		if cols[f] == "" {
+			fails = append(fails, failure{
+				Field: f,
+				Msg:   "a column must be provided",
+			})
+		}
+	}
 	return fails
 }
 
+func validPositiveInt(field string, i int) []failure {
+	if i <= 0 {
+		return []failure{{
+			Field: field,
+			Msg:   "must be greater than 0",
+		}}
+	}
+	return nil
+}
+
+var histogramPositions = map[string]bool{
+	"overlaid": true,
+	"stacked":  true,
+}
+
+func validHistogramPosition(position string) []failure {
+	if position != "" && !histogramPositions[position] {
+		return []failure{{
+			Field: fieldChartPosition,
+			Msg:   fmt.Sprintf("position provided is not supported: %q", position),
+		}}
+	}
+	return nil
+}
+
 var geometryTypes = map[string]bool{
 	"line":    true,
 	"step":    true,
@@ -903,10 +2311,20 @@ type color struct {
 }
 
 // TODO:
-//  - verify templates are desired
-//  - template colors so references can be shared
+//   - verify templates are desired
+//   - template colors so references can be shared
 type colors []*color
 
+// hexes returns the hex values of the colors, as is used by charts that only
+// take a color scale.
+func (c colors) hexes() []string {
+	var hexes []string
+	for _, cc := range c {
+		hexes = append(hexes, cc.Hex)
+	}
+	return hexes
+}
+
 func (c colors) influxViewColors() []influxdb.ViewColor {
 	ptrToFloat64 := func(f *float64) float64 {
 		if f == nil {
@@ -931,8 +2349,9 @@ func (c colors) influxViewColors() []influxdb.ViewColor {
 }
 
 // TODO: looks like much of these are actually getting defaults in
-//  the UI. looking at sytem charts, seeign lots of failures for missing
-//  color types or no colors at all.
+//
+//	the UI. looking at sytem charts, seeign lots of failures for missing
+//	color types or no colors at all.
 func (c colors) hasTypes(types ...string) []failure {
 	tMap := make(map[string]bool)
 	for _, cc := range c {
@@ -966,6 +2385,93 @@ func (c colors) valid() []failure {
 	return fails
 }
 
+const (
+	fieldTableOptionsFixFirstColumn   = "fixFirstColumn"
+	fieldTableOptionsSortBy           = "sortBy"
+	fieldTableOptionsVerticalTimeAxis = "verticalTimeAxis"
+	fieldTableOptionsWrapping         = "wrapping"
+)
+
+type tableOptions struct {
+	VerticalTimeAxis bool   `json:"verticalTimeAxis,omitempty" yaml:"verticalTimeAxis,omitempty"`
+	SortBy           string `json:"sortBy,omitempty" yaml:"sortBy,omitempty"`
+	Wrapping         string `json:"wrapping,omitempty" yaml:"wrapping,omitempty"`
+	FixFirstColumn   bool   `json:"fixFirstColumn,omitempty" yaml:"fixFirstColumn,omitempty"`
+}
+
+var tableWrappings = map[string]bool{
+	"truncate":    true,
+	"wrap":        true,
+	"single-line": true,
+}
+
+func (t tableOptions) valid() []failure {
+	if t.Wrapping != "" && !tableWrappings[t.Wrapping] {
+		return []failure{{
+			Field: fmt.Sprintf("%s.%s", fieldChartTableOptions, fieldTableOptionsWrapping),
+			Msg:   fmt.Sprintf("wrapping provided is not supported: %q", t.Wrapping),
+		}}
+	}
+	return nil
+}
+
+const (
+	fieldFieldOptionDisplayName = "displayName"
+	fieldFieldOptionFieldName   = "fieldName"
+	fieldFieldOptionVisible     = "visible"
+)
+
+type fieldOption struct {
+	FieldName   string `json:"fieldName,omitempty" yaml:"fieldName,omitempty"`
+	DisplayName string `json:"displayName,omitempty" yaml:"displayName,omitempty"`
+	Visible     bool   `json:"visible,omitempty" yaml:"visible,omitempty"`
+}
+
+type fieldOptions []fieldOption
+
+func (f fieldOptions) influxFieldOptions() []influxdb.RenamableField {
+	var iOpts []influxdb.RenamableField
+	for _, opt := range f {
+		iOpts = append(iOpts, influxdb.RenamableField{
+			InternalName: opt.FieldName,
+			DisplayName:  opt.DisplayName,
+			Visible:      opt.Visible,
+		})
+	}
+	return iOpts
+}
+
+// renamableField returns the field of the field name, using the display name
+// and visibility of its field option when one is provided.
+func (f fieldOptions) renamableField(fieldName string) influxdb.RenamableField {
+	for _, opt := range f {
+		if opt.FieldName == fieldName {
+			return influxdb.RenamableField{
+				InternalName: opt.FieldName,
+				DisplayName:  opt.DisplayName,
+				Visible:      opt.Visible,
+			}
+		}
+	}
+	return influxdb.RenamableField{
+		InternalName: fieldName,
+		Visible:      true,
+	}
+}
+
+func (f fieldOptions) valid() []failure {
+	var fails []failure
+	for i, opt := range f {
+		if opt.FieldName == "" {
+			fails = append(fails, failure{
+				Field: fmt.Sprintf("%s[%d].%s", fieldChartFieldOptions, i, fieldFieldOptionFieldName),
+				Msg:   "a field option must have a field name provided",
+			})
+		}
+	}
+	return fails
+}
+
 type query struct {
 	Query string `json:"query" yaml:"query"`
 }
@@ -986,6 +2492,20 @@ func (q queries) influxDashQueries() []influxdb.DashboardQuery {
 	return iQueries
 }
 
+// varRefPattern matches references to a variable in a flux query, i.e. v.bucket.
+var varRefPattern = regexp.MustCompile(`(?:^|[^\w.])v\.([a-zA-Z_]\w*)`)
+
+// variableRefs returns the names of the variables referenced by the queries.
+func (q queries) variableRefs() []string {
+	var names []string
+	for _, qq := range q {
+		for _, m := range varRefPattern.FindAllStringSubmatch(qq.Query, -1) {
+			names = append(names, m[1])
+		}
+	}
+	return names
+}
+
 func (q queries) valid() []failure {
 	var fails []failure
 	if len(q) == 0 {
@@ -1008,22 +2528,33 @@ func (q queries) valid() []failure {
 }
 
 const (
-	fieldAxisBase  = "base"
-	fieldAxisLabel = "label"
-	fieldAxisScale = "scale"
+	fieldAxisBase   = "base"
+	fieldAxisDomain = "domain"
+	fieldAxisLabel  = "label"
+	fieldAxisScale  = "scale"
 )
 
 type axis struct {
-	Base   string `json:"base,omitempty" yaml:"base,omitempty"`
-	Label  string `json:"label,omitempty" yaml:"label,omitempty"`
-	Name   string `json:"name,omitempty" yaml:"name,omitempty"`
-	Prefix string `json:"prefix,omitempty" yaml:"prefix,omitempty"`
-	Scale  string `json:"scale,omitempty" yaml:"scale,omitempty"`
-	Suffix string `json:"suffix,omitempty" yaml:"suffix,omitempty"`
+	Base   string    `json:"base,omitempty" yaml:"base,omitempty"`
+	Domain []float64 `json:"domain,omitempty" yaml:"domain,omitempty"`
+	Label  string    `json:"label,omitempty" yaml:"label,omitempty"`
+	Name   string    `json:"name,omitempty" yaml:"name,omitempty"`
+	Prefix string    `json:"prefix,omitempty" yaml:"prefix,omitempty"`
+	Scale  string    `json:"scale,omitempty" yaml:"scale,omitempty"`
+	Suffix string    `json:"suffix,omitempty" yaml:"suffix,omitempty"`
 }
 
 type axes []axis
 
+func (a axes) get(name string) axis {
+	for _, ax := range a {
+		if ax.Name == name {
+			return ax
+		}
+	}
+	return axis{}
+}
+
 func (a axes) influxAxes() map[string]influxdb.Axis {
 	m := make(map[string]influxdb.Axis)
 	for _, ax := range a {
@@ -1039,6 +2570,30 @@ func (a axes) influxAxes() map[string]influxdb.Axis {
 	return m
 }
 
+func (a axes) validDomains() []failure {
+	var fails []failure
+	for i, ax := range a {
+		if len(ax.Domain) == 0 {
+			continue
+		}
+
+		field := fmt.Sprintf("axes[%d].%s", i, fieldAxisDomain)
+		switch {
+		case len(ax.Domain) != 2:
+			fails = append(fails, failure{
+				Field: field,
+				Msg:   "must provide a min and max value",
+			})
+		case ax.Domain[0] >= ax.Domain[1]:
+			fails = append(fails, failure{
+				Field: field,
+				Msg:   "min must be less than max",
+			})
+		}
+	}
+	return fails
+}
+
 func (a axes) hasAxes(expectedAxes ...string) []failure {
 	mAxes := make(map[string]bool)
 	for _, ax := range a {
@@ -1058,6 +2613,36 @@ func (a axes) hasAxes(expectedAxes ...string) []failure {
 	return failures
 }
 
+// hasExactAxes verifies the expected axes are each provided exactly once and
+// no other axes are provided.
+func (a axes) hasExactAxes(expectedAxes ...string) []failure {
+	failures := a.hasAxes(expectedAxes...)
+
+	mExpected := make(map[string]bool)
+	for _, expected := range expectedAxes {
+		mExpected[expected] = true
+	}
+
+	seen := make(map[string]bool)
+	for _, ax := range a {
+		switch {
+		case !mExpected[ax.Name]:
+			failures = append(failures, failure{
+				Field: "axes",
+				Msg:   fmt.Sprintf("unexpected axis: %q; must be one of %q", ax.Name, expectedAxes),
+			})
+		case seen[ax.Name]:
+			failures = append(failures, failure{
+				Field: "axes",
+				Msg:   fmt.Sprintf("duplicate axis: %q", ax.Name),
+			})
+		}
+		seen[ax.Name] = true
+	}
+
+	return failures
+}
+
 const (
 	fieldLegendLanguage    = "language"
 	fieldLegendOrientation = "orientation"
@@ -123,4 +123,141 @@ func TestPkg(t *testing.T) {
 			assert.Equal(t, label1.Name, mapping1.LabelName)
 		})
 	})
+
+	t.Run("Contains", func(t *testing.T) {
+		pkg, err := Parse(EncodingYAML, FromString(`apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName:      pkg_name
+  pkgVersion:   1
+  description:  pack description
+spec:
+  resources:
+    - kind: Label
+      name: label_1
+    - kind: Bucket
+      name: rucket_1
+    - kind: Dashboard
+      name: dash_1
+    - kind: Variable
+      name: var_1
+      type: constant
+      values:
+        - first val
+`))
+		require.NoError(t, err)
+
+		tests := []struct {
+			kind     Kind
+			name     string
+			expected interface{}
+		}{
+			{kind: KindBucket, name: "rucket_1", expected: pkg.mBuckets["rucket_1"].summarize()},
+			{kind: KindDashboard, name: "dash_1", expected: pkg.mDashboards["dash_1"].summarize()},
+			{kind: KindLabel, name: "label_1", expected: pkg.mLabels["label_1"].summarize()},
+			{kind: KindVariable, name: "var_1", expected: pkg.mVariables["var_1"].summarize()},
+		}
+
+		for _, tt := range tests {
+			fn := func(t *testing.T) {
+				t.Run("present", func(t *testing.T) {
+					assert.True(t, pkg.Contains(tt.kind, tt.name))
+
+					res, ok := pkg.Resource(tt.kind, tt.name)
+					require.True(t, ok)
+					assert.Equal(t, tt.expected, res)
+				})
+
+				t.Run("absent", func(t *testing.T) {
+					assert.False(t, pkg.Contains(tt.kind, "not_present"))
+
+					res, ok := pkg.Resource(tt.kind, "not_present")
+					assert.False(t, ok)
+					assert.Nil(t, res)
+				})
+			}
+			t.Run(tt.kind.String(), fn)
+		}
+
+		t.Run("name of another kind is absent", func(t *testing.T) {
+			assert.False(t, pkg.Contains(KindBucket, "label_1"))
+			assert.False(t, pkg.Contains(KindPackage, "pkg_name"))
+		})
+
+		t.Run("summary is typed by kind", func(t *testing.T) {
+			res, ok := pkg.Resource(KindBucket, "rucket_1")
+			require.True(t, ok)
+
+			b, ok := res.(SummaryBucket)
+			require.True(t, ok)
+			assert.Equal(t, "rucket_1", b.Name)
+		})
+	})
+}
+
+func TestKind(t *testing.T) {
+	tests := []struct {
+		name     string
+		kind     string
+		expected Kind
+	}{
+		{name: "singular", kind: "Bucket", expected: KindBucket},
+		{name: "plural buckets", kind: "Buckets", expected: KindBucket},
+		{name: "plural labels", kind: "Labels", expected: KindLabel},
+		{name: "padded plural", kind: " variables ", expected: KindVariable},
+	}
+
+	for _, tt := range tests {
+		fn := func(t *testing.T) {
+			k := newKind(tt.kind)
+			require.NoError(t, k.OK())
+			assert.Equal(t, tt.expected, k)
+			assert.True(t, Kind(tt.kind).is(tt.expected))
+		}
+		t.Run(tt.name, fn)
+	}
+
+	t.Run("unknown plural is rejected", func(t *testing.T) {
+		k := newKind("Widgets")
+		assert.Equal(t, Kind("widgets"), k)
+
+		err := k.OK()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `"widgets"`)
+		assert.Contains(t, err.Error(), "must be one of bucket, dashboard, label, package, variable")
+	})
+}
+
+func TestSummaryBreakdown(t *testing.T) {
+	diff := Diff{
+		Buckets: []DiffBucket{
+			{Name: "rucket_new", NewRetention: time.Hour},
+			{ID: 1, Name: "rucket_updated", OldDesc: "old", NewDesc: "new"},
+			{ID: 2, Name: "rucket_unchanged", OldDesc: "same", NewDesc: "same"},
+		},
+		Dashboards: []DiffDashboard{{Name: "dash_1"}, {Name: "dash_2"}},
+		Labels: []DiffLabel{
+			{ID: 3, Name: "label_unchanged"},
+		},
+		Variables: []DiffVariable{
+			{Name: "var_new"},
+			{ID: 4, Name: "var_updated", OldDesc: "old", NewDesc: "new"},
+		},
+	}
+
+	sum := Summary{Breakdown: newSummaryBreakdown(diff)}
+	sum.Breakdown = append(sum.Breakdown, SummaryBreakdown{Kind: KindTelegraf, Skipped: 2})
+
+	expected := []SummaryBreakdown{
+		{Kind: KindBucket, Created: 1, Updated: 1, Unchanged: 1},
+		{Kind: KindDashboard, Created: 2},
+		{Kind: KindLabel, Unchanged: 1},
+		{Kind: KindVariable, Created: 1, Updated: 1},
+		{Kind: KindTelegraf, Skipped: 2},
+	}
+	assert.Equal(t, expected, sum.Breakdown)
+
+	assert.Equal(t, 3, sum.BreakdownOf(KindBucket).Total())
+	assert.Equal(t, 2, sum.BreakdownOf(KindTelegraf).Skipped)
+	assert.Equal(t, SummaryBreakdown{Kind: KindCheck}, sum.BreakdownOf(KindCheck))
 }
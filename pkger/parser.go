@@ -1,18 +1,30 @@
 package pkger
 
 import (
+	"bufio"
 	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/csv"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/BurntSushi/toml"
 	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/notification/endpoint"
 	"gopkg.in/yaml.v3"
 )
 
@@ -29,6 +41,7 @@ const (
 	EncodingUnknown Encoding = iota
 	EncodingYAML
 	EncodingJSON
+	EncodingSource // EncodingSource draws the encoding from the source of the pkg, i.e. FromHTTP
 )
 
 // String provides the string representation of the encoding.
@@ -38,6 +51,8 @@ func (e Encoding) String() string {
 		return "json"
 	case EncodingYAML:
 		return "yaml"
+	case EncodingSource:
+		return "source"
 	default:
 		return "unknown"
 	}
@@ -46,24 +61,118 @@ func (e Encoding) String() string {
 // ErrInvalidEncoding indicates the encoding is invalid type for the parser.
 var ErrInvalidEncoding = errors.New("invalid encoding provided")
 
+// ParseSetFn is a functional input for setting the parse options.
+type ParseSetFn func(opt *parseOpt)
+
+type parseOpt struct {
+	env            map[string]string
+	fileDir        string
+	readFiles      bool
+	validPkgName   bool
+	skipValidation bool
+}
+
+// WithEnv sets the env the ${NAME} references within the string fields of the
+// pkg resources are resolved from. A reference may provide a default with
+// ${NAME:-default}, a reference to a name that is not in the env and provides
+// no default is a validation error. A $${ is left as a literal ${. References
+// are only resolved when an env is provided.
+func WithEnv(env map[string]string) ParseSetFn {
+	return func(opt *parseOpt) {
+		opt.env = env
+	}
+}
+
+// WithFileDir enables reading the files referenced by the pkg, i.e. the
+// valuesFile of a constant variable, resolving relative paths from the dir.
+// Files are only read for a trusted pkg, such as a pkg file on local disk, as
+// their contents become part of the pkg. Without it, referencing a file is a
+// validation error.
+func WithFileDir(dir string) ParseSetFn {
+	return func(opt *parseOpt) {
+		opt.readFiles = true
+		opt.fileDir = dir
+	}
+}
+
+// WithPkgNameRules validates the pkgName of the pkg is a DNS label, made up
+// of at most 63 lowercase alphanumeric characters and hyphens that starts and
+// ends with an alphanumeric character. This is useful for pkgs published to a
+// shared catalog, where the pkgName identifies the pkg.
+func WithPkgNameRules() ParseSetFn {
+	return func(opt *parseOpt) {
+		opt.validPkgName = true
+	}
+}
+
+// WithoutValidation skips validating and graphing the resources of the pkg.
+// This is useful for a pkg that is only one part of a larger pkg, its resources
+// may be associated with resources found in the other parts. The parts are
+// validated once they are put together by Combine.
+func WithoutValidation() ParseSetFn {
+	return func(opt *parseOpt) {
+		opt.skipValidation = true
+	}
+}
+
 // Parse parses a pkg defined by the encoding and readerFns. As of writing this
-// we can parse both a YAML and JSON format of the Pkg model.
-func Parse(encoding Encoding, readerFn ReaderFn) (*Pkg, error) {
+// we can parse both a YAML and JSON format of the Pkg model, either of which
+// may be gzipped.
+func Parse(encoding Encoding, readerFn ReaderFn, setters ...ParseSetFn) (*Pkg, error) {
+	var opt parseOpt
+	for _, setFn := range setters {
+		setFn(&opt)
+	}
+
 	r, err := readerFn()
 	if err != nil {
 		return nil, err
 	}
 
+	if encoding == EncodingSource {
+		encoding = EncodingUnknown
+		if er, ok := r.(encodedReader); ok {
+			encoding = er.encoding
+		}
+	}
+
+	r, err = decompressReader(r)
+	if err != nil {
+		return nil, err
+	}
+
 	switch encoding {
 	case EncodingYAML:
-		return parseYAML(r)
+		return parseYAML(r, opt)
 	case EncodingJSON:
-		return parseJSON(r)
+		return parseJSON(r, opt)
 	default:
 		return nil, ErrInvalidEncoding
 	}
 }
 
+// gzipMagic are the leading bytes of a gzip stream.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// decompressReader transparently decompresses a gzipped pkg. A pkg that is not
+// gzipped is read as is.
+func decompressReader(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	magic, err := br.Peek(len(gzipMagic))
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	if !bytes.Equal(magic, gzipMagic) {
+		return br, nil
+	}
+
+	gr, err := gzip.NewReader(br)
+	if err != nil {
+		return nil, err
+	}
+	return gr, nil
+}
+
 // FromFile reads a file from disk and provides a reader from it.
 func FromFile(filePath string) ReaderFn {
 	return func() (io.Reader, error) {
@@ -76,6 +185,80 @@ func FromFile(filePath string) ReaderFn {
 	}
 }
 
+// FromHTTP downloads the pkg from the url provided. The encoding of the pkg
+// is available to an EncodingSource Parse. It is taken from the extension of the
+// url path and when that is ambiguous, from the Content-Type of the response. A
+// pkg that can not be downloaded is reported as a FetchErr.
+func FromHTTP(ctx context.Context, addr string) ReaderFn {
+	return func() (io.Reader, error) {
+		req, err := http.NewRequest(http.MethodGet, addr, nil)
+		if err != nil {
+			return nil, &FetchErr{URL: addr, Err: err}
+		}
+
+		resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+		if err != nil {
+			return nil, &FetchErr{URL: addr, Err: err}
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode/100 != 2 {
+			return nil, &FetchErr{
+				URL: addr,
+				Err: fmt.Errorf("unexpected response status: %s", resp.Status),
+			}
+		}
+
+		b, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return nil, &FetchErr{URL: addr, Err: err}
+		}
+
+		encoding := encodingFromExt(path.Ext(strings.TrimSuffix(req.URL.Path, ".gz")))
+		if encoding == EncodingUnknown {
+			encoding = encodingFromContentType(resp.Header.Get("Content-Type"))
+		}
+
+		return encodedReader{
+			Reader:   bytes.NewReader(b),
+			encoding: encoding,
+		}, nil
+	}
+}
+
+// encodedReader is a reader that knows the encoding of the pkg it reads.
+type encodedReader struct {
+	io.Reader
+	encoding Encoding
+}
+
+func encodingFromExt(ext string) Encoding {
+	switch strings.ToLower(ext) {
+	case ".yaml", ".yml":
+		return EncodingYAML
+	case ".json":
+		return EncodingJSON
+	default:
+		return EncodingUnknown
+	}
+}
+
+func encodingFromContentType(contentType string) Encoding {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return EncodingUnknown
+	}
+
+	switch mediaType {
+	case "application/json":
+		return EncodingJSON
+	case "application/x-yaml", "application/yaml", "text/yaml", "text/x-yaml":
+		return EncodingYAML
+	default:
+		return EncodingUnknown
+	}
+}
+
 // FromReader simply passes the reader along. Useful when consuming
 // this from an HTTP request body. There are a number of other useful
 // places for this functional input.
@@ -93,23 +276,48 @@ func FromString(s string) ReaderFn {
 	}
 }
 
-func parseYAML(r io.Reader) (*Pkg, error) {
-	return parse(yaml.NewDecoder(r))
+func parseYAML(r io.Reader, opt parseOpt) (*Pkg, error) {
+	var node yaml.Node
+	if err := yaml.NewDecoder(r).Decode(&node); err != nil {
+		return nil, err
+	}
+	dupKeys := yamlDupMapKeys(&node)
+	return parse(&node, dupKeys, opt)
 }
 
-func parseJSON(r io.Reader) (*Pkg, error) {
-	return parse(json.NewDecoder(r))
+func parseJSON(r io.Reader, opt parseOpt) (*Pkg, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return parse(json.NewDecoder(bytes.NewReader(b)), jsonDupMapKeys(b), opt)
 }
 
 type decoder interface {
 	Decode(interface{}) error
 }
 
-func parse(dec decoder) (*Pkg, error) {
+func parse(dec decoder, varDupMapKeys map[string][]string, opt parseOpt) (*Pkg, error) {
 	var pkg Pkg
 	if err := dec.Decode(&pkg); err != nil {
 		return nil, err
 	}
+	pkg.varDupMapKeys = varDupMapKeys
+	pkg.validPkgName = opt.validPkgName
+
+	if opt.env != nil {
+		if err := pkg.resolveEnvRefs(opt.env); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := pkg.resolveValuesFiles(opt.readFiles, opt.fileDir); err != nil {
+		return nil, err
+	}
+
+	if opt.skipValidation {
+		return &pkg, nil
+	}
 
 	if err := pkg.Validate(); err != nil {
 		return nil, err
@@ -118,6 +326,418 @@ func parse(dec decoder) (*Pkg, error) {
 	return &pkg, nil
 }
 
+// Combine merges the resources of the pkgs into a single pkg and validates it.
+// Resource names must be unique across all the pkgs and associations may refer
+// to resources of any of the pkgs. The metadata of the combined pkg is that of
+// the first pkg.
+func Combine(pkgs ...*Pkg) (*Pkg, error) {
+	if len(pkgs) == 0 {
+		return nil, errors.New("at least 1 pkg must be provided to combine")
+	}
+
+	var combined Pkg
+	combined.APIVersion = pkgs[0].APIVersion
+	combined.Kind = pkgs[0].Kind
+	combined.Metadata = pkgs[0].Metadata
+	combined.varDupMapKeys = make(map[string][]string)
+	for _, p := range pkgs {
+		combined.Spec.Resources = append(combined.Spec.Resources, p.Spec.Resources...)
+		for name, keys := range p.varDupMapKeys {
+			combined.varDupMapKeys[name] = append(combined.varDupMapKeys[name], keys...)
+		}
+		combined.validPkgName = combined.validPkgName || p.validPkgName
+	}
+
+	if err := combined.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &combined, nil
+}
+
+// resolveEnvRefs replaces the env references within the string fields of every
+// resource with their value from the env. All references that can not be
+// resolved are reported against the field of the resource they are found in.
+func (p *Pkg) resolveEnvRefs(env map[string]string) error {
+	var parseErr ParseErr
+	for i, r := range p.Spec.Resources {
+		var failures []failure
+		for _, k := range sortedKeys(r) {
+			v, fails := resolveEnvRefsIn(env, k, r[k])
+			r[k] = v
+			failures = append(failures, fails...)
+		}
+		if len(failures) == 0 {
+			continue
+		}
+
+		res := errResource{
+			Kind: r.stringShort(fieldKind),
+			Idx:  i,
+		}
+		for _, f := range failures {
+			res.ValidationFails = append(res.ValidationFails, struct {
+				Field string
+				Msg   string
+			}{Field: f.Field, Msg: f.Msg})
+		}
+		parseErr.append(res)
+	}
+
+	if len(parseErr.Resources) > 0 {
+		return &parseErr
+	}
+	return nil
+}
+
+// resolveValuesFiles expands the valuesFile of every constant variable into
+// its values. The file is a CSV or newline delimited list of values, with an
+// optional @ prefix on its path, i.e. valuesFile: "@regions.csv". Files that
+// can not be read or hold no values are reported against the variable.
+func (p *Pkg) resolveValuesFiles(readFiles bool, dir string) error {
+	var parseErr ParseErr
+	for i, r := range p.Spec.Resources {
+		k, err := r.kind()
+		if err != nil || !k.is(KindVariable) {
+			continue
+		}
+
+		valuesFile, ok := r.string(fieldVarValuesFile)
+		if !ok {
+			continue
+		}
+
+		var (
+			values []string
+			fail   *failure
+		)
+		if readFiles {
+			values, fail = readValuesFile(dir, r, valuesFile)
+		} else {
+			fail = &failure{
+				Field: fieldVarValuesFile,
+				Msg:   "values files are only read for pkgs parsed from a local file",
+			}
+		}
+		if fail != nil {
+			parseErr.append(errResource{
+				Kind: k.String(),
+				Idx:  i,
+				ValidationFails: []struct {
+					Field string
+					Msg   string
+				}{{Field: fail.Field, Msg: fail.Msg}},
+			})
+			continue
+		}
+		// the expanded pkg no longer references the file, it encodes with
+		// its values in place
+		delete(r, fieldVarValuesFile)
+		r[fieldValues] = values
+	}
+
+	if len(parseErr.Resources) > 0 {
+		return &parseErr
+	}
+	return nil
+}
+
+func readValuesFile(dir string, r Resource, valuesFile string) ([]string, *failure) {
+	if typ := strings.ToLower(r.stringShort(fieldType)); typ != fieldArgTypeConstant {
+		return nil, &failure{
+			Field: fieldVarValuesFile,
+			Msg:   fmt.Sprintf("only a %s variable may provide a values file; got type %q", fieldArgTypeConstant, typ),
+		}
+	}
+	if _, ok := r[fieldValues]; ok {
+		return nil, &failure{
+			Field: fieldVarValuesFile,
+			Msg:   "only one of values or valuesFile may be provided",
+		}
+	}
+
+	filePath := strings.TrimPrefix(strings.TrimSpace(valuesFile), "@")
+	if filePath == "" {
+		return nil, &failure{Field: fieldVarValuesFile, Msg: "must be provided"}
+	}
+	if !filepath.IsAbs(filePath) {
+		filePath = filepath.Join(dir, filePath)
+	}
+
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, &failure{
+			Field: fieldVarValuesFile,
+			Msg:   fmt.Sprintf("unable to read values file: %s", err),
+		}
+	}
+	defer f.Close()
+
+	cr := csv.NewReader(f)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, &failure{
+			Field: fieldVarValuesFile,
+			Msg:   fmt.Sprintf("unable to parse values file %s: %s", filePath, err),
+		}
+	}
+
+	var values []string
+	for _, record := range records {
+		for _, v := range record {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+	}
+	if len(values) == 0 {
+		return nil, &failure{
+			Field: fieldVarValuesFile,
+			Msg:   fmt.Sprintf("values file %s provides no values", filePath),
+		}
+	}
+
+	return values, nil
+}
+
+// resolveEnvRefsIn resolves the env references of the value found at field,
+// descending into any nested maps and slices.
+func resolveEnvRefsIn(env map[string]string, field string, v interface{}) (interface{}, []failure) {
+	var failures []failure
+	switch val := v.(type) {
+	case string:
+		s, unresolved := substituteEnv(val, env)
+		for _, name := range unresolved {
+			failures = append(failures, failure{
+				Field: field,
+				Msg:   fmt.Sprintf("env reference %q is not set and provides no default", name),
+			})
+		}
+		return s, failures
+	case []interface{}:
+		for i := range val {
+			var fails []failure
+			val[i], fails = resolveEnvRefsIn(env, fmt.Sprintf("%s[%d]", field, i), val[i])
+			failures = append(failures, fails...)
+		}
+		return val, failures
+	default:
+		m, ok := ifaceToResource(v)
+		if !ok {
+			return v, nil
+		}
+		for _, k := range sortedKeys(m) {
+			var fails []failure
+			m[k], fails = resolveEnvRefsIn(env, field+"."+k, m[k])
+			failures = append(failures, fails...)
+		}
+		return m, failures
+	}
+}
+
+// substituteEnv replaces the ${NAME} and ${NAME:-default} references in s with
+// their value from the env and returns the names of those that can not be
+// resolved. Only a ${ followed by a valid env name and a closing } is a
+// reference, leaving a flux query's other uses of $ untouched.
+func substituteEnv(s string, env map[string]string) (string, []string) {
+	var (
+		sb         strings.Builder
+		unresolved []string
+	)
+	for {
+		i := strings.Index(s, "${")
+		if i == -1 {
+			sb.WriteString(s)
+			break
+		}
+
+		if i > 0 && s[i-1] == '$' {
+			sb.WriteString(s[:i-1] + "${")
+			s = s[i+2:]
+			continue
+		}
+
+		end := strings.IndexByte(s[i:], '}')
+		if end == -1 {
+			sb.WriteString(s)
+			break
+		}
+		end += i
+
+		sb.WriteString(s[:i])
+		name, def := s[i+2:end], ""
+		hasDef := false
+		if idx := strings.Index(name, ":-"); idx != -1 {
+			name, def, hasDef = name[:idx], name[idx+2:], true
+		}
+
+		switch val, ok := env[name]; {
+		case !isEnvName(name):
+			sb.WriteString(s[i : end+1])
+		case ok:
+			sb.WriteString(val)
+		case hasDef:
+			sb.WriteString(def)
+		default:
+			unresolved = append(unresolved, name)
+			sb.WriteString(s[i : end+1])
+		}
+		s = s[end+1:]
+	}
+	return sb.String(), unresolved
+}
+
+func isEnvName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, c := range name {
+		switch {
+		case c == '_', c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z':
+		case i > 0 && c >= '0' && c <= '9':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func sortedKeys(r Resource) []string {
+	keys := make([]string, 0, len(r))
+	for k := range r {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// yamlDupMapKeys walks the raw yaml nodes of the pkg resources and returns the
+// duplicate keys of any resource values map, keyed by resource name. The yaml
+// decoder rejects duplicate keys outright, so the duplicates are dropped from
+// the node tree here, leaving the validation to report them against the resource.
+func yamlDupMapKeys(doc *yaml.Node) map[string][]string {
+	root := doc
+	if root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+		root = root.Content[0]
+	}
+
+	resources := yamlMapValue(yamlMapValue(root, "spec"), "resources")
+	if resources == nil || resources.Kind != yaml.SequenceNode {
+		return nil
+	}
+
+	dupKeys := make(map[string][]string)
+	for _, res := range resources.Content {
+		values := yamlMapValue(res, fieldValues)
+		if values == nil || values.Kind != yaml.MappingNode {
+			continue
+		}
+
+		var name string
+		if n := yamlMapValue(res, fieldName); n != nil {
+			name = n.Value
+		}
+
+		seen := make(map[string]bool)
+		content := make([]*yaml.Node, 0, len(values.Content))
+		for i := 0; i+1 < len(values.Content); i += 2 {
+			k := values.Content[i]
+			if seen[k.Value] {
+				dupKeys[name] = append(dupKeys[name], k.Value)
+				continue
+			}
+			seen[k.Value] = true
+			content = append(content, k, values.Content[i+1])
+		}
+		values.Content = content
+	}
+	return dupKeys
+}
+
+func yamlMapValue(n *yaml.Node, key string) *yaml.Node {
+	if n == nil || n.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return n.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// jsonDupMapKeys returns the duplicate keys of any resource values map, keyed by
+// resource name. The json decoder silently keeps the last value for a duplicate
+// key, so the raw object keys are inspected here. Malformed json is left for the
+// decoder to report.
+func jsonDupMapKeys(b []byte) map[string][]string {
+	var raw struct {
+		Spec struct {
+			Resources []map[string]json.RawMessage `json:"resources"`
+		} `json:"spec"`
+	}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return nil
+	}
+
+	dupKeys := make(map[string][]string)
+	for _, res := range raw.Spec.Resources {
+		values, ok := res[fieldValues]
+		if !ok {
+			continue
+		}
+
+		keys, err := jsonObjectKeys(values)
+		if err != nil {
+			continue
+		}
+
+		var name string
+		_ = json.Unmarshal(res[fieldName], &name)
+
+		seen := make(map[string]bool)
+		for _, k := range keys {
+			if seen[k] {
+				dupKeys[name] = append(dupKeys[name], k)
+				continue
+			}
+			seen[k] = true
+		}
+	}
+	return dupKeys
+}
+
+// jsonObjectKeys returns the keys of a raw json object in the order they are
+// defined, duplicates included. A raw value that is not an object has no keys.
+func jsonObjectKeys(raw json.RawMessage) ([]string, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	t, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	if d, ok := t.(json.Delim); !ok || d != '{' {
+		return nil, nil
+	}
+
+	var keys []string
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		k, _ := t.(string)
+		keys = append(keys, k)
+
+		var v json.RawMessage
+		if err := dec.Decode(&v); err != nil {
+			return nil, err
+		}
+	}
+	return keys, nil
+}
+
 // Pkg is the model for a package. The resources are more generic that one might
 // expect at first glance. This was done on purpose. The way json/yaml/toml or
 // w/e scripting you want to use, can have very different ways of parsing. The
@@ -134,11 +754,23 @@ type Pkg struct {
 
 	mLabels     map[string]*label
 	mBuckets    map[string]*bucket
+	mChecks     map[string]*check
 	mDashboards map[string]*dashboard
+	mTelegrafs  map[string]*telegraf
 	mVariables  map[string]*variable
 
-	isVerified bool // dry run has verified pkg resources with existing resources
-	isParsed   bool // indicates the pkg has been parsed and all resources graphed accordingly
+	mNotificationEndpoints map[string]*notificationEndpoint
+	mNotificationRules     map[string]*notificationRule
+
+	varDupMapKeys map[string][]string // duplicate values map keys found in the raw pkg, keyed by resource name
+	deletions     []DiffDeletion      // existing resources not in the pkg, deleted when applied with replace
+	prunes        []DiffDeletion      // existing resources of the pkg no longer in it, deleted when applied with prune
+	breakdown     []SummaryBreakdown  // resources of the pkg counted by the changes the dry run found
+	stages        map[resourceRef]int // the stage each resource is applied in, see applyStages
+
+	validPkgName bool // the pkgName is validated against the pkg name rules
+	isVerified   bool // dry run has verified pkg resources with existing resources
+	isParsed     bool // indicates the pkg has been parsed and all resources graphed accordingly
 }
 
 // Summary returns a package Summary that describes all the resources and
@@ -151,27 +783,174 @@ func (p *Pkg) Summary() Summary {
 		sum.Buckets = append(sum.Buckets, b.summarize())
 	}
 
+	for _, c := range p.checks() {
+		sum.Checks = append(sum.Checks, c.summarize())
+	}
+
 	for _, d := range p.dashboards() {
 		sum.Dashboards = append(sum.Dashboards, d.summarize())
 	}
 
-	for _, l := range p.labels() {
-		sum.Labels = append(sum.Labels, l.summarize())
+	for _, l := range p.labels() {
+		sum.Labels = append(sum.Labels, l.summarize())
+	}
+
+	for _, m := range p.labelMappings() {
+		sum.LabelMappings = append(sum.LabelMappings, SummaryLabelMapping{
+			ResourceName: m.ResourceName,
+			LabelName:    m.LabelName,
+			LabelMapping: m.LabelMapping,
+		})
+	}
+
+	for _, e := range p.notificationEndpoints() {
+		sum.NotificationEndpoints = append(sum.NotificationEndpoints, e.summarize())
+	}
+
+	for _, r := range p.notificationRules() {
+		sum.NotificationRules = append(sum.NotificationRules, r.summarize())
+	}
+
+	for _, t := range p.telegrafs() {
+		sum.TelegrafConfigs = append(sum.TelegrafConfigs, t.summarize())
+	}
+
+	for _, v := range p.variables() {
+		sum.Variables = append(sum.Variables, v.summarize())
+	}
+
+	sum.Breakdown = p.breakdown
+
+	return sum
+}
+
+// Contains identifies if a pkg contains a given resource of the provided
+// kind and name.
+func (p *Pkg) Contains(k Kind, name string) bool {
+	_, ok := p.Resource(k, name)
+	return ok
+}
+
+// Resource returns the summary of the resource of the provided kind and
+// name. The summary is a SummaryBucket, SummaryCheck, SummaryDashboard,
+// SummaryLabel, SummaryNotificationEndpoint, SummaryNotificationRule,
+// SummaryTelegraf or SummaryVariable matching the kind. The bool return is
+// false when the pkg does not contain the resource.
+func (p *Pkg) Resource(k Kind, name string) (interface{}, bool) {
+	switch {
+	case k.is(KindBucket):
+		if b, ok := p.mBuckets[name]; ok {
+			return b.summarize(), true
+		}
+	case k.is(KindCheck):
+		if c, ok := p.mChecks[name]; ok {
+			return c.summarize(), true
+		}
+	case k.is(KindDashboard):
+		if d, ok := p.mDashboards[name]; ok {
+			return d.summarize(), true
+		}
+	case k.is(KindLabel):
+		if l, ok := p.mLabels[name]; ok {
+			return l.summarize(), true
+		}
+	case k.is(KindNotificationEndpoint):
+		if e, ok := p.mNotificationEndpoints[name]; ok {
+			return e.summarize(), true
+		}
+	case k.is(KindNotificationRule):
+		if r, ok := p.mNotificationRules[name]; ok {
+			return r.summarize(), true
+		}
+	case k.is(KindTelegraf):
+		if t, ok := p.mTelegrafs[name]; ok {
+			return t.summarize(), true
+		}
+	case k.is(KindVariable):
+		if v, ok := p.mVariables[name]; ok {
+			return v.summarize(), true
+		}
+	}
+	return nil, false
+}
+
+// Normalize canonicalizes the pkg so semantically identical pkgs encode
+// identically. Resources are ordered by kind, dependencies first, and then by
+// name. Kinds are lowercased and names trimmed of whitespace. The associations
+// of each resource are normalized in the same manner.
+func (p *Pkg) Normalize() {
+	normalizeResources(p.Spec.Resources)
+	for _, r := range p.Spec.Resources {
+		associations := r.slcResource(fieldAssociations)
+		if len(associations) == 0 {
+			continue
+		}
+		normalizeResources(associations)
+		r[fieldAssociations] = associations
+	}
+}
+
+// Encode encodes the pkg in the provided encoding.
+func (p *Pkg) Encode(encoding Encoding) ([]byte, error) {
+	var (
+		buf bytes.Buffer
+		err error
+	)
+	switch encoding {
+	case EncodingYAML:
+		enc := yaml.NewEncoder(&buf)
+		err = enc.Encode(p)
+		if err == nil {
+			err = enc.Close()
+		}
+	case EncodingJSON:
+		enc := json.NewEncoder(&buf)
+		enc.SetIndent("", "\t")
+		err = enc.Encode(p)
+	default:
+		return nil, ErrInvalidEncoding
 	}
-
-	for _, m := range p.labelMappings() {
-		sum.LabelMappings = append(sum.LabelMappings, SummaryLabelMapping{
-			ResourceName: m.ResourceName,
-			LabelName:    m.LabelName,
-			LabelMapping: m.LabelMapping,
-		})
+	if err != nil {
+		return nil, err
 	}
+	return buf.Bytes(), nil
+}
 
-	for _, v := range p.variables() {
-		sum.Variables = append(sum.Variables, v.summarize())
+// normalizedKindOrder is the order kinds are sorted in when normalizing a pkg,
+// resources precede the resources that depend on them.
+var normalizedKindOrder = map[Kind]int{
+	KindLabel:                1,
+	KindBucket:               2,
+	KindVariable:             3,
+	KindDashboard:            4,
+	KindTelegraf:             5,
+	KindCheck:                6,
+	KindNotificationEndpoint: 7,
+	KindNotificationRule:     8,
+}
+
+func normalizeResources(resources []Resource) {
+	for _, r := range resources {
+		if k, err := r.kind(); err == nil {
+			r[fieldKind] = k.String()
+		}
+		if _, ok := r.string(fieldName); ok {
+			r[fieldName] = r.Name()
+		}
 	}
 
-	return sum
+	sort.SliceStable(resources, func(i, j int) bool {
+		iKind, _ := resources[i].kind()
+		jKind, _ := resources[j].kind()
+		if iKind != jKind {
+			iOrder, jOrder := normalizedKindOrder[iKind], normalizedKindOrder[jKind]
+			if iOrder != jOrder {
+				return iOrder < jOrder
+			}
+			return iKind < jKind
+		}
+		return resources[i].Name() < resources[j].Name()
+	})
 }
 
 // Validate will graph all resources and validate every thing is in a useful form.
@@ -180,6 +959,7 @@ func (p *Pkg) Validate() error {
 		p.validMetadata,
 		p.validResources,
 		p.graphResources,
+		p.graphDependsOn,
 	}
 
 	for _, fn := range setupFns {
@@ -218,6 +998,19 @@ func (p *Pkg) labels() []*label {
 	return labels
 }
 
+func (p *Pkg) checks() []*check {
+	checks := make([]*check, 0, len(p.mChecks))
+	for _, c := range p.mChecks {
+		checks = append(checks, c)
+	}
+
+	sort.Slice(checks, func(i, j int) bool {
+		return checks[i].Name < checks[j].Name
+	})
+
+	return checks
+}
+
 func (p *Pkg) dashboards() []*dashboard {
 	dashes := make([]*dashboard, 0, len(p.mDashboards))
 	for _, d := range p.mDashboards {
@@ -231,6 +1024,45 @@ func (p *Pkg) dashboards() []*dashboard {
 	return dashes
 }
 
+func (p *Pkg) notificationEndpoints() []*notificationEndpoint {
+	endpoints := make([]*notificationEndpoint, 0, len(p.mNotificationEndpoints))
+	for _, e := range p.mNotificationEndpoints {
+		endpoints = append(endpoints, e)
+	}
+
+	sort.Slice(endpoints, func(i, j int) bool {
+		return endpoints[i].Name < endpoints[j].Name
+	})
+
+	return endpoints
+}
+
+func (p *Pkg) notificationRules() []*notificationRule {
+	rules := make([]*notificationRule, 0, len(p.mNotificationRules))
+	for _, r := range p.mNotificationRules {
+		rules = append(rules, r)
+	}
+
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].Name < rules[j].Name
+	})
+
+	return rules
+}
+
+func (p *Pkg) telegrafs() []*telegraf {
+	teles := make([]*telegraf, 0, len(p.mTelegrafs))
+	for _, t := range p.mTelegrafs {
+		teles = append(teles, t)
+	}
+
+	sort.Slice(teles, func(i, j int) bool {
+		return teles[i].Name() < teles[j].Name()
+	})
+
+	return teles
+}
+
 func (p *Pkg) variables() []*variable {
 	vars := make([]*variable, 0, len(p.mVariables))
 	for _, v := range p.mVariables {
@@ -244,6 +1076,20 @@ func (p *Pkg) variables() []*variable {
 	return vars
 }
 
+// declaredKinds returns the kinds of the resources the pkg declares.
+func (p *Pkg) declaredKinds() map[Kind]bool {
+	return map[Kind]bool{
+		KindBucket:               len(p.mBuckets) > 0,
+		KindCheck:                len(p.mChecks) > 0,
+		KindDashboard:            len(p.mDashboards) > 0,
+		KindLabel:                len(p.mLabels) > 0,
+		KindNotificationEndpoint: len(p.mNotificationEndpoints) > 0,
+		KindNotificationRule:     len(p.mNotificationRules) > 0,
+		KindTelegraf:             len(p.mTelegrafs) > 0,
+		KindVariable:             len(p.mVariables) > 0,
+	}
+}
+
 // labelMappings returns the mappings that will be created for
 // valid pairs of labels and resources of which all have IDs.
 // If a resource does not exist yet, a label mapping will not
@@ -277,10 +1123,10 @@ func (p *Pkg) labelMappings() []SummaryLabelMapping {
 
 func (p *Pkg) validMetadata() error {
 	var failures []*failure
-	if p.APIVersion != APIVersion {
+	if msg, ok := validAPIVersion(p.APIVersion); !ok {
 		failures = append(failures, &failure{
 			Field: "apiVersion",
-			Msg:   "must be version " + APIVersion,
+			Msg:   msg,
 		})
 	}
 
@@ -304,6 +1150,11 @@ func (p *Pkg) validMetadata() error {
 			Field: "meta.pkgName",
 			Msg:   "must be at least 1 char",
 		})
+	} else if p.validPkgName && !pkgNameRegex.MatchString(p.Metadata.Name) {
+		failures = append(failures, &failure{
+			Field: "meta.pkgName",
+			Msg:   fmt.Sprintf("must be at most 63 lowercase alphanumeric chars or hyphens that start and end with an alphanumeric char; got=%q", p.Metadata.Name),
+		})
 	}
 
 	if len(failures) == 0 {
@@ -328,6 +1179,66 @@ func (p *Pkg) validMetadata() error {
 	return &err
 }
 
+var pkgNameRegex = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// validAPIVersion verifies the version falls within the supported range of
+// apiVersions. When the version is not supported, a message describing the
+// required and supported versions is returned.
+func validAPIVersion(version string) (string, bool) {
+	v, ok := parseAPIVersion(version)
+	if !ok {
+		return "must be version " + APIVersion, false
+	}
+
+	latest, _ := parseAPIVersion(APIVersion)
+	if latest.less(v) {
+		const msgFmt = "pkg requires apiVersion %s which is newer than the latest supported apiVersion %s"
+		return fmt.Sprintf(msgFmt, version, APIVersion), false
+	}
+
+	min, _ := parseAPIVersion(MinAPIVersion)
+	if v.less(min) {
+		const msgFmt = "pkg requires apiVersion %s which is older than the minimum supported apiVersion %s"
+		return fmt.Sprintf(msgFmt, version, MinAPIVersion), false
+	}
+
+	return "", true
+}
+
+type apiVersion struct {
+	major, minor, patch int
+}
+
+// parseAPIVersion parses a major.minor.patch version. The patch is
+// optional and defaults to 0 when not provided.
+func parseAPIVersion(s string) (apiVersion, bool) {
+	parts := strings.Split(strings.TrimSpace(s), ".")
+	if len(parts) < 2 || len(parts) > 3 {
+		return apiVersion{}, false
+	}
+
+	var nums [3]int
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return apiVersion{}, false
+		}
+		nums[i] = n
+	}
+
+	return apiVersion{major: nums[0], minor: nums[1], patch: nums[2]}, true
+}
+
+func (v apiVersion) less(other apiVersion) bool {
+	if v.major != other.major {
+		return v.major < other.major
+	}
+	if v.minor != other.minor {
+		return v.minor < other.minor
+	}
+	return v.patch < other.patch
+}
+
 func (p *Pkg) validResources() error {
 	if len(p.Spec.Resources) > 0 {
 		return nil
@@ -353,134 +1264,513 @@ func (p *Pkg) graphResources() error {
 		p.graphVariables,
 		p.graphBuckets,
 		p.graphDashboards,
+		p.graphTelegrafs,
+		p.graphChecks,
+		// checks and notification endpoints precede the notification rules
+		// referencing them
+		p.graphNotificationEndpoints,
+		p.graphNotificationRules,
+	}
+
+	for _, fn := range graphFns {
+		if err := fn(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *Pkg) graphBuckets() error {
+	p.mBuckets = make(map[string]*bucket)
+	return p.eachResource(KindBucket, func(r Resource) []failure {
+		if r.Name() == "" {
+			return []failure{{
+				Field: "name",
+				Msg:   "must be a string of at least 2 chars in length",
+			}}
+		}
+
+		if _, ok := p.mBuckets[r.Name()]; ok {
+			return []failure{{
+				Field: "name",
+				Msg:   "duplicate name: " + r.Name(),
+			}}
+		}
+
+		bkt := &bucket{
+			Name:            r.Name(),
+			Description:     r.stringShort(fieldDescription),
+			RetentionPeriod: r.duration(fieldBucketRetentionPeriod),
+			SchemaType:      influxdb.SchemaTypeImplicit,
+			lookupID:        r.lookupID(),
+		}
+		if st, ok := r.string(fieldBucketSchemaType); ok {
+			bkt.SchemaType = influxdb.SchemaType(st)
+		}
+		if !bkt.SchemaType.Valid() {
+			return []failure{{
+				Field: fieldBucketSchemaType,
+				Msg:   fmt.Sprintf("must be 1 in [%s, %s]; got=%q", influxdb.SchemaTypeImplicit, influxdb.SchemaTypeExplicit, bkt.SchemaType),
+			}}
+		}
+		if dur, ok := r.string(fieldBucketShardGroupDuration); ok {
+			sgd, err := time.ParseDuration(dur)
+			if err != nil || sgd <= 0 {
+				return []failure{{
+					Field: fieldBucketShardGroupDuration,
+					Msg:   fmt.Sprintf("must be a positive duration; got=%q", dur),
+				}}
+			}
+			// a zero retention period is infinite retention, any shard group duration fits
+			if bkt.RetentionPeriod > 0 && sgd > bkt.RetentionPeriod {
+				return []failure{{
+					Field: fieldBucketShardGroupDuration,
+					Msg:   fmt.Sprintf("must not be greater than the retention period %s; got=%s", bkt.RetentionPeriod, sgd),
+				}}
+			}
+			bkt.ShardGroupDuration = sgd
+		}
+
+		failures := p.parseNestedLabels(r, func(l *label) error {
+			bkt.labels = append(bkt.labels, l)
+			p.mLabels[l.Name].setBucketMapping(bkt, false)
+			return nil
+		})
+		if len(failures) > 0 {
+			return failures
+		}
+		sort.Slice(bkt.labels, func(i, j int) bool {
+			return bkt.labels[i].Name < bkt.labels[j].Name
+		})
+
+		p.mBuckets[r.Name()] = bkt
+
+		return failures
+	})
+}
+
+func (p *Pkg) graphLabels() error {
+	p.mLabels = make(map[string]*label)
+	return p.eachResource(KindLabel, func(r Resource) []failure {
+		if r.Name() == "" {
+			return []failure{{
+				Field: "name",
+				Msg:   "must be a string of at least 2 chars in length",
+			}}
+		}
+
+		if _, ok := p.mLabels[r.Name()]; ok {
+			return []failure{{
+				Field: "name",
+				Msg:   "duplicate name: " + r.Name(),
+			}}
+		}
+		// normalizing the color here keeps a named color from diffing against
+		// a label that already has its hex color.
+		color, err := normalizeLabelColor(r.stringShort(fieldLabelColor))
+		if err != nil {
+			return []failure{{
+				Field: fieldLabelColor,
+				Msg:   err.Error(),
+			}}
+		}
+
+		p.mLabels[r.Name()] = &label{
+			Name:        r.Name(),
+			Color:       color,
+			Description: r.stringShort(fieldDescription),
+			lookupID:    r.lookupID(),
+		}
+
+		return nil
+	})
+}
+
+func (p *Pkg) graphDashboards() error {
+	p.mDashboards = make(map[string]*dashboard)
+	return p.eachResource(KindDashboard, func(r Resource) []failure {
+		if r.Name() == "" {
+			return []failure{{
+				Field: "name",
+				Msg:   "must be a string of at least 2 chars in length",
+			}}
+		}
+
+		if _, ok := p.mDashboards[r.Name()]; ok {
+			return []failure{{
+				Field: "name",
+				Msg:   "duplicate name: " + r.Name(),
+			}}
+		}
+
+		dash := &dashboard{
+			Name:        r.Name(),
+			Description: r.stringShort(fieldDescription),
+		}
+
+		failures := p.parseNestedLabels(r, func(l *label) error {
+			dash.labels = append(dash.labels, l)
+			p.mLabels[l.Name].setDashboardMapping(dash)
+			return nil
+		})
+		sort.Slice(dash.labels, func(i, j int) bool {
+			return dash.labels[i].Name < dash.labels[j].Name
+		})
+
+		layout := strings.ToLower(strings.TrimSpace(r.stringShort(fieldDashLayout)))
+		if layout != "" && layout != dashLayoutAuto {
+			failures = append(failures, failure{
+				Field: fieldDashLayout,
+				Msg:   fmt.Sprintf("must be %q when provided; got=%q", dashLayoutAuto, layout),
+			})
+		}
+
+		columns := dashLayoutDefaultColumns
+		if cols, ok := r.int(fieldDashLayoutColumns); ok {
+			columns = cols
+			if columns <= 0 {
+				failures = append(failures, failure{
+					Field: fieldDashLayoutColumns,
+					Msg:   "must be greater than 0",
+				})
+			}
+		}
+
+		var positioned []bool
+		for i, cr := range r.slcResource(fieldDashCharts) {
+			ch, fails := parseChart(cr)
+			if fails != nil {
+				for _, f := range fails {
+					failures = append(failures, failure{
+						Field: fmt.Sprintf("charts[%d].%s", i, f.Field),
+						Msg:   f.Msg,
+					})
+				}
+				continue
+			}
+			dash.Charts = append(dash.Charts, ch)
+
+			_, hasX := cr[fieldChartXPos]
+			_, hasY := cr[fieldChartYPos]
+			positioned = append(positioned, hasX || hasY)
+		}
+
+		if len(failures) > 0 {
+			return failures
+		}
+
+		if layout == dashLayoutAuto {
+			if fails := autoLayoutCharts(dash.Charts, positioned, columns); len(fails) > 0 {
+				return fails
+			}
+		}
+
+		p.mDashboards[r.Name()] = dash
+
+		return nil
+	})
+}
+
+// autoLayoutCharts places the charts without an explicit position in a grid
+// of the given number of columns. In declaration order, each chart is placed
+// at the first position, filling rows top to bottom and left to right, where
+// it does not overlap any chart placed before it. Charts with an explicit
+// position keep it.
+func autoLayoutCharts(charts []chart, positioned []bool, columns int) []failure {
+	var placed []chart
+	for i, c := range charts {
+		if positioned[i] {
+			placed = append(placed, c)
+		}
+	}
+
+	var failures []failure
+	for i := range charts {
+		if positioned[i] {
+			continue
+		}
+
+		c := &charts[i]
+		if c.Width > columns {
+			failures = append(failures, failure{
+				Field: fmt.Sprintf("charts[%d].%s", i, fieldChartWidth),
+				Msg:   fmt.Sprintf("must not exceed the %d layout columns; got=%d", columns, c.Width),
+			})
+			continue
+		}
+
+	search:
+		for y := 0; ; y++ {
+			for x := 0; x+c.Width <= columns; x++ {
+				c.XPos, c.YPos = x, y
+				if !overlapsAny(*c, placed) {
+					break search
+				}
+			}
+		}
+		placed = append(placed, *c)
+	}
+	return failures
+}
+
+func overlapsAny(c chart, charts []chart) bool {
+	for _, other := range charts {
+		if c.XPos < other.XPos+other.Width && other.XPos < c.XPos+c.Width &&
+			c.YPos < other.YPos+other.Height && other.YPos < c.YPos+c.Height {
+			return true
+		}
 	}
+	return false
+}
 
-	for _, fn := range graphFns {
-		if err := fn(); err != nil {
-			return err
+func (p *Pkg) graphNotificationEndpoints() error {
+	p.mNotificationEndpoints = make(map[string]*notificationEndpoint)
+	return p.eachResource(KindNotificationEndpoint, func(r Resource) []failure {
+		if r.Name() == "" {
+			return []failure{{
+				Field: "name",
+				Msg:   "must be provided",
+			}}
 		}
-	}
 
-	return nil
+		if _, ok := p.mNotificationEndpoints[r.Name()]; ok {
+			return []failure{{
+				Field: "name",
+				Msg:   "duplicate name: " + r.Name(),
+			}}
+		}
+
+		e := &notificationEndpoint{
+			Name:        r.Name(),
+			Description: r.stringShort(fieldDescription),
+			Type:        strings.ToLower(strings.TrimSpace(r.stringShort(fieldType))),
+			Status:      influxdb.Status(strings.ToLower(strings.TrimSpace(r.stringShort(fieldNotificationEndpointStatus)))),
+			URL:         strings.TrimSpace(r.stringShort(fieldNotificationEndpointURL)),
+			ClientURL:   strings.TrimSpace(r.stringShort(fieldNotificationEndpointClientURL)),
+			RoutingKey:  r.stringShort(fieldNotificationEndpointRoutingKey),
+			Token:       r.stringShort(fieldNotificationEndpointToken),
+			Method:      strings.ToUpper(strings.TrimSpace(r.stringShort(fieldNotificationEndpointMethod))),
+			AuthMethod:  strings.ToLower(strings.TrimSpace(r.stringShort(fieldNotificationEndpointAuthMethod))),
+			Username:    r.stringShort(fieldNotificationEndpointUsername),
+			Password:    r.stringShort(fieldNotificationEndpointPassword),
+		}
+		if e.Status == "" {
+			e.Status = influxdb.Active
+		}
+		if e.Type == endpoint.HTTPType {
+			if e.Method == "" {
+				e.Method = "POST"
+			}
+			if e.AuthMethod == "" {
+				e.AuthMethod = "none"
+			}
+		}
+
+		failures := p.parseNestedLabels(r, func(l *label) error {
+			e.labels = append(e.labels, l)
+			p.mLabels[l.Name].setNotificationEndpointMapping(e)
+			return nil
+		})
+		sort.Slice(e.labels, func(i, j int) bool {
+			return e.labels[i].Name < e.labels[j].Name
+		})
+
+		failures = append(failures, e.valid()...)
+		if len(failures) > 0 {
+			return failures
+		}
+
+		p.mNotificationEndpoints[r.Name()] = e
+
+		return nil
+	})
 }
 
-func (p *Pkg) graphBuckets() error {
-	p.mBuckets = make(map[string]*bucket)
-	return p.eachResource(KindBucket, func(r Resource) []failure {
+func (p *Pkg) graphNotificationRules() error {
+	p.mNotificationRules = make(map[string]*notificationRule)
+	return p.eachResource(KindNotificationRule, func(r Resource) []failure {
 		if r.Name() == "" {
 			return []failure{{
 				Field: "name",
-				Msg:   "must be a string of at least 2 chars in length",
+				Msg:   "must be provided",
 			}}
 		}
 
-		if _, ok := p.mBuckets[r.Name()]; ok {
+		if _, ok := p.mNotificationRules[r.Name()]; ok {
 			return []failure{{
 				Field: "name",
 				Msg:   "duplicate name: " + r.Name(),
 			}}
 		}
 
-		bkt := &bucket{
+		nr := &notificationRule{
 			Name:            r.Name(),
 			Description:     r.stringShort(fieldDescription),
-			RetentionPeriod: r.duration(fieldBucketRetentionPeriod),
+			Every:           strings.TrimSpace(r.stringShort(fieldEvery)),
+			Offset:          strings.TrimSpace(r.stringShort(fieldOffset)),
+			Channel:         r.stringShort(fieldNotificationRuleChannel),
+			MessageTemplate: r.stringShort(fieldNotificationRuleMessageTemplate),
+		}
+		for _, sr := range r.slcResource(fieldNotificationRuleStatusRules) {
+			nr.StatusRules = append(nr.StatusRules, statusRule{
+				CurrentLevel:  strings.ToUpper(strings.TrimSpace(sr.stringShort(fieldNotificationRuleCurrentLevel))),
+				PreviousLevel: strings.ToUpper(strings.TrimSpace(sr.stringShort(fieldNotificationRulePreviousLevel))),
+			})
 		}
 
-		failures := p.parseNestedLabels(r, func(l *label) error {
-			bkt.labels = append(bkt.labels, l)
-			p.mLabels[l.Name].setBucketMapping(bkt, false)
+		var failures []failure
+		if checkName := strings.TrimSpace(r.stringShort(fieldNotificationRuleCheckName)); checkName != "" {
+			if c, ok := p.mChecks[checkName]; ok {
+				nr.check = c
+			} else {
+				failures = append(failures, failure{
+					Field: fieldNotificationRuleCheckName,
+					Msg:   fmt.Sprintf("check %q does not exist in pkg", checkName),
+				})
+			}
+		}
+
+		endpointName := strings.TrimSpace(r.stringShort(fieldNotificationRuleEndpointName))
+		if e, ok := p.mNotificationEndpoints[endpointName]; ok {
+			nr.endpoint = e
+		} else if endpointName == "" {
+			failures = append(failures, failure{
+				Field: fieldNotificationRuleEndpointName,
+				Msg:   "must be provided",
+			})
+		} else {
+			failures = append(failures, failure{
+				Field: fieldNotificationRuleEndpointName,
+				Msg:   fmt.Sprintf("notification endpoint %q does not exist in pkg", endpointName),
+			})
+		}
+
+		failures = append(failures, p.parseNestedLabels(r, func(l *label) error {
+			nr.labels = append(nr.labels, l)
+			p.mLabels[l.Name].setNotificationRuleMapping(nr)
 			return nil
+		})...)
+		sort.Slice(nr.labels, func(i, j int) bool {
+			return nr.labels[i].Name < nr.labels[j].Name
 		})
+
+		failures = append(failures, nr.valid()...)
 		if len(failures) > 0 {
 			return failures
 		}
-		sort.Slice(bkt.labels, func(i, j int) bool {
-			return bkt.labels[i].Name < bkt.labels[j].Name
-		})
 
-		p.mBuckets[r.Name()] = bkt
+		p.mNotificationRules[r.Name()] = nr
 
-		return failures
+		return nil
 	})
 }
 
-func (p *Pkg) graphLabels() error {
-	p.mLabels = make(map[string]*label)
-	return p.eachResource(KindLabel, func(r Resource) []failure {
+func (p *Pkg) graphTelegrafs() error {
+	p.mTelegrafs = make(map[string]*telegraf)
+	return p.eachResource(KindTelegraf, func(r Resource) []failure {
 		if r.Name() == "" {
 			return []failure{{
 				Field: "name",
-				Msg:   "must be a string of at least 2 chars in length",
+				Msg:   "must be provided",
 			}}
 		}
 
-		if _, ok := p.mLabels[r.Name()]; ok {
+		if _, ok := p.mTelegrafs[r.Name()]; ok {
 			return []failure{{
 				Field: "name",
 				Msg:   "duplicate name: " + r.Name(),
 			}}
 		}
-		p.mLabels[r.Name()] = &label{
-			Name:        r.Name(),
-			Color:       r.stringShort(fieldLabelColor),
-			Description: r.stringShort(fieldDescription),
+
+		tele := new(telegraf)
+
+		failures := p.parseNestedLabels(r, func(l *label) error {
+			tele.labels = append(tele.labels, l)
+			p.mLabels[l.Name].setTelegrafMapping(tele)
+			return nil
+		})
+		sort.Slice(tele.labels, func(i, j int) bool {
+			return tele.labels[i].Name < tele.labels[j].Name
+		})
+
+		cfg := r.stringShort(fieldTelegrafConfig)
+		if strings.TrimSpace(cfg) == "" {
+			failures = append(failures, failure{
+				Field: fieldTelegrafConfig,
+				Msg:   "no config provided",
+			})
+		} else if err := toml.Unmarshal([]byte(cfg), &tele.config); err != nil {
+			failures = append(failures, failure{
+				Field: fieldTelegrafConfig,
+				Msg:   "invalid config provided: " + err.Error(),
+			})
+		}
+
+		if len(failures) > 0 {
+			return failures
 		}
 
+		tele.config.Name = r.Name()
+		tele.config.Description = r.stringShort(fieldDescription)
+		p.mTelegrafs[r.Name()] = tele
+
 		return nil
 	})
 }
 
-func (p *Pkg) graphDashboards() error {
-	p.mDashboards = make(map[string]*dashboard)
-	return p.eachResource(KindDashboard, func(r Resource) []failure {
+func (p *Pkg) graphChecks() error {
+	p.mChecks = make(map[string]*check)
+	return p.eachResource(KindCheck, func(r Resource) []failure {
 		if r.Name() == "" {
 			return []failure{{
 				Field: "name",
-				Msg:   "must be a string of at least 2 chars in length",
+				Msg:   "must be provided",
 			}}
 		}
 
-		if _, ok := p.mDashboards[r.Name()]; ok {
+		if _, ok := p.mChecks[r.Name()]; ok {
 			return []failure{{
 				Field: "name",
 				Msg:   "duplicate name: " + r.Name(),
 			}}
 		}
 
-		dash := &dashboard{
-			Name:        r.Name(),
-			Description: r.stringShort(fieldDescription),
+		ch := &check{
+			Name:          r.Name(),
+			Description:   r.stringShort(fieldDescription),
+			Query:         strings.TrimSpace(r.stringShort(fieldQuery)),
+			StatusMessage: r.stringShort(fieldCheckStatusMessageTemplate),
+			Every:         strings.TrimSpace(r.stringShort(fieldEvery)),
+			Offset:        strings.TrimSpace(r.stringShort(fieldOffset)),
+			lookupID:      r.lookupID(),
+		}
+		for _, tr := range r.slcResource(fieldCheckThresholds) {
+			ch.Thresholds = append(ch.Thresholds, threshold{
+				Type:      strings.ToLower(strings.TrimSpace(tr.stringShort(fieldType))),
+				Level:     strings.ToUpper(strings.TrimSpace(tr.stringShort(fieldCheckLevel))),
+				AllValues: tr.boolShort(fieldCheckAllValues),
+				Val:       tr.float64Short(fieldValue),
+				Min:       tr.float64Short(fieldCheckMin),
+				Max:       tr.float64Short(fieldCheckMax),
+			})
 		}
 
 		failures := p.parseNestedLabels(r, func(l *label) error {
-			dash.labels = append(dash.labels, l)
-			p.mLabels[l.Name].setDashboardMapping(dash)
+			ch.labels = append(ch.labels, l)
+			p.mLabels[l.Name].setCheckMapping(ch, false)
 			return nil
 		})
-		sort.Slice(dash.labels, func(i, j int) bool {
-			return dash.labels[i].Name < dash.labels[j].Name
+		sort.Slice(ch.labels, func(i, j int) bool {
+			return ch.labels[i].Name < ch.labels[j].Name
 		})
 
-		for i, cr := range r.slcResource(fieldDashCharts) {
-			ch, fails := parseChart(cr)
-			if fails != nil {
-				for _, f := range fails {
-					failures = append(failures, failure{
-						Field: fmt.Sprintf("charts[%d].%s", i, f.Field),
-						Msg:   f.Msg,
-					})
-				}
-				continue
-			}
-			dash.Charts = append(dash.Charts, ch)
-		}
-
+		failures = append(failures, ch.valid()...)
 		if len(failures) > 0 {
 			return failures
 		}
 
-		p.mDashboards[r.Name()] = dash
+		p.mChecks[r.Name()] = ch
 
 		return nil
 	})
@@ -511,6 +1801,7 @@ func (p *Pkg) graphVariables() error {
 			Language:    strings.ToLower(strings.TrimSpace(r.stringShort(fieldLegendLanguage))),
 			ConstValues: r.slcStr(fieldValues),
 			MapValues:   r.mapStrStr(fieldValues),
+			lookupID:    r.lookupID(),
 		}
 
 		failures := p.parseNestedLabels(r, func(l *label) error {
@@ -532,10 +1823,264 @@ func (p *Pkg) graphVariables() error {
 		// invalid. So the mapping is correct. So we keep this
 		// to validate that mapping is correct, and return fails
 		// to indicate fails from the var.
-		return append(failures, newVar.valid()...)
+		failures = append(failures, newVar.valid()...)
+		if keys := p.varDupMapKeys[r.Name()]; newVar.Type == "map" && len(keys) > 0 {
+			failures = append(failures, failure{
+				Field: "values",
+				Msg:   fmt.Sprintf("duplicate map keys: %s", strings.Join(keys, ", ")),
+			})
+		}
+		return failures
 	})
 }
 
+// resourceRef identifies a resource of the pkg by its kind and name.
+type resourceRef struct {
+	kind Kind
+	name string
+}
+
+func (r resourceRef) String() string {
+	return r.kind.String() + "/" + r.name
+}
+
+// graphDependsOn orders the resources into the stages they are applied in. A
+// resource is applied in a later stage than every resource it depends on,
+// either explicitly by its dependsOn list of kind/name references or
+// implicitly, i.e. a notification rule depends on its notification endpoint.
+// A cycle of dependencies is a validation failure.
+func (p *Pkg) graphDependsOn() error {
+	var parseErr ParseErr
+	appendFails := func(idx int, k Kind, failures []failure) {
+		err := errResource{
+			Kind: k.String(),
+			Idx:  idx,
+		}
+		for _, f := range failures {
+			err.ValidationFails = append(err.ValidationFails, struct {
+				Field string
+				Msg   string
+			}{Field: f.Field, Msg: f.Msg})
+		}
+		parseErr.append(err)
+	}
+
+	var refs []resourceRef
+	refIdxs := make(map[resourceRef]int)
+	deps := make(map[resourceRef][]resourceRef)
+	for i, r := range p.Spec.Resources {
+		k, _ := r.kind()
+		ref := resourceRef{kind: k, name: r.Name()}
+		refs = append(refs, ref)
+		refIdxs[ref] = i
+
+		var failures []failure
+		for j, v := range r.slcStr(fieldDependsOn) {
+			dep, err := p.parseResourceRef(v)
+			if err != nil {
+				failures = append(failures, failure{
+					Field: fmt.Sprintf("%s[%d]", fieldDependsOn, j),
+					Msg:   err.Error(),
+				})
+				continue
+			}
+			deps[ref] = append(deps[ref], dep)
+		}
+		if len(failures) > 0 {
+			appendFails(i, k, failures)
+		}
+	}
+	if len(parseErr.Resources) > 0 {
+		return &parseErr
+	}
+
+	for _, r := range p.mNotificationRules {
+		ref := resourceRef{kind: KindNotificationRule, name: r.Name}
+		deps[ref] = append(deps[ref], resourceRef{kind: KindNotificationEndpoint, name: r.endpoint.Name})
+	}
+
+	var (
+		cycle    []resourceRef
+		visiting = make(map[resourceRef]bool)
+		stages   = make(map[resourceRef]int)
+	)
+	var stageOf func(ref resourceRef, path []resourceRef) int
+	stageOf = func(ref resourceRef, path []resourceRef) int {
+		if stage, ok := stages[ref]; ok {
+			return stage
+		}
+		if visiting[ref] {
+			for i := range path {
+				if cycle == nil && path[i] == ref {
+					cycle = append(append([]resourceRef{}, path[i:]...), ref)
+				}
+			}
+			return 0
+		}
+
+		visiting[ref] = true
+		path = append(path, ref)
+		var stage int
+		for _, dep := range deps[ref] {
+			if depStage := stageOf(dep, path) + 1; depStage > stage {
+				stage = depStage
+			}
+		}
+		visiting[ref] = false
+		stages[ref] = stage
+		return stage
+	}
+	for _, ref := range refs {
+		stageOf(ref, nil)
+	}
+
+	if cycle != nil {
+		cycleRefs := make([]string, 0, len(cycle))
+		for _, ref := range cycle {
+			cycleRefs = append(cycleRefs, ref.String())
+		}
+		appendFails(refIdxs[cycle[0]], cycle[0].kind, []failure{{
+			Field: fieldDependsOn,
+			Msg:   "dependency cycle: " + strings.Join(cycleRefs, " -> "),
+		}})
+		return &parseErr
+	}
+
+	p.stages = stages
+	return nil
+}
+
+// parseResourceRef parses a kind/name reference to a resource of the pkg.
+func (p *Pkg) parseResourceRef(v string) (resourceRef, error) {
+	parts := strings.SplitN(v, "/", 2)
+	if len(parts) != 2 {
+		return resourceRef{}, fmt.Errorf("invalid reference %q; must be of the form kind/name", v)
+	}
+
+	k := newKind(parts[0])
+	if err := k.OK(); err != nil {
+		return resourceRef{}, err
+	}
+
+	ref := resourceRef{kind: k, name: strings.TrimSpace(parts[1])}
+	if !p.Contains(ref.kind, ref.name) {
+		return resourceRef{}, fmt.Errorf("%s %q does not exist in pkg", ref.kind, ref.name)
+	}
+	return ref, nil
+}
+
+// applyStage are the resources of the pkg that are applied together. A stage
+// is applied once all the stages before it have been applied.
+type applyStage struct {
+	labels                []*label
+	variables             []*variable
+	buckets               []*bucket
+	dashboards            []*dashboard
+	telegrafs             []*telegraf
+	checks                []*check
+	notificationEndpoints []*notificationEndpoint
+	notificationRules     []*notificationRule
+}
+
+// applyStages groups the resources by the stage they are applied in, in the
+// order the stages are applied.
+func (p *Pkg) applyStages() []applyStage {
+	numStages := 1
+	for _, stage := range p.stages {
+		if stage+1 > numStages {
+			numStages = stage + 1
+		}
+	}
+
+	stages := make([]applyStage, numStages)
+	stageOf := func(k Kind, name string) *applyStage {
+		return &stages[p.stages[resourceRef{kind: k, name: name}]]
+	}
+	for _, l := range p.labels() {
+		st := stageOf(KindLabel, l.Name)
+		st.labels = append(st.labels, l)
+	}
+	for _, v := range p.variables() {
+		st := stageOf(KindVariable, v.Name)
+		st.variables = append(st.variables, v)
+	}
+	for _, b := range p.buckets() {
+		st := stageOf(KindBucket, b.Name)
+		st.buckets = append(st.buckets, b)
+	}
+	for _, d := range p.dashboards() {
+		st := stageOf(KindDashboard, d.Name)
+		st.dashboards = append(st.dashboards, d)
+	}
+	for _, t := range p.telegrafs() {
+		st := stageOf(KindTelegraf, t.Name())
+		st.telegrafs = append(st.telegrafs, t)
+	}
+	for _, c := range p.checks() {
+		st := stageOf(KindCheck, c.Name)
+		st.checks = append(st.checks, c)
+	}
+	for _, e := range p.notificationEndpoints() {
+		st := stageOf(KindNotificationEndpoint, e.Name)
+		st.notificationEndpoints = append(st.notificationEndpoints, e)
+	}
+	for _, r := range p.notificationRules() {
+		st := stageOf(KindNotificationRule, r.Name)
+		st.notificationRules = append(st.notificationRules, r)
+	}
+
+	return stages
+}
+
+// validAnnotations validates the annotations of the resource, when provided,
+// are a map of strings.
+func validAnnotations(r Resource) []failure {
+	v, ok := r[fieldAnnotations]
+	if !ok {
+		return nil
+	}
+
+	annotations, ok := ifaceToResource(v)
+	if !ok {
+		return []failure{{Field: fieldAnnotations, Msg: "must be a map of strings"}}
+	}
+
+	keys := make([]string, 0, len(annotations))
+	for k := range annotations {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if _, ok := annotations[k].(string); !ok {
+			return []failure{{
+				Field: fieldAnnotations,
+				Msg:   fmt.Sprintf("value of %q must be a string", k),
+			}}
+		}
+	}
+	return nil
+}
+
+// validID validates the id of the resource, when provided, is a valid id.
+func validID(r Resource) []failure {
+	v, ok := r[fieldID]
+	if !ok {
+		return nil
+	}
+
+	s, ok := ifaceToStr(v)
+	if !ok {
+		return []failure{{Field: fieldID, Msg: "must be a string"}}
+	}
+	if _, err := influxdb.IDFromString(s); err != nil {
+		return []failure{{
+			Field: fieldID,
+			Msg:   fmt.Sprintf("must be a valid id; got=%q", s),
+		}}
+	}
+	return nil
+}
+
 func (p *Pkg) eachResource(resourceKind Kind, fn func(r Resource) []failure) error {
 	var parseErr ParseErr
 	for i, r := range p.Spec.Resources {
@@ -560,7 +2105,14 @@ func (p *Pkg) eachResource(resourceKind Kind, fn func(r Resource) []failure) err
 			continue
 		}
 
-		if failures := fn(r); failures != nil {
+		failures := fn(r)
+		if annFails := validAnnotations(r); len(annFails) > 0 {
+			failures = append(failures, annFails...)
+		}
+		if idFails := validID(r); len(idFails) > 0 {
+			failures = append(failures, idFails...)
+		}
+		if failures != nil {
 			err := errResource{
 				Kind: resourceKind.String(),
 				Idx:  i,
@@ -655,20 +2207,51 @@ func parseChart(r Resource) (chart, []failure) {
 	}
 
 	c := chart{
-		Kind:        ck,
-		Name:        r.Name(),
-		Prefix:      r.stringShort(fieldPrefix),
-		Suffix:      r.stringShort(fieldSuffix),
-		Note:        r.stringShort(fieldChartNote),
-		NoteOnEmpty: r.boolShort(fieldChartNoteOnEmpty),
-		Shade:       r.boolShort(fieldChartShade),
-		XCol:        r.stringShort(fieldChartXCol),
-		YCol:        r.stringShort(fieldChartYCol),
-		XPos:        r.intShort(fieldChartXPos),
-		YPos:        r.intShort(fieldChartYPos),
-		Height:      r.intShort(fieldChartHeight),
-		Width:       r.intShort(fieldChartWidth),
-		Geom:        r.stringShort(fieldChartGeom),
+		Kind:          ck,
+		Name:          r.Name(),
+		Prefix:        r.stringShort(fieldPrefix),
+		Suffix:        r.stringShort(fieldSuffix),
+		Note:          r.stringShort(fieldChartNote),
+		NoteOnEmpty:   r.boolShort(fieldChartNoteOnEmpty),
+		Shade:         r.boolShort(fieldChartShade),
+		XCol:          r.stringShort(fieldChartXCol),
+		YCol:          r.stringShort(fieldChartYCol),
+		XPos:          r.intShort(fieldChartXPos),
+		YPos:          r.intShort(fieldChartYPos),
+		Height:        r.intShort(fieldChartHeight),
+		Width:         r.intShort(fieldChartWidth),
+		Geom:          r.stringShort(fieldChartGeom),
+		BinCount:      r.intShort(fieldChartBinCount),
+		BinSize:       r.intShort(fieldChartBinSize),
+		FillColumns:   r.slcStr(fieldChartFillColumns),
+		SymbolColumns: r.slcStr(fieldChartSymbolColumns),
+		Position:      r.stringShort(fieldChartPosition),
+		TimeFormat:    r.stringShort(fieldChartTimeFormat),
+	}
+
+	if presTableOpts, ok := r[fieldChartTableOptions].(tableOptions); ok {
+		c.TableOptions = presTableOpts
+	} else {
+		if tableOpts, ok := ifaceToResource(r[fieldChartTableOptions]); ok {
+			c.TableOptions = tableOptions{
+				VerticalTimeAxis: tableOpts.boolShort(fieldTableOptionsVerticalTimeAxis),
+				SortBy:           tableOpts.stringShort(fieldTableOptionsSortBy),
+				Wrapping:         tableOpts.stringShort(fieldTableOptionsWrapping),
+				FixFirstColumn:   tableOpts.boolShort(fieldTableOptionsFixFirstColumn),
+			}
+		}
+	}
+
+	if presFieldOpts, ok := r[fieldChartFieldOptions].(fieldOptions); ok {
+		c.FieldOptions = presFieldOpts
+	} else {
+		for _, fo := range r.slcResource(fieldChartFieldOptions) {
+			c.FieldOptions = append(c.FieldOptions, fieldOption{
+				FieldName:   fo.stringShort(fieldFieldOptionFieldName),
+				DisplayName: fo.stringShort(fieldFieldOptionDisplayName),
+				Visible:     fo.boolShort(fieldFieldOptionVisible),
+			})
+		}
 	}
 
 	if presLeg, ok := r[fieldChartLegend].(legend); ok {
@@ -717,6 +2300,7 @@ func parseChart(r Resource) (chart, []failure) {
 		for _, ra := range r.slcResource(fieldChartAxes) {
 			c.Axes = append(c.Axes, axis{
 				Base:   ra.stringShort(fieldAxisBase),
+				Domain: ra.slcFloat64(fieldAxisDomain),
 				Label:  ra.stringShort(fieldAxisLabel),
 				Name:   ra.Name(),
 				Prefix: ra.stringShort(fieldPrefix),
@@ -746,6 +2330,17 @@ func (r Resource) Name() string {
 	return strings.TrimSpace(r.stringShort(fieldName))
 }
 
+// lookupID returns the id provided for the resource, i.e. the id it was
+// applied as and written back with. The zero id is returned when none is
+// provided.
+func (r Resource) lookupID() influxdb.ID {
+	id, err := influxdb.IDFromString(strings.TrimSpace(r.stringShort(fieldID)))
+	if err != nil {
+		return 0
+	}
+	return *id
+}
+
 func (r Resource) kind() (Kind, error) {
 	resKind, ok := r.string(fieldKind)
 	if !ok {
@@ -883,6 +2478,34 @@ func (r Resource) slcStr(key string) []string {
 	return out
 }
 
+func (r Resource) slcFloat64(key string) []float64 {
+	v, ok := r[key]
+	if !ok {
+		return nil
+	}
+
+	if fSlc, ok := v.([]float64); ok {
+		return fSlc
+	}
+
+	iFaceSlc, ok := v.([]interface{})
+	if !ok {
+		return nil
+	}
+
+	var out []float64
+	for _, iface := range iFaceSlc {
+		switch f := iface.(type) {
+		case float64:
+			out = append(out, f)
+		case int:
+			out = append(out, float64(f))
+		}
+	}
+
+	return out
+}
+
 func (r Resource) mapStrStr(key string) map[string]string {
 	v, ok := r[key]
 	if !ok {
@@ -1017,6 +2640,25 @@ func IsParseErr(err error) (*ParseErr, bool) {
 	return pErr, ok
 }
 
+// FetchErr is the error returned when a pkg can not be fetched from its
+// source. It is distinct from a ParseErr, which describes a pkg that was
+// fetched but is not valid.
+type FetchErr struct {
+	URL string
+	Err error
+}
+
+// Error implements the error interface.
+func (e *FetchErr) Error() string {
+	return fmt.Sprintf("failed to fetch pkg from %s: %s", e.URL, e.Err)
+}
+
+// IsFetchErr inspects a given error to determine if it is a FetchErr.
+func IsFetchErr(err error) (*FetchErr, bool) {
+	fErr, ok := err.(*FetchErr)
+	return fErr, ok
+}
+
 type errResource struct {
 	Kind            string
 	Idx             int
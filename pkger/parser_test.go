@@ -1,12 +1,23 @@
 package pkger
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/notification"
+	icheck "github.com/influxdata/influxdb/notification/check"
+	"github.com/influxdata/influxdb/notification/endpoint"
+	"github.com/influxdata/influxdb/notification/rule"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -37,6 +48,21 @@ spec:
 					name: "apiVersion is invalid version",
 					pkgStr: `apiVersion: 222.2 #invalid apiVersion
 kind: Package
+meta:
+  pkgName:      first_bucket_package
+  pkgVersion:   1
+spec:
+  resources:
+    - kind: Bucket
+      name: buck_1
+      retention_period: 1h
+`,
+					valFields: []string{"apiVersion"},
+				},
+				{
+					name: "apiVersion is newer than supported",
+					pkgStr: `apiVersion: 0.2.0
+kind: Package
 meta:
   pkgName:      first_bucket_package
   pkgVersion:   1
@@ -108,6 +134,171 @@ spec:
 		})
 	})
 
+	t.Run("pkg apiVersion", func(t *testing.T) {
+		newPkgStr := func(version string) string {
+			return `apiVersion: ` + version + `
+kind: Package
+meta:
+  pkgName:      first_bucket_package
+  pkgVersion:   1
+spec:
+  resources:
+    - kind: Bucket
+      name: buck_1
+      retention_period: 1h
+`
+		}
+
+		t.Run("compatible version is parsed", func(t *testing.T) {
+			for _, version := range []string{"0.1.0", `"0.1"`} {
+				pkg, err := Parse(EncodingYAML, FromString(newPkgStr(version)))
+				require.NoError(t, err, version)
+				require.Len(t, pkg.buckets(), 1)
+			}
+		})
+
+		t.Run("too new version names required and supported versions", func(t *testing.T) {
+			_, err := Parse(EncodingYAML, FromString(newPkgStr("1.3.0")))
+			require.Error(t, err)
+
+			pErr, ok := IsParseErr(err)
+			require.True(t, ok)
+			require.Len(t, pErr.Resources, 1)
+			require.Len(t, pErr.Resources[0].ValidationFails, 1)
+
+			fail := pErr.Resources[0].ValidationFails[0]
+			assert.Equal(t, "apiVersion", fail.Field)
+			assert.Contains(t, fail.Msg, "1.3.0")
+			assert.Contains(t, fail.Msg, APIVersion)
+		})
+	})
+
+	t.Run("pkg with dependsOn", func(t *testing.T) {
+		t.Run("orders a dependency chain into stages", func(t *testing.T) {
+			testfileRunner(t, "testdata/depends_on", func(t *testing.T, pkg *Pkg) {
+				stages := pkg.applyStages()
+				require.Len(t, stages, 3)
+
+				require.Len(t, stages[0].buckets, 1)
+				assert.Equal(t, "rucket_1", stages[0].buckets[0].Name)
+				assert.Empty(t, stages[0].labels)
+
+				require.Len(t, stages[1].labels, 1)
+				assert.Equal(t, "label_1", stages[1].labels[0].Name)
+				assert.Empty(t, stages[1].buckets)
+
+				require.Len(t, stages[2].buckets, 1)
+				assert.Equal(t, "rucket_2", stages[2].buckets[0].Name)
+			})
+		})
+
+		t.Run("a dependency cycle is a validation error", func(t *testing.T) {
+			_, err := Parse(EncodingYAML, FromFile("testdata/depends_on_cycle.yml"))
+			require.Error(t, err)
+
+			pErr, ok := IsParseErr(err)
+			require.True(t, ok)
+			require.Len(t, pErr.Resources, 1)
+			require.Len(t, pErr.Resources[0].ValidationFails, 1)
+
+			fail := pErr.Resources[0].ValidationFails[0]
+			assert.Equal(t, "dependsOn", fail.Field)
+			assert.Equal(t, "dependency cycle: bucket/rucket_1 -> bucket/rucket_2 -> bucket/rucket_1", fail.Msg)
+		})
+
+		t.Run("handles invalid config", func(t *testing.T) {
+			tests := []testPkgResourceError{
+				{
+					name:           "reference to a missing resource",
+					validationErrs: 1,
+					valFields:      []string{"dependsOn[0]"},
+					pkgStr: `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName:      pkg_name
+  pkgVersion:   1
+  description:  pack description
+spec:
+  resources:
+    - kind: Bucket
+      name: rucket_1
+      dependsOn:
+        - label/label_1
+`,
+				},
+				{
+					name:           "reference without a kind",
+					validationErrs: 1,
+					valFields:      []string{"dependsOn[0]"},
+					pkgStr: `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName:      pkg_name
+  pkgVersion:   1
+  description:  pack description
+spec:
+  resources:
+    - kind: Label
+      name: label_1
+    - kind: Bucket
+      name: rucket_1
+      dependsOn:
+        - label_1
+`,
+				},
+			}
+
+			for _, tt := range tests {
+				testPkgErrors(t, KindBucket, tt)
+			}
+		})
+	})
+
+	t.Run("pkg name rules", func(t *testing.T) {
+		t.Run("valid name is parsed", func(t *testing.T) {
+			pkg, err := Parse(EncodingYAML, FromFile("testdata/pkg_name_rules.yml"), WithPkgNameRules())
+			require.NoError(t, err)
+			assert.Equal(t, "team-buckets-1", pkg.Metadata.Name)
+		})
+
+		t.Run("invalid name is a validation error", func(t *testing.T) {
+			_, err := Parse(EncodingYAML, FromFile("testdata/pkg_name_rules_invalid.yml"), WithPkgNameRules())
+			require.Error(t, err)
+
+			pErr, ok := IsParseErr(err)
+			require.True(t, ok)
+			require.Len(t, pErr.Resources, 1)
+			assert.Equal(t, KindPackage.String(), pErr.Resources[0].Kind)
+			require.Len(t, pErr.Resources[0].ValidationFails, 1)
+			assert.Equal(t, "meta.pkgName", pErr.Resources[0].ValidationFails[0].Field)
+		})
+
+		t.Run("names are not validated without the option", func(t *testing.T) {
+			_, err := Parse(EncodingYAML, FromFile("testdata/pkg_name_rules_invalid.yml"))
+			require.NoError(t, err)
+		})
+
+		t.Run("name rules", func(t *testing.T) {
+			tests := []struct {
+				name  string
+				valid bool
+			}{
+				{name: "a", valid: true},
+				{name: "pkg-1", valid: true},
+				{name: strings.Repeat("a", 63), valid: true},
+				{name: strings.Repeat("a", 64)},
+				{name: "-pkg"},
+				{name: "pkg-"},
+				{name: "pkg_name"},
+				{name: "Pkg"},
+				{name: "pkg.name"},
+			}
+			for _, tt := range tests {
+				assert.Equal(t, tt.valid, pkgNameRegex.MatchString(tt.name), tt.name)
+			}
+		})
+	})
+
 	t.Run("pkg with a bucket", func(t *testing.T) {
 		t.Run("with valid bucket pkg should be valid", func(t *testing.T) {
 			testfileRunner(t, "testdata/bucket", func(t *testing.T, pkg *Pkg) {
@@ -119,11 +310,40 @@ spec:
 					Name:            "rucket_11",
 					Description:     "bucket 1 description",
 					RetentionPeriod: time.Hour,
+					SchemaType:      influxdb.SchemaTypeImplicit,
 				}
 				assert.Equal(t, expectedBucket, *actual)
 			})
 		})
 
+		t.Run("with schema types", func(t *testing.T) {
+			testfileRunner(t, "testdata/bucket_schema_type", func(t *testing.T, pkg *Pkg) {
+				buckets := pkg.buckets()
+				require.Len(t, buckets, 3)
+
+				expected := []influxdb.SchemaType{
+					influxdb.SchemaTypeExplicit,
+					influxdb.SchemaTypeImplicit,
+					influxdb.SchemaTypeImplicit,
+				}
+				for i, st := range expected {
+					assert.Equal(t, st, buckets[i].SchemaType)
+				}
+			})
+		})
+
+		t.Run("with shard group durations", func(t *testing.T) {
+			testfileRunner(t, "testdata/bucket_shard_group_duration", func(t *testing.T, pkg *Pkg) {
+				sum := pkg.Summary()
+				require.Len(t, sum.Buckets, 3)
+
+				expected := []time.Duration{7 * 24 * time.Hour, time.Hour, 0}
+				for i, dur := range expected {
+					assert.Equal(t, dur, sum.Buckets[i].ShardGroupDuration)
+				}
+			})
+		})
+
 		t.Run("handles bad config", func(t *testing.T) {
 			tests := []testPkgResourceError{
 				{
@@ -198,6 +418,91 @@ spec:
     - kind: Bucket
       retention_period: 1h
       name: valid name
+`,
+				},
+				{
+					name:           "annotation that is not a string",
+					validationErrs: 1,
+					valFields:      []string{"annotations"},
+					pkgStr: `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName:      pkg_name
+  pkgVersion:   1
+spec:
+  resources:
+    - kind: Bucket
+      name: rucket_1
+      annotations:
+        owner:
+          team: storage
+`,
+				},
+				{
+					name:           "invalid schema type",
+					validationErrs: 1,
+					valFields:      []string{"schemaType"},
+					pkgStr: `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName:      first_bucket_package
+  pkgVersion:   1
+spec:
+  resources:
+    - kind: Bucket
+      name: rucket_1
+      retention_period: 1h
+      schemaType: strict
+`,
+				},
+				{
+					name:           "invalid shard group duration",
+					validationErrs: 1,
+					valFields:      []string{"shard_group_duration"},
+					pkgStr: `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName:      first_bucket_package
+  pkgVersion:   1
+spec:
+  resources:
+    - kind: Bucket
+      name: rucket_1
+      retention_period: 1h
+      shard_group_duration: forever
+`,
+				},
+				{
+					name:           "shard group duration greater than retention",
+					validationErrs: 1,
+					valFields:      []string{"shard_group_duration"},
+					pkgStr: `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName:      first_bucket_package
+  pkgVersion:   1
+spec:
+  resources:
+    - kind: Bucket
+      name: rucket_1
+      retention_period: 1h
+      shard_group_duration: 2h
+`,
+				},
+				{
+					name:           "invalid id",
+					validationErrs: 1,
+					valFields:      []string{"id"},
+					pkgStr: `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName:      first_bucket_package
+  pkgVersion:   1
+spec:
+  resources:
+    - kind: Bucket
+      name: rucket_1
+      id: not_an_id
 `,
 				},
 			}
@@ -276,6 +581,38 @@ spec:
   resources:
     - kind: Label
     - kind: Label
+`,
+				},
+				{
+					name:           "unknown color name",
+					validationErrs: 1,
+					valFields:      []string{"color"},
+					pkgStr: `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName: label_pkg
+  pkgVersion: 1
+spec:
+  resources:
+    - kind: Label
+      name: label_1
+      color: blurple
+`,
+				},
+				{
+					name:           "invalid hex color",
+					validationErrs: 1,
+					valFields:      []string{"color"},
+					pkgStr: `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName: label_pkg
+  pkgVersion: 1
+spec:
+  resources:
+    - kind: Label
+      name: label_1
+      color: "#abcd"
 `,
 				},
 			}
@@ -1204,69 +1541,149 @@ spec:
 					  }  
 `,
 					},
-				}
-
-				for _, tt := range tests {
-					testPkgErrors(t, KindDashboard, tt)
-				}
-			})
-		})
-
-		t.Run("pkg with single dashboard gauge chart", func(t *testing.T) {
-			testfileRunner(t, "testdata/dashboard_gauge", func(t *testing.T, pkg *Pkg) {
-				sum := pkg.Summary()
-				require.Len(t, sum.Dashboards, 1)
-
-				actual := sum.Dashboards[0]
-				assert.Equal(t, "dash_1", actual.Name)
-				assert.Equal(t, "desc1", actual.Description)
-
-				require.Len(t, actual.Charts, 1)
-				actualChart := actual.Charts[0]
-				assert.Equal(t, 3, actualChart.Height)
-				assert.Equal(t, 6, actualChart.Width)
-				assert.Equal(t, 1, actualChart.XPosition)
-				assert.Equal(t, 2, actualChart.YPosition)
-
-				props, ok := actualChart.Properties.(influxdb.GaugeViewProperties)
-				require.True(t, ok)
-				assert.Equal(t, "gauge", props.GetType())
-				assert.Equal(t, "gauge note", props.Note)
-				assert.True(t, props.ShowNoteWhenEmpty)
-
-				require.Len(t, props.Queries, 1)
-				q := props.Queries[0]
-				queryText := `from(bucket: v.bucket)  |> range(start: v.timeRangeStart, stop: v.timeRangeStop)  |> filter(fn: (r) => r._measurement == "boltdb_writes_total")  |> filter(fn: (r) => r._field == "counter")`
-				assert.Equal(t, queryText, q.Text)
-				assert.Equal(t, "advanced", q.EditMode)
-
-				require.Len(t, props.ViewColors, 3)
-				c := props.ViewColors[0]
-				assert.NotZero(t, c.ID)
-				assert.Equal(t, "laser", c.Name)
-				assert.Equal(t, "min", c.Type)
-				assert.Equal(t, "#8F8AF4", c.Hex)
-				assert.Equal(t, 0.0, c.Value)
-			})
-
-			t.Run("handles invalid config", func(t *testing.T) {
-				tests := []testPkgResourceError{
 					{
-						name:           "color a gauge type",
-						encoding:       EncodingJSON,
+						name:           "duplicate x axis",
 						validationErrs: 1,
-						valFields:      []string{"charts[0].colors"},
-						pkgStr: `
-{
-	"apiVersion": "0.1.0",
-	"kind": "Package",
-	"meta": {
-		"pkgName": "pkg_name",
-		"pkgVersion": "1",
-		"description": "pack description"
-	},
-	"spec": {
-		"resources": [
+						valFields:      []string{"charts[0].axes"},
+						pkgStr: `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName:      pkg_name
+  pkgVersion:   1
+  description:  pack description
+spec:
+  resources:
+    - kind: Dashboard
+      name: dash_1
+      description: desc1
+      charts:
+        - kind:   XY
+          name:   xy chart
+          width:  6
+          height: 3
+          geom: line
+          queries:
+            - query: >
+                from(bucket: v.bucket)  |> range(start: v.timeRangeStart, stop: v.timeRangeStop)  |> filter(fn: (r) => r._measurement == "boltdb_writes_total")  |> filter(fn: (r) => r._field == "counter")
+          colors:
+            - name: laser
+              type: scale
+              hex: "#8F8AF4"
+              value: 3
+          axes:
+            - name: "x"
+              base: 10
+              scale: linear
+            - name: "x"
+              base: 10
+              scale: linear
+            - name: "y"
+              base: 10
+              scale: linear`,
+					},
+					{
+						name:           "extraneous z axis",
+						validationErrs: 1,
+						valFields:      []string{"charts[0].axes"},
+						pkgStr: `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName:      pkg_name
+  pkgVersion:   1
+  description:  pack description
+spec:
+  resources:
+    - kind: Dashboard
+      name: dash_1
+      description: desc1
+      charts:
+        - kind:   XY
+          name:   xy chart
+          width:  6
+          height: 3
+          geom: line
+          queries:
+            - query: >
+                from(bucket: v.bucket)  |> range(start: v.timeRangeStart, stop: v.timeRangeStop)  |> filter(fn: (r) => r._measurement == "boltdb_writes_total")  |> filter(fn: (r) => r._field == "counter")
+          colors:
+            - name: laser
+              type: scale
+              hex: "#8F8AF4"
+              value: 3
+          axes:
+            - name: "x"
+              base: 10
+              scale: linear
+            - name: "y"
+              base: 10
+              scale: linear
+            - name: "z"
+              base: 10
+              scale: linear`,
+					},
+				}
+
+				for _, tt := range tests {
+					testPkgErrors(t, KindDashboard, tt)
+				}
+			})
+		})
+
+		t.Run("pkg with single dashboard gauge chart", func(t *testing.T) {
+			testfileRunner(t, "testdata/dashboard_gauge", func(t *testing.T, pkg *Pkg) {
+				sum := pkg.Summary()
+				require.Len(t, sum.Dashboards, 1)
+
+				actual := sum.Dashboards[0]
+				assert.Equal(t, "dash_1", actual.Name)
+				assert.Equal(t, "desc1", actual.Description)
+
+				require.Len(t, actual.Charts, 1)
+				actualChart := actual.Charts[0]
+				assert.Equal(t, 3, actualChart.Height)
+				assert.Equal(t, 6, actualChart.Width)
+				assert.Equal(t, 1, actualChart.XPosition)
+				assert.Equal(t, 2, actualChart.YPosition)
+
+				props, ok := actualChart.Properties.(influxdb.GaugeViewProperties)
+				require.True(t, ok)
+				assert.Equal(t, "gauge", props.GetType())
+				assert.Equal(t, "gauge note", props.Note)
+				assert.True(t, props.ShowNoteWhenEmpty)
+
+				require.Len(t, props.Queries, 1)
+				q := props.Queries[0]
+				queryText := `from(bucket: v.bucket)  |> range(start: v.timeRangeStart, stop: v.timeRangeStop)  |> filter(fn: (r) => r._measurement == "boltdb_writes_total")  |> filter(fn: (r) => r._field == "counter")`
+				assert.Equal(t, queryText, q.Text)
+				assert.Equal(t, "advanced", q.EditMode)
+
+				require.Len(t, props.ViewColors, 3)
+				c := props.ViewColors[0]
+				assert.NotZero(t, c.ID)
+				assert.Equal(t, "laser", c.Name)
+				assert.Equal(t, "min", c.Type)
+				assert.Equal(t, "#8F8AF4", c.Hex)
+				assert.Equal(t, 0.0, c.Value)
+			})
+
+			t.Run("handles invalid config", func(t *testing.T) {
+				tests := []testPkgResourceError{
+					{
+						name:           "color a gauge type",
+						encoding:       EncodingJSON,
+						validationErrs: 1,
+						valFields:      []string{"charts[0].colors"},
+						pkgStr: `
+{
+	"apiVersion": "0.1.0",
+	"kind": "Package",
+	"meta": {
+		"pkgName": "pkg_name",
+		"pkgVersion": "1",
+		"description": "pack description"
+	},
+	"spec": {
+		"resources": [
 		{
 			"kind": "Dashboard",
 			"name": "dash_1",
@@ -1455,189 +1872,1718 @@ spec:
 				}
 			})
 		})
-	})
 
-	t.Run("pkg with dashboard and labels associated", func(t *testing.T) {
-		testfileRunner(t, "testdata/dashboard_associates_label", func(t *testing.T, pkg *Pkg) {
-			sum := pkg.Summary()
-			require.Len(t, sum.Dashboards, 1)
+		t.Run("pkg with single dashboard heatmap chart", func(t *testing.T) {
+			testfileRunner(t, "testdata/dashboard_heatmap", func(t *testing.T, pkg *Pkg) {
+				sum := pkg.Summary()
+				require.Len(t, sum.Dashboards, 1)
 
-			actual := sum.Dashboards[0]
-			assert.Equal(t, "dash_1", actual.Name)
-			assert.Equal(t, "desc1", actual.Description)
+				actual := sum.Dashboards[0]
+				assert.Equal(t, "dash_1", actual.Name)
+				assert.Equal(t, "desc1", actual.Description)
 
-			require.Len(t, actual.LabelAssociations, 1)
-			actualLabel := actual.LabelAssociations[0]
-			assert.Equal(t, "label_1", actualLabel.Name)
+				require.Len(t, actual.Charts, 1)
+				actualChart := actual.Charts[0]
+				assert.Equal(t, 3, actualChart.Height)
+				assert.Equal(t, 6, actualChart.Width)
+				assert.Equal(t, 1, actualChart.XPosition)
+				assert.Equal(t, 2, actualChart.YPosition)
 
-			expectedMappings := []SummaryLabelMapping{
-				{
-					ResourceName: "dash_1",
-					LabelName:    "label_1",
-				},
-			}
-			require.Len(t, sum.LabelMappings, len(expectedMappings))
+				props, ok := actualChart.Properties.(influxdb.HeatmapViewProperties)
+				require.True(t, ok)
+				assert.Equal(t, "heatmap", props.GetType())
+				assert.Equal(t, "heatmap note", props.Note)
+				assert.Equal(t, int32(10), props.BinSize)
+				assert.True(t, props.ShowNoteWhenEmpty)
 
-			for i, expected := range expectedMappings {
-				expected.LabelMapping.ResourceType = influxdb.DashboardsResourceType
-				assert.Equal(t, expected, sum.LabelMappings[i])
-			}
-		})
+				assert.Equal(t, "_time", props.XColumn)
+				assert.Equal(t, "_value", props.YColumn)
+				assert.Equal(t, []float64{0, 10}, props.XDomain)
+				assert.Equal(t, []float64{0, 100}, props.YDomain)
+				assert.Equal(t, "x_label", props.XAxisLabel)
+				assert.Equal(t, "y_label", props.YAxisLabel)
+				assert.Equal(t, "x_prefix", props.XPrefix)
+				assert.Equal(t, "y_suffix", props.YSuffix)
 
-		t.Run("association doesn't exist then provides an error", func(t *testing.T) {
-			tests := []testPkgResourceError{
-				{
-					name:    "no labels provided",
-					assErrs: 1,
-					assIdxs: []int{0},
-					pkgStr: `apiVersion: 0.1.0
-kind: Package
-meta:
-  pkgName: label_pkg
-  pkgVersion: 1
-spec:
-  resources:
-    - kind: Dashboard
-      name: dash_1
-      associations:
-        - kind: Label
-          name: label_1
-`,
-				},
-				{
-					name:    "mixed found and not found",
-					assErrs: 1,
-					assIdxs: []int{1},
-					pkgStr: `apiVersion: 0.1.0
+				require.Len(t, props.Queries, 1)
+				q := props.Queries[0]
+				queryText := `from(bucket: v.bucket)  |> range(start: v.timeRangeStart, stop: v.timeRangeStop)  |> filter(fn: (r) => r._measurement == "mem")  |> filter(fn: (r) => r._field == "used_percent")`
+				assert.Equal(t, queryText, q.Text)
+				assert.Equal(t, "advanced", q.EditMode)
+
+				assert.Equal(t, []string{"#000004", "#110a30"}, props.ViewColors)
+			})
+
+			t.Run("handles invalid config", func(t *testing.T) {
+				tests := []testPkgResourceError{
+					{
+						name:           "missing columns and bin size",
+						validationErrs: 1,
+						valFields:      []string{"charts[0].xCol", "charts[0].yCol", "charts[0].binSize"},
+						pkgStr: `apiVersion: 0.1.0
 kind: Package
 meta:
-  pkgName: label_pkg
-  pkgVersion: 1
+  pkgName:      pkg_name
+  pkgVersion:   1
+  description:  pack description
 spec:
   resources:
-    - kind: Label
-      name: label_1
     - kind: Dashboard
       name: dash_1
-      associations:
-        - kind: Label
-          name: label_1
-        - kind: Label
-          name: unfound label
+      description: desc1
+      charts:
+        - kind:   heatmap
+          name:   heatmap
+          xPos:  1
+          yPos:  2
+          width:  6
+          height: 3
+          queries:
+            - query: "from(bucket: v.bucket) |> range(start: v.timeRangeStart)"
+          colors:
+            - hex: "#000004"
+              type: scale
+          axes:
+            - name: "x"
+            - name: "y"
 `,
-				},
-				{
-					name:    "multiple not found",
-					assErrs: 1,
-					assIdxs: []int{0, 1},
-					pkgStr: `apiVersion: 0.1.0
+					},
+					{
+						name:           "invalid axis domain",
+						validationErrs: 1,
+						valFields:      []string{"charts[0].axes[0].domain", "charts[0].axes[1].domain"},
+						pkgStr: `apiVersion: 0.1.0
 kind: Package
 meta:
-  pkgName: label_pkg
-  pkgVersion: 1
+  pkgName:      pkg_name
+  pkgVersion:   1
+  description:  pack description
 spec:
   resources:
-    - kind: Label
-      name: label_1
     - kind: Dashboard
       name: dash_1
-      associations:
-        - kind: Label
-          name: not found 1
-        - kind: Label
-          name: unfound label
+      description: desc1
+      charts:
+        - kind:   heatmap
+          name:   heatmap
+          xPos:  1
+          yPos:  2
+          width:  6
+          height: 3
+          binSize: 10
+          xCol: _time
+          yCol: _value
+          queries:
+            - query: "from(bucket: v.bucket) |> range(start: v.timeRangeStart)"
+          colors:
+            - hex: "#000004"
+              type: scale
+          axes:
+            - name: "x"
+              domain:
+                - 0
+            - name: "y"
+              domain:
+                - 100
+                - 0
 `,
-				},
-				{
-					name:    "duplicate valid nested labels",
+					},
+				}
+
+				for _, tt := range tests {
+					testPkgErrors(t, KindDashboard, tt)
+				}
+			})
+		})
+
+		t.Run("pkg with single dashboard histogram chart", func(t *testing.T) {
+			testfileRunner(t, "testdata/dashboard_histogram", func(t *testing.T, pkg *Pkg) {
+				sum := pkg.Summary()
+				require.Len(t, sum.Dashboards, 1)
+
+				actual := sum.Dashboards[0]
+				assert.Equal(t, "dash_1", actual.Name)
+				assert.Equal(t, "desc1", actual.Description)
+
+				require.Len(t, actual.Charts, 1)
+				actualChart := actual.Charts[0]
+				assert.Equal(t, 3, actualChart.Height)
+				assert.Equal(t, 6, actualChart.Width)
+				assert.Equal(t, 1, actualChart.XPosition)
+				assert.Equal(t, 2, actualChart.YPosition)
+
+				props, ok := actualChart.Properties.(influxdb.HistogramViewProperties)
+				require.True(t, ok)
+				assert.Equal(t, "histogram", props.GetType())
+				assert.Equal(t, "histogram note", props.Note)
+				assert.True(t, props.ShowNoteWhenEmpty)
+				assert.Equal(t, 30, props.BinCount)
+				assert.Equal(t, "stacked", props.Position)
+				assert.Equal(t, "_value", props.XColumn)
+				assert.Equal(t, []string{"a", "b"}, props.FillColumns)
+				assert.Equal(t, []float64{0, 10}, props.XDomain)
+				assert.Equal(t, "x_label", props.XAxisLabel)
+
+				require.Len(t, props.Queries, 1)
+				q := props.Queries[0]
+				queryText := `from(bucket: v.bucket)  |> range(start: v.timeRangeStart, stop: v.timeRangeStop)  |> filter(fn: (r) => r._measurement == "boltdb_reads_total")  |> filter(fn: (r) => r._field == "counter")`
+				assert.Equal(t, queryText, q.Text)
+				assert.Equal(t, "advanced", q.EditMode)
+
+				require.Len(t, props.ViewColors, 1)
+				c := props.ViewColors[0]
+				assert.NotZero(t, c.ID)
+				assert.Equal(t, "laser", c.Name)
+				assert.Equal(t, "scale", c.Type)
+				assert.Equal(t, "#8F8AF4", c.Hex)
+			})
+
+			t.Run("handles invalid config", func(t *testing.T) {
+				tests := []testPkgResourceError{
+					{
+						name:           "missing bin count and column",
+						validationErrs: 1,
+						valFields:      []string{"charts[0].xCol", "charts[0].binCount"},
+						pkgStr: `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName:      pkg_name
+  pkgVersion:   1
+  description:  pack description
+spec:
+  resources:
+    - kind: Dashboard
+      name: dash_1
+      description: desc1
+      charts:
+        - kind:   histogram
+          name:   histogram
+          xPos:  1
+          yPos:  2
+          width:  6
+          height: 3
+          queries:
+            - query: "from(bucket: v.bucket) |> range(start: v.timeRangeStart)"
+          colors:
+            - hex: "#8F8AF4"
+              type: scale
+`,
+					},
+					{
+						name:           "invalid position",
+						validationErrs: 1,
+						valFields:      []string{"charts[0].position"},
+						pkgStr: `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName:      pkg_name
+  pkgVersion:   1
+  description:  pack description
+spec:
+  resources:
+    - kind: Dashboard
+      name: dash_1
+      description: desc1
+      charts:
+        - kind:   histogram
+          name:   histogram
+          xPos:  1
+          yPos:  2
+          width:  6
+          height: 3
+          binCount: 30
+          xCol: _value
+          position: sideways
+          queries:
+            - query: "from(bucket: v.bucket) |> range(start: v.timeRangeStart)"
+          colors:
+            - hex: "#8F8AF4"
+              type: scale
+`,
+					},
+				}
+
+				for _, tt := range tests {
+					testPkgErrors(t, KindDashboard, tt)
+				}
+			})
+		})
+
+		t.Run("pkg with single dashboard scatter chart", func(t *testing.T) {
+			testfileRunner(t, "testdata/dashboard_scatter", func(t *testing.T, pkg *Pkg) {
+				sum := pkg.Summary()
+				require.Len(t, sum.Dashboards, 1)
+
+				actual := sum.Dashboards[0]
+				assert.Equal(t, "dash_1", actual.Name)
+				assert.Equal(t, "desc1", actual.Description)
+
+				require.Len(t, actual.Charts, 1)
+				actualChart := actual.Charts[0]
+				assert.Equal(t, 3, actualChart.Height)
+				assert.Equal(t, 6, actualChart.Width)
+				assert.Equal(t, 1, actualChart.XPosition)
+				assert.Equal(t, 2, actualChart.YPosition)
+
+				props, ok := actualChart.Properties.(influxdb.ScatterViewProperties)
+				require.True(t, ok)
+				assert.Equal(t, "scatter", props.GetType())
+				assert.Equal(t, "scatter note", props.Note)
+				assert.True(t, props.ShowNoteWhenEmpty)
+
+				assert.Equal(t, "_time", props.XColumn)
+				assert.Equal(t, "_value", props.YColumn)
+				assert.Equal(t, []string{"a"}, props.FillColumns)
+				assert.Equal(t, []string{"b"}, props.SymbolColumns)
+				assert.Equal(t, []float64{0, 10}, props.XDomain)
+				assert.Equal(t, []float64{0, 100}, props.YDomain)
+				assert.Equal(t, "x_label", props.XAxisLabel)
+				assert.Equal(t, "y_label", props.YAxisLabel)
+				assert.Equal(t, "x_prefix", props.XPrefix)
+				assert.Equal(t, "y_suffix", props.YSuffix)
+
+				require.Len(t, props.Queries, 1)
+				q := props.Queries[0]
+				queryText := `from(bucket: v.bucket)  |> range(start: v.timeRangeStart, stop: v.timeRangeStop)  |> filter(fn: (r) => r._measurement == "mem")  |> filter(fn: (r) => r._field == "used_percent")`
+				assert.Equal(t, queryText, q.Text)
+				assert.Equal(t, "advanced", q.EditMode)
+
+				assert.Equal(t, []string{"#000004", "#110a30"}, props.ViewColors)
+			})
+
+			t.Run("handles invalid config", func(t *testing.T) {
+				tests := []testPkgResourceError{
+					{
+						name:           "missing queries and columns",
+						validationErrs: 1,
+						valFields:      []string{"charts[0].queries", "charts[0].xCol", "charts[0].yCol"},
+						pkgStr: `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName:      pkg_name
+  pkgVersion:   1
+  description:  pack description
+spec:
+  resources:
+    - kind: Dashboard
+      name: dash_1
+      description: desc1
+      charts:
+        - kind:   scatter
+          name:   scatter
+          xPos:  1
+          yPos:  2
+          width:  6
+          height: 3
+          colors:
+            - hex: "#000004"
+              type: scale
+          axes:
+            - name: "x"
+            - name: "y"
+`,
+					},
+					{
+						name:           "geom provided",
+						validationErrs: 1,
+						valFields:      []string{"charts[0].geom"},
+						pkgStr: `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName:      pkg_name
+  pkgVersion:   1
+  description:  pack description
+spec:
+  resources:
+    - kind: Dashboard
+      name: dash_1
+      description: desc1
+      charts:
+        - kind:   scatter
+          name:   scatter
+          xPos:  1
+          yPos:  2
+          width:  6
+          height: 3
+          geom: line
+          xCol: _time
+          yCol: _value
+          queries:
+            - query: "from(bucket: v.bucket) |> range(start: v.timeRangeStart)"
+          colors:
+            - hex: "#000004"
+              type: scale
+          axes:
+            - name: "x"
+            - name: "y"
+`,
+					},
+					{
+						name:           "missing width and height",
+						validationErrs: 1,
+						valFields:      []string{"charts[0].width", "charts[0].height"},
+						pkgStr: `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName:      pkg_name
+  pkgVersion:   1
+  description:  pack description
+spec:
+  resources:
+    - kind: Dashboard
+      name: dash_1
+      description: desc1
+      charts:
+        - kind:   scatter
+          name:   scatter
+          xPos:  1
+          yPos:  2
+          xCol: _time
+          yCol: _value
+          queries:
+            - query: "from(bucket: v.bucket) |> range(start: v.timeRangeStart)"
+          colors:
+            - hex: "#000004"
+              type: scale
+          axes:
+            - name: "x"
+            - name: "y"
+`,
+					},
+				}
+
+				for _, tt := range tests {
+					testPkgErrors(t, KindDashboard, tt)
+				}
+			})
+		})
+
+		t.Run("pkg with single dashboard table chart", func(t *testing.T) {
+			testfileRunner(t, "testdata/dashboard_table", func(t *testing.T, pkg *Pkg) {
+				sum := pkg.Summary()
+				require.Len(t, sum.Dashboards, 1)
+
+				actual := sum.Dashboards[0]
+				assert.Equal(t, "dash_1", actual.Name)
+				assert.Equal(t, "desc1", actual.Description)
+
+				require.Len(t, actual.Charts, 1)
+				actualChart := actual.Charts[0]
+				assert.Equal(t, 3, actualChart.Height)
+				assert.Equal(t, 6, actualChart.Width)
+				assert.Equal(t, 1, actualChart.XPosition)
+				assert.Equal(t, 2, actualChart.YPosition)
+
+				props, ok := actualChart.Properties.(influxdb.TableViewProperties)
+				require.True(t, ok)
+				assert.Equal(t, "table", props.GetType())
+				assert.Equal(t, "table note", props.Note)
+				assert.True(t, props.ShowNoteWhenEmpty)
+				assert.True(t, props.DecimalPlaces.IsEnforced)
+				assert.Equal(t, int32(1), props.DecimalPlaces.Digits)
+				assert.Equal(t, "YYYY-MM-DD HH:mm:ss", props.TimeFormat)
+
+				expectedTableOptions := influxdb.TableOptions{
+					VerticalTimeAxis: true,
+					SortBy: influxdb.RenamableField{
+						InternalName: "_time",
+						DisplayName:  "time",
+						Visible:      true,
+					},
+					Wrapping:       "truncate",
+					FixFirstColumn: true,
+				}
+				assert.Equal(t, expectedTableOptions, props.TableOptions)
+
+				expectedFieldOptions := []influxdb.RenamableField{
+					{
+						InternalName: "_time",
+						DisplayName:  "time",
+						Visible:      true,
+					},
+					{
+						InternalName: "_value",
+						DisplayName:  "value",
+						Visible:      true,
+					},
+				}
+				assert.Equal(t, expectedFieldOptions, props.FieldOptions)
+
+				require.Len(t, props.Queries, 1)
+				q := props.Queries[0]
+				queryText := `from(bucket: v.bucket)  |> range(start: v.timeRangeStart, stop: v.timeRangeStop)  |> filter(fn: (r) => r._measurement == "boltdb_reads_total")  |> filter(fn: (r) => r._field == "counter")`
+				assert.Equal(t, queryText, q.Text)
+				assert.Equal(t, "advanced", q.EditMode)
+
+				require.Len(t, props.ViewColors, 1)
+				c := props.ViewColors[0]
+				assert.NotZero(t, c.ID)
+				assert.Equal(t, "laser", c.Name)
+				assert.Equal(t, "min", c.Type)
+				assert.Equal(t, "#8F8AF4", c.Hex)
+				assert.Equal(t, 3.0, c.Value)
+			})
+
+			t.Run("handles invalid config", func(t *testing.T) {
+				tests := []testPkgResourceError{
+					{
+						name:           "color missing hex value",
+						validationErrs: 1,
+						valFields:      []string{"charts[0].colors[0].hex"},
+						pkgStr: `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName:      pkg_name
+  pkgVersion:   1
+  description:  pack description
+spec:
+  resources:
+    - kind: Dashboard
+      name: dash_1
+      description: desc1
+      charts:
+        - kind:   table
+          name:   table
+          xPos:  1
+          yPos:  2
+          width:  6
+          height: 3
+          queries:
+            - query: "from(bucket: v.bucket) |> range(start: v.timeRangeStart)"
+          colors:
+            - name: laser
+              type: min
+              value: 3.0
+`,
+					},
+					{
+						name:           "field option missing field name",
+						validationErrs: 1,
+						valFields:      []string{"charts[0].fieldOptions[1].fieldName"},
+						pkgStr: `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName:      pkg_name
+  pkgVersion:   1
+  description:  pack description
+spec:
+  resources:
+    - kind: Dashboard
+      name: dash_1
+      description: desc1
+      charts:
+        - kind:   table
+          name:   table
+          xPos:  1
+          yPos:  2
+          width:  6
+          height: 3
+          fieldOptions:
+            - fieldName: _time
+              displayName: time
+            - displayName: value
+          queries:
+            - query: "from(bucket: v.bucket) |> range(start: v.timeRangeStart)"
+          colors:
+            - hex: "#8F8AF4"
+              type: min
+`,
+					},
+					{
+						name:           "invalid wrapping",
+						validationErrs: 1,
+						valFields:      []string{"charts[0].tableOptions.wrapping"},
+						pkgStr: `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName:      pkg_name
+  pkgVersion:   1
+  description:  pack description
+spec:
+  resources:
+    - kind: Dashboard
+      name: dash_1
+      description: desc1
+      charts:
+        - kind:   table
+          name:   table
+          xPos:  1
+          yPos:  2
+          width:  6
+          height: 3
+          tableOptions:
+            wrapping: sideways
+          queries:
+            - query: "from(bucket: v.bucket) |> range(start: v.timeRangeStart)"
+          colors:
+            - hex: "#8F8AF4"
+              type: min
+`,
+					},
+				}
+
+				for _, tt := range tests {
+					testPkgErrors(t, KindDashboard, tt)
+				}
+			})
+		})
+
+		t.Run("pkg with single dashboard markdown chart", func(t *testing.T) {
+			testfileRunner(t, "testdata/dashboard_markdown", func(t *testing.T, pkg *Pkg) {
+				sum := pkg.Summary()
+				require.Len(t, sum.Dashboards, 1)
+
+				actual := sum.Dashboards[0]
+				assert.Equal(t, "dash_1", actual.Name)
+				assert.Equal(t, "desc1", actual.Description)
+
+				require.Len(t, actual.Charts, 1)
+				actualChart := actual.Charts[0]
+				assert.Equal(t, 3, actualChart.Height)
+				assert.Equal(t, 6, actualChart.Width)
+				assert.Equal(t, 1, actualChart.XPosition)
+				assert.Equal(t, 2, actualChart.YPosition)
+
+				props, ok := actualChart.Properties.(influxdb.MarkdownViewProperties)
+				require.True(t, ok)
+				assert.Equal(t, "markdown", props.GetType())
+				assert.Equal(t, "## markdown note\n", props.Note)
+			})
+
+			t.Run("handles invalid config", func(t *testing.T) {
+				tests := []testPkgResourceError{
+					{
+						name:           "missing note",
+						validationErrs: 1,
+						valFields:      []string{"charts[0].note"},
+						pkgStr: `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName:      pkg_name
+  pkgVersion:   1
+  description:  pack description
+spec:
+  resources:
+    - kind: Dashboard
+      name: dash_1
+      description: desc1
+      charts:
+        - kind:   markdown
+          name:   markdown
+          xPos:  1
+          yPos:  2
+          width:  6
+          height: 3
+`,
+					},
+				}
+
+				for _, tt := range tests {
+					testPkgErrors(t, KindDashboard, tt)
+				}
+			})
+		})
+
+		t.Run("pkg with dashboard auto layout", func(t *testing.T) {
+			testfileRunner(t, "testdata/dashboard_auto_layout", func(t *testing.T, pkg *Pkg) {
+				dashs := pkg.dashboards()
+				require.Len(t, dashs, 1)
+
+				charts := dashs[0].Charts
+				require.Len(t, charts, 5)
+
+				expected := []struct {
+					name       string
+					xPos, yPos int
+				}{
+					{name: "chart_a", xPos: 0, yPos: 0},
+					{name: "chart_b", xPos: 6, yPos: 0},
+					{name: "chart_c", xPos: 0, yPos: 3},
+					{name: "chart_d", xPos: 4, yPos: 3},
+					{name: "chart_e", xPos: 0, yPos: 7},
+				}
+				for i, exp := range expected {
+					assert.Equal(t, exp.name, charts[i].Name)
+					assert.Equal(t, exp.xPos, charts[i].XPos, exp.name)
+					assert.Equal(t, exp.yPos, charts[i].YPos, exp.name)
+					assert.True(t, charts[i].XPos+charts[i].Width <= 12, exp.name)
+				}
+
+				for i := range charts {
+					assert.False(t, overlapsAny(charts[i], append(charts[:i:i], charts[i+1:]...)), charts[i].Name)
+				}
+			})
+
+			t.Run("handles invalid config", func(t *testing.T) {
+				tests := []testPkgResourceError{
+					{
+						name:           "invalid layout",
+						validationErrs: 1,
+						valFields:      []string{"layout"},
+						pkgStr: `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName:      pkg_name
+  pkgVersion:   1
+  description:  pack description
+spec:
+  resources:
+    - kind: Dashboard
+      name: dash_1
+      layout: grid
+      charts:
+        - kind:   markdown
+          name:   markdown
+          width:  6
+          height: 3
+          note: note
+`,
+					},
+					{
+						name:           "chart wider than the layout columns",
+						validationErrs: 1,
+						valFields:      []string{"charts[1].width"},
+						pkgStr: `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName:      pkg_name
+  pkgVersion:   1
+  description:  pack description
+spec:
+  resources:
+    - kind: Dashboard
+      name: dash_1
+      layout: auto
+      layoutColumns: 4
+      charts:
+        - kind:   markdown
+          name:   markdown
+          width:  4
+          height: 3
+          note: note
+        - kind:   markdown
+          name:   markdown_wide
+          width:  6
+          height: 3
+          note: note
+`,
+					},
+				}
+
+				for _, tt := range tests {
+					testPkgErrors(t, KindDashboard, tt)
+				}
+			})
+		})
+	})
+
+	t.Run("pkg with dashboard and labels associated", func(t *testing.T) {
+		testfileRunner(t, "testdata/dashboard_associates_label", func(t *testing.T, pkg *Pkg) {
+			sum := pkg.Summary()
+			require.Len(t, sum.Dashboards, 1)
+
+			actual := sum.Dashboards[0]
+			assert.Equal(t, "dash_1", actual.Name)
+			assert.Equal(t, "desc1", actual.Description)
+
+			require.Len(t, actual.LabelAssociations, 1)
+			actualLabel := actual.LabelAssociations[0]
+			assert.Equal(t, "label_1", actualLabel.Name)
+
+			expectedMappings := []SummaryLabelMapping{
+				{
+					ResourceName: "dash_1",
+					LabelName:    "label_1",
+				},
+			}
+			require.Len(t, sum.LabelMappings, len(expectedMappings))
+
+			for i, expected := range expectedMappings {
+				expected.LabelMapping.ResourceType = influxdb.DashboardsResourceType
+				assert.Equal(t, expected, sum.LabelMappings[i])
+			}
+		})
+
+		t.Run("association doesn't exist then provides an error", func(t *testing.T) {
+			tests := []testPkgResourceError{
+				{
+					name:    "no labels provided",
+					assErrs: 1,
+					assIdxs: []int{0},
+					pkgStr: `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName: label_pkg
+  pkgVersion: 1
+spec:
+  resources:
+    - kind: Dashboard
+      name: dash_1
+      associations:
+        - kind: Label
+          name: label_1
+`,
+				},
+				{
+					name:    "mixed found and not found",
+					assErrs: 1,
+					assIdxs: []int{1},
+					pkgStr: `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName: label_pkg
+  pkgVersion: 1
+spec:
+  resources:
+    - kind: Label
+      name: label_1
+    - kind: Dashboard
+      name: dash_1
+      associations:
+        - kind: Label
+          name: label_1
+        - kind: Label
+          name: unfound label
+`,
+				},
+				{
+					name:    "multiple not found",
+					assErrs: 1,
+					assIdxs: []int{0, 1},
+					pkgStr: `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName: label_pkg
+  pkgVersion: 1
+spec:
+  resources:
+    - kind: Label
+      name: label_1
+    - kind: Dashboard
+      name: dash_1
+      associations:
+        - kind: Label
+          name: not found 1
+        - kind: Label
+          name: unfound label
+`,
+				},
+				{
+					name:    "duplicate valid nested labels",
 					assErrs: 1,
 					assIdxs: []int{1},
 					pkgStr: `apiVersion: 0.1.0
 kind: Package
 meta:
-  pkgName: label_pkg
-  pkgVersion: 1
+  pkgName: label_pkg
+  pkgVersion: 1
+spec:
+  resources:
+    - kind: Label
+      name: label_1
+    - kind: Dashboard
+      name: dash_1
+      associations:
+        - kind: Label
+          name: label_1
+        - kind: Label
+          name: label_1
+`,
+				},
+			}
+
+			for _, tt := range tests {
+				testPkgErrors(t, KindDashboard, tt)
+			}
+		})
+	})
+
+	t.Run("pkg with a variable", func(t *testing.T) {
+		t.Run("with valid fields should produce summary", func(t *testing.T) {
+			testfileRunner(t, "testdata/variables", func(t *testing.T, pkg *Pkg) {
+				sum := pkg.Summary()
+
+				require.Len(t, sum.Variables, 4)
+
+				varEquals := func(t *testing.T, name, vType string, vals interface{}, v SummaryVariable) {
+					t.Helper()
+
+					assert.Equal(t, name, v.Name)
+					assert.Equal(t, name+" desc", v.Description)
+					require.NotNil(t, v.Arguments)
+					assert.Equal(t, vType, v.Arguments.Type)
+					assert.Equal(t, vals, v.Arguments.Values)
+				}
+
+				// validates we support all known variable types
+				varEquals(t,
+					"var_const",
+					"constant",
+					influxdb.VariableConstantValues([]string{"first val"}),
+					sum.Variables[0],
+				)
+
+				varEquals(t,
+					"var_map",
+					"map",
+					influxdb.VariableMapValues{"k1": "v1"},
+					sum.Variables[1],
+				)
+
+				varEquals(t,
+					"var_query_1",
+					"query",
+					influxdb.VariableQueryValues{
+						Query:    `buckets()  |> filter(fn: (r) => r.name !~ /^_/)  |> rename(columns: {name: "_value"})  |> keep(columns: ["_value"])`,
+						Language: "flux",
+					},
+					sum.Variables[2],
+				)
+
+				varEquals(t,
+					"var_query_2",
+					"query",
+					influxdb.VariableQueryValues{
+						Query:    "an influxql query of sorts",
+						Language: "influxql",
+					},
+					sum.Variables[3],
+				)
+			})
+		})
+
+		t.Run("handles bad config", func(t *testing.T) {
+			tests := []testPkgResourceError{
+				{
+					name:           "name missing",
+					validationErrs: 1,
+					valFields:      []string{"name"},
+					pkgStr: `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName:      pkg_name
+  pkgVersion:   1
+  description:  pack description
+spec:
+  resources:
+    - kind: Variable
+      type: map
+      values:
+        k1: v1
+`,
+				},
+				{
+					name:           "map var missing values",
+					validationErrs: 1,
+					valFields:      []string{"values"},
+					pkgStr: `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName:      pkg_name
+  pkgVersion:   1
+  description:  pack description
+spec:
+  resources:
+    - kind: Variable
+      name: var
+      type: map
+`,
+				},
+				{
+					name:           "const var missing values",
+					validationErrs: 1,
+					valFields:      []string{"values"},
+					pkgStr: `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName:      pkg_name
+  pkgVersion:   1
+  description:  pack description
+spec:
+  resources:
+    - kind: Variable
+      name: var
+      type: constant
+`,
+				},
+				{
+					name:           "query var missing query",
+					validationErrs: 1,
+					valFields:      []string{"query"},
+					pkgStr: `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName:      pkg_name
+  pkgVersion:   1
+  description:  pack description
+spec:
+  resources:
+    - kind: Variable
+      name: var
+      type: query
+      language: influxql
+`,
+				},
+				{
+					name:           "query var missing query language",
+					validationErrs: 1,
+					valFields:      []string{"language"},
+					pkgStr: `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName:      pkg_name
+  pkgVersion:   1
+  description:  pack description
+spec:
+  resources:
+    - kind: Variable
+      name: var
+      type: query
+      query: from(v.bucket) |> count()
+`,
+				},
+				{
+					name:           "query var provides incorrect query language",
+					validationErrs: 1,
+					valFields:      []string{"language"},
+					pkgStr: `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName:      pkg_name
+  pkgVersion:   1
+  description:  pack description
+spec:
+  resources:
+    - kind: Variable
+      name: var
+      type: query
+      query: from(v.bucket) |> count()
+      language: wrongo language
+`,
+				},
+				{
+					name:           "duplicate var names",
+					validationErrs: 1,
+					valFields:      []string{"name"},
+					pkgStr: `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName:      pkg_name
+  pkgVersion:   1
+  description:  pack description
+spec:
+  resources:
+    - kind: Variable
+      name: var
+      type: query
+      query: from(v.bucket) |> count()
+      language: flux
+    - kind: Variable
+      name: var
+      type: query
+      query: from(v.bucket) |> mean()
+      language: flux
+`,
+				},
+			}
+
+			for _, tt := range tests {
+				testPkgErrors(t, KindVariable, tt)
+			}
+		})
+
+		t.Run("with duplicate map keys", func(t *testing.T) {
+			tests := []struct {
+				name      string
+				extension string
+				encoding  Encoding
+			}{
+				{name: "yaml", extension: ".yml", encoding: EncodingYAML},
+				{name: "json", extension: ".json", encoding: EncodingJSON},
+			}
+
+			for _, tt := range tests {
+				b, err := ioutil.ReadFile("testdata/variables_duplicate_map_keys" + tt.extension)
+				require.NoError(t, err)
+
+				testPkgErrors(t, KindVariable, testPkgResourceError{
+					name:           tt.name,
+					encoding:       tt.encoding,
+					validationErrs: 1,
+					valFields:      []string{"values"},
+					pkgStr:         string(b),
+				})
+			}
+		})
+
+		t.Run("with a values file", func(t *testing.T) {
+			t.Run("expands the file into constant values", func(t *testing.T) {
+				for _, ext := range []string{".yml", ".json"} {
+					t.Run(ext, func(t *testing.T) {
+						pkg, err := Parse(EncodingSource, FromFile("testdata/variables_values_file"+ext), WithFileDir("testdata"))
+						require.NoError(t, err)
+
+						vars := pkg.Summary().Variables
+						require.Len(t, vars, 1)
+
+						actual := vars[0]
+						assert.Equal(t, "var_const", actual.Name)
+						require.NotNil(t, actual.Arguments)
+						assert.Equal(t, "constant", actual.Arguments.Type)
+						expected := influxdb.VariableConstantValues{"first val", "second val", "third val", "fourth val"}
+						assert.Equal(t, expected, actual.Arguments.Values)
+					})
+				}
+			})
+
+			t.Run("handles bad config", func(t *testing.T) {
+				newPkgStr := func(fields string) string {
+					return `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName:      pkg_name
+  pkgVersion:   1
+  description:  pack description
+spec:
+  resources:
+    - kind: Variable
+      name: var
+` + fields
+				}
+
+				dir, err := ioutil.TempDir("", "pkger")
+				require.NoError(t, err)
+				defer os.RemoveAll(dir)
+				require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "empty.csv"), []byte("\n , \n"), 0600))
+				require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "values.csv"), []byte("a,b"), 0600))
+
+				tests := []struct {
+					name     string
+					readFile bool
+					pkgStr   string
+				}{
+					{
+						name:     "missing file",
+						readFile: true,
+						pkgStr: newPkgStr(`      type: constant
+      valuesFile: "@missing.csv"
+`),
+					},
+					{
+						name:     "empty file",
+						readFile: true,
+						pkgStr: newPkgStr(`      type: constant
+      valuesFile: "@empty.csv"
+`),
+					},
+					{
+						name:     "non constant variable",
+						readFile: true,
+						pkgStr: newPkgStr(`      type: map
+      valuesFile: "@values.csv"
+`),
+					},
+					{
+						name:     "values and a values file",
+						readFile: true,
+						pkgStr: newPkgStr(`      type: constant
+      values: [a]
+      valuesFile: "@values.csv"
+`),
+					},
+					{
+						name: "file reads are not enabled",
+						pkgStr: newPkgStr(`      type: constant
+      valuesFile: "@values.csv"
+`),
+					},
+				}
+
+				for _, tt := range tests {
+					fn := func(t *testing.T) {
+						var opts []ParseSetFn
+						if tt.readFile {
+							opts = append(opts, WithFileDir(dir))
+						}
+
+						_, err := Parse(EncodingYAML, FromString(tt.pkgStr), opts...)
+						require.Error(t, err)
+
+						pErr, ok := IsParseErr(err)
+						require.True(t, ok, err)
+						require.Len(t, pErr.Resources, 1)
+
+						resErr := pErr.Resources[0]
+						assert.Equal(t, KindVariable.String(), resErr.Kind)
+						require.Len(t, resErr.ValidationFails, 1)
+						assert.Equal(t, "valuesFile", resErr.ValidationFails[0].Field)
+					}
+					t.Run(tt.name, fn)
+				}
+			})
+		})
+	})
+
+	t.Run("pkg with variable and labels associated", func(t *testing.T) {
+		testfileRunner(t, "testdata/variables_associates_label.yml", func(t *testing.T, pkg *Pkg) {
+			sum := pkg.Summary()
+			require.Len(t, sum.Labels, 1)
+
+			vars := sum.Variables
+			require.Len(t, vars, 1)
+
+			expectedLabelMappings := []struct {
+				varName string
+				labels  []string
+			}{
+				{
+					varName: "var_1",
+					labels:  []string{"label_1"},
+				},
+			}
+			for i, expected := range expectedLabelMappings {
+				v := vars[i]
+				require.Len(t, v.LabelAssociations, len(expected.labels))
+
+				for j, label := range expected.labels {
+					assert.Equal(t, label, v.LabelAssociations[j].Name)
+				}
+			}
+
+			expectedMappings := []SummaryLabelMapping{
+				{
+					ResourceName: "var_1",
+					LabelName:    "label_1",
+				},
+			}
+
+			require.Len(t, sum.LabelMappings, len(expectedMappings))
+			for i, expected := range expectedMappings {
+				expected.LabelMapping.ResourceType = influxdb.VariablesResourceType
+				assert.Equal(t, expected, sum.LabelMappings[i])
+			}
+		})
+	})
+
+	t.Run("pkg with telegraf and label associated", func(t *testing.T) {
+		testfileRunner(t, "testdata/telegraf", func(t *testing.T, pkg *Pkg) {
+			sum := pkg.Summary()
+			require.Len(t, sum.TelegrafConfigs, 1)
+
+			actual := sum.TelegrafConfigs[0]
+			assert.Equal(t, "first_tele_config", actual.TelegrafConfig.Name)
+			assert.Equal(t, "desc", actual.TelegrafConfig.Description)
+			assert.Equal(t, int64(10000), actual.TelegrafConfig.Agent.Interval)
+			require.Len(t, actual.TelegrafConfig.Plugins, 2)
+
+			require.Len(t, actual.LabelAssociations, 1)
+			assert.Equal(t, "label_1", actual.LabelAssociations[0].Name)
+
+			expectedMappings := []SummaryLabelMapping{
+				{
+					ResourceName: "first_tele_config",
+					LabelName:    "label_1",
+				},
+			}
+			require.Len(t, sum.LabelMappings, len(expectedMappings))
+			for i, expected := range expectedMappings {
+				expected.LabelMapping.ResourceType = influxdb.TelegrafsResourceType
+				assert.Equal(t, expected, sum.LabelMappings[i])
+			}
+		})
+
+		t.Run("handles bad config", func(t *testing.T) {
+			tests := []testPkgResourceError{
+				{
+					name:           "config missing",
+					validationErrs: 1,
+					valFields:      []string{"config"},
+					pkgStr: `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName:      pkg_name
+  pkgVersion:   1
+  description:  pack description
+spec:
+  resources:
+    - kind: Telegraf
+      name: first_tele_config
+`,
+				},
+				{
+					name:           "config without agent",
+					validationErrs: 1,
+					valFields:      []string{"config"},
+					pkgStr: `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName:      pkg_name
+  pkgVersion:   1
+  description:  pack description
 spec:
   resources:
-    - kind: Label
-      name: label_1
-    - kind: Dashboard
-      name: dash_1
-      associations:
-        - kind: Label
-          name: label_1
-        - kind: Label
-          name: label_1
+    - kind: Telegraf
+      name: first_tele_config
+      config: |
+        [[inputs.cpu]]
+          percpu = true
+`,
+				},
+				{
+					name:           "missing name",
+					validationErrs: 1,
+					valFields:      []string{"name"},
+					pkgStr: `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName:      pkg_name
+  pkgVersion:   1
+  description:  pack description
+spec:
+  resources:
+    - kind: Telegraf
+      config: |
+        [agent]
+          interval = "10s"
 `,
 				},
 			}
 
 			for _, tt := range tests {
-				testPkgErrors(t, KindDashboard, tt)
+				testPkgErrors(t, KindTelegraf, tt)
 			}
 		})
 	})
 
-	t.Run("pkg with a variable", func(t *testing.T) {
-		t.Run("with valid fields should produce summary", func(t *testing.T) {
-			testfileRunner(t, "testdata/variables", func(t *testing.T, pkg *Pkg) {
-				sum := pkg.Summary()
+	t.Run("pkg with check and label associated", func(t *testing.T) {
+		testfileRunner(t, "testdata/checks", func(t *testing.T, pkg *Pkg) {
+			sum := pkg.Summary()
+			require.Len(t, sum.Checks, 1)
 
-				require.Len(t, sum.Variables, 4)
+			actual, ok := sum.Checks[0].Check.(*icheck.Threshold)
+			require.True(t, ok)
+			assert.Equal(t, "check_1", actual.Name)
+			assert.Equal(t, "desc_1", actual.Description)
+			assert.Equal(t, "Check: ${ r._check_name } is: ${ r._level }", actual.StatusMessageTemplate)
+			assert.True(t, strings.HasPrefix(actual.Query.Text, `from(bucket: "rucket_1")`))
+			require.NotNil(t, actual.Every)
+			assert.Equal(t, time.Minute, actual.Every.TimeDuration())
+			require.NotNil(t, actual.Offset)
+			assert.Equal(t, 15*time.Second, actual.Offset.TimeDuration())
 
-				varEquals := func(t *testing.T, name, vType string, vals interface{}, v SummaryVariable) {
-					t.Helper()
+			expectedThresholds := []icheck.ThresholdConfig{
+				&icheck.Greater{
+					ThresholdConfigBase: icheck.ThresholdConfigBase{AllValues: true, Level: notification.Critical},
+					Value:               50,
+				},
+				&icheck.Lesser{
+					ThresholdConfigBase: icheck.ThresholdConfigBase{Level: notification.Warn},
+					Value:               49.9,
+				},
+				&icheck.Range{
+					ThresholdConfigBase: icheck.ThresholdConfigBase{Level: notification.Info},
+					Min:                 30,
+					Max:                 45,
+					Within:              true,
+				},
+				&icheck.Range{
+					ThresholdConfigBase: icheck.ThresholdConfigBase{Level: notification.Ok},
+					Min:                 30,
+					Max:                 35,
+				},
+			}
+			assert.Equal(t, expectedThresholds, actual.Thresholds)
 
-					assert.Equal(t, name, v.Name)
-					assert.Equal(t, name+" desc", v.Description)
-					require.NotNil(t, v.Arguments)
-					assert.Equal(t, vType, v.Arguments.Type)
-					assert.Equal(t, vals, v.Arguments.Values)
+			require.Len(t, sum.Checks[0].LabelAssociations, 1)
+			assert.Equal(t, "label_1", sum.Checks[0].LabelAssociations[0].Name)
+
+			expectedMappings := []SummaryLabelMapping{
+				{
+					ResourceName: "check_1",
+					LabelName:    "label_1",
+				},
+			}
+			require.Len(t, sum.LabelMappings, len(expectedMappings))
+			for i, expected := range expectedMappings {
+				expected.LabelMapping.ResourceType = influxdb.ChecksResourceType
+				assert.Equal(t, expected, sum.LabelMappings[i])
+			}
+		})
+
+		t.Run("handles bad check", func(t *testing.T) {
+			tests := []testPkgResourceError{
+				{
+					name:           "missing query",
+					validationErrs: 1,
+					valFields:      []string{"query"},
+					pkgStr: `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName:      pkg_name
+  pkgVersion:   1
+  description:  pack description
+spec:
+  resources:
+    - kind: Check
+      name: check_1
+      every: 1m
+      thresholds:
+        - type: greater
+          level: CRIT
+          value: 50.0
+`,
+				},
+				{
+					name:           "missing thresholds",
+					validationErrs: 1,
+					valFields:      []string{"thresholds"},
+					pkgStr: `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName:      pkg_name
+  pkgVersion:   1
+  description:  pack description
+spec:
+  resources:
+    - kind: Check
+      name: check_1
+      every: 1m
+      query: 'from(bucket: "rucket_1") |> range(start: -1m)'
+`,
+				},
+				{
+					name:           "invalid threshold level",
+					validationErrs: 1,
+					valFields:      []string{"thresholds[0].level"},
+					pkgStr: `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName:      pkg_name
+  pkgVersion:   1
+  description:  pack description
+spec:
+  resources:
+    - kind: Check
+      name: check_1
+      every: 1m
+      query: 'from(bucket: "rucket_1") |> range(start: -1m)'
+      thresholds:
+        - type: greater
+          level: SEVERE
+          value: 50.0
+`,
+				},
+				{
+					name:           "invalid threshold type",
+					validationErrs: 1,
+					valFields:      []string{"thresholds[0].type"},
+					pkgStr: `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName:      pkg_name
+  pkgVersion:   1
+  description:  pack description
+spec:
+  resources:
+    - kind: Check
+      name: check_1
+      every: 1m
+      query: 'from(bucket: "rucket_1") |> range(start: -1m)'
+      thresholds:
+        - type: greatest
+          level: CRIT
+          value: 50.0
+`,
+				},
+				{
+					name:           "missing schedule",
+					validationErrs: 1,
+					valFields:      []string{"every"},
+					pkgStr: `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName:      pkg_name
+  pkgVersion:   1
+  description:  pack description
+spec:
+  resources:
+    - kind: Check
+      name: check_1
+      query: 'from(bucket: "rucket_1") |> range(start: -1m)'
+      thresholds:
+        - type: greater
+          level: CRIT
+          value: 50.0
+`,
+				},
+			}
+
+			for _, tt := range tests {
+				testPkgErrors(t, KindCheck, tt)
+			}
+		})
+	})
+
+	t.Run("pkg with notification endpoints and labels associated", func(t *testing.T) {
+		testfileRunner(t, "testdata/notification_endpoint", func(t *testing.T, pkg *Pkg) {
+			sum := pkg.Summary()
+			endpoints := sum.NotificationEndpoints
+			require.Len(t, endpoints, 5)
+
+			expectedEndpoints := []influxdb.NotificationEndpoint{
+				&endpoint.HTTP{
+					Base: endpoint.Base{
+						Name:        "http_basic_auth_notification_endpoint",
+						Description: "http basic auth desc",
+						Status:      influxdb.Inactive,
+					},
+					URL:        "https://www.example.com/endpoint/basicauth",
+					AuthMethod: "basic",
+					Method:     "POST",
+					Username:   influxdb.SecretField{Value: strPtr("secret username")},
+					Password:   influxdb.SecretField{Value: strPtr("secret password")},
+				},
+				&endpoint.HTTP{
+					Base: endpoint.Base{
+						Name:        "http_bearer_auth_notification_endpoint",
+						Description: "http bearer auth desc",
+						Status:      influxdb.Active,
+					},
+					URL:        "https://www.example.com/endpoint/bearerauth",
+					AuthMethod: "bearer",
+					Method:     "PUT",
+					Token:      influxdb.SecretField{Value: strPtr("secret token")},
+				},
+				&endpoint.HTTP{
+					Base: endpoint.Base{
+						Name:        "http_none_auth_notification_endpoint",
+						Description: "http none auth desc",
+						Status:      influxdb.Active,
+					},
+					URL:        "https://www.example.com/endpoint/noneauth",
+					AuthMethod: "none",
+					Method:     "POST",
+				},
+				&endpoint.PagerDuty{
+					Base: endpoint.Base{
+						Name:        "pager_duty_notification_endpoint",
+						Description: "pager duty desc",
+						Status:      influxdb.Active,
+					},
+					ClientURL:  "http://localhost:8080/orgs/7167eb6719fa34e5/alert-history",
+					RoutingKey: influxdb.SecretField{Value: strPtr("secret routing-key")},
+				},
+				&endpoint.Slack{
+					Base: endpoint.Base{
+						Name:        "slack_notification_endpoint",
+						Description: "slack desc",
+						Status:      influxdb.Active,
+					},
+					URL:   "https://hooks.slack.com/services/bip/piddy/boppidy",
+					Token: influxdb.SecretField{Value: strPtr("tokenval")},
+				},
+			}
+
+			for i, expected := range expectedEndpoints {
+				actual := endpoints[i]
+				assert.Equal(t, expected, actual.NotificationEndpoint)
+				require.Len(t, actual.LabelAssociations, 1)
+				assert.Equal(t, "label_1", actual.LabelAssociations[0].Name)
+			}
+
+			require.Len(t, sum.LabelMappings, len(expectedEndpoints))
+			for i, expected := range expectedEndpoints {
+				expectedMapping := SummaryLabelMapping{
+					ResourceName: expected.GetName(),
+					LabelName:    "label_1",
 				}
+				expectedMapping.LabelMapping.ResourceType = influxdb.NotificationEndpointResourceType
+				assert.Equal(t, expectedMapping, sum.LabelMappings[i])
+			}
+		})
 
-				// validates we support all known variable types
-				varEquals(t,
-					"var_const",
-					"constant",
-					influxdb.VariableConstantValues([]string{"first val"}),
-					sum.Variables[0],
-				)
+		t.Run("handles bad config", func(t *testing.T) {
+			tests := []testPkgResourceError{
+				{
+					name:           "missing type",
+					validationErrs: 1,
+					valFields:      []string{"type"},
+					pkgStr: `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName:      pkg_name
+  pkgVersion:   1
+  description:  pack description
+spec:
+  resources:
+    - kind: NotificationEndpoint
+      name: endpoint_0
+      url: https://www.example.com/endpoint
+`,
+				},
+				{
+					name:           "http missing url",
+					validationErrs: 1,
+					valFields:      []string{"url"},
+					pkgStr: `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName:      pkg_name
+  pkgVersion:   1
+  description:  pack description
+spec:
+  resources:
+    - kind: NotificationEndpoint
+      name: endpoint_0
+      type: http
+`,
+				},
+				{
+					name:           "http invalid method",
+					validationErrs: 1,
+					valFields:      []string{"method"},
+					pkgStr: `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName:      pkg_name
+  pkgVersion:   1
+  description:  pack description
+spec:
+  resources:
+    - kind: NotificationEndpoint
+      name: endpoint_0
+      type: http
+      url: https://www.example.com/endpoint
+      method: DELETE
+`,
+				},
+				{
+					name:           "http basic auth missing password",
+					validationErrs: 1,
+					valFields:      []string{"authMethod"},
+					pkgStr: `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName:      pkg_name
+  pkgVersion:   1
+  description:  pack description
+spec:
+  resources:
+    - kind: NotificationEndpoint
+      name: endpoint_0
+      type: http
+      url: https://www.example.com/endpoint
+      authMethod: basic
+      username: user
+`,
+				},
+				{
+					name:           "pagerduty missing routing key",
+					validationErrs: 1,
+					valFields:      []string{"routingKey"},
+					pkgStr: `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName:      pkg_name
+  pkgVersion:   1
+  description:  pack description
+spec:
+  resources:
+    - kind: NotificationEndpoint
+      name: endpoint_0
+      type: pagerduty
+      clientURL: http://localhost:8080/orgs/7167eb6719fa34e5/alert-history
+`,
+				},
+				{
+					name:           "slack missing url and token",
+					validationErrs: 1,
+					valFields:      []string{"url"},
+					pkgStr: `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName:      pkg_name
+  pkgVersion:   1
+  description:  pack description
+spec:
+  resources:
+    - kind: NotificationEndpoint
+      name: endpoint_0
+      type: slack
+`,
+				},
+				{
+					name:           "invalid status",
+					validationErrs: 1,
+					valFields:      []string{"status"},
+					pkgStr: `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName:      pkg_name
+  pkgVersion:   1
+  description:  pack description
+spec:
+  resources:
+    - kind: NotificationEndpoint
+      name: endpoint_0
+      type: slack
+      url: https://hooks.slack.com/services/bip/piddy/boppidy
+      status: paused
+`,
+				},
+			}
+
+			for _, tt := range tests {
+				testPkgErrors(t, KindNotificationEndpoint, tt)
+			}
+		})
+	})
+
+	t.Run("pkg with notification rule and label associated", func(t *testing.T) {
+		testfileRunner(t, "testdata/notification_rule", func(t *testing.T, pkg *Pkg) {
+			sum := pkg.Summary()
+			require.Len(t, sum.NotificationRules, 1)
 
-				varEquals(t,
-					"var_map",
-					"map",
-					influxdb.VariableMapValues{"k1": "v1"},
-					sum.Variables[1],
-				)
+			actual := sum.NotificationRules[0]
+			assert.Equal(t, "endpoint_0", actual.EndpointName)
+			require.Len(t, actual.LabelAssociations, 1)
+			assert.Equal(t, "label_1", actual.LabelAssociations[0].Name)
 
-				varEquals(t,
-					"var_query_1",
-					"query",
-					influxdb.VariableQueryValues{
-						Query:    `buckets()  |> filter(fn: (r) => r.name !~ /^_/)  |> rename(columns: {name: "_value"})  |> keep(columns: ["_value"])`,
-						Language: "flux",
-					},
-					sum.Variables[2],
-				)
+			slackRule, ok := actual.NotificationRule.(*rule.Slack)
+			require.True(t, ok)
+			assert.Equal(t, "rule_0", slackRule.Name)
+			assert.Equal(t, "desc_0", slackRule.Description)
+			assert.Equal(t, "#alerts", slackRule.Channel)
+			assert.Equal(t, "Notification Rule: ${ r._notification_rule_name } triggered by check: ${ r._check_name }: ${ r._message }", slackRule.MessageTemplate)
+			require.NotNil(t, slackRule.Every)
+			assert.Equal(t, 10*time.Minute, slackRule.Every.TimeDuration())
+			require.NotNil(t, slackRule.Offset)
+			assert.Equal(t, 30*time.Second, slackRule.Offset.TimeDuration())
 
-				varEquals(t,
-					"var_query_2",
-					"query",
-					influxdb.VariableQueryValues{
-						Query:    "an influxql query of sorts",
-						Language: "influxql",
-					},
-					sum.Variables[3],
-				)
-			})
+			prevLevel := notification.Ok
+			expectedStatusRules := []notification.StatusRule{
+				{CurrentLevel: notification.Critical, PreviousLevel: &prevLevel},
+				{CurrentLevel: notification.Warn},
+			}
+			assert.Equal(t, expectedStatusRules, slackRule.StatusRules)
+
+			var ruleMappings []SummaryLabelMapping
+			for _, m := range sum.LabelMappings {
+				if m.ResourceType == influxdb.NotificationRuleResourceType {
+					ruleMappings = append(ruleMappings, m)
+				}
+			}
+			require.Len(t, ruleMappings, 1)
+			assert.Equal(t, "rule_0", ruleMappings[0].ResourceName)
+			assert.Equal(t, "label_1", ruleMappings[0].LabelName)
 		})
 
 		t.Run("handles bad config", func(t *testing.T) {
 			tests := []testPkgResourceError{
 				{
-					name:           "name missing",
+					name:           "missing endpoint name",
 					validationErrs: 1,
-					valFields:      []string{"name"},
+					valFields:      []string{"endpointName"},
 					pkgStr: `apiVersion: 0.1.0
 kind: Package
 meta:
@@ -1646,16 +3592,19 @@ meta:
   description:  pack description
 spec:
   resources:
-    - kind: Variable
-      type: map
-      values:
-        k1: v1
+    - kind: NotificationEndpoint
+      name: endpoint_0
+      type: slack
+      url: https://hooks.slack.com/services/bip/piddy/boppidy
+    - kind: NotificationRule
+      name: rule_0
+      every: 10m
 `,
 				},
 				{
-					name:           "map var missing values",
+					name:           "endpoint not in pkg",
 					validationErrs: 1,
-					valFields:      []string{"values"},
+					valFields:      []string{"endpointName"},
 					pkgStr: `apiVersion: 0.1.0
 kind: Package
 meta:
@@ -1664,15 +3613,20 @@ meta:
   description:  pack description
 spec:
   resources:
-    - kind: Variable
-      name: var
-      type: map
+    - kind: NotificationEndpoint
+      name: endpoint_0
+      type: slack
+      url: https://hooks.slack.com/services/bip/piddy/boppidy
+    - kind: NotificationRule
+      name: rule_0
+      endpointName: endpoint_1
+      every: 10m
 `,
 				},
 				{
-					name:           "const var missing values",
+					name:           "missing every",
 					validationErrs: 1,
-					valFields:      []string{"values"},
+					valFields:      []string{"every"},
 					pkgStr: `apiVersion: 0.1.0
 kind: Package
 meta:
@@ -1681,15 +3635,19 @@ meta:
   description:  pack description
 spec:
   resources:
-    - kind: Variable
-      name: var
-      type: constant
+    - kind: NotificationEndpoint
+      name: endpoint_0
+      type: slack
+      url: https://hooks.slack.com/services/bip/piddy/boppidy
+    - kind: NotificationRule
+      name: rule_0
+      endpointName: endpoint_0
 `,
 				},
 				{
-					name:           "query var missing query",
+					name:           "check not in pkg",
 					validationErrs: 1,
-					valFields:      []string{"query"},
+					valFields:      []string{"checkName"},
 					pkgStr: `apiVersion: 0.1.0
 kind: Package
 meta:
@@ -1698,16 +3656,21 @@ meta:
   description:  pack description
 spec:
   resources:
-    - kind: Variable
-      name: var
-      type: query
-      language: influxql
+    - kind: NotificationEndpoint
+      name: endpoint_0
+      type: slack
+      url: https://hooks.slack.com/services/bip/piddy/boppidy
+    - kind: NotificationRule
+      name: rule_0
+      checkName: check_1
+      endpointName: endpoint_0
+      every: 10m
 `,
 				},
 				{
-					name:           "query var missing query language",
+					name:           "invalid every",
 					validationErrs: 1,
-					valFields:      []string{"language"},
+					valFields:      []string{"every"},
 					pkgStr: `apiVersion: 0.1.0
 kind: Package
 meta:
@@ -1716,16 +3679,20 @@ meta:
   description:  pack description
 spec:
   resources:
-    - kind: Variable
-      name: var
-      type: query
-      query: from(v.bucket) |> count()
+    - kind: NotificationEndpoint
+      name: endpoint_0
+      type: slack
+      url: https://hooks.slack.com/services/bip/piddy/boppidy
+    - kind: NotificationRule
+      name: rule_0
+      endpointName: endpoint_0
+      every: 10x
 `,
 				},
 				{
-					name:           "query var provides incorrect query language",
+					name:           "offset not less than every",
 					validationErrs: 1,
-					valFields:      []string{"language"},
+					valFields:      []string{"offset"},
 					pkgStr: `apiVersion: 0.1.0
 kind: Package
 meta:
@@ -1734,17 +3701,21 @@ meta:
   description:  pack description
 spec:
   resources:
-    - kind: Variable
-      name: var
-      type: query
-      query: from(v.bucket) |> count()
-      language: wrongo language
+    - kind: NotificationEndpoint
+      name: endpoint_0
+      type: slack
+      url: https://hooks.slack.com/services/bip/piddy/boppidy
+    - kind: NotificationRule
+      name: rule_0
+      endpointName: endpoint_0
+      every: 10m
+      offset: 10m
 `,
 				},
 				{
-					name:           "duplicate var names",
+					name:           "invalid status rule level",
 					validationErrs: 1,
-					valFields:      []string{"name"},
+					valFields:      []string{"statusRules[0].currentLevel"},
 					pkgStr: `apiVersion: 0.1.0
 kind: Package
 meta:
@@ -1753,68 +3724,430 @@ meta:
   description:  pack description
 spec:
   resources:
-    - kind: Variable
-      name: var
-      type: query
-      query: from(v.bucket) |> count()
-      language: flux
-    - kind: Variable
-      name: var
-      type: query
-      query: from(v.bucket) |> mean()
-      language: flux
+    - kind: NotificationEndpoint
+      name: endpoint_0
+      type: slack
+      url: https://hooks.slack.com/services/bip/piddy/boppidy
+    - kind: NotificationRule
+      name: rule_0
+      endpointName: endpoint_0
+      every: 10m
+      statusRules:
+        - currentLevel: SEVERE
 `,
 				},
 			}
 
 			for _, tt := range tests {
-				testPkgErrors(t, KindVariable, tt)
+				testPkgErrors(t, KindNotificationRule, tt)
 			}
 		})
 	})
 
-	t.Run("pkg with variable and labels associated", func(t *testing.T) {
-		testfileRunner(t, "testdata/variables_associates_label.yml", func(t *testing.T, pkg *Pkg) {
+	t.Run("pkg with notification rule referencing a check", func(t *testing.T) {
+		testfileRunner(t, "testdata/notification_rule_check", func(t *testing.T, pkg *Pkg) {
 			sum := pkg.Summary()
-			require.Len(t, sum.Labels, 1)
+			require.Len(t, sum.NotificationRules, 1)
 
-			vars := sum.Variables
-			require.Len(t, vars, 1)
+			actual := sum.NotificationRules[0]
+			assert.Equal(t, "check_0", actual.CheckName)
+			assert.Equal(t, "endpoint_0", actual.EndpointName)
 
-			expectedLabelMappings := []struct {
-				varName string
-				labels  []string
-			}{
-				{
-					varName: "var_1",
-					labels:  []string{"label_1"},
-				},
+			slackRule, ok := actual.NotificationRule.(*rule.Slack)
+			require.True(t, ok)
+			expectedTagRules := []notification.TagRule{
+				{Tag: influxdb.Tag{Key: "_check_name", Value: "check_0"}, Operator: influxdb.Equal},
 			}
-			for i, expected := range expectedLabelMappings {
-				v := vars[i]
-				require.Len(t, v.LabelAssociations, len(expected.labels))
+			assert.Equal(t, expectedTagRules, slackRule.TagRules)
+			assert.Equal(t, []notification.StatusRule{{CurrentLevel: notification.Critical}}, slackRule.StatusRules)
+		})
+	})
 
-				for j, label := range expected.labels {
-					assert.Equal(t, label, v.LabelAssociations[j].Name)
-				}
-			}
+	t.Run("pkg with notification rule referencing a missing endpoint", func(t *testing.T) {
+		_, err := Parse(EncodingYAML, FromFile("testdata/notification_rule_missing_endpoint.yml"))
+		require.Error(t, err)
 
-			expectedMappings := []SummaryLabelMapping{
-				{
-					ResourceName: "var_1",
-					LabelName:    "label_1",
-				},
-			}
+		pErr, ok := IsParseErr(err)
+		require.True(t, ok)
+		require.Len(t, pErr.Resources, 1)
+		assert.Equal(t, KindNotificationRule.String(), pErr.Resources[0].Kind)
+		require.Len(t, pErr.Resources[0].ValidationFails, 1)
+		assert.Equal(t, "endpointName", pErr.Resources[0].ValidationFails[0].Field)
+	})
 
-			require.Len(t, sum.LabelMappings, len(expectedMappings))
-			for i, expected := range expectedMappings {
-				expected.LabelMapping.ResourceType = influxdb.VariablesResourceType
-				assert.Equal(t, expected, sum.LabelMappings[i])
-			}
+	t.Run("pkg with env references", func(t *testing.T) {
+		for _, tt := range []struct {
+			name     string
+			path     string
+			encoding Encoding
+		}{
+			{name: "yaml", path: "testdata/env_refs.yml", encoding: EncodingYAML},
+			{name: "json", path: "testdata/env_refs.json", encoding: EncodingJSON},
+		} {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Run("resolves references from the env", func(t *testing.T) {
+					env := map[string]string{"ENV_NAME": "prod", "RETENTION": "2h"}
+					pkg, err := Parse(tt.encoding, FromFile(tt.path), WithEnv(env))
+					require.NoError(t, err)
+
+					labels := pkg.labels()
+					require.Len(t, labels, 1)
+					assert.Equal(t, "prod_label", labels[0].Name)
+
+					buckets := pkg.buckets()
+					require.Len(t, buckets, 1)
+					assert.Equal(t, "prod_bucket", buckets[0].Name)
+					assert.Equal(t, "costs $5 a month", buckets[0].Description)
+					assert.Equal(t, 2*time.Hour, buckets[0].RetentionPeriod)
+					require.Len(t, buckets[0].labels, 1)
+					assert.Equal(t, "prod_label", buckets[0].labels[0].Name)
+
+					vars := pkg.variables()
+					require.Len(t, vars, 1)
+					expectedQuery := `from(bucket: "prod_bucket") |> map(fn: (r) => ({r with _value: "${r._value} ${r._field}"}))` + "\n"
+					assert.Equal(t, expectedQuery, vars[0].Query)
+				})
+
+				t.Run("unresolved references without a default are validation errors", func(t *testing.T) {
+					_, err := Parse(tt.encoding, FromFile(tt.path), WithEnv(map[string]string{}))
+					require.Error(t, err)
+
+					pErr, ok := IsParseErr(err)
+					require.True(t, ok)
+					require.Len(t, pErr.Resources, 3)
+
+					bktErr := pErr.Resources[1]
+					assert.Equal(t, KindBucket.String(), bktErr.Kind)
+					assert.Equal(t, 1, bktErr.Idx)
+					require.Len(t, bktErr.ValidationFails, 2)
+					assert.Equal(t, "associations[0].name", bktErr.ValidationFails[0].Field)
+					assert.Equal(t, "name", bktErr.ValidationFails[1].Field)
+					assert.Contains(t, bktErr.ValidationFails[1].Msg, "ENV_NAME")
+
+					varErr := pErr.Resources[2]
+					require.Len(t, varErr.ValidationFails, 1)
+					assert.Equal(t, "query", varErr.ValidationFails[0].Field)
+				})
+			})
+		}
+
+		t.Run("references are left as is without an env", func(t *testing.T) {
+			_, err := Parse(EncodingYAML, FromFile("testdata/env_refs.yml"))
+			require.NoError(t, err)
 		})
 	})
 }
 
+func TestFromHTTP(t *testing.T) {
+	ymlBytes, err := ioutil.ReadFile("testdata/bucket.yml")
+	require.NoError(t, err)
+	jsonBytes, err := ioutil.ReadFile("testdata/bucket.json")
+	require.NoError(t, err)
+
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/pkgs/bucket.yml":
+			w.Header().Set("Content-Type", "text/plain")
+			w.Write(ymlBytes)
+		case "/pkgs/bucket":
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			w.Write(jsonBytes)
+		case "/pkgs/invalid.yml":
+			w.Write([]byte("apiVersion: 0.1.0\nkind: Package\n"))
+		case "/pkgs/slow.yml":
+			<-r.Context().Done()
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer svr.Close()
+
+	t.Run("infers the encoding from the url extension", func(t *testing.T) {
+		pkg, err := Parse(EncodingSource, FromHTTP(context.Background(), svr.URL+"/pkgs/bucket.yml"))
+		require.NoError(t, err)
+
+		require.Len(t, pkg.buckets(), 1)
+		assert.Equal(t, "rucket_11", pkg.buckets()[0].Name)
+	})
+
+	t.Run("infers the encoding from the content type", func(t *testing.T) {
+		pkg, err := Parse(EncodingSource, FromHTTP(context.Background(), svr.URL+"/pkgs/bucket"))
+		require.NoError(t, err)
+
+		require.Len(t, pkg.buckets(), 1)
+		assert.Equal(t, "rucket_11", pkg.buckets()[0].Name)
+	})
+
+	t.Run("explicit encoding is honored", func(t *testing.T) {
+		pkg, err := Parse(EncodingJSON, FromHTTP(context.Background(), svr.URL+"/pkgs/bucket"))
+		require.NoError(t, err)
+
+		require.Len(t, pkg.buckets(), 1)
+	})
+
+	t.Run("network errors are fetch errors", func(t *testing.T) {
+		_, err := Parse(EncodingSource, FromHTTP(context.Background(), svr.URL+"/pkgs/missing.yml"))
+		require.Error(t, err)
+
+		_, ok := IsFetchErr(err)
+		assert.True(t, ok)
+		_, ok = IsParseErr(err)
+		assert.False(t, ok)
+	})
+
+	t.Run("honors the context deadline", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		_, err := Parse(EncodingSource, FromHTTP(ctx, svr.URL+"/pkgs/slow.yml"))
+		require.Error(t, err)
+
+		_, ok := IsFetchErr(err)
+		assert.True(t, ok)
+	})
+
+	t.Run("invalid pkg is a parse error", func(t *testing.T) {
+		_, err := Parse(EncodingSource, FromHTTP(context.Background(), svr.URL+"/pkgs/invalid.yml"))
+		require.Error(t, err)
+
+		_, ok := IsParseErr(err)
+		assert.True(t, ok)
+		_, ok = IsFetchErr(err)
+		assert.False(t, ok)
+	})
+}
+
+func TestParseGzip(t *testing.T) {
+	gzipFile := func(t *testing.T, path string) []byte {
+		t.Helper()
+
+		b, err := ioutil.ReadFile(path)
+		require.NoError(t, err)
+
+		var buf bytes.Buffer
+		gw := gzip.NewWriter(&buf)
+		_, err = gw.Write(b)
+		require.NoError(t, err)
+		require.NoError(t, gw.Close())
+		return buf.Bytes()
+	}
+
+	t.Run("yaml", func(t *testing.T) {
+		pkg, err := Parse(EncodingYAML, FromReader(bytes.NewReader(gzipFile(t, "testdata/bucket.yml"))))
+		require.NoError(t, err)
+
+		require.Len(t, pkg.buckets(), 1)
+		assert.Equal(t, "rucket_11", pkg.buckets()[0].Name)
+	})
+
+	t.Run("json", func(t *testing.T) {
+		pkg, err := Parse(EncodingJSON, FromReader(bytes.NewReader(gzipFile(t, "testdata/bucket.json"))))
+		require.NoError(t, err)
+
+		require.Len(t, pkg.buckets(), 1)
+		assert.Equal(t, "rucket_11", pkg.buckets()[0].Name)
+	})
+
+	t.Run("infers the encoding from the inner extension of the url", func(t *testing.T) {
+		gzBytes := gzipFile(t, "testdata/bucket.yml")
+		svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/gzip")
+			w.Write(gzBytes)
+		}))
+		defer svr.Close()
+
+		pkg, err := Parse(EncodingSource, FromHTTP(context.Background(), svr.URL+"/pkgs/bucket.yml.gz"))
+		require.NoError(t, err)
+
+		require.Len(t, pkg.buckets(), 1)
+		assert.Equal(t, "rucket_11", pkg.buckets()[0].Name)
+	})
+}
+
+func TestCombine(t *testing.T) {
+	newPkg := func(t *testing.T, resources string) *Pkg {
+		t.Helper()
+
+		pkgStr := `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName:      pkg_name
+  pkgVersion:   1
+spec:
+  resources:
+` + resources
+		pkg, err := Parse(EncodingYAML, FromString(pkgStr), WithoutValidation())
+		require.NoError(t, err)
+		return pkg
+	}
+
+	t.Run("resolves associations across pkgs", func(t *testing.T) {
+		pkg, err := Combine(
+			newPkg(t, `
+    - kind: Bucket
+      name: rucket_1
+      associations:
+        - kind: Label
+          name: label_1
+`),
+			newPkg(t, `
+    - kind: Label
+      name: label_1
+`),
+		)
+		require.NoError(t, err)
+
+		require.Len(t, pkg.buckets(), 1)
+		require.Len(t, pkg.buckets()[0].labels, 1)
+		assert.Equal(t, "label_1", pkg.buckets()[0].labels[0].Name)
+		require.Len(t, pkg.labels(), 1)
+	})
+
+	t.Run("names must be unique across pkgs", func(t *testing.T) {
+		bkt := `
+    - kind: Bucket
+      name: rucket_1
+`
+		_, err := Combine(newPkg(t, bkt), newPkg(t, bkt))
+		require.Error(t, err)
+
+		_, ok := IsParseErr(err)
+		assert.True(t, ok)
+	})
+}
+
+func TestPkg_Normalize(t *testing.T) {
+	pkgStr := `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName:      pkg_name
+  pkgVersion:   1
+spec:
+  resources:
+    - kind: Bucket
+      name: rucket_2
+      associations:
+        - kind: Label
+          name: label_2
+        - kind: Label
+          name: label_1
+    - kind: label
+      name:   label_2
+    - kind: Bucket
+      name: rucket_1
+      retention_period: 1h
+    - kind: Label
+      name: label_1
+`
+
+	reorderedStr := `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName: pkg_name
+  pkgVersion: 1
+spec:
+  resources:
+    - name: label_1
+      kind: label
+    - name: label_2
+      kind: label
+    - retention_period: 1h
+      name: rucket_1
+      kind: bucket
+    - name: rucket_2
+      kind: bucket
+      associations:
+        - name: label_1
+          kind: label
+        - name: label_2
+          kind: label
+`
+
+	normalized := func(t *testing.T, encoding Encoding, readerFn ReaderFn) []byte {
+		t.Helper()
+
+		pkg, err := Parse(encoding, readerFn)
+		require.NoError(t, err)
+
+		pkg.Normalize()
+		b, err := pkg.Encode(encoding)
+		require.NoError(t, err)
+		return b
+	}
+
+	t.Run("semantically identical pkgs normalize identically", func(t *testing.T) {
+		expected := normalized(t, EncodingYAML, FromString(reorderedStr))
+		assert.Equal(t, string(expected), string(normalized(t, EncodingYAML, FromString(pkgStr))))
+	})
+
+	t.Run("orders resources by kind and name", func(t *testing.T) {
+		pkg, err := Parse(EncodingYAML, FromString(pkgStr))
+		require.NoError(t, err)
+
+		pkg.Normalize()
+
+		var names []string
+		for _, r := range pkg.Spec.Resources {
+			names = append(names, r.stringShort(fieldKind)+"/"+r.Name())
+		}
+		expected := []string{"label/label_1", "label/label_2", "bucket/rucket_1", "bucket/rucket_2"}
+		assert.Equal(t, expected, names)
+	})
+
+	t.Run("annotations survive formatting", func(t *testing.T) {
+		annotatedStr := `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName:      pkg_name
+  pkgVersion:   1
+spec:
+  resources:
+    - kind: Bucket
+      name: rucket_1
+      annotations:
+        owner: team-storage
+        notes: holds the raw metrics
+`
+
+		for _, encoding := range []Encoding{EncodingYAML, EncodingJSON} {
+			t.Run(encoding.String(), func(t *testing.T) {
+				pkg, err := Parse(EncodingYAML, FromString(annotatedStr))
+				require.NoError(t, err)
+
+				pkg.Normalize()
+				b, err := pkg.Encode(encoding)
+				require.NoError(t, err)
+
+				formatted, err := Parse(encoding, FromString(string(b)))
+				require.NoError(t, err)
+
+				require.Len(t, formatted.Spec.Resources, 1)
+				expected := map[string]string{
+					"owner": "team-storage",
+					"notes": "holds the raw metrics",
+				}
+				assert.Equal(t, expected, formatted.Spec.Resources[0].mapStrStr(fieldAnnotations))
+			})
+		}
+	})
+
+	t.Run("formatting is idempotent", func(t *testing.T) {
+		for _, encoding := range []Encoding{EncodingYAML, EncodingJSON} {
+			t.Run(encoding.String(), func(t *testing.T) {
+				pkg, err := Parse(EncodingYAML, FromString(pkgStr))
+				require.NoError(t, err)
+
+				pkg.Normalize()
+				first, err := pkg.Encode(encoding)
+				require.NoError(t, err)
+
+				second := normalized(t, encoding, FromString(string(first)))
+				assert.Equal(t, string(first), string(second))
+			})
+		}
+	})
+}
+
 type testPkgResourceError struct {
 	name           string
 	encoding       Encoding
@@ -1945,3 +4278,7 @@ func testfileRunner(t *testing.T, path string, testFn func(t *testing.T, pkg *Pk
 		t.Run(tt.name, fn)
 	}
 }
+
+func strPtr(s string) *string {
+	return &s
+}
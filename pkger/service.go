@@ -10,17 +10,23 @@ import (
 	"time"
 
 	"github.com/influxdata/influxdb"
+	pctx "github.com/influxdata/influxdb/context"
 	"go.uber.org/zap"
 )
 
 // APIVersion marks the current APIVersion for influx packages.
 const APIVersion = "0.1.0"
 
+// MinAPIVersion marks the oldest APIVersion a pkg may declare and still be
+// parsed. Any pkg declaring an APIVersion between the MinAPIVersion and
+// APIVersion, inclusive, is supported.
+const MinAPIVersion = "0.1.0"
+
 // SVC is the packages service interface.
 type SVC interface {
 	CreatePkg(ctx context.Context, setters ...CreatePkgSetFn) (*Pkg, error)
-	DryRun(ctx context.Context, orgID influxdb.ID, pkg *Pkg) (Summary, Diff, error)
-	Apply(ctx context.Context, orgID influxdb.ID, pkg *Pkg) (Summary, error)
+	DryRun(ctx context.Context, orgID influxdb.ID, pkg *Pkg, opts ...ApplyOptFn) (Summary, Diff, error)
+	Apply(ctx context.Context, orgID influxdb.ID, pkg *Pkg, opts ...ApplyOptFn) (Summary, error)
 }
 
 type serviceOpt struct {
@@ -28,8 +34,14 @@ type serviceOpt struct {
 
 	labelSVC  influxdb.LabelService
 	bucketSVC influxdb.BucketService
+	checkSVC  influxdb.CheckService
 	dashSVC   influxdb.DashboardService
+	taskSVC   influxdb.TaskService
+	teleSVC   influxdb.TelegrafConfigStore
 	varSVC    influxdb.VariableService
+
+	endpointSVC influxdb.NotificationEndpointService
+	ruleSVC     influxdb.NotificationRuleStore
 }
 
 // ServiceSetterFn is a means of setting dependencies on the Service type.
@@ -49,6 +61,13 @@ func WithBucketSVC(bktSVC influxdb.BucketService) ServiceSetterFn {
 	}
 }
 
+// WithCheckSVC sets the check service.
+func WithCheckSVC(checkSVC influxdb.CheckService) ServiceSetterFn {
+	return func(opt *serviceOpt) {
+		opt.checkSVC = checkSVC
+	}
+}
+
 // WithDashboardSVC sets the dashboard service.
 func WithDashboardSVC(dashSVC influxdb.DashboardService) ServiceSetterFn {
 	return func(opt *serviceOpt) {
@@ -63,6 +82,35 @@ func WithLabelSVC(labelSVC influxdb.LabelService) ServiceSetterFn {
 	}
 }
 
+// WithNotificationEndpointSVC sets the notification endpoint service.
+func WithNotificationEndpointSVC(endpointSVC influxdb.NotificationEndpointService) ServiceSetterFn {
+	return func(opt *serviceOpt) {
+		opt.endpointSVC = endpointSVC
+	}
+}
+
+// WithNotificationRuleSVC sets the notification rule service.
+func WithNotificationRuleSVC(ruleSVC influxdb.NotificationRuleStore) ServiceSetterFn {
+	return func(opt *serviceOpt) {
+		opt.ruleSVC = ruleSVC
+	}
+}
+
+// WithTelegrafSVC sets the telegraf config service.
+func WithTelegrafSVC(teleSVC influxdb.TelegrafConfigStore) ServiceSetterFn {
+	return func(opt *serviceOpt) {
+		opt.teleSVC = teleSVC
+	}
+}
+
+// WithTaskSVC sets the task service. The status of an existing check lives on
+// its task, it is read from the task service so a rollback can restore it.
+func WithTaskSVC(taskSVC influxdb.TaskService) ServiceSetterFn {
+	return func(opt *serviceOpt) {
+		opt.taskSVC = taskSVC
+	}
+}
+
 // WithVariableSVC sets the variable service.
 func WithVariableSVC(varSVC influxdb.VariableService) ServiceSetterFn {
 	return func(opt *serviceOpt) {
@@ -70,6 +118,14 @@ func WithVariableSVC(varSVC influxdb.VariableService) ServiceSetterFn {
 	}
 }
 
+var (
+	errCheckSVCNotConfigured    = errors.New("check service not configured")
+	errEndpointSVCNotConfigured = errors.New("notification endpoint service not configured")
+	errRuleSVCNotConfigured     = errors.New("notification rule service not configured")
+	errTaskSVCNotConfigured     = errors.New("task service not configured")
+	errTeleSVCNotConfigured     = errors.New("telegraf service not configured")
+)
+
 // Service provides the pkger business logic including all the dependencies to make
 // this resource sausage.
 type Service struct {
@@ -77,8 +133,14 @@ type Service struct {
 
 	labelSVC  influxdb.LabelService
 	bucketSVC influxdb.BucketService
+	checkSVC  influxdb.CheckService
 	dashSVC   influxdb.DashboardService
+	taskSVC   influxdb.TaskService
+	teleSVC   influxdb.TelegrafConfigStore
 	varSVC    influxdb.VariableService
+
+	endpointSVC influxdb.NotificationEndpointService
+	ruleSVC     influxdb.NotificationRuleStore
 }
 
 // NewService is a constructor for a pkger Service.
@@ -93,9 +155,15 @@ func NewService(opts ...ServiceSetterFn) *Service {
 	return &Service{
 		logger:    opt.logger,
 		bucketSVC: opt.bucketSVC,
+		checkSVC:  opt.checkSVC,
 		labelSVC:  opt.labelSVC,
 		dashSVC:   opt.dashSVC,
+		taskSVC:   opt.taskSVC,
+		teleSVC:   opt.teleSVC,
 		varSVC:    opt.varSVC,
+
+		endpointSVC: opt.endpointSVC,
+		ruleSVC:     opt.ruleSVC,
 	}
 }
 
@@ -156,14 +224,55 @@ func (s *Service) CreatePkg(ctx context.Context, setters ...CreatePkgSetFn) (*Pk
 		pkg.Metadata.Version = "v1"
 	}
 
+	// cloned tracks the name given to every resource in the pkg so dependencies
+	// are not duplicated and associations reference the name in the pkg.
+	cloned := make(map[cloneKey]string)
+	var deps []cloneDependency
 	for _, r := range opt.resources {
-		newResource, err := s.resourceCloneToResource(ctx, r)
+		newResource, resDeps, err := s.resourceCloneToResource(ctx, r)
+		if err != nil {
+			return nil, err
+		}
+		labelDeps, err := s.labelDependencies(ctx, r)
+		if err != nil {
+			return nil, err
+		}
+		resDeps = append(labelDeps, resDeps...)
+		cloned[newCloneKey(r)] = newResource.Name()
+		pkg.Spec.Resources = append(pkg.Spec.Resources, newResource)
+		for _, dep := range resDeps {
+			deps = append(deps, cloneDependency{
+				resIdx: len(pkg.Spec.Resources) - 1,
+				dep:    dep,
+			})
+		}
+	}
+
+	for _, d := range deps {
+		k := newCloneKey(d.dep)
+		if _, ok := cloned[k]; ok {
+			continue
+		}
+		newResource, _, err := s.resourceCloneToResource(ctx, d.dep)
 		if err != nil {
 			return nil, err
 		}
+		newResource[fieldDependency] = true
+		cloned[k] = newResource.Name()
 		pkg.Spec.Resources = append(pkg.Spec.Resources, newResource)
 	}
 
+	for _, d := range deps {
+		if !d.dep.Kind.is(KindLabel) {
+			continue
+		}
+		r := pkg.Spec.Resources[d.resIdx]
+		r[fieldAssociations] = append(r.slcResource(fieldAssociations), Resource{
+			fieldKind: KindLabel.String(),
+			fieldName: cloned[newCloneKey(d.dep)],
+		})
+	}
+
 	if err := pkg.Validate(); err != nil {
 		return nil, err
 	}
@@ -171,52 +280,270 @@ func (s *Service) CreatePkg(ctx context.Context, setters ...CreatePkgSetFn) (*Pk
 	return pkg, nil
 }
 
-func (s *Service) resourceCloneToResource(ctx context.Context, r ResourceToClone) (Resource, error) {
+// resourceCloneToResource converts the existing resource to a pkg resource. Any
+// resources the cloned resource depends on are returned alongside it, a dashboard
+// depends on the variables referenced by its chart queries.
+func (s *Service) resourceCloneToResource(ctx context.Context, r ResourceToClone) (Resource, []ResourceToClone, error) {
 	switch {
 	case r.Kind.is(KindBucket):
 		bkt, err := s.bucketSVC.FindBucketByID(ctx, r.ID)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
-		return bucketToResource(*bkt, r.Name), nil
+		return bucketToResource(*bkt, r.Name), nil, nil
 	case r.Kind.is(KindDashboard):
 		dash, err := s.dashSVC.FindDashboardByID(ctx, r.ID)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		var cellViews []cellView
 		for _, cell := range dash.Cells {
 			v, err := s.dashSVC.GetDashboardCellView(ctx, r.ID, cell.ID)
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 			cellViews = append(cellViews, cellView{
 				c: *cell,
 				v: *v,
 			})
 		}
-		return dashboardToResource(*dash, cellViews, r.Name), nil
+		deps, err := s.dashboardDependencies(ctx, *dash, cellViews)
+		if err != nil {
+			return nil, nil, err
+		}
+		return dashboardToResource(*dash, cellViews, r.Name), deps, nil
 	case r.Kind.is(KindLabel):
 		l, err := s.labelSVC.FindLabelByID(ctx, r.ID)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
-		return labelToResource(*l, r.Name), nil
+		return labelToResource(*l, r.Name), nil, nil
 	case r.Kind.is(KindVariable):
 		v, err := s.varSVC.FindVariableByID(ctx, r.ID)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
-		return variableToResource(*v, r.Name), nil
+		return variableToResource(*v, r.Name), nil, nil
+	default:
+		return nil, nil, errors.New("unsupported kind provided: " + string(r.Kind))
+	}
+}
+
+// labelDependencies returns the labels associated with the cloned resource.
+// Labels are not associated with other labels.
+func (s *Service) labelDependencies(ctx context.Context, r ResourceToClone) ([]ResourceToClone, error) {
+	var resType influxdb.ResourceType
+	switch {
+	case r.Kind.is(KindBucket):
+		resType = influxdb.BucketsResourceType
+	case r.Kind.is(KindDashboard):
+		resType = influxdb.DashboardsResourceType
+	case r.Kind.is(KindVariable):
+		resType = influxdb.VariablesResourceType
 	default:
-		return nil, errors.New("unsupported kind provided: " + string(r.Kind))
+		return nil, nil
+	}
+
+	labels, err := s.labelSVC.FindResourceLabels(ctx, influxdb.LabelMappingFilter{
+		ResourceID:   r.ID,
+		ResourceType: resType,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var deps []ResourceToClone
+	for _, l := range labels {
+		deps = append(deps, ResourceToClone{
+			Kind: KindLabel,
+			ID:   l.ID,
+		})
+	}
+	return deps, nil
+}
+
+func (s *Service) dashboardDependencies(ctx context.Context, dash influxdb.Dashboard, cellViews []cellView) ([]ResourceToClone, error) {
+	varNames := make(map[string]bool)
+	for _, cv := range cellViews {
+		for _, name := range convertCellView(cv).Queries.variableRefs() {
+			varNames[name] = true
+		}
+	}
+	if len(varNames) == 0 {
+		return nil, nil
+	}
+
+	vars, err := s.varSVC.FindVariables(ctx, influxdb.VariableFilter{
+		OrganizationID: &dash.OrganizationID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var deps []ResourceToClone
+	for _, v := range vars {
+		if !varNames[v.Name] {
+			continue
+		}
+		deps = append(deps, ResourceToClone{
+			Kind: KindVariable,
+			ID:   v.ID,
+		})
+	}
+
+	return deps, nil
+}
+
+// ExportFilter limits the resources of an org that are exported into a pkg.
+type ExportFilter struct {
+	// Kinds limits the export to resources of the provided kinds. Buckets,
+	// dashboards, labels and variables are all exported when empty.
+	Kinds []Kind
+}
+
+func (f ExportFilter) includes(k Kind) bool {
+	if len(f.Kinds) == 0 {
+		return true
+	}
+	for _, kind := range f.Kinds {
+		if kind.is(k) {
+			return true
+		}
+	}
+	return false
+}
+
+// Export produces a pkg from the existing resources in the org. The labels
+// associated with the exported resources are exported alongside them. The
+// pkg does not reference any IDs, so it may be applied to any org. The setters
+// are applied as they are in a CreatePkg call.
+func (s *Service) Export(ctx context.Context, orgID influxdb.ID, filter ExportFilter, setters ...CreatePkgSetFn) (*Pkg, error) {
+	var resources []ResourceToClone
+
+	if filter.includes(KindBucket) {
+		bkts, _, err := s.bucketSVC.FindBuckets(ctx, influxdb.BucketFilter{OrganizationID: &orgID})
+		if err != nil {
+			return nil, err
+		}
+		for _, b := range bkts {
+			if b.Type == influxdb.BucketTypeSystem {
+				continue
+			}
+			resources = append(resources, ResourceToClone{Kind: KindBucket, ID: b.ID})
+		}
+	}
+
+	if filter.includes(KindDashboard) {
+		dashes, _, err := s.dashSVC.FindDashboards(ctx, influxdb.DashboardFilter{OrganizationID: &orgID}, influxdb.DefaultDashboardFindOptions)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range dashes {
+			resources = append(resources, ResourceToClone{Kind: KindDashboard, ID: d.ID})
+		}
+	}
+
+	if filter.includes(KindLabel) {
+		labels, err := s.labelSVC.FindLabels(ctx, influxdb.LabelFilter{OrgID: &orgID})
+		if err != nil {
+			return nil, err
+		}
+		for _, l := range labels {
+			resources = append(resources, ResourceToClone{Kind: KindLabel, ID: l.ID})
+		}
+	}
+
+	if filter.includes(KindVariable) {
+		vars, err := s.varSVC.FindVariables(ctx, influxdb.VariableFilter{
+			OrganizationID: &orgID,
+		}, influxdb.FindOptions{Limit: 10000})
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range vars {
+			resources = append(resources, ResourceToClone{Kind: KindVariable, ID: v.ID})
+		}
+	}
+
+	if len(resources) == 0 {
+		return nil, errors.New("no resources found to export")
+	}
+
+	setters = append(setters, WithResourceClones(resources...))
+	return s.CreatePkg(ctx, setters...)
+}
+
+// ApplyOptFn is a functional input for setting the options of a DryRun or Apply call.
+type ApplyOptFn func(opt *applyOpt)
+
+type applyOpt struct {
+	replace bool
+	prune   bool
+	userID  influxdb.ID
+}
+
+// WithReplace makes the resources of the org that belong to the pkg match the
+// pkg exactly. The resources in the pkg are upserted and associated with the
+// pkg label, and the existing resources associated with the pkg label that are
+// not present in the pkg are deleted. Dashboards, telegrafs, notification
+// endpoints and rules are always created new, so the existing ones of the pkg
+// are deleted. Resources in the org that do not belong to the pkg are never
+// deleted. As with a prune, a failed deletion is returned as a DeleteErr and
+// does not roll back the applied resources. Replace can not be combined with
+// prune.
+func WithReplace() ApplyOptFn {
+	return func(opt *applyOpt) {
+		opt.replace = true
+	}
+}
+
+// WithPrune deletes the existing resources in the org that belong to the pkg
+// but are no longer present in it. A resource belongs to the pkg when it is
+// associated with the pkg label, which an apply with prune or replace
+// associates with every resource of the pkg. Only resources of the kinds the
+// pkg declares are pruned, and resources that are not associated with the pkg
+// label are never pruned. The deletions run after every resource of the pkg
+// is applied, a failed deletion is returned as a DeleteErr and does not roll
+// back the applied resources. Prune can not be combined with replace.
+func WithPrune() ApplyOptFn {
+	return func(opt *applyOpt) {
+		opt.prune = true
+	}
+}
+
+// WithApplyUserID sets the user the checks, notification endpoints,
+// notification rules and telegrafs of the pkg are created for when the
+// context of the Apply call carries no authorizer, i.e. when the pkg is
+// applied outside of an authenticated request.
+func WithApplyUserID(userID influxdb.ID) ApplyOptFn {
+	return func(opt *applyOpt) {
+		opt.userID = userID
+	}
+}
+
+// PkgLabelName is the name of the label that marks the resources applied with
+// replace or prune as belonging to the pkg of the given name.
+func PkgLabelName(pkgName string) string {
+	return "pkg:" + pkgName
+}
+
+func newApplyOpt(opts ...ApplyOptFn) applyOpt {
+	var opt applyOpt
+	for _, o := range opts {
+		o(&opt)
 	}
+	return opt
 }
 
 // DryRun provides a dry run of the pkg application. The pkg will be marked verified
 // for later calls to Apply. This func will be run on an Apply if it has not been run
 // already.
-func (s *Service) DryRun(ctx context.Context, orgID influxdb.ID, pkg *Pkg) (Summary, Diff, error) {
+func (s *Service) DryRun(ctx context.Context, orgID influxdb.ID, pkg *Pkg, opts ...ApplyOptFn) (Summary, Diff, error) {
+	opt := newApplyOpt(opts...)
+	if opt.replace && opt.prune {
+		return Summary{}, Diff{}, errors.New("replace and prune are mutually exclusive")
+	}
+
 	if !pkg.isParsed {
 		if err := pkg.Validate(); err != nil {
 			return Summary{}, Diff{}, err
@@ -228,6 +555,11 @@ func (s *Service) DryRun(ctx context.Context, orgID influxdb.ID, pkg *Pkg) (Summ
 		return Summary{}, Diff{}, err
 	}
 
+	diffChecks, err := s.dryRunChecks(ctx, orgID, pkg)
+	if err != nil {
+		return Summary{}, Diff{}, err
+	}
+
 	diffDashes, err := s.dryRunDashboards(ctx, orgID, pkg)
 	if err != nil {
 		return Summary{}, Diff{}, err
@@ -238,6 +570,21 @@ func (s *Service) DryRun(ctx context.Context, orgID influxdb.ID, pkg *Pkg) (Summ
 		return Summary{}, Diff{}, err
 	}
 
+	diffEndpoints, err := s.dryRunNotificationEndpoints(ctx, orgID, pkg)
+	if err != nil {
+		return Summary{}, Diff{}, err
+	}
+
+	diffRules, err := s.dryRunNotificationRules(ctx, orgID, pkg)
+	if err != nil {
+		return Summary{}, Diff{}, err
+	}
+
+	diffTeles, err := s.dryRunTelegraf(ctx, orgID, pkg)
+	if err != nil {
+		return Summary{}, Diff{}, err
+	}
+
 	diffVars, err := s.dryRunVariables(ctx, orgID, pkg)
 	if err != nil {
 		return Summary{}, Diff{}, err
@@ -248,6 +595,28 @@ func (s *Service) DryRun(ctx context.Context, orgID influxdb.ID, pkg *Pkg) (Summ
 		return Summary{}, Diff{}, err
 	}
 
+	pkg.deletions = nil
+	if opt.replace {
+		pkg.deletions, err = s.dryRunDeletions(ctx, orgID, pkg, nil)
+		if err != nil {
+			return Summary{}, Diff{}, err
+		}
+	}
+
+	pkg.prunes = nil
+	if opt.prune {
+		// a prune only removes resources of the kinds the pkg declares, a pkg
+		// without any variables leaves the variables of the pkg label be.
+		pkg.prunes, err = s.dryRunDeletions(ctx, orgID, pkg, pkg.declaredKinds())
+		if err != nil {
+			return Summary{}, Diff{}, err
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return Summary{}, Diff{}, err
+	}
+
 	// verify the pkg is verified by a dry run. when calling Service.Apply this
 	// is required to have been run. if it is not true, then apply runs
 	// the Dry run.
@@ -255,11 +624,20 @@ func (s *Service) DryRun(ctx context.Context, orgID influxdb.ID, pkg *Pkg) (Summ
 
 	diff := Diff{
 		Buckets:       diffBuckets,
+		Checks:        diffChecks,
 		Dashboards:    diffDashes,
 		Labels:        diffLabels,
 		LabelMappings: diffLabelMappings,
+		Telegrafs:     diffTeles,
 		Variables:     diffVars,
+		Deletions:     pkg.deletions,
+		Prunes:        pkg.prunes,
+
+		NotificationEndpoints: diffEndpoints,
+		NotificationRules:     diffRules,
 	}
+	pkg.breakdown = newSummaryBreakdown(diff)
+
 	return pkg.Summary(), diff, nil
 }
 
@@ -267,14 +645,25 @@ func (s *Service) dryRunBuckets(ctx context.Context, orgID influxdb.ID, pkg *Pkg
 	mExistingBkts := make(map[string]DiffBucket)
 	bkts := pkg.buckets()
 	for i := range bkts {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		b := bkts[i]
-		existingBkt, err := s.bucketSVC.FindBucketByName(ctx, orgID, b.Name)
+		existingBkt, err := s.findBucket(ctx, orgID, b)
 		switch err {
 		// TODO: case for err not found here and another case handle where
 		//  err isn't a not found (some other error)
 		case nil:
 			b.existing = existingBkt
-			mExistingBkts[b.Name] = newDiffBucket(b, *existingBkt)
+			diff := newDiffBucket(b, *existingBkt)
+			if !diff.IsNew() && diff.OldSchemaType != diff.NewSchemaType {
+				return nil, &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg: fmt.Sprintf("bucket %q: schemaType of an existing bucket can not be changed from %s to %s",
+						b.Name, diff.OldSchemaType, diff.NewSchemaType),
+				}
+			}
+			mExistingBkts[b.Name] = diff
 		default:
 			mExistingBkts[b.Name] = newDiffBucket(b, influxdb.Bucket{})
 		}
@@ -291,6 +680,69 @@ func (s *Service) dryRunBuckets(ctx context.Context, orgID influxdb.ID, pkg *Pkg
 	return diffs, nil
 }
 
+func (s *Service) dryRunChecks(ctx context.Context, orgID influxdb.ID, pkg *Pkg) ([]DiffCheck, error) {
+	checks := pkg.checks()
+	if len(checks) > 0 && s.checkSVC == nil {
+		return nil, errCheckSVCNotConfigured
+	}
+
+	diffs := make([]DiffCheck, 0, len(checks))
+	for _, c := range checks {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		c.OrgID = orgID
+		existing, err := s.findCheck(ctx, orgID, c)
+		switch {
+		case err == nil:
+			c.existing = existing
+		case influxdb.ErrorCode(err) == influxdb.ENotFound:
+			c.existing = nil
+		default:
+			return nil, err
+		}
+		diffs = append(diffs, newDiffCheck(c, c.existing))
+	}
+
+	return diffs, nil
+}
+
+// findBucket finds the existing bucket of the pkg bucket by the id the pkg
+// provides, falling back to its name when the id is not found in the org.
+func (s *Service) findBucket(ctx context.Context, orgID influxdb.ID, b *bucket) (*influxdb.Bucket, error) {
+	if b.lookupID.Valid() {
+		existing, err := s.bucketSVC.FindBucketByID(ctx, b.lookupID)
+		switch {
+		case err == nil && existing.OrgID == orgID:
+			return existing, nil
+		case err != nil && influxdb.ErrorCode(err) != influxdb.ENotFound:
+			return nil, err
+		}
+	}
+	return s.bucketSVC.FindBucketByName(ctx, orgID, b.Name)
+}
+
+// findCheck finds the existing check of the pkg check by the id the pkg
+// provides, falling back to its name when the id is not found in the org.
+func (s *Service) findCheck(ctx context.Context, orgID influxdb.ID, c *check) (influxdb.Check, error) {
+	if c.lookupID.Valid() {
+		existing, err := s.checkSVC.FindCheckByID(ctx, c.lookupID)
+		switch {
+		case err == nil && existing.GetOrgID() == orgID:
+			return existing, nil
+		case err != nil && influxdb.ErrorCode(err) != influxdb.ENotFound:
+			return nil, err
+		}
+	}
+
+	name := c.Name
+	return s.checkSVC.FindCheck(ctx, influxdb.CheckFilter{
+		Name:  &name,
+		OrgID: &orgID,
+	})
+}
+
 func (s *Service) dryRunDashboards(ctx context.Context, orgID influxdb.ID, pkg *Pkg) ([]DiffDashboard, error) {
 	var diffs []DiffDashboard
 	for _, d := range pkg.dashboards() {
@@ -304,15 +756,59 @@ func (s *Service) dryRunDashboards(ctx context.Context, orgID influxdb.ID, pkg *
 	return diffs, nil
 }
 
+func (s *Service) dryRunNotificationEndpoints(ctx context.Context, orgID influxdb.ID, pkg *Pkg) ([]DiffNotificationEndpoint, error) {
+	endpoints := pkg.notificationEndpoints()
+	if len(endpoints) > 0 && s.endpointSVC == nil {
+		return nil, errEndpointSVCNotConfigured
+	}
+
+	var diffs []DiffNotificationEndpoint
+	for _, e := range endpoints {
+		diffs = append(diffs, newDiffNotificationEndpoint(e))
+	}
+	return diffs, nil
+}
+
+func (s *Service) dryRunNotificationRules(ctx context.Context, orgID influxdb.ID, pkg *Pkg) ([]DiffNotificationRule, error) {
+	rules := pkg.notificationRules()
+	if len(rules) > 0 && s.ruleSVC == nil {
+		return nil, errRuleSVCNotConfigured
+	}
+
+	var diffs []DiffNotificationRule
+	for _, r := range rules {
+		diffs = append(diffs, newDiffNotificationRule(r))
+	}
+	return diffs, nil
+}
+
+func (s *Service) dryRunTelegraf(ctx context.Context, orgID influxdb.ID, pkg *Pkg) ([]DiffTelegraf, error) {
+	teles := pkg.telegrafs()
+	if len(teles) > 0 && s.teleSVC == nil {
+		return nil, errTeleSVCNotConfigured
+	}
+
+	var diffs []DiffTelegraf
+	for _, t := range teles {
+		diffs = append(diffs, newDiffTelegraf(t))
+	}
+
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].Name < diffs[j].Name
+	})
+
+	return diffs, nil
+}
+
 func (s *Service) dryRunLabels(ctx context.Context, orgID influxdb.ID, pkg *Pkg) ([]DiffLabel, error) {
 	mExistingLabels := make(map[string]DiffLabel)
 	labels := pkg.labels()
 	for i := range labels {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		pkgLabel := labels[i]
-		existingLabels, err := s.labelSVC.FindLabels(ctx, influxdb.LabelFilter{
-			Name:  pkgLabel.Name,
-			OrgID: &orgID,
-		}, influxdb.FindOptions{Limit: 1})
+		existingLabels, err := s.findLabels(ctx, orgID, pkgLabel)
 		switch {
 		// TODO: case for err not found here and another case handle where
 		//  err isn't a not found (some other error)
@@ -336,13 +832,47 @@ func (s *Service) dryRunLabels(ctx context.Context, orgID influxdb.ID, pkg *Pkg)
 	return diffs, nil
 }
 
+// findLabels finds the existing label of the pkg label by the id the pkg
+// provides, falling back to its name when the id is not found in the org.
+func (s *Service) findLabels(ctx context.Context, orgID influxdb.ID, l *label) ([]*influxdb.Label, error) {
+	if l.lookupID.Valid() {
+		existing, err := s.labelSVC.FindLabelByID(ctx, l.lookupID)
+		switch {
+		case err == nil && existing.OrgID == orgID:
+			return []*influxdb.Label{existing}, nil
+		case err != nil && influxdb.ErrorCode(err) != influxdb.ENotFound:
+			return nil, err
+		}
+	}
+
+	return s.labelSVC.FindLabels(ctx, influxdb.LabelFilter{
+		Name:  l.Name,
+		OrgID: &orgID,
+	}, influxdb.FindOptions{Limit: 1})
+}
+
 func (s *Service) dryRunVariables(ctx context.Context, orgID influxdb.ID, pkg *Pkg) ([]DiffVariable, error) {
 	mExistingLabels := make(map[string]DiffVariable)
 	variables := pkg.variables()
 
 VarLoop:
 	for i := range variables {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		pkgVar := variables[i]
+		if pkgVar.lookupID.Valid() {
+			existingVar, err := s.varSVC.FindVariableByID(ctx, pkgVar.lookupID)
+			switch {
+			case err == nil && existingVar.OrganizationID == orgID:
+				pkgVar.existing = existingVar
+				mExistingLabels[pkgVar.Name] = newDiffVariable(pkgVar, *existingVar)
+				continue VarLoop
+			case err != nil && influxdb.ErrorCode(err) != influxdb.ENotFound:
+				return nil, err
+			}
+		}
+
 		existingLabels, err := s.varSVC.FindVariables(ctx, influxdb.VariableFilter{
 			OrganizationID: &orgID,
 			// TODO: would be ideal to extend find variables to allow for a name matcher
@@ -408,14 +938,82 @@ func (s *Service) dryRunLabelMappings(ctx context.Context, pkg *Pkg) ([]DiffLabe
 		}
 	}
 
-	for _, d := range pkg.dashboards() {
-		err := s.dryRunResourceLabelMapping(ctx, d, d.labels, func(labelID influxdb.ID, labelName string, isNew bool) {
-			pkg.mLabels[labelName].setDashboardMapping(d)
+	for _, c := range pkg.checks() {
+		err := s.dryRunResourceLabelMapping(ctx, c, c.labels, func(labelID influxdb.ID, labelName string, isNew bool) {
+			pkg.mLabels[labelName].setCheckMapping(c, !isNew)
 			diffs = append(diffs, DiffLabelMapping{
 				IsNew:     isNew,
-				ResType:   d.ResourceType(),
-				ResID:     SafeID(d.ID()),
-				ResName:   d.Name,
+				ResType:   c.ResourceType(),
+				ResID:     SafeID(c.ID()),
+				ResName:   c.Name,
+				LabelID:   SafeID(labelID),
+				LabelName: labelName,
+			})
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, d := range pkg.dashboards() {
+		err := s.dryRunResourceLabelMapping(ctx, d, d.labels, func(labelID influxdb.ID, labelName string, isNew bool) {
+			pkg.mLabels[labelName].setDashboardMapping(d)
+			diffs = append(diffs, DiffLabelMapping{
+				IsNew:     isNew,
+				ResType:   d.ResourceType(),
+				ResID:     SafeID(d.ID()),
+				ResName:   d.Name,
+				LabelID:   SafeID(labelID),
+				LabelName: labelName,
+			})
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, e := range pkg.notificationEndpoints() {
+		err := s.dryRunResourceLabelMapping(ctx, e, e.labels, func(labelID influxdb.ID, labelName string, isNew bool) {
+			pkg.mLabels[labelName].setNotificationEndpointMapping(e)
+			diffs = append(diffs, DiffLabelMapping{
+				IsNew:     isNew,
+				ResType:   e.ResourceType(),
+				ResID:     SafeID(e.ID()),
+				ResName:   e.Name,
+				LabelID:   SafeID(labelID),
+				LabelName: labelName,
+			})
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, r := range pkg.notificationRules() {
+		err := s.dryRunResourceLabelMapping(ctx, r, r.labels, func(labelID influxdb.ID, labelName string, isNew bool) {
+			pkg.mLabels[labelName].setNotificationRuleMapping(r)
+			diffs = append(diffs, DiffLabelMapping{
+				IsNew:     isNew,
+				ResType:   r.ResourceType(),
+				ResID:     SafeID(r.ID()),
+				ResName:   r.Name,
+				LabelID:   SafeID(labelID),
+				LabelName: labelName,
+			})
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, t := range pkg.telegrafs() {
+		err := s.dryRunResourceLabelMapping(ctx, t, t.labels, func(labelID influxdb.ID, labelName string, isNew bool) {
+			pkg.mLabels[labelName].setTelegrafMapping(t)
+			diffs = append(diffs, DiffLabelMapping{
+				IsNew:     isNew,
+				ResType:   t.ResourceType(),
+				ResID:     SafeID(t.ID()),
+				ResName:   t.Name(),
 				LabelID:   SafeID(labelID),
 				LabelName: labelName,
 			})
@@ -464,6 +1062,10 @@ func (s *Service) dryRunLabelMappings(ctx context.Context, pkg *Pkg) ([]DiffLabe
 }
 
 func (s *Service) dryRunResourceLabelMapping(ctx context.Context, la labelAssociater, labels []*label, mappingFn labelMappingDiffFn) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	if !la.Exists() {
 		for _, l := range labels {
 			mappingFn(l.ID(), l.Name, true)
@@ -498,6 +1100,207 @@ func (s *Service) dryRunResourceLabelMapping(ctx context.Context, la labelAssoci
 	return nil
 }
 
+// dryRunDeletions finds the existing resources in the org that belong to the
+// pkg, by way of their association with the pkg label, but are not present in
+// the pkg. Without a pkg label, the pkg has never been applied with replace or
+// prune and no resources belong to it. Resources that are not associated with
+// the pkg label are never deleted, nor are system buckets or the pkg label.
+// The kinds of resources the service is not provided a service for are skipped.
+func (s *Service) dryRunDeletions(ctx context.Context, orgID influxdb.ID, pkg *Pkg, kinds map[Kind]bool) ([]DiffDeletion, error) {
+	pkgLabel, err := s.findPkgLabel(ctx, orgID, pkg)
+	if err != nil || pkgLabel == nil {
+		return nil, err
+	}
+
+	// the existing resources the pkg resolved to are kept, matching them by
+	// id keeps a resource the pkg renames from being deleted.
+	pkgIDs := make(map[influxdb.ID]bool)
+	for _, b := range pkg.buckets() {
+		if b.existing != nil {
+			pkgIDs[b.ID()] = true
+		}
+	}
+	for _, c := range pkg.checks() {
+		if c.existing != nil {
+			pkgIDs[c.ID()] = true
+		}
+	}
+	for _, l := range pkg.labels() {
+		if l.existing != nil {
+			pkgIDs[l.ID()] = true
+		}
+	}
+	for _, v := range pkg.variables() {
+		if v.existing != nil {
+			pkgIDs[v.ID()] = true
+		}
+	}
+	pkgIDs[pkgLabel.ID] = true
+
+	type candidate struct {
+		resType influxdb.ResourceType
+		DiffDeletion
+	}
+	var candidates []candidate
+	// every kind is a candidate for deletion unless the kinds are limited.
+	deletesKind := func(k Kind) bool {
+		return kinds == nil || kinds[k]
+	}
+	addCandidate := func(resType influxdb.ResourceType, k Kind, id influxdb.ID, name string) {
+		if pkgIDs[id] {
+			return
+		}
+		candidates = append(candidates, candidate{
+			resType:      resType,
+			DiffDeletion: DiffDeletion{Kind: k, ID: SafeID(id), Name: name},
+		})
+	}
+
+	if s.bucketSVC != nil && deletesKind(KindBucket) {
+		existingBkts, _, err := s.bucketSVC.FindBuckets(ctx, influxdb.BucketFilter{OrganizationID: &orgID})
+		if err != nil {
+			return nil, err
+		}
+		for _, b := range existingBkts {
+			if b.Type == influxdb.BucketTypeSystem {
+				continue
+			}
+			addCandidate(influxdb.BucketsResourceType, KindBucket, b.ID, b.Name)
+		}
+	}
+
+	if s.checkSVC != nil && deletesKind(KindCheck) {
+		existingChecks, _, err := s.checkSVC.FindChecks(ctx, influxdb.CheckFilter{OrgID: &orgID})
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range existingChecks {
+			addCandidate(influxdb.ChecksResourceType, KindCheck, c.GetID(), c.GetName())
+		}
+	}
+
+	// dashboards, telegrafs, notification endpoints and rules are always
+	// created new, the existing ones of the pkg are replaced.
+	if s.dashSVC != nil && deletesKind(KindDashboard) {
+		existingDashes, _, err := s.dashSVC.FindDashboards(ctx, influxdb.DashboardFilter{OrganizationID: &orgID}, influxdb.DefaultDashboardFindOptions)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range existingDashes {
+			addCandidate(influxdb.DashboardsResourceType, KindDashboard, d.ID, d.Name)
+		}
+	}
+
+	if s.labelSVC != nil && deletesKind(KindLabel) {
+		existingLabels, err := s.labelSVC.FindLabels(ctx, influxdb.LabelFilter{OrgID: &orgID})
+		if err != nil {
+			return nil, err
+		}
+		for _, l := range existingLabels {
+			addCandidate(influxdb.LabelsResourceType, KindLabel, l.ID, l.Name)
+		}
+	}
+
+	if s.endpointSVC != nil && deletesKind(KindNotificationEndpoint) {
+		existingEndpoints, _, err := s.endpointSVC.FindNotificationEndpoints(ctx, influxdb.NotificationEndpointFilter{OrgID: &orgID})
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range existingEndpoints {
+			addCandidate(influxdb.NotificationEndpointResourceType, KindNotificationEndpoint, e.GetID(), e.GetName())
+		}
+	}
+
+	if s.ruleSVC != nil && deletesKind(KindNotificationRule) {
+		existingRules, _, err := s.ruleSVC.FindNotificationRules(ctx, influxdb.NotificationRuleFilter{OrgID: &orgID})
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range existingRules {
+			addCandidate(influxdb.NotificationRuleResourceType, KindNotificationRule, r.GetID(), r.GetName())
+		}
+	}
+
+	if s.teleSVC != nil && deletesKind(KindTelegraf) {
+		existingTeles, _, err := s.teleSVC.FindTelegrafConfigs(ctx, influxdb.TelegrafConfigFilter{OrgID: &orgID})
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range existingTeles {
+			addCandidate(influxdb.TelegrafsResourceType, KindTelegraf, t.ID, t.Name)
+		}
+	}
+
+	if s.varSVC != nil && deletesKind(KindVariable) {
+		existingVars, err := s.varSVC.FindVariables(ctx, influxdb.VariableFilter{
+			OrganizationID: &orgID,
+		}, influxdb.FindOptions{Limit: 10000})
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range existingVars {
+			addCandidate(influxdb.VariablesResourceType, KindVariable, v.ID, v.Name)
+		}
+	}
+
+	var deletions []DiffDeletion
+	for _, c := range candidates {
+		ok, err := s.hasPkgLabel(ctx, pkgLabel.ID, c.resType, influxdb.ID(c.ID))
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			deletions = append(deletions, c.DiffDeletion)
+		}
+	}
+
+	sortDeletions(deletions)
+	return deletions, nil
+}
+
+func sortDeletions(deletions []DiffDeletion) {
+	sort.Slice(deletions, func(i, j int) bool {
+		if deletions[i].Kind == deletions[j].Kind {
+			return deletions[i].Name < deletions[j].Name
+		}
+		return deletions[i].Kind < deletions[j].Kind
+	})
+}
+
+// findPkgLabel returns the pkg label of the pkg, it is nil when the label does
+// not exist.
+func (s *Service) findPkgLabel(ctx context.Context, orgID influxdb.ID, pkg *Pkg) (*influxdb.Label, error) {
+	labels, err := s.labelSVC.FindLabels(ctx, influxdb.LabelFilter{
+		Name:  PkgLabelName(pkg.Metadata.Name),
+		OrgID: &orgID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range labels {
+		if l.Name == PkgLabelName(pkg.Metadata.Name) {
+			return l, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *Service) hasPkgLabel(ctx context.Context, pkgLabelID influxdb.ID, resType influxdb.ResourceType, id influxdb.ID) (bool, error) {
+	labels, err := s.labelSVC.FindResourceLabels(ctx, influxdb.LabelMappingFilter{
+		ResourceID:   id,
+		ResourceType: resType,
+	})
+	if err != nil {
+		return false, err
+	}
+	for _, l := range labels {
+		if l.ID == pkgLabelID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 func labelSlcToMap(labels []*label) map[string]*label {
 	m := make(map[string]*label)
 	for i := range labels {
@@ -509,53 +1312,141 @@ func labelSlcToMap(labels []*label) map[string]*label {
 // Apply will apply all the resources identified in the provided pkg. The entire pkg will be applied
 // in its entirety. If a failure happens midway then the entire pkg will be rolled back to the state
 // from before the pkg were applied.
-func (s *Service) Apply(ctx context.Context, orgID influxdb.ID, pkg *Pkg) (sum Summary, e error) {
+func (s *Service) Apply(ctx context.Context, orgID influxdb.ID, pkg *Pkg, opts ...ApplyOptFn) (Summary, error) {
+	opt := newApplyOpt(opts...)
+
 	if !pkg.isParsed {
 		if err := pkg.Validate(); err != nil {
 			return Summary{}, err
 		}
 	}
 
-	if !pkg.isVerified {
-		_, _, err := s.DryRun(ctx, orgID, pkg)
+	// a replace or prune requires the deletions found by a dry run with them,
+	// the dry run is rerun to guarantee they are up to date.
+	if !pkg.isVerified || opt.replace || opt.prune {
+		_, _, err := s.DryRun(ctx, orgID, pkg, opts...)
 		if err != nil {
 			return Summary{}, err
 		}
 	}
 
+	if err := s.applyResources(ctx, orgID, pkg, opt); err != nil {
+		return Summary{}, err
+	}
+
+	// deletions can not be rolled back, they run as the final stage once
+	// every resource of the pkg is applied and nothing is left to roll back.
+	// A failed deletion leaves the applied resources in place, the summary is
+	// returned along with the DeleteErr.
+	var deletions []DiffDeletion
+	switch {
+	case opt.replace:
+		deletions = pkg.deletions
+	case opt.prune:
+		deletions = pkg.prunes
+	}
+	if err := s.applyDeletions(ctx, deletions); err != nil {
+		return pkg.Summary(), err
+	}
+
+	return pkg.Summary(), nil
+}
+
+// applyResources applies every resource of the pkg, rolling back the applied
+// resources when any of them fails.
+func (s *Service) applyResources(ctx context.Context, orgID influxdb.ID, pkg *Pkg, opt applyOpt) (e error) {
 	coordinator := new(rollbackCoordinator)
 	defer coordinator.rollback(s.logger, &e)
 
-	runners := [][]applier{
-		// each grouping here runs for its entirety, then returns an error that
-		// is indicative of running all appliers provided. For instance, the labels
-		// may have 1 label fail and one of the buckets fails. The errors aggregate so
-		// the caller will be informed of both the failed label and the failed bucket.
-		// the groupings here allow for steps to occur before exiting. The first step is
-		// adding the primary resources. Here we get all the errors associated with them.
-		// If those are all good, then we run the secondary(dependent) resources which
-		// rely on the primary resources having been created.
-		{
-			// primary resources
-			s.applyLabels(pkg.labels()),
-			s.applyVariables(pkg.variables()),
-			s.applyBuckets(pkg.buckets()),
-			s.applyDashboards(pkg.dashboards()),
-		},
-		{
-			// secondary (dependent) resources
-			s.applyLabelMappings(pkg),
-		},
+	// each grouping here runs for its entirety, then returns an error that
+	// is indicative of running all appliers provided. For instance, the labels
+	// may have 1 label fail and one of the buckets fails. The errors aggregate so
+	// the caller will be informed of both the failed label and the failed bucket.
+	// the groupings here allow for steps to occur before exiting. Each grouping
+	// is a stage of the pkg, the resources of a stage rely on the resources of
+	// the stages before it having been created. Every resource is in the first
+	// stage unless it depends on another resource, i.e. a notification rule
+	// depends on its notification endpoint or a resource lists the resources it
+	// dependsOn.
+	var runners [][]applier
+	for _, st := range pkg.applyStages() {
+		runners = append(runners, []applier{
+			s.applyLabels(st.labels),
+			s.applyVariables(st.variables),
+			s.applyBuckets(st.buckets),
+			s.applyDashboards(st.dashboards),
+			s.applyTelegrafs(st.telegrafs, opt.userID),
+			s.applyChecks(st.checks, opt.userID),
+			s.applyNotificationEndpoints(st.notificationEndpoints, opt.userID),
+			s.applyNotificationRules(st.notificationRules, opt.userID),
+		})
+	}
+	// the label mappings rely on every resource having been created
+	last := len(runners) - 1
+	runners[last] = append(runners[last], s.applyLabelMappings(pkg))
+
+	if opt.replace || opt.prune {
+		// the pkg label marks every resource of the pkg, including those that
+		// already exist, as belonging to the pkg for later replaces and prunes.
+		runners = append(runners, []applier{s.applyPkgLabel(pkg)})
 	}
 
 	for _, appliers := range runners {
-		err := coordinator.runTilEnd(ctx, orgID, appliers...)
+		if err := coordinator.runTilEnd(ctx, orgID, appliers...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Service) applyDeletions(ctx context.Context, deletions []DiffDeletion) error {
+	ctx, cancel := context.WithTimeout(ctx, 1*time.Minute)
+	defer cancel()
+
+	var deleteErr DeleteErr
+	for _, d := range deletions {
+		err := ctx.Err()
 		if err != nil {
-			return Summary{}, err
+			deleteErr.Resources = append(deleteErr.Resources, ApplyErrResource{
+				Kind: d.Kind.String(),
+				Name: d.Name,
+				Err:  err,
+			})
+			continue
+		}
+
+		id := influxdb.ID(d.ID)
+		switch d.Kind {
+		case KindBucket:
+			err = s.bucketSVC.DeleteBucket(ctx, id)
+		case KindDashboard:
+			err = s.dashSVC.DeleteDashboard(ctx, id)
+		case KindLabel:
+			err = s.labelSVC.DeleteLabel(ctx, id)
+		case KindCheck:
+			err = s.checkSVC.DeleteCheck(ctx, id)
+		case KindNotificationEndpoint:
+			_, _, err = s.endpointSVC.DeleteNotificationEndpoint(ctx, id)
+		case KindNotificationRule:
+			err = s.ruleSVC.DeleteNotificationRule(ctx, id)
+		case KindTelegraf:
+			err = s.teleSVC.DeleteTelegrafConfig(ctx, id)
+		case KindVariable:
+			err = s.varSVC.DeleteVariable(ctx, id)
+		}
+		if err != nil {
+			deleteErr.Resources = append(deleteErr.Resources, ApplyErrResource{
+				Kind: d.Kind.String(),
+				Name: d.Name,
+				Err:  err,
+			})
 		}
 	}
 
-	return pkg.Summary(), nil
+	if len(deleteErr.Resources) > 0 {
+		return &deleteErr
+	}
+	return nil
 }
 
 func (s *Service) applyBuckets(buckets []*bucket) applier {
@@ -568,6 +1459,9 @@ func (s *Service) applyBuckets(buckets []*bucket) applier {
 
 		var errs applyErrs
 		for i, b := range buckets {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
 			buckets[i].OrgID = orgID
 			if !b.shouldApply() {
 				continue
@@ -576,7 +1470,7 @@ func (s *Service) applyBuckets(buckets []*bucket) applier {
 			if err != nil {
 				errs = append(errs, applyErrBody{
 					name: b.Name,
-					msg:  err.Error(),
+					err:  err,
 				})
 				continue
 			}
@@ -584,7 +1478,7 @@ func (s *Service) applyBuckets(buckets []*bucket) applier {
 			rollbackBuckets = append(rollbackBuckets, buckets[i])
 		}
 
-		return errs.toError(resource, "failed to create bucket")
+		return errs.toError(resource)
 	}
 
 	return applier{
@@ -608,8 +1502,10 @@ func (s *Service) rollbackBuckets(buckets []*bucket) error {
 		}
 
 		_, err := s.bucketSVC.UpdateBucket(context.Background(), b.ID(), influxdb.BucketUpdate{
-			Description:     &b.Description,
-			RetentionPeriod: &b.RetentionPeriod,
+			Name:               &b.existing.Name,
+			Description:        &b.existing.Description,
+			RetentionPeriod:    &b.existing.RetentionPeriod,
+			ShardGroupDuration: &b.existing.ShardGroupDuration,
 		})
 		if err != nil {
 			errs = append(errs, b.ID().String())
@@ -626,10 +1522,17 @@ func (s *Service) rollbackBuckets(buckets []*bucket) error {
 
 func (s *Service) applyBucket(ctx context.Context, b *bucket) (influxdb.Bucket, error) {
 	if b.existing != nil {
-		influxBucket, err := s.bucketSVC.UpdateBucket(ctx, b.ID(), influxdb.BucketUpdate{
-			Description:     &b.Description,
-			RetentionPeriod: &b.RetentionPeriod,
-		})
+		shardGroupDur := b.shardGroupDuration(b.existing.ShardGroupDuration)
+		upd := influxdb.BucketUpdate{
+			Description:        &b.Description,
+			RetentionPeriod:    &b.RetentionPeriod,
+			ShardGroupDuration: &shardGroupDur,
+		}
+		// a bucket found by its id is renamed to the name of the pkg
+		if b.Name != b.existing.Name {
+			upd.Name = &b.Name
+		}
+		influxBucket, err := s.bucketSVC.UpdateBucket(ctx, b.ID(), upd)
 		if err != nil {
 			return influxdb.Bucket{}, err
 		}
@@ -637,10 +1540,12 @@ func (s *Service) applyBucket(ctx context.Context, b *bucket) (influxdb.Bucket,
 	}
 
 	influxBucket := influxdb.Bucket{
-		OrgID:           b.OrgID,
-		Description:     b.Description,
-		Name:            b.Name,
-		RetentionPeriod: b.RetentionPeriod,
+		OrgID:              b.OrgID,
+		Description:        b.Description,
+		Name:               b.Name,
+		RetentionPeriod:    b.RetentionPeriod,
+		SchemaType:         b.SchemaType,
+		ShardGroupDuration: b.ShardGroupDuration,
 	}
 	err := s.bucketSVC.CreateBucket(ctx, &influxBucket)
 	if err != nil {
@@ -650,6 +1555,117 @@ func (s *Service) applyBucket(ctx context.Context, b *bucket) (influxdb.Bucket,
 	return influxBucket, nil
 }
 
+func (s *Service) applyChecks(checks []*check, userID influxdb.ID) applier {
+	const resource = "check"
+
+	rollbackChecks := make([]*check, 0, len(checks))
+	createFn := func(ctx context.Context, orgID influxdb.ID) error {
+		ctx, cancel := context.WithTimeout(ctx, 1*time.Minute)
+		defer cancel()
+
+		var errs applyErrs
+		for i := range checks {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			c := checks[i]
+			c.OrgID = orgID
+			if !c.shouldApply() {
+				continue
+			}
+			influxCheck, err := s.applyCheck(ctx, c, userID)
+			if err != nil {
+				errs = append(errs, applyErrBody{
+					name: c.Name,
+					err:  err,
+				})
+				continue
+			}
+			c.id = influxCheck.GetID()
+			rollbackChecks = append(rollbackChecks, c)
+		}
+
+		return errs.toError(resource)
+	}
+
+	return applier{
+		creater: createFn,
+		rollbacker: rollbacker{
+			resource: resource,
+			fn:       func() error { return s.rollbackChecks(rollbackChecks) },
+		},
+	}
+}
+
+func (s *Service) rollbackChecks(checks []*check) error {
+	var errs []string
+	for _, c := range checks {
+		if c.existing == nil {
+			err := s.checkSVC.DeleteCheck(context.Background(), c.ID())
+			if err != nil {
+				errs = append(errs, c.ID().String())
+			}
+			continue
+		}
+
+		_, err := s.checkSVC.UpdateCheck(context.Background(), c.ID(), influxdb.CheckCreate{
+			Check:  c.existing,
+			Status: c.existingStatus,
+		})
+		if err != nil {
+			errs = append(errs, c.ID().String())
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf(`check_ids=[%s] err="unable to delete check"`, strings.Join(errs, ", "))
+	}
+
+	return nil
+}
+
+// applyCheck updates the check when it exists already, otherwise the check is
+// created and owned by the user applying the pkg.
+func (s *Service) applyCheck(ctx context.Context, c *check, userID influxdb.ID) (influxdb.Check, error) {
+	if s.checkSVC == nil {
+		return nil, errCheckSVCNotConfigured
+	}
+
+	if c.existing != nil {
+		// the status of the check lives on its task, it is captured before
+		// the update activates the check so a rollback can restore it.
+		if s.taskSVC == nil {
+			return nil, errTaskSVCNotConfigured
+		}
+		t, err := s.taskSVC.FindTaskByID(ctx, c.existing.GetTaskID())
+		if err != nil {
+			return nil, err
+		}
+		c.existingStatus = influxdb.Status(t.Status)
+
+		return s.checkSVC.UpdateCheck(ctx, c.ID(), influxdb.CheckCreate{
+			Check:  c.influxCheck(),
+			Status: influxdb.Active,
+		})
+	}
+
+	userID, err := applyUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	influxCheck := c.influxCheck()
+	err = s.checkSVC.CreateCheck(ctx, influxdb.CheckCreate{
+		Check:  influxCheck,
+		Status: influxdb.Active,
+	}, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return influxCheck, nil
+}
+
 func (s *Service) applyDashboards(dashboards []*dashboard) applier {
 	const resource = "dashboard"
 
@@ -660,13 +1676,16 @@ func (s *Service) applyDashboards(dashboards []*dashboard) applier {
 
 		var errs applyErrs
 		for i := range dashboards {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
 			d := dashboards[i]
 			d.OrgID = orgID
 			influxBucket, err := s.applyDashboard(ctx, d)
 			if err != nil {
 				errs = append(errs, applyErrBody{
 					name: d.Name,
-					msg:  err.Error(),
+					err:  err,
 				})
 				continue
 			}
@@ -674,7 +1693,7 @@ func (s *Service) applyDashboards(dashboards []*dashboard) applier {
 			rollbackDashboards = append(rollbackDashboards, d)
 		}
 
-		return errs.toError(resource, "failed to create bucket")
+		return errs.toError(resource)
 	}
 
 	return applier{
@@ -761,6 +1780,9 @@ func (s *Service) applyLabels(labels []*label) applier {
 
 		var errs applyErrs
 		for i, l := range labels {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
 			labels[i].OrgID = orgID
 			if !l.shouldApply() {
 				continue
@@ -769,7 +1791,7 @@ func (s *Service) applyLabels(labels []*label) applier {
 			if err != nil {
 				errs = append(errs, applyErrBody{
 					name: l.Name,
-					msg:  err.Error(),
+					err:  err,
 				})
 				continue
 			}
@@ -777,7 +1799,7 @@ func (s *Service) applyLabels(labels []*label) applier {
 			rollBackLabels = append(rollBackLabels, labels[i])
 		}
 
-		return errs.toError(resource, "failed to create label")
+		return errs.toError(resource)
 	}
 
 	return applier{
@@ -817,9 +1839,13 @@ func (s *Service) rollbackLabels(labels []*label) error {
 
 func (s *Service) applyLabel(ctx context.Context, l *label) (influxdb.Label, error) {
 	if l.existing != nil {
-		updatedlabel, err := s.labelSVC.UpdateLabel(ctx, l.ID(), influxdb.LabelUpdate{
+		upd := influxdb.LabelUpdate{
 			Properties: l.properties(),
-		})
+		}
+		if l.Name != l.existing.Name {
+			upd.Name = l.Name
+		}
+		updatedlabel, err := s.labelSVC.UpdateLabel(ctx, l.ID(), upd)
 		if err != nil {
 			return influxdb.Label{}, err
 		}
@@ -839,6 +1865,239 @@ func (s *Service) applyLabel(ctx context.Context, l *label) (influxdb.Label, err
 	return influxLabel, nil
 }
 
+func (s *Service) applyNotificationEndpoints(endpoints []*notificationEndpoint, userID influxdb.ID) applier {
+	const resource = "notification_endpoint"
+
+	rollbackEndpoints := make([]*notificationEndpoint, 0, len(endpoints))
+	createFn := func(ctx context.Context, orgID influxdb.ID) error {
+		ctx, cancel := context.WithTimeout(ctx, 1*time.Minute)
+		defer cancel()
+
+		var errs applyErrs
+		for i := range endpoints {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			e := endpoints[i]
+			e.OrgID = orgID
+			if err := s.applyNotificationEndpoint(ctx, e, userID); err != nil {
+				errs = append(errs, applyErrBody{
+					name: e.Name,
+					err:  err,
+				})
+				continue
+			}
+			rollbackEndpoints = append(rollbackEndpoints, e)
+		}
+
+		return errs.toError(resource)
+	}
+
+	return applier{
+		creater: createFn,
+		rollbacker: rollbacker{
+			resource: resource,
+			fn:       func() error { return s.rollbackNotificationEndpoints(rollbackEndpoints) },
+		},
+	}
+}
+
+func (s *Service) rollbackNotificationEndpoints(endpoints []*notificationEndpoint) error {
+	var errs []string
+	for _, e := range endpoints {
+		_, _, err := s.endpointSVC.DeleteNotificationEndpoint(context.Background(), e.ID())
+		if err != nil {
+			errs = append(errs, e.ID().String())
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf(`notification_endpoint_ids=[%s] err="unable to delete notification endpoint"`, strings.Join(errs, ", "))
+	}
+
+	return nil
+}
+
+// applyNotificationEndpoint creates the notification endpoint, it is owned by
+// the user applying the pkg.
+func (s *Service) applyNotificationEndpoint(ctx context.Context, e *notificationEndpoint, userID influxdb.ID) error {
+	if s.endpointSVC == nil {
+		return errEndpointSVCNotConfigured
+	}
+
+	userID, err := applyUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	influxEndpoint := e.influxEndpoint()
+	if err := s.endpointSVC.CreateNotificationEndpoint(ctx, influxEndpoint, userID); err != nil {
+		return err
+	}
+	e.id = influxEndpoint.GetID()
+
+	return nil
+}
+
+func (s *Service) applyNotificationRules(rules []*notificationRule, userID influxdb.ID) applier {
+	const resource = "notification_rule"
+
+	rollbackRules := make([]*notificationRule, 0, len(rules))
+	createFn := func(ctx context.Context, orgID influxdb.ID) error {
+		ctx, cancel := context.WithTimeout(ctx, 1*time.Minute)
+		defer cancel()
+
+		var errs applyErrs
+		for i := range rules {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			r := rules[i]
+			r.OrgID = orgID
+			if err := s.applyNotificationRule(ctx, r, userID); err != nil {
+				errs = append(errs, applyErrBody{
+					name: r.Name,
+					err:  err,
+				})
+				continue
+			}
+			rollbackRules = append(rollbackRules, r)
+		}
+
+		return errs.toError(resource)
+	}
+
+	return applier{
+		creater: createFn,
+		rollbacker: rollbacker{
+			resource: resource,
+			fn:       func() error { return s.rollbackNotificationRules(rollbackRules) },
+		},
+	}
+}
+
+func (s *Service) rollbackNotificationRules(rules []*notificationRule) error {
+	var errs []string
+	for _, r := range rules {
+		err := s.ruleSVC.DeleteNotificationRule(context.Background(), r.ID())
+		if err != nil {
+			errs = append(errs, r.ID().String())
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf(`notification_rule_ids=[%s] err="unable to delete notification rule"`, strings.Join(errs, ", "))
+	}
+
+	return nil
+}
+
+// applyNotificationRule creates the notification rule for the endpoint
+// created from the pkg, it is owned by the user applying the pkg.
+func (s *Service) applyNotificationRule(ctx context.Context, r *notificationRule, userID influxdb.ID) error {
+	if s.ruleSVC == nil {
+		return errRuleSVCNotConfigured
+	}
+
+	userID, err := applyUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	influxRule := r.influxRule()
+	err = s.ruleSVC.CreateNotificationRule(ctx, influxdb.NotificationRuleCreate{
+		NotificationRule: influxRule,
+		Status:           influxdb.Active,
+	}, userID)
+	if err != nil {
+		return err
+	}
+	r.id = influxRule.GetID()
+
+	return nil
+}
+
+func (s *Service) applyTelegrafs(teles []*telegraf, userID influxdb.ID) applier {
+	const resource = "telegraf"
+
+	rollbackTelegrafs := make([]*telegraf, 0, len(teles))
+	createFn := func(ctx context.Context, orgID influxdb.ID) error {
+		ctx, cancel := context.WithTimeout(ctx, 1*time.Minute)
+		defer cancel()
+
+		var errs applyErrs
+		for i := range teles {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			t := teles[i]
+			t.config.OrgID = orgID
+			if err := s.applyTelegrafConfig(ctx, t, userID); err != nil {
+				errs = append(errs, applyErrBody{
+					name: t.Name(),
+					err:  err,
+				})
+				continue
+			}
+			rollbackTelegrafs = append(rollbackTelegrafs, t)
+		}
+
+		return errs.toError(resource)
+	}
+
+	return applier{
+		creater: createFn,
+		rollbacker: rollbacker{
+			resource: resource,
+			fn:       func() error { return s.rollbackTelegrafs(rollbackTelegrafs) },
+		},
+	}
+}
+
+func (s *Service) rollbackTelegrafs(teles []*telegraf) error {
+	var errs []string
+	for _, t := range teles {
+		err := s.teleSVC.DeleteTelegrafConfig(context.Background(), t.ID())
+		if err != nil {
+			errs = append(errs, t.ID().String())
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf(`telegraf_ids=[%s] err="unable to delete telegraf config"`, strings.Join(errs, ", "))
+	}
+
+	return nil
+}
+
+// applyTelegrafConfig creates the telegraf config, it is owned by the user
+// applying the pkg.
+func (s *Service) applyTelegrafConfig(ctx context.Context, t *telegraf, userID influxdb.ID) error {
+	if s.teleSVC == nil {
+		return errTeleSVCNotConfigured
+	}
+
+	userID, err := applyUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	return s.teleSVC.CreateTelegrafConfig(ctx, &t.config, userID)
+}
+
+// applyUserID returns the user of the authorizer in the context, falling back
+// to the user provided with WithApplyUserID when the context has none.
+func applyUserID(ctx context.Context, userID influxdb.ID) (influxdb.ID, error) {
+	auth, err := pctx.GetAuthorizer(ctx)
+	if err == nil {
+		return auth.GetUserID(), nil
+	}
+	if userID.Valid() {
+		return userID, nil
+	}
+	return 0, err
+}
+
 func (s *Service) applyVariables(vars []*variable) applier {
 	const resource = "variable"
 
@@ -849,6 +2108,9 @@ func (s *Service) applyVariables(vars []*variable) applier {
 
 		var errs applyErrs
 		for i, v := range vars {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
 			vars[i].OrgID = orgID
 			if !v.shouldApply() {
 				continue
@@ -857,7 +2119,7 @@ func (s *Service) applyVariables(vars []*variable) applier {
 			if err != nil {
 				errs = append(errs, applyErrBody{
 					name: v.Name,
-					msg:  err.Error(),
+					err:  err,
 				})
 				continue
 			}
@@ -865,7 +2127,7 @@ func (s *Service) applyVariables(vars []*variable) applier {
 			rollBackVars = append(rollBackVars, vars[i])
 		}
 
-		return errs.toError(resource, "failed to create variable")
+		return errs.toError(resource)
 	}
 
 	return applier{
@@ -906,10 +2168,14 @@ func (s *Service) rollbackVariables(variables []*variable) error {
 
 func (s *Service) applyVariable(ctx context.Context, v *variable) (influxdb.Variable, error) {
 	if v.existing != nil {
-		updatedVar, err := s.varSVC.UpdateVariable(ctx, v.ID(), &influxdb.VariableUpdate{
+		upd := &influxdb.VariableUpdate{
 			Description: v.Description,
 			Arguments:   v.influxVarArgs(),
-		})
+		}
+		if v.Name != v.existing.Name {
+			upd.Name = v.Name
+		}
+		updatedVar, err := s.varSVC.UpdateVariable(ctx, v.ID(), upd)
 		if err != nil {
 			return influxdb.Variable{}, err
 		}
@@ -936,8 +2202,12 @@ func (s *Service) applyLabelMappings(pkg *Pkg) applier {
 		ctx, cancel := context.WithTimeout(ctx, time.Minute)
 		defer cancel()
 
+		var errs applyErrs
 		labelMappings := pkg.labelMappings()
 		for i := range labelMappings {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
 			mapping := labelMappings[i]
 			if mapping.exists {
 				// this block here does 2 things, it does note write a
@@ -950,12 +2220,16 @@ func (s *Service) applyLabelMappings(pkg *Pkg) applier {
 			}
 			err := s.labelSVC.CreateLabelMapping(ctx, &mapping.LabelMapping)
 			if err != nil {
-				return err
+				errs = append(errs, applyErrBody{
+					name: fmt.Sprintf("%s:%s", mapping.LabelName, mapping.ResourceName),
+					err:  err,
+				})
+				continue
 			}
 			mappings = append(mappings, mapping.LabelMapping)
 		}
 
-		return nil
+		return errs.toError("label_mapping")
 	}
 
 	return applier{
@@ -967,6 +2241,115 @@ func (s *Service) applyLabelMappings(pkg *Pkg) applier {
 	}
 }
 
+// applyPkgLabel associates the pkg label with every resource of the pkg. The pkg
+// label is created when it does not exist.
+func (s *Service) applyPkgLabel(pkg *Pkg) applier {
+	const resource = "pkg_label"
+
+	var (
+		createdLabel *influxdb.Label
+		mappings     []influxdb.LabelMapping
+	)
+	createFn := func(ctx context.Context, orgID influxdb.ID) error {
+		ctx, cancel := context.WithTimeout(ctx, time.Minute)
+		defer cancel()
+
+		pkgLabel, err := s.findPkgLabel(ctx, orgID, pkg)
+		if err != nil {
+			return err
+		}
+		if pkgLabel == nil {
+			pkgLabel = &influxdb.Label{
+				OrgID: orgID,
+				Name:  PkgLabelName(pkg.Metadata.Name),
+			}
+			if err := s.labelSVC.CreateLabel(ctx, pkgLabel); err != nil {
+				return err
+			}
+			createdLabel = pkgLabel
+		}
+
+		type pkgResource struct {
+			resType influxdb.ResourceType
+			id      influxdb.ID
+			name    string
+			exists  bool
+		}
+		var resources []pkgResource
+		for _, b := range pkg.buckets() {
+			resources = append(resources, pkgResource{influxdb.BucketsResourceType, b.ID(), b.Name, b.existing != nil})
+		}
+		for _, c := range pkg.checks() {
+			resources = append(resources, pkgResource{influxdb.ChecksResourceType, c.ID(), c.Name, c.existing != nil})
+		}
+		for _, d := range pkg.dashboards() {
+			resources = append(resources, pkgResource{influxdb.DashboardsResourceType, d.ID(), d.Name, false})
+		}
+		for _, l := range pkg.labels() {
+			resources = append(resources, pkgResource{influxdb.LabelsResourceType, l.ID(), l.Name, l.existing != nil})
+		}
+		for _, e := range pkg.notificationEndpoints() {
+			resources = append(resources, pkgResource{influxdb.NotificationEndpointResourceType, e.ID(), e.Name, false})
+		}
+		for _, r := range pkg.notificationRules() {
+			resources = append(resources, pkgResource{influxdb.NotificationRuleResourceType, r.ID(), r.Name, false})
+		}
+		for _, t := range pkg.telegrafs() {
+			resources = append(resources, pkgResource{influxdb.TelegrafsResourceType, t.ID(), t.Name(), false})
+		}
+		for _, v := range pkg.variables() {
+			resources = append(resources, pkgResource{influxdb.VariablesResourceType, v.ID(), v.Name, v.existing != nil})
+		}
+
+		var errs applyErrs
+		for _, res := range resources {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			if res.exists && createdLabel == nil {
+				ok, err := s.hasPkgLabel(ctx, pkgLabel.ID, res.resType, res.id)
+				if err != nil {
+					errs = append(errs, applyErrBody{name: res.name, err: err})
+					continue
+				}
+				if ok {
+					continue
+				}
+			}
+
+			mapping := influxdb.LabelMapping{
+				LabelID:      pkgLabel.ID,
+				ResourceID:   res.id,
+				ResourceType: res.resType,
+			}
+			if err := s.labelSVC.CreateLabelMapping(ctx, &mapping); err != nil {
+				errs = append(errs, applyErrBody{name: res.name, err: err})
+				continue
+			}
+			mappings = append(mappings, mapping)
+		}
+
+		return errs.toError(resource)
+	}
+
+	return applier{
+		creater: createFn,
+		rollbacker: rollbacker{
+			resource: resource,
+			fn: func() error {
+				if err := s.rollbackLabelMappings(mappings); err != nil {
+					return err
+				}
+				if createdLabel == nil {
+					return nil
+				}
+				return s.labelSVC.DeleteLabel(context.Background(), createdLabel.ID)
+			},
+		},
+	}
+}
+
 func (s *Service) rollbackLabelMappings(mappings []influxdb.LabelMapping) error {
 	var errs []string
 	for i := range mappings {
@@ -1003,48 +2386,145 @@ type rollbackCoordinator struct {
 }
 
 func (r *rollbackCoordinator) runTilEnd(ctx context.Context, orgID influxdb.ID, appliers ...applier) error {
-	var errs []string
+	var applyErr ApplyErr
 	for _, app := range appliers {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
 		r.rollbacks = append(r.rollbacks, app.rollbacker)
 		if err := app.creater(ctx, orgID); err != nil {
-			errs = append(errs, fmt.Sprintf("failed %s create: %s", app.rollbacker.resource, err.Error()))
+			// a cancelled or expired context aborts the apply, the caller
+			// receives the context error rather than the aggregated errors.
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			if aErr, ok := IsApplyErr(err); ok {
+				applyErr.Resources = append(applyErr.Resources, aErr.Resources...)
+				continue
+			}
+			applyErr.Resources = append(applyErr.Resources, ApplyErrResource{
+				Kind: app.rollbacker.resource,
+				Err:  err,
+			})
 		}
 	}
 
-	if len(errs) > 0 {
-		// TODO: fix error up to be more actionable
-		return errors.New(strings.Join(errs, "\n"))
+	if len(applyErr.Resources) > 0 {
+		return &applyErr
 	}
 	return nil
 }
 
+// rollback undoes every applier that has run when the apply fails. The
+// appliers are undone in reverse, dependent resources like label mappings are
+// removed before the resources they depend on. The rollback is best effort,
+// any failures to roll back are added to the apply error.
 func (r *rollbackCoordinator) rollback(l *zap.Logger, err *error) {
 	if *err == nil {
 		return
 	}
 
-	for _, r := range r.rollbacks {
-		if err := r.fn(); err != nil {
-			l.Error("failed to delete "+r.resource, zap.Error(err))
+	var errs []error
+	for i := len(r.rollbacks) - 1; i >= 0; i-- {
+		rb := r.rollbacks[i]
+		if rbErr := rb.fn(); rbErr != nil {
+			l.Error("failed to delete "+rb.resource, zap.Error(rbErr))
+			errs = append(errs, fmt.Errorf("failed %s rollback: %s", rb.resource, rbErr.Error()))
 		}
 	}
+	if len(errs) == 0 {
+		return
+	}
+
+	if aErr, ok := IsApplyErr(*err); ok {
+		aErr.RollbackErrs = append(aErr.RollbackErrs, errs...)
+		return
+	}
+
+	errMsgs := []string{(*err).Error()}
+	for _, e := range errs {
+		errMsgs = append(errMsgs, e.Error())
+	}
+	*err = errors.New(strings.Join(errMsgs, "\n"))
 }
 
-// TODO: clean up apply errors to inform the user in an actionable way
 type applyErrBody struct {
 	name string
-	msg  string
+	err  error
 }
 
 type applyErrs []applyErrBody
 
-func (a applyErrs) toError(resType, msg string) error {
+func (a applyErrs) toError(resType string) error {
 	if len(a) == 0 {
 		return nil
 	}
-	errMsg := fmt.Sprintf(`resource_type=%q err=%q`, resType, msg)
+
+	var err ApplyErr
 	for _, e := range a {
-		errMsg += fmt.Sprintf("\n\tname=%q err_msg=%q", e.name, e.msg)
+		err.Resources = append(err.Resources, ApplyErrResource{
+			Kind: resType,
+			Name: e.name,
+			Err:  e.err,
+		})
 	}
-	return errors.New(errMsg)
+	return &err
+}
+
+// ApplyErr is the error returned when resources of a pkg fail to be applied.
+// It provides every resource that failed, not only the first, along with the
+// cause of each failure. Any failures to roll back the resources applied
+// before the failure are provided as well.
+type ApplyErr struct {
+	Resources    []ApplyErrResource
+	RollbackErrs []error
+}
+
+// ApplyErrResource is a resource of a pkg that failed to be applied.
+type ApplyErrResource struct {
+	Kind string
+	Name string
+	Err  error
+}
+
+// Error implements the error interface.
+func (e *ApplyErr) Error() string {
+	var errMsg []string
+	for _, r := range e.Resources {
+		errMsg = append(errMsg, fmt.Sprintf("resource_type=%q name=%q err_msg=%q", r.Kind, r.Name, r.Err.Error()))
+	}
+	for _, err := range e.RollbackErrs {
+		errMsg = append(errMsg, err.Error())
+	}
+	return strings.Join(errMsg, "\n")
+}
+
+// IsApplyErr inspects a given error to determine if it is an ApplyErr.
+func IsApplyErr(err error) (*ApplyErr, bool) {
+	aErr, ok := err.(*ApplyErr)
+	return aErr, ok
+}
+
+// DeleteErr is the error returned when resources a replace or prune deletes
+// fail to be deleted. The resources of the pkg are applied by the time the
+// deletions run and are not rolled back, only the failed deletions are
+// provided.
+type DeleteErr struct {
+	Resources []ApplyErrResource
+}
+
+// Error implements the error interface.
+func (e *DeleteErr) Error() string {
+	var errMsg []string
+	for _, r := range e.Resources {
+		errMsg = append(errMsg, fmt.Sprintf("failed to delete resource_type=%q name=%q err_msg=%q", r.Kind, r.Name, r.Err.Error()))
+	}
+	return strings.Join(errMsg, "\n")
+}
+
+// IsDeleteErr inspects a given error to determine if it is a DeleteErr.
+func IsDeleteErr(err error) (*DeleteErr, bool) {
+	dErr, ok := err.(*DeleteErr)
+	return dErr, ok
 }
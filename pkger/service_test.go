@@ -1,13 +1,20 @@
 package pkger
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"testing"
 	"time"
 
 	"github.com/influxdata/influxdb"
+	pctx "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/inmem"
+	"github.com/influxdata/influxdb/kv"
 	"github.com/influxdata/influxdb/mock"
+	icheck "github.com/influxdata/influxdb/notification/check"
+	"github.com/influxdata/influxdb/notification/endpoint"
+	"github.com/influxdata/influxdb/notification/rule"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -35,12 +42,14 @@ func TestService(t *testing.T) {
 					require.Len(t, diff.Buckets, 1)
 
 					expected := DiffBucket{
-						ID:           SafeID(1),
-						Name:         "rucket_11",
-						OldDesc:      "old desc",
-						NewDesc:      "bucket 1 description",
-						OldRetention: 30 * time.Hour,
-						NewRetention: time.Hour,
+						ID:            SafeID(1),
+						Name:          "rucket_11",
+						OldDesc:       "old desc",
+						NewDesc:       "bucket 1 description",
+						OldRetention:  30 * time.Hour,
+						NewRetention:  time.Hour,
+						OldSchemaType: influxdb.SchemaTypeImplicit,
+						NewSchemaType: influxdb.SchemaTypeImplicit,
 					}
 					assert.Equal(t, expected, diff.Buckets[0])
 				})
@@ -60,13 +69,189 @@ func TestService(t *testing.T) {
 					require.Len(t, diff.Buckets, 1)
 
 					expected := DiffBucket{
-						Name:         "rucket_11",
-						NewDesc:      "bucket 1 description",
-						NewRetention: time.Hour,
+						Name:          "rucket_11",
+						NewDesc:       "bucket 1 description",
+						NewRetention:  time.Hour,
+						NewSchemaType: influxdb.SchemaTypeImplicit,
 					}
 					assert.Equal(t, expected, diff.Buckets[0])
 				})
 			})
+
+			t.Run("rejects a schema type change of an existing bucket", func(t *testing.T) {
+				testfileRunner(t, "testdata/bucket_schema_type", func(t *testing.T, pkg *Pkg) {
+					fakeBktSVC := mock.NewBucketService()
+					fakeBktSVC.FindBucketByNameFn = func(_ context.Context, orgID influxdb.ID, name string) (*influxdb.Bucket, error) {
+						if name != "rucket_explicit" {
+							return nil, errors.New("not found")
+						}
+						return &influxdb.Bucket{
+							ID:              influxdb.ID(1),
+							OrgID:           orgID,
+							Name:            name,
+							RetentionPeriod: time.Hour,
+						}, nil
+					}
+					svc := NewService(WithBucketSVC(fakeBktSVC), WithLabelSVC(mock.NewLabelService()))
+
+					_, _, err := svc.DryRun(context.TODO(), influxdb.ID(100), pkg)
+					require.Error(t, err)
+					assert.Equal(t, influxdb.EInvalid, influxdb.ErrorCode(err))
+
+					_, err = svc.Apply(context.TODO(), influxdb.ID(100), pkg)
+					require.Error(t, err)
+				})
+			})
+
+			t.Run("existing bucket without a schema type is implicit", func(t *testing.T) {
+				testfileRunner(t, "testdata/bucket_schema_type", func(t *testing.T, pkg *Pkg) {
+					fakeBktSVC := mock.NewBucketService()
+					fakeBktSVC.FindBucketByNameFn = func(_ context.Context, orgID influxdb.ID, name string) (*influxdb.Bucket, error) {
+						if name != "rucket_implicit" {
+							return nil, errors.New("not found")
+						}
+						return &influxdb.Bucket{
+							ID:              influxdb.ID(1),
+							OrgID:           orgID,
+							Name:            name,
+							RetentionPeriod: time.Hour,
+						}, nil
+					}
+					svc := NewService(WithBucketSVC(fakeBktSVC), WithLabelSVC(mock.NewLabelService()))
+
+					_, diff, err := svc.DryRun(context.TODO(), influxdb.ID(100), pkg)
+					require.NoError(t, err)
+
+					for _, b := range diff.Buckets {
+						if b.Name == "rucket_implicit" {
+							assert.False(t, b.HasChanges())
+						}
+					}
+				})
+			})
+
+			t.Run("shard group durations diffed", func(t *testing.T) {
+				testfileRunner(t, "testdata/bucket_shard_group_duration", func(t *testing.T, pkg *Pkg) {
+					fakeBktSVC := mock.NewBucketService()
+					fakeBktSVC.FindBucketByNameFn = func(_ context.Context, orgID influxdb.ID, name string) (*influxdb.Bucket, error) {
+						return &influxdb.Bucket{
+							ID:                 influxdb.ID(1),
+							OrgID:              orgID,
+							Name:               name,
+							RetentionPeriod:    24 * time.Hour,
+							ShardGroupDuration: 2 * time.Hour,
+						}, nil
+					}
+					svc := NewService(WithBucketSVC(fakeBktSVC), WithLabelSVC(mock.NewLabelService()))
+
+					_, diff, err := svc.DryRun(context.TODO(), influxdb.ID(100), pkg)
+					require.NoError(t, err)
+
+					require.Len(t, diff.Buckets, 3)
+
+					expected := map[string][2]time.Duration{
+						"rucket_infinite": {2 * time.Hour, 7 * 24 * time.Hour},
+						"rucket_sharded":  {2 * time.Hour, time.Hour},
+						// unset leaves the existing shard group duration in place
+						"rucket_unset": {2 * time.Hour, 2 * time.Hour},
+					}
+					for _, b := range diff.Buckets {
+						assert.Equal(t, expected[b.Name], [2]time.Duration{b.OldShardDur, b.NewShardDur}, b.Name)
+					}
+				})
+			})
+
+			t.Run("bucket is found by its pkg id before its name", func(t *testing.T) {
+				testfileRunner(t, "testdata/bucket_with_id", func(t *testing.T, pkg *Pkg) {
+					fakeBktSVC := mock.NewBucketService()
+					fakeBktSVC.FindBucketByIDFn = func(_ context.Context, id influxdb.ID) (*influxdb.Bucket, error) {
+						return &influxdb.Bucket{
+							ID:              id,
+							OrgID:           influxdb.ID(100),
+							Name:            "rucket_old",
+							RetentionPeriod: time.Hour,
+						}, nil
+					}
+					fakeBktSVC.FindBucketByNameFn = func(_ context.Context, orgID influxdb.ID, name string) (*influxdb.Bucket, error) {
+						return nil, &influxdb.Error{Code: influxdb.ENotFound}
+					}
+					var updatedName *string
+					fakeBktSVC.UpdateBucketFn = func(_ context.Context, id influxdb.ID, upd influxdb.BucketUpdate) (*influxdb.Bucket, error) {
+						updatedName = upd.Name
+						return &influxdb.Bucket{ID: id, Name: *upd.Name}, nil
+					}
+					svc := NewService(WithBucketSVC(fakeBktSVC), WithLabelSVC(mock.NewLabelService()))
+
+					_, diff, err := svc.DryRun(context.TODO(), influxdb.ID(100), pkg)
+					require.NoError(t, err)
+
+					require.Len(t, diff.Buckets, 1)
+					assert.Equal(t, SafeID(1), diff.Buckets[0].ID)
+
+					_, err = svc.Apply(context.TODO(), influxdb.ID(100), pkg)
+					require.NoError(t, err)
+
+					require.NotNil(t, updatedName)
+					assert.Equal(t, "rucket_renamed", *updatedName)
+				})
+			})
+		})
+
+		t.Run("checks", func(t *testing.T) {
+			t.Run("existing check is diffed against the pkg check", func(t *testing.T) {
+				testfileRunner(t, "testdata/checks", func(t *testing.T, pkg *Pkg) {
+					existing := &icheck.Deadman{
+						Base: icheck.Base{ID: influxdb.ID(1), Name: "check_1", Description: "old desc"},
+					}
+					fakeCheckSVC := mock.NewCheckService()
+					fakeCheckSVC.FindCheckFn = func(_ context.Context, f influxdb.CheckFilter) (influxdb.Check, error) {
+						if *f.Name != "check_1" {
+							return nil, &influxdb.Error{Code: influxdb.ENotFound}
+						}
+						return existing, nil
+					}
+					svc := NewService(WithCheckSVC(fakeCheckSVC), WithLabelSVC(mock.NewLabelService()))
+
+					_, diff, err := svc.DryRun(context.TODO(), influxdb.ID(100), pkg)
+					require.NoError(t, err)
+
+					require.Len(t, diff.Checks, 1)
+					actual := diff.Checks[0]
+					assert.Equal(t, SafeID(1), actual.ID)
+					assert.Equal(t, "check_1", actual.Name)
+					assert.Equal(t, existing, actual.Old)
+					require.NotNil(t, actual.New)
+					assert.Equal(t, "desc_1", actual.New.GetDescription())
+					assert.True(t, actual.HasChanges())
+				})
+			})
+
+			t.Run("check not found is new", func(t *testing.T) {
+				testfileRunner(t, "testdata/checks", func(t *testing.T, pkg *Pkg) {
+					fakeCheckSVC := mock.NewCheckService()
+					fakeCheckSVC.FindCheckFn = func(_ context.Context, f influxdb.CheckFilter) (influxdb.Check, error) {
+						return nil, &influxdb.Error{Code: influxdb.ENotFound}
+					}
+					svc := NewService(WithCheckSVC(fakeCheckSVC), WithLabelSVC(mock.NewLabelService()))
+
+					_, diff, err := svc.DryRun(context.TODO(), influxdb.ID(100), pkg)
+					require.NoError(t, err)
+
+					require.Len(t, diff.Checks, 1)
+					assert.True(t, diff.Checks[0].IsNew())
+					assert.Nil(t, diff.Checks[0].Old)
+				})
+			})
+
+			t.Run("errors when the check service is not configured", func(t *testing.T) {
+				testfileRunner(t, "testdata/checks", func(t *testing.T, pkg *Pkg) {
+					svc := NewService(WithLabelSVC(mock.NewLabelService()))
+
+					_, _, err := svc.DryRun(context.TODO(), influxdb.ID(100), pkg)
+					require.Error(t, err)
+					assert.Equal(t, errCheckSVCNotConfigured, err)
+				})
+			})
 		})
 
 		t.Run("labels", func(t *testing.T) {
@@ -135,6 +320,60 @@ func TestService(t *testing.T) {
 					assert.Equal(t, expected, diff.Labels[1])
 				})
 			})
+
+			t.Run("named color of an existing label with its hex color is unchanged", func(t *testing.T) {
+				pkg, err := Parse(EncodingYAML, FromString(`apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName:      pkg_name
+  pkgVersion:   1
+spec:
+  resources:
+    - kind: Label
+      name: label_1
+      color: red
+`))
+				require.NoError(t, err)
+
+				fakeLabelSVC := mock.NewLabelService()
+				fakeLabelSVC.FindLabelsFn = func(_ context.Context, filter influxdb.LabelFilter) ([]*influxdb.Label, error) {
+					return []*influxdb.Label{
+						{
+							ID:         influxdb.ID(1),
+							Name:       filter.Name,
+							Properties: map[string]string{"color": "#ff0000"},
+						},
+					}, nil
+				}
+				svc := NewService(WithLabelSVC(fakeLabelSVC))
+
+				_, diff, err := svc.DryRun(context.TODO(), influxdb.ID(100), pkg)
+				require.NoError(t, err)
+
+				require.Len(t, diff.Labels, 1)
+				assert.Equal(t, "#ff0000", diff.Labels[0].NewColor)
+				assert.False(t, diff.Labels[0].HasChanges())
+			})
+		})
+
+		t.Run("returns the context error when the context is cancelled", func(t *testing.T) {
+			testfileRunner(t, "testdata/bucket", func(t *testing.T, pkg *Pkg) {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+
+				fakeBktSVC := mock.NewBucketService()
+				var findCallCount int
+				fakeBktSVC.FindBucketByNameFn = func(_ context.Context, id influxdb.ID, s string) (*influxdb.Bucket, error) {
+					findCallCount++
+					return nil, errors.New("not found")
+				}
+				svc := NewService(WithBucketSVC(fakeBktSVC))
+
+				_, _, err := svc.DryRun(ctx, influxdb.ID(100), pkg)
+				require.Error(t, err)
+				assert.Equal(t, context.Canceled, err)
+				assert.Zero(t, findCallCount)
+			})
 		})
 
 		t.Run("variables", func(t *testing.T) {
@@ -188,6 +427,35 @@ func TestService(t *testing.T) {
 	})
 
 	t.Run("Apply", func(t *testing.T) {
+		t.Run("applies resources after the resources they dependsOn", func(t *testing.T) {
+			testfileRunner(t, "testdata/depends_on", func(t *testing.T, pkg *Pkg) {
+				var created []string
+				fakeBktSVC := mock.NewBucketService()
+				fakeBktSVC.FindBucketByNameFn = func(_ context.Context, id influxdb.ID, s string) (*influxdb.Bucket, error) {
+					return nil, errors.New("not found")
+				}
+				fakeBktSVC.CreateBucketFn = func(_ context.Context, b *influxdb.Bucket) error {
+					created = append(created, "bucket/"+b.Name)
+					b.ID = influxdb.ID(len(created))
+					return nil
+				}
+				fakeLabelSVC := mock.NewLabelService()
+				fakeLabelSVC.CreateLabelFn = func(_ context.Context, l *influxdb.Label) error {
+					created = append(created, "label/"+l.Name)
+					l.ID = influxdb.ID(len(created))
+					return nil
+				}
+
+				svc := NewService(WithBucketSVC(fakeBktSVC), WithLabelSVC(fakeLabelSVC))
+
+				_, err := svc.Apply(context.TODO(), influxdb.ID(9000), pkg)
+				require.NoError(t, err)
+
+				expected := []string{"bucket/rucket_1", "label/label_1", "bucket/rucket_2"}
+				assert.Equal(t, expected, created)
+			})
+		})
+
 		t.Run("buckets", func(t *testing.T) {
 			t.Run("successfully creates pkg of buckets", func(t *testing.T) {
 				testfileRunner(t, "testdata/bucket.yml", func(t *testing.T, pkg *Pkg) {
@@ -221,6 +489,32 @@ func TestService(t *testing.T) {
 				})
 			})
 
+			t.Run("creates buckets with their schema type", func(t *testing.T) {
+				testfileRunner(t, "testdata/bucket_schema_type", func(t *testing.T, pkg *Pkg) {
+					created := make(map[string]influxdb.SchemaType)
+					fakeBktSVC := mock.NewBucketService()
+					fakeBktSVC.CreateBucketFn = func(_ context.Context, b *influxdb.Bucket) error {
+						created[b.Name] = b.SchemaType
+						return nil
+					}
+					fakeBktSVC.FindBucketByNameFn = func(_ context.Context, id influxdb.ID, s string) (*influxdb.Bucket, error) {
+						return nil, errors.New("not found")
+					}
+
+					svc := NewService(WithBucketSVC(fakeBktSVC))
+
+					_, err := svc.Apply(context.TODO(), influxdb.ID(9000), pkg)
+					require.NoError(t, err)
+
+					expected := map[string]influxdb.SchemaType{
+						"rucket_explicit": influxdb.SchemaTypeExplicit,
+						"rucket_implicit": influxdb.SchemaTypeImplicit,
+						"rucket_unset":    influxdb.SchemaTypeImplicit,
+					}
+					assert.Equal(t, expected, created)
+				})
+			})
+
 			t.Run("will not apply bucket if no changes to be applied", func(t *testing.T) {
 				testfileRunner(t, "testdata/bucket", func(t *testing.T, pkg *Pkg) {
 					orgID := influxdb.ID(9000)
@@ -275,7 +569,7 @@ func TestService(t *testing.T) {
 					var c int
 					fakeBktSVC.CreateBucketFn = func(_ context.Context, b *influxdb.Bucket) error {
 						if c == 2 {
-							return errors.New("blowed up ")
+							return errors.New("blowed up")
 						}
 						c++
 						return nil
@@ -299,106 +593,383 @@ func TestService(t *testing.T) {
 					assert.Equal(t, 2, count)
 				})
 			})
-		})
 
-		t.Run("labels", func(t *testing.T) {
-			t.Run("successfully creates pkg of labels", func(t *testing.T) {
-				testfileRunner(t, "testdata/label", func(t *testing.T, pkg *Pkg) {
-					fakeLabelSVC := mock.NewLabelService()
-					id := 1
-					fakeLabelSVC.CreateLabelFn = func(_ context.Context, l *influxdb.Label) error {
-						l.ID = influxdb.ID(id)
-						id++
-						return nil
-					}
+			t.Run("restores the prior existing bucket on an error", func(t *testing.T) {
+				testfileRunner(t, "testdata/bucket", func(t *testing.T, pkg *Pkg) {
+					orgID := influxdb.ID(9000)
 
-					svc := NewService(WithLabelSVC(fakeLabelSVC))
+					pkg.isVerified = true
+					pkgBkt := pkg.mBuckets["rucket_11"]
+					newBkt := *pkgBkt
+					newBkt.Name = "copybuck"
+					pkg.mBuckets[newBkt.Name] = &newBkt
 
-					orgID := influxdb.ID(9000)
+					pkgBkt.existing = &influxdb.Bucket{
+						ID:                 influxdb.ID(3),
+						OrgID:              orgID,
+						Name:               "old_rucket",
+						Description:        "old description",
+						RetentionPeriod:    2 * time.Hour,
+						ShardGroupDuration: time.Hour,
+					}
 
-					sum, err := svc.Apply(context.TODO(), orgID, pkg)
-					require.NoError(t, err)
+					fakeBktSVC := mock.NewBucketService()
+					fakeBktSVC.CreateBucketFn = func(_ context.Context, b *influxdb.Bucket) error {
+						return errors.New("blowed up")
+					}
+					var updates []influxdb.BucketUpdate
+					fakeBktSVC.UpdateBucketFn = func(_ context.Context, id influxdb.ID, upd influxdb.BucketUpdate) (*influxdb.Bucket, error) {
+						updates = append(updates, upd)
+						return &influxdb.Bucket{ID: id}, nil
+					}
 
-					require.Len(t, sum.Labels, 2)
-					label1 := sum.Labels[0]
-					assert.Equal(t, influxdb.ID(1), label1.ID)
-					assert.Equal(t, orgID, label1.OrgID)
-					assert.Equal(t, "label_1", label1.Name)
-					assert.Equal(t, "#FFFFFF", label1.Properties["color"])
-					assert.Equal(t, "label 1 description", label1.Properties["description"])
+					svc := NewService(WithBucketSVC(fakeBktSVC))
 
-					label2 := sum.Labels[1]
-					assert.Equal(t, influxdb.ID(2), label2.ID)
-					assert.Equal(t, orgID, label2.OrgID)
-					assert.Equal(t, "label_2", label2.Name)
-					assert.Equal(t, "#000000", label2.Properties["color"])
-					assert.Equal(t, "label 2 description", label2.Properties["description"])
+					_, err := svc.Apply(context.TODO(), orgID, pkg)
+					require.Error(t, err)
+
+					require.Len(t, updates, 2)
+					restored := updates[1]
+					require.NotNil(t, restored.Name)
+					assert.Equal(t, "old_rucket", *restored.Name)
+					require.NotNil(t, restored.Description)
+					assert.Equal(t, "old description", *restored.Description)
+					require.NotNil(t, restored.RetentionPeriod)
+					assert.Equal(t, 2*time.Hour, *restored.RetentionPeriod)
+					require.NotNil(t, restored.ShardGroupDuration)
+					assert.Equal(t, time.Hour, *restored.ShardGroupDuration)
 				})
 			})
 
-			t.Run("rolls back all created labels on an error", func(t *testing.T) {
-				testfileRunner(t, "testdata/label", func(t *testing.T, pkg *Pkg) {
+			t.Run("rolls back label mappings before the resources they map and reports rollback failures", func(t *testing.T) {
+				testfileRunner(t, "testdata/bucket_associates_label", func(t *testing.T, pkg *Pkg) {
+					var deleted []string
+
+					fakeBktSVC := mock.NewBucketService()
+					fakeBktSVC.FindBucketByNameFn = func(_ context.Context, id influxdb.ID, s string) (*influxdb.Bucket, error) {
+						// forces the bucket to be created a new
+						return nil, errors.New("an error")
+					}
+					var bktID influxdb.ID = 100
+					fakeBktSVC.CreateBucketFn = func(_ context.Context, b *influxdb.Bucket) error {
+						bktID++
+						b.ID = bktID
+						return nil
+					}
+					fakeBktSVC.DeleteBucketFn = func(_ context.Context, id influxdb.ID) error {
+						deleted = append(deleted, "bucket")
+						return errors.New("bucket delete blowed up")
+					}
+
 					fakeLabelSVC := mock.NewLabelService()
-					var c int
+					var labelID influxdb.ID
 					fakeLabelSVC.CreateLabelFn = func(_ context.Context, l *influxdb.Label) error {
-						// 4th label will return the error here, and 3 before should be rolled back
-						if c == 3 {
-							return errors.New("blowed up ")
-						}
-						c++
+						labelID++
+						l.ID = labelID
 						return nil
 					}
-					var count int
 					fakeLabelSVC.DeleteLabelFn = func(_ context.Context, id influxdb.ID) error {
-						count++
+						deleted = append(deleted, "label")
+						return nil
+					}
+					var mappingCalls int
+					fakeLabelSVC.CreateLabelMappingFn = func(_ context.Context, m *influxdb.LabelMapping) error {
+						mappingCalls++
+						if mappingCalls == 3 {
+							return errors.New("blowed up")
+						}
+						return nil
+					}
+					fakeLabelSVC.DeleteLabelMappingFn = func(_ context.Context, m *influxdb.LabelMapping) error {
+						deleted = append(deleted, "label_mapping")
 						return nil
 					}
 
-					pkg.mLabels["copy1"] = pkg.mLabels["label_1"]
-					pkg.mLabels["copy2"] = pkg.mLabels["label_2"]
-
-					svc := NewService(WithLabelSVC(fakeLabelSVC))
-
-					orgID := influxdb.ID(9000)
+					svc := NewService(WithBucketSVC(fakeBktSVC), WithLabelSVC(fakeLabelSVC))
 
-					_, err := svc.Apply(context.TODO(), orgID, pkg)
+					_, err := svc.Apply(context.TODO(), influxdb.ID(9000), pkg)
 					require.Error(t, err)
+					assert.Contains(t, err.Error(), "blowed up ")
+					assert.Contains(t, err.Error(), "failed bucket rollback")
 
-					assert.Equal(t, 3, count)
+					require.Len(t, deleted, 8)
+					assert.Equal(t, []string{"label_mapping", "label_mapping", "label_mapping"}, deleted[:3])
+					for _, resource := range deleted[3:] {
+						assert.NotEqual(t, "label_mapping", resource)
+					}
 				})
 			})
 
-			t.Run("will not apply label if no changes to be applied", func(t *testing.T) {
-				testfileRunner(t, "testdata/label", func(t *testing.T, pkg *Pkg) {
-					orgID := influxdb.ID(9000)
-
-					pkg.isVerified = true
-					pkgLabel := pkg.mLabels["label_1"]
-					pkgLabel.existing = &influxdb.Label{
-						// makes all pkg changes same as they are on the existing
-						ID:    influxdb.ID(1),
-						OrgID: orgID,
-						Name:  pkgLabel.Name,
-						Properties: map[string]string{
-							"color":       pkgLabel.Color,
-							"description": pkgLabel.Description,
-						},
+			t.Run("aggregates every resource that fails into the apply error", func(t *testing.T) {
+				testfileRunner(t, "testdata/bucket_associates_label", func(t *testing.T, pkg *Pkg) {
+					fakeBktSVC := mock.NewBucketService()
+					fakeBktSVC.FindBucketByNameFn = func(_ context.Context, id influxdb.ID, s string) (*influxdb.Bucket, error) {
+						// forces the bucket to be created a new
+						return nil, errors.New("an error")
+					}
+					fakeBktSVC.CreateBucketFn = func(_ context.Context, b *influxdb.Bucket) error {
+						if b.Name == "rucket_3" {
+							return errors.New("bucket blowed up")
+						}
+						b.ID = influxdb.ID(1)
+						return nil
+					}
+					fakeBktSVC.DeleteBucketFn = func(_ context.Context, id influxdb.ID) error {
+						return nil
 					}
 
 					fakeLabelSVC := mock.NewLabelService()
-					var createCallCount int
 					fakeLabelSVC.CreateLabelFn = func(_ context.Context, l *influxdb.Label) error {
-						createCallCount++
 						if l.Name == "label_2" {
-							l.ID = influxdb.ID(2)
-							return nil
+							return errors.New("label blowed up")
 						}
+						l.ID = influxdb.ID(2)
 						return nil
 					}
-					fakeLabelSVC.UpdateLabelFn = func(_ context.Context, id influxdb.ID, l influxdb.LabelUpdate) (*influxdb.Label, error) {
-						if id == influxdb.ID(3) {
-							return nil, errors.New("invalid id provided")
-						}
+					fakeLabelSVC.DeleteLabelFn = func(_ context.Context, id influxdb.ID) error {
+						return nil
+					}
+
+					svc := NewService(WithBucketSVC(fakeBktSVC), WithLabelSVC(fakeLabelSVC))
+
+					_, err := svc.Apply(context.TODO(), influxdb.ID(9000), pkg)
+					require.Error(t, err)
+
+					aErr, ok := IsApplyErr(err)
+					require.True(t, ok)
+					require.Len(t, aErr.Resources, 2)
+
+					assert.Equal(t, "label", aErr.Resources[0].Kind)
+					assert.Equal(t, "label_2", aErr.Resources[0].Name)
+					assert.EqualError(t, aErr.Resources[0].Err, "label blowed up")
+
+					assert.Equal(t, "bucket", aErr.Resources[1].Kind)
+					assert.Equal(t, "rucket_3", aErr.Resources[1].Name)
+					assert.EqualError(t, aErr.Resources[1].Err, "bucket blowed up")
+					assert.Empty(t, aErr.RollbackErrs)
+				})
+			})
+
+			t.Run("attributes an org resolution failure to the bucket being created", func(t *testing.T) {
+				testfileRunner(t, "testdata/bucket", func(t *testing.T, pkg *Pkg) {
+					// the kv bucket service resolves the org of every bucket it creates,
+					// the org is never created so the resolution fails.
+					kvSVC := kv.NewService(inmem.NewKVStore())
+					require.NoError(t, kvSVC.Initialize(context.TODO()))
+
+					svc := NewService(WithBucketSVC(kvSVC))
+
+					_, err := svc.Apply(context.TODO(), influxdb.ID(9000), pkg)
+					require.Error(t, err)
+
+					aErr, ok := IsApplyErr(err)
+					require.True(t, ok)
+					require.Len(t, aErr.Resources, 1)
+
+					assert.Equal(t, "bucket", aErr.Resources[0].Kind)
+					assert.Equal(t, "rucket_11", aErr.Resources[0].Name)
+					assert.Equal(t, influxdb.ENotFound, influxdb.ErrorCode(aErr.Resources[0].Err))
+					assert.Contains(t, err.Error(), `name="rucket_11"`)
+				})
+			})
+
+			t.Run("rolls back created buckets when the context is cancelled mid apply", func(t *testing.T) {
+				testfileRunner(t, "testdata/bucket", func(t *testing.T, pkg *Pkg) {
+					ctx, cancel := context.WithCancel(context.Background())
+					defer cancel()
+
+					fakeBktSVC := mock.NewBucketService()
+					fakeBktSVC.FindBucketByNameFn = func(_ context.Context, id influxdb.ID, s string) (*influxdb.Bucket, error) {
+						// forces the bucket to be created a new
+						return nil, errors.New("an error")
+					}
+					var createCallCount int
+					fakeBktSVC.CreateBucketFn = func(_ context.Context, b *influxdb.Bucket) error {
+						createCallCount++
+						b.ID = influxdb.ID(createCallCount)
+						// simulates the caller giving up while the apply is underway
+						cancel()
+						return nil
+					}
+					var deletedIDs []influxdb.ID
+					fakeBktSVC.DeleteBucketFn = func(ctx context.Context, id influxdb.ID) error {
+						if err := ctx.Err(); err != nil {
+							return err
+						}
+						deletedIDs = append(deletedIDs, id)
+						return nil
+					}
+
+					pkg.mBuckets["copybuck1"] = &bucket{Name: "copybuck1"}
+					pkg.mBuckets["copybuck2"] = &bucket{Name: "copybuck2"}
+
+					svc := NewService(WithBucketSVC(fakeBktSVC))
+
+					orgID := influxdb.ID(9000)
+
+					_, err := svc.DryRun(ctx, orgID, pkg)
+					require.NoError(t, err)
+
+					_, err = svc.Apply(ctx, orgID, pkg)
+					require.Error(t, err)
+					assert.Equal(t, context.Canceled, err)
+
+					assert.Equal(t, 1, createCallCount)
+					assert.Equal(t, []influxdb.ID{1}, deletedIDs)
+				})
+			})
+		})
+
+		t.Run("labels", func(t *testing.T) {
+			t.Run("successfully creates pkg of labels", func(t *testing.T) {
+				testfileRunner(t, "testdata/label", func(t *testing.T, pkg *Pkg) {
+					fakeLabelSVC := mock.NewLabelService()
+					id := 1
+					fakeLabelSVC.CreateLabelFn = func(_ context.Context, l *influxdb.Label) error {
+						l.ID = influxdb.ID(id)
+						id++
+						return nil
+					}
+
+					svc := NewService(WithLabelSVC(fakeLabelSVC))
+
+					orgID := influxdb.ID(9000)
+
+					sum, err := svc.Apply(context.TODO(), orgID, pkg)
+					require.NoError(t, err)
+
+					require.Len(t, sum.Labels, 2)
+					label1 := sum.Labels[0]
+					assert.Equal(t, influxdb.ID(1), label1.ID)
+					assert.Equal(t, orgID, label1.OrgID)
+					assert.Equal(t, "label_1", label1.Name)
+					assert.Equal(t, "#FFFFFF", label1.Properties["color"])
+					assert.Equal(t, "label 1 description", label1.Properties["description"])
+
+					label2 := sum.Labels[1]
+					assert.Equal(t, influxdb.ID(2), label2.ID)
+					assert.Equal(t, orgID, label2.OrgID)
+					assert.Equal(t, "label_2", label2.Name)
+					assert.Equal(t, "#000000", label2.Properties["color"])
+					assert.Equal(t, "label 2 description", label2.Properties["description"])
+				})
+			})
+
+			t.Run("rolls back all created labels on an error", func(t *testing.T) {
+				testfileRunner(t, "testdata/label", func(t *testing.T, pkg *Pkg) {
+					fakeLabelSVC := mock.NewLabelService()
+					var c int
+					fakeLabelSVC.CreateLabelFn = func(_ context.Context, l *influxdb.Label) error {
+						// 4th label will return the error here, and 3 before should be rolled back
+						if c == 3 {
+							return errors.New("blowed up")
+						}
+						c++
+						return nil
+					}
+					var count int
+					fakeLabelSVC.DeleteLabelFn = func(_ context.Context, id influxdb.ID) error {
+						count++
+						return nil
+					}
+
+					pkg.mLabels["copy1"] = pkg.mLabels["label_1"]
+					pkg.mLabels["copy2"] = pkg.mLabels["label_2"]
+
+					svc := NewService(WithLabelSVC(fakeLabelSVC))
+
+					orgID := influxdb.ID(9000)
+
+					_, err := svc.Apply(context.TODO(), orgID, pkg)
+					require.Error(t, err)
+
+					assert.Equal(t, 3, count)
+				})
+			})
+
+			t.Run("normalizes label colors", func(t *testing.T) {
+				newPkg := func(t *testing.T, color string) *Pkg {
+					t.Helper()
+
+					pkg, err := Parse(EncodingYAML, FromString(`apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName:      pkg_name
+  pkgVersion:   1
+spec:
+  resources:
+    - kind: Label
+      name: label_1
+      color: "`+color+`"
+`))
+					require.NoError(t, err)
+					return pkg
+				}
+
+				tests := []struct {
+					color    string
+					expected string
+				}{
+					{color: "red", expected: "#ff0000"},
+					{color: "Green", expected: "#008000"},
+					{color: "#abc", expected: "#aabbcc"},
+					{color: "#FFFFFF", expected: "#FFFFFF"},
+				}
+
+				for _, tt := range tests {
+					fn := func(t *testing.T) {
+						fakeLabelSVC := mock.NewLabelService()
+						var created influxdb.Label
+						fakeLabelSVC.CreateLabelFn = func(_ context.Context, l *influxdb.Label) error {
+							l.ID = influxdb.ID(1)
+							created = *l
+							return nil
+						}
+
+						svc := NewService(WithLabelSVC(fakeLabelSVC))
+
+						sum, err := svc.Apply(context.TODO(), influxdb.ID(9000), newPkg(t, tt.color))
+						require.NoError(t, err)
+
+						assert.Equal(t, tt.expected, created.Properties["color"])
+						require.Len(t, sum.Labels, 1)
+						assert.Equal(t, tt.expected, sum.Labels[0].Properties["color"])
+					}
+					t.Run(tt.color, fn)
+				}
+			})
+
+			t.Run("will not apply label if no changes to be applied", func(t *testing.T) {
+				testfileRunner(t, "testdata/label", func(t *testing.T, pkg *Pkg) {
+					orgID := influxdb.ID(9000)
+
+					pkg.isVerified = true
+					pkgLabel := pkg.mLabels["label_1"]
+					pkgLabel.existing = &influxdb.Label{
+						// makes all pkg changes same as they are on the existing
+						ID:    influxdb.ID(1),
+						OrgID: orgID,
+						Name:  pkgLabel.Name,
+						Properties: map[string]string{
+							"color":       pkgLabel.Color,
+							"description": pkgLabel.Description,
+						},
+					}
+
+					fakeLabelSVC := mock.NewLabelService()
+					var createCallCount int
+					fakeLabelSVC.CreateLabelFn = func(_ context.Context, l *influxdb.Label) error {
+						createCallCount++
+						if l.Name == "label_2" {
+							l.ID = influxdb.ID(2)
+							return nil
+						}
+						return nil
+					}
+					fakeLabelSVC.UpdateLabelFn = func(_ context.Context, id influxdb.ID, l influxdb.LabelUpdate) (*influxdb.Label, error) {
+						if id == influxdb.ID(3) {
+							return nil, errors.New("invalid id provided")
+						}
 						return &influxdb.Label{ID: id}, nil
 					}
 
@@ -466,7 +1037,7 @@ func TestService(t *testing.T) {
 					fakeDashSVC.CreateDashboardF = func(_ context.Context, d *influxdb.Dashboard) error {
 						// error out on second dashboard attempted
 						if c == 1 {
-							return errors.New("blowed up ")
+							return errors.New("blowed up")
 						}
 						c++
 						d.ID = influxdb.ID(c)
@@ -519,139 +1090,1273 @@ func TestService(t *testing.T) {
 						numLabelMappings++
 						return nil
 					}
-					fakeDashSVC := mock.NewDashboardService()
+					fakeDashSVC := mock.NewDashboardService()
+					svc := NewService(
+						WithBucketSVC(fakeBktSVC),
+						WithLabelSVC(fakeLabelSVC),
+						WithDashboardSVC(fakeDashSVC),
+					)
+
+					orgID := influxdb.ID(9000)
+
+					_, err := svc.Apply(context.TODO(), orgID, pkg)
+					require.NoError(t, err)
+
+					assert.Equal(t, 4, numLabelMappings)
+				})
+			})
+
+			t.Run("maps bucket labels with the bucket resource type and IDs", func(t *testing.T) {
+				testfileRunner(t, "testdata/bucket_associates_label", func(t *testing.T, pkg *Pkg) {
+					bktIDs := map[string]influxdb.ID{
+						"rucket_1": 11,
+						"rucket_2": 12,
+						"rucket_3": 13,
+					}
+					fakeBktSVC := mock.NewBucketService()
+					fakeBktSVC.CreateBucketFn = func(_ context.Context, b *influxdb.Bucket) error {
+						b.ID = bktIDs[b.Name]
+						return nil
+					}
+					fakeBktSVC.FindBucketByNameFn = func(_ context.Context, id influxdb.ID, s string) (*influxdb.Bucket, error) {
+						// forces the bucket to be created a new
+						return nil, errors.New("an error")
+					}
+
+					labelIDs := map[string]influxdb.ID{
+						"label_1": 1,
+						"label_2": 2,
+					}
+					fakeLabelSVC := mock.NewLabelService()
+					fakeLabelSVC.CreateLabelFn = func(_ context.Context, l *influxdb.Label) error {
+						l.ID = labelIDs[l.Name]
+						return nil
+					}
+					var mappings []influxdb.LabelMapping
+					fakeLabelSVC.CreateLabelMappingFn = func(_ context.Context, mapping *influxdb.LabelMapping) error {
+						mappings = append(mappings, *mapping)
+						return nil
+					}
+
+					svc := NewService(WithBucketSVC(fakeBktSVC), WithLabelSVC(fakeLabelSVC))
+
+					_, err := svc.Apply(context.TODO(), influxdb.ID(9000), pkg)
+					require.NoError(t, err)
+
+					newMapping := func(labelID, bktID influxdb.ID) influxdb.LabelMapping {
+						return influxdb.LabelMapping{
+							LabelID:      labelID,
+							ResourceID:   bktID,
+							ResourceType: influxdb.BucketsResourceType,
+						}
+					}
+					expected := []influxdb.LabelMapping{
+						newMapping(1, 11),
+						newMapping(2, 12),
+						newMapping(1, 13),
+						newMapping(2, 13),
+					}
+					assert.ElementsMatch(t, expected, mappings)
+				})
+			})
+		})
+
+		t.Run("variables", func(t *testing.T) {
+			t.Run("successfully creates pkg of variables", func(t *testing.T) {
+				testfileRunner(t, "testdata/variables.yml", func(t *testing.T, pkg *Pkg) {
+					fakeVarSVC := mock.NewVariableService()
+					id := 1
+					fakeVarSVC.CreateVariableF = func(_ context.Context, v *influxdb.Variable) error {
+						v.ID = influxdb.ID(id)
+						id++
+						return nil
+					}
+
+					svc := NewService(
+						WithLabelSVC(mock.NewLabelService()),
+						WithVariableSVC(fakeVarSVC),
+					)
+
+					orgID := influxdb.ID(9000)
+
+					sum, err := svc.Apply(context.TODO(), orgID, pkg)
+					require.NoError(t, err)
+
+					require.Len(t, sum.Variables, 4)
+					expected := sum.Variables[0]
+					assert.Equal(t, influxdb.ID(1), expected.ID)
+					assert.Equal(t, orgID, expected.OrganizationID)
+					assert.Equal(t, "var_const", expected.Name)
+					assert.Equal(t, "var_const desc", expected.Description)
+					require.NotNil(t, expected.Arguments)
+					assert.Equal(t, influxdb.VariableConstantValues{"first val"}, expected.Arguments.Values)
+
+					for i := 1; i < 3; i++ {
+						expected = sum.Variables[i]
+						assert.Equal(t, influxdb.ID(i+1), expected.ID)
+					}
+				})
+			})
+
+			t.Run("rolls back all created variables on an error", func(t *testing.T) {
+				testfileRunner(t, "testdata/variables.yml", func(t *testing.T, pkg *Pkg) {
+					fakeVarSVC := mock.NewVariableService()
+					var c int
+					fakeVarSVC.CreateVariableF = func(_ context.Context, l *influxdb.Variable) error {
+						// 4th variable will return the error here, and 3 before should be rolled back
+						if c == 3 {
+							return errors.New("blowed up")
+						}
+						c++
+						return nil
+					}
+					var count int
+					fakeVarSVC.DeleteVariableF = func(_ context.Context, id influxdb.ID) error {
+						count++
+						return nil
+					}
+
+					svc := NewService(
+						WithLabelSVC(mock.NewLabelService()),
+						WithVariableSVC(fakeVarSVC),
+					)
+
+					orgID := influxdb.ID(9000)
+
+					_, err := svc.Apply(context.TODO(), orgID, pkg)
+					require.Error(t, err)
+
+					assert.Equal(t, 3, count)
+				})
+			})
+
+			t.Run("will not apply variable if no changes to be applied", func(t *testing.T) {
+				testfileRunner(t, "testdata/variables.yml", func(t *testing.T, pkg *Pkg) {
+					orgID := influxdb.ID(9000)
+
+					pkg.isVerified = true
+					pkgLabel := pkg.mVariables["var_const"]
+					pkgLabel.existing = &influxdb.Variable{
+						// makes all pkg changes same as they are on the existing
+						ID:             influxdb.ID(1),
+						OrganizationID: orgID,
+						Name:           pkgLabel.Name,
+						Arguments: &influxdb.VariableArguments{
+							Type:   "constant",
+							Values: influxdb.VariableConstantValues{"first val"},
+						},
+					}
+
+					fakeVarSVC := mock.NewVariableService()
+					var createCallCount int
+					fakeVarSVC.CreateVariableF = func(_ context.Context, l *influxdb.Variable) error {
+						createCallCount++
+						if l.Name == "var_const" {
+							return errors.New("shouldn't get here")
+						}
+						return nil
+					}
+					fakeVarSVC.UpdateVariableF = func(_ context.Context, id influxdb.ID, v *influxdb.VariableUpdate) (*influxdb.Variable, error) {
+						if id > influxdb.ID(1) {
+							return nil, errors.New("this id should not be updated")
+						}
+						return &influxdb.Variable{ID: id}, nil
+					}
+
+					svc := NewService(
+						WithLabelSVC(mock.NewLabelService()),
+						WithVariableSVC(fakeVarSVC),
+					)
+
+					sum, err := svc.Apply(context.TODO(), orgID, pkg)
+					require.NoError(t, err)
+
+					require.Len(t, sum.Variables, 4)
+					expected := sum.Variables[0]
+					assert.Equal(t, influxdb.ID(1), expected.ID)
+					assert.Equal(t, "var_const", expected.Name)
+
+					assert.Equal(t, 3, createCallCount) // only called for last 3 labels
+				})
+			})
+		})
+
+		t.Run("checks", func(t *testing.T) {
+			t.Run("successfully creates checks owned by the user", func(t *testing.T) {
+				testfileRunner(t, "testdata/checks", func(t *testing.T, pkg *Pkg) {
+					fakeLabelSVC := mock.NewLabelService()
+					fakeLabelSVC.CreateLabelFn = func(_ context.Context, l *influxdb.Label) error {
+						l.ID = influxdb.ID(1)
+						return nil
+					}
+					var mappings []influxdb.LabelMapping
+					fakeLabelSVC.CreateLabelMappingFn = func(_ context.Context, mapping *influxdb.LabelMapping) error {
+						mappings = append(mappings, *mapping)
+						return nil
+					}
+
+					var ownerID influxdb.ID
+					fakeCheckSVC := mock.NewCheckService()
+					fakeCheckSVC.CreateCheckFn = func(_ context.Context, c influxdb.CheckCreate, userID influxdb.ID) error {
+						c.SetID(influxdb.ID(2))
+						ownerID = userID
+						return nil
+					}
+
+					svc := NewService(
+						WithCheckSVC(fakeCheckSVC),
+						WithLabelSVC(fakeLabelSVC),
+					)
+
+					orgID := influxdb.ID(9000)
+					ctx := pctx.SetAuthorizer(context.TODO(), &influxdb.Authorization{UserID: influxdb.ID(3)})
+
+					sum, err := svc.Apply(ctx, orgID, pkg)
+					require.NoError(t, err)
+
+					require.Len(t, sum.Checks, 1)
+					actual := sum.Checks[0].Check
+					assert.Equal(t, influxdb.ID(2), actual.GetID())
+					assert.Equal(t, orgID, actual.GetOrgID())
+					assert.Equal(t, "check_1", actual.GetName())
+					assert.Equal(t, "desc_1", actual.GetDescription())
+					assert.Equal(t, influxdb.ID(3), ownerID)
+
+					expectedMappings := []influxdb.LabelMapping{
+						{
+							LabelID:      influxdb.ID(1),
+							ResourceID:   influxdb.ID(2),
+							ResourceType: influxdb.ChecksResourceType,
+						},
+					}
+					assert.Equal(t, expectedMappings, mappings)
+				})
+			})
+
+			t.Run("rolls back all created checks on an error", func(t *testing.T) {
+				testfileRunner(t, "testdata/checks", func(t *testing.T, pkg *Pkg) {
+					var c int
+					fakeCheckSVC := mock.NewCheckService()
+					fakeCheckSVC.CreateCheckFn = func(_ context.Context, ch influxdb.CheckCreate, userID influxdb.ID) error {
+						// error out on second check attempted
+						if c == 1 {
+							return errors.New("blowed up")
+						}
+						c++
+						ch.SetID(influxdb.ID(c))
+						return nil
+					}
+					deletedChecks := make(map[influxdb.ID]bool)
+					fakeCheckSVC.DeleteCheckFn = func(_ context.Context, id influxdb.ID) error {
+						deletedChecks[id] = true
+						return nil
+					}
+
+					copied := *pkg.mChecks["check_1"]
+					copied.Name = "copy_1"
+					pkg.mChecks["copy_1"] = &copied
+
+					svc := NewService(
+						WithCheckSVC(fakeCheckSVC),
+						WithLabelSVC(mock.NewLabelService()),
+					)
+
+					ctx := pctx.SetAuthorizer(context.TODO(), &influxdb.Authorization{UserID: influxdb.ID(3)})
+
+					_, err := svc.Apply(ctx, influxdb.ID(9000), pkg)
+					require.Error(t, err)
+
+					assert.True(t, deletedChecks[influxdb.ID(1)])
+				})
+			})
+
+			t.Run("restores the status of an existing check on an error", func(t *testing.T) {
+				testfileRunner(t, "testdata/checks", func(t *testing.T, pkg *Pkg) {
+					pkg.isVerified = true
+					pkg.mChecks["check_1"].existing = &icheck.Threshold{
+						Base: icheck.Base{
+							ID:     influxdb.ID(1),
+							Name:   "check_1",
+							TaskID: influxdb.ID(7),
+						},
+					}
+
+					fakeCheckSVC := mock.NewCheckService()
+					fakeCheckSVC.CreateCheckFn = func(_ context.Context, ch influxdb.CheckCreate, userID influxdb.ID) error {
+						return errors.New("blowed up")
+					}
+					var statuses []influxdb.Status
+					fakeCheckSVC.UpdateCheckFn = func(_ context.Context, id influxdb.ID, ch influxdb.CheckCreate) (influxdb.Check, error) {
+						statuses = append(statuses, ch.Status)
+						return ch.Check, nil
+					}
+
+					fakeTaskSVC := &mock.TaskService{
+						FindTaskByIDFn: func(_ context.Context, id influxdb.ID) (*influxdb.Task, error) {
+							if id != influxdb.ID(7) {
+								return nil, errors.New("not found")
+							}
+							return &influxdb.Task{ID: id, Status: string(influxdb.Inactive)}, nil
+						},
+					}
+
+					svc := NewService(
+						WithCheckSVC(fakeCheckSVC),
+						WithLabelSVC(mock.NewLabelService()),
+						WithTaskSVC(fakeTaskSVC),
+					)
+
+					ctx := pctx.SetAuthorizer(context.TODO(), &influxdb.Authorization{UserID: influxdb.ID(3)})
+
+					_, err := svc.Apply(ctx, influxdb.ID(9000), pkg)
+					require.Error(t, err)
+
+					assert.Equal(t, []influxdb.Status{influxdb.Active, influxdb.Inactive}, statuses)
+				})
+			})
+
+			t.Run("errors updating an existing check when the task service is not configured", func(t *testing.T) {
+				testfileRunner(t, "testdata/checks", func(t *testing.T, pkg *Pkg) {
+					pkg.isVerified = true
+					pkg.mChecks["check_1"].existing = &icheck.Threshold{
+						Base: icheck.Base{ID: influxdb.ID(1), Name: "check_1"},
+					}
+
+					svc := NewService(
+						WithCheckSVC(mock.NewCheckService()),
+						WithLabelSVC(mock.NewLabelService()),
+					)
+
+					ctx := pctx.SetAuthorizer(context.TODO(), &influxdb.Authorization{UserID: influxdb.ID(3)})
+
+					_, err := svc.Apply(ctx, influxdb.ID(9000), pkg)
+					require.Error(t, err)
+					assert.Contains(t, err.Error(), errTaskSVCNotConfigured.Error())
+				})
+			})
+		})
+
+		t.Run("notification endpoints", func(t *testing.T) {
+			t.Run("successfully creates notification endpoints owned by the user", func(t *testing.T) {
+				testfileRunner(t, "testdata/notification_endpoint", func(t *testing.T, pkg *Pkg) {
+					fakeLabelSVC := mock.NewLabelService()
+					fakeLabelSVC.CreateLabelFn = func(_ context.Context, l *influxdb.Label) error {
+						l.ID = influxdb.ID(1)
+						return nil
+					}
+					var mappings []influxdb.LabelMapping
+					fakeLabelSVC.CreateLabelMappingFn = func(_ context.Context, mapping *influxdb.LabelMapping) error {
+						mappings = append(mappings, *mapping)
+						return nil
+					}
+
+					var (
+						c       int
+						ownerID influxdb.ID
+					)
+					fakeEndpointSVC := &mock.NotificationEndpointService{
+						CreateNotificationEndpointF: func(_ context.Context, e influxdb.NotificationEndpoint, userID influxdb.ID) error {
+							c++
+							e.SetID(influxdb.ID(c + 1))
+							ownerID = userID
+							return nil
+						},
+					}
+
+					svc := NewService(
+						WithNotificationEndpointSVC(fakeEndpointSVC),
+						WithLabelSVC(fakeLabelSVC),
+					)
+
+					orgID := influxdb.ID(9000)
+					ctx := pctx.SetAuthorizer(context.TODO(), &influxdb.Authorization{UserID: influxdb.ID(3)})
+
+					sum, err := svc.Apply(ctx, orgID, pkg)
+					require.NoError(t, err)
+
+					require.Len(t, sum.NotificationEndpoints, 5)
+					actual := sum.NotificationEndpoints[0].NotificationEndpoint
+					assert.True(t, actual.GetID().Valid())
+					assert.Equal(t, orgID, actual.GetOrgID())
+					assert.Equal(t, "http_basic_auth_notification_endpoint", actual.GetName())
+					assert.Equal(t, influxdb.ID(3), ownerID)
+
+					require.Len(t, mappings, 5)
+					for _, m := range mappings {
+						assert.Equal(t, influxdb.NotificationEndpointResourceType, m.ResourceType)
+						assert.True(t, m.ResourceID.Valid())
+					}
+				})
+			})
+
+			t.Run("rolls back all created notification endpoints on an error", func(t *testing.T) {
+				testfileRunner(t, "testdata/notification_endpoint", func(t *testing.T, pkg *Pkg) {
+					var c int
+					deletedEndpoints := make(map[influxdb.ID]bool)
+					fakeEndpointSVC := &mock.NotificationEndpointService{
+						CreateNotificationEndpointF: func(_ context.Context, e influxdb.NotificationEndpoint, userID influxdb.ID) error {
+							// error out on fifth endpoint attempted
+							if c == 4 {
+								return errors.New("blowed up")
+							}
+							c++
+							e.SetID(influxdb.ID(c))
+							return nil
+						},
+						DeleteNotificationEndpointF: func(_ context.Context, id influxdb.ID) ([]influxdb.SecretField, influxdb.ID, error) {
+							deletedEndpoints[id] = true
+							return nil, 0, nil
+						},
+					}
+
+					svc := NewService(
+						WithNotificationEndpointSVC(fakeEndpointSVC),
+						WithLabelSVC(mock.NewLabelService()),
+					)
+
+					ctx := pctx.SetAuthorizer(context.TODO(), &influxdb.Authorization{UserID: influxdb.ID(3)})
+
+					_, err := svc.Apply(ctx, influxdb.ID(9000), pkg)
+					require.Error(t, err)
+
+					for i := 1; i <= 4; i++ {
+						assert.True(t, deletedEndpoints[influxdb.ID(i)])
+					}
+				})
+			})
+		})
+
+		t.Run("notification rules", func(t *testing.T) {
+			t.Run("successfully creates notification rule against its endpoint", func(t *testing.T) {
+				testfileRunner(t, "testdata/notification_rule", func(t *testing.T, pkg *Pkg) {
+					fakeEndpointSVC := &mock.NotificationEndpointService{
+						CreateNotificationEndpointF: func(_ context.Context, e influxdb.NotificationEndpoint, userID influxdb.ID) error {
+							e.SetID(influxdb.ID(2))
+							return nil
+						},
+					}
+
+					var ownerID influxdb.ID
+					fakeRuleStore := &mock.NotificationRuleStore{
+						CreateNotificationRuleF: func(_ context.Context, nr influxdb.NotificationRuleCreate, userID influxdb.ID) error {
+							nr.SetID(influxdb.ID(3))
+							ownerID = userID
+							return nil
+						},
+					}
+
+					svc := NewService(
+						WithNotificationEndpointSVC(fakeEndpointSVC),
+						WithNotificationRuleSVC(fakeRuleStore),
+						WithLabelSVC(mock.NewLabelService()),
+					)
+
+					orgID := influxdb.ID(9000)
+					ctx := pctx.SetAuthorizer(context.TODO(), &influxdb.Authorization{UserID: influxdb.ID(4)})
+
+					sum, err := svc.Apply(ctx, orgID, pkg)
+					require.NoError(t, err)
+
+					require.Len(t, sum.NotificationRules, 1)
+					actual := sum.NotificationRules[0]
+					assert.Equal(t, influxdb.ID(3), actual.NotificationRule.GetID())
+					assert.Equal(t, influxdb.ID(2), actual.NotificationRule.GetEndpointID())
+					assert.Equal(t, orgID, actual.NotificationRule.GetOrgID())
+					assert.Equal(t, "rule_0", actual.NotificationRule.GetName())
+					assert.Equal(t, "endpoint_0", actual.EndpointName)
+					assert.Equal(t, influxdb.ID(4), ownerID)
+				})
+			})
+
+			t.Run("creates notification endpoint and rule for the apply user without an authorizer", func(t *testing.T) {
+				testfileRunner(t, "testdata/notification_rule", func(t *testing.T, pkg *Pkg) {
+					var endpointOwnerID influxdb.ID
+					fakeEndpointSVC := &mock.NotificationEndpointService{
+						CreateNotificationEndpointF: func(_ context.Context, e influxdb.NotificationEndpoint, userID influxdb.ID) error {
+							e.SetID(influxdb.ID(2))
+							endpointOwnerID = userID
+							return nil
+						},
+					}
+
+					var ruleOwnerID influxdb.ID
+					fakeRuleStore := &mock.NotificationRuleStore{
+						CreateNotificationRuleF: func(_ context.Context, nr influxdb.NotificationRuleCreate, userID influxdb.ID) error {
+							nr.SetID(influxdb.ID(3))
+							ruleOwnerID = userID
+							return nil
+						},
+					}
+
+					svc := NewService(
+						WithNotificationEndpointSVC(fakeEndpointSVC),
+						WithNotificationRuleSVC(fakeRuleStore),
+						WithLabelSVC(mock.NewLabelService()),
+					)
+
+					_, err := svc.Apply(context.TODO(), influxdb.ID(9000), pkg, WithApplyUserID(influxdb.ID(7)))
+					require.NoError(t, err)
+
+					assert.Equal(t, influxdb.ID(7), endpointOwnerID)
+					assert.Equal(t, influxdb.ID(7), ruleOwnerID)
+				})
+			})
+
+			t.Run("rolls back created notification rule on an error", func(t *testing.T) {
+				testfileRunner(t, "testdata/notification_rule", func(t *testing.T, pkg *Pkg) {
+					fakeEndpointSVC := &mock.NotificationEndpointService{
+						CreateNotificationEndpointF: func(_ context.Context, e influxdb.NotificationEndpoint, userID influxdb.ID) error {
+							e.SetID(influxdb.ID(2))
+							return nil
+						},
+						DeleteNotificationEndpointF: func(_ context.Context, id influxdb.ID) ([]influxdb.SecretField, influxdb.ID, error) {
+							return nil, 0, nil
+						},
+					}
+
+					var deletedRule influxdb.ID
+					fakeRuleStore := &mock.NotificationRuleStore{
+						CreateNotificationRuleF: func(_ context.Context, nr influxdb.NotificationRuleCreate, userID influxdb.ID) error {
+							nr.SetID(influxdb.ID(3))
+							return nil
+						},
+						DeleteNotificationRuleF: func(_ context.Context, id influxdb.ID) error {
+							deletedRule = id
+							return nil
+						},
+					}
+
+					fakeLabelSVC := mock.NewLabelService()
+					fakeLabelSVC.CreateLabelMappingFn = func(_ context.Context, _ *influxdb.LabelMapping) error {
+						return errors.New("blowed up")
+					}
+
+					svc := NewService(
+						WithNotificationEndpointSVC(fakeEndpointSVC),
+						WithNotificationRuleSVC(fakeRuleStore),
+						WithLabelSVC(fakeLabelSVC),
+					)
+
+					ctx := pctx.SetAuthorizer(context.TODO(), &influxdb.Authorization{UserID: influxdb.ID(4)})
+
+					_, err := svc.Apply(ctx, influxdb.ID(9000), pkg)
+					require.Error(t, err)
+
+					assert.Equal(t, influxdb.ID(3), deletedRule)
+				})
+			})
+		})
+
+		t.Run("telegrafs", func(t *testing.T) {
+			t.Run("successfully creates telegraf configs owned by the user", func(t *testing.T) {
+				testfileRunner(t, "testdata/telegraf", func(t *testing.T, pkg *Pkg) {
+					fakeLabelSVC := mock.NewLabelService()
+					fakeLabelSVC.CreateLabelFn = func(_ context.Context, l *influxdb.Label) error {
+						l.ID = influxdb.ID(1)
+						return nil
+					}
+					var mappings []influxdb.LabelMapping
+					fakeLabelSVC.CreateLabelMappingFn = func(_ context.Context, mapping *influxdb.LabelMapping) error {
+						mappings = append(mappings, *mapping)
+						return nil
+					}
+
+					var ownerID influxdb.ID
+					fakeTeleSVC := &mock.TelegrafConfigStore{
+						CreateTelegrafConfigF: func(_ context.Context, tc *influxdb.TelegrafConfig, userID influxdb.ID) error {
+							tc.ID = influxdb.ID(2)
+							ownerID = userID
+							return nil
+						},
+					}
+
+					svc := NewService(
+						WithLabelSVC(fakeLabelSVC),
+						WithTelegrafSVC(fakeTeleSVC),
+					)
+
+					orgID := influxdb.ID(9000)
+					ctx := pctx.SetAuthorizer(context.TODO(), &influxdb.Authorization{UserID: influxdb.ID(3)})
+
+					sum, err := svc.Apply(ctx, orgID, pkg)
+					require.NoError(t, err)
+
+					require.Len(t, sum.TelegrafConfigs, 1)
+					actual := sum.TelegrafConfigs[0].TelegrafConfig
+					assert.Equal(t, influxdb.ID(2), actual.ID)
+					assert.Equal(t, orgID, actual.OrgID)
+					assert.Equal(t, "first_tele_config", actual.Name)
+					assert.Equal(t, "desc", actual.Description)
+					assert.Equal(t, influxdb.ID(3), ownerID)
+
+					expectedMappings := []influxdb.LabelMapping{
+						{
+							LabelID:      influxdb.ID(1),
+							ResourceID:   influxdb.ID(2),
+							ResourceType: influxdb.TelegrafsResourceType,
+						},
+					}
+					assert.Equal(t, expectedMappings, mappings)
+				})
+			})
+
+			t.Run("creates telegraf configs for the apply user without an authorizer", func(t *testing.T) {
+				testfileRunner(t, "testdata/telegraf", func(t *testing.T, pkg *Pkg) {
+					var ownerID influxdb.ID
+					fakeTeleSVC := &mock.TelegrafConfigStore{
+						CreateTelegrafConfigF: func(_ context.Context, tc *influxdb.TelegrafConfig, userID influxdb.ID) error {
+							tc.ID = influxdb.ID(2)
+							ownerID = userID
+							return nil
+						},
+					}
+
+					svc := NewService(
+						WithLabelSVC(mock.NewLabelService()),
+						WithTelegrafSVC(fakeTeleSVC),
+					)
+
+					_, err := svc.Apply(context.TODO(), influxdb.ID(9000), pkg, WithApplyUserID(influxdb.ID(7)))
+					require.NoError(t, err)
+
+					assert.Equal(t, influxdb.ID(7), ownerID)
+				})
+			})
+
+			t.Run("errors creating telegraf configs without an authorizer or apply user", func(t *testing.T) {
+				testfileRunner(t, "testdata/telegraf", func(t *testing.T, pkg *Pkg) {
+					fakeTeleSVC := &mock.TelegrafConfigStore{
+						CreateTelegrafConfigF: func(_ context.Context, tc *influxdb.TelegrafConfig, userID influxdb.ID) error {
+							tc.ID = influxdb.ID(2)
+							return nil
+						},
+						DeleteTelegrafConfigF: func(_ context.Context, id influxdb.ID) error {
+							return nil
+						},
+					}
+
+					svc := NewService(
+						WithLabelSVC(mock.NewLabelService()),
+						WithTelegrafSVC(fakeTeleSVC),
+					)
+
+					_, err := svc.Apply(context.TODO(), influxdb.ID(9000), pkg)
+					require.Error(t, err)
+				})
+			})
+
+			t.Run("rolls back all created telegraf configs on an error", func(t *testing.T) {
+				testfileRunner(t, "testdata/telegraf", func(t *testing.T, pkg *Pkg) {
+					fakeLabelSVC := mock.NewLabelService()
+
+					var c int
+					fakeTeleSVC := &mock.TelegrafConfigStore{
+						CreateTelegrafConfigF: func(_ context.Context, tc *influxdb.TelegrafConfig, userID influxdb.ID) error {
+							// error out on second telegraf config attempted
+							if c == 1 {
+								return errors.New("blowed up")
+							}
+							c++
+							tc.ID = influxdb.ID(c)
+							return nil
+						},
+					}
+					deletedTeles := make(map[influxdb.ID]bool)
+					fakeTeleSVC.DeleteTelegrafConfigF = func(_ context.Context, id influxdb.ID) error {
+						deletedTeles[id] = true
+						return nil
+					}
+
+					pkg.mTelegrafs["copy1"] = &telegraf{config: pkg.mTelegrafs["first_tele_config"].config}
+
+					svc := NewService(
+						WithLabelSVC(fakeLabelSVC),
+						WithTelegrafSVC(fakeTeleSVC),
+					)
+
+					ctx := pctx.SetAuthorizer(context.TODO(), &influxdb.Authorization{UserID: influxdb.ID(3)})
+
+					_, err := svc.Apply(ctx, influxdb.ID(9000), pkg)
+					require.Error(t, err)
+
+					assert.True(t, deletedTeles[influxdb.ID(1)])
+				})
+			})
+		})
+
+		t.Run("with replace", func(t *testing.T) {
+			t.Run("deletes only the existing resources of the pkg not present in it", func(t *testing.T) {
+				testfileRunner(t, "testdata/bucket.yml", func(t *testing.T, pkg *Pkg) {
+					orgID := influxdb.ID(9000)
+					pkgLabel := &influxdb.Label{ID: 10, OrgID: orgID, Name: PkgLabelName("pkg_name")}
+
+					fakeBktSVC := mock.NewBucketService()
+					fakeBktSVC.FindBucketByNameFn = func(_ context.Context, id influxdb.ID, name string) (*influxdb.Bucket, error) {
+						if name != "rucket_11" {
+							return nil, errors.New("not found")
+						}
+						return &influxdb.Bucket{ID: 3, OrgID: orgID, Name: name}, nil
+					}
+					fakeBktSVC.FindBucketsFn = func(_ context.Context, f influxdb.BucketFilter, _ ...influxdb.FindOptions) ([]*influxdb.Bucket, int, error) {
+						bkts := []*influxdb.Bucket{
+							{ID: 3, OrgID: orgID, Name: "rucket_11"},
+							{ID: 4, OrgID: orgID, Name: "removed_rucket"},
+							{ID: 5, OrgID: orgID, Name: "_monitoring", Type: influxdb.BucketTypeSystem},
+							{ID: 7, OrgID: orgID, Name: "unowned_rucket"},
+						}
+						return bkts, len(bkts), nil
+					}
+					fakeBktSVC.UpdateBucketFn = func(_ context.Context, id influxdb.ID, upd influxdb.BucketUpdate) (*influxdb.Bucket, error) {
+						return &influxdb.Bucket{ID: id}, nil
+					}
+					var deletedIDs []influxdb.ID
+					fakeBktSVC.DeleteBucketFn = func(_ context.Context, id influxdb.ID) error {
+						deletedIDs = append(deletedIDs, id)
+						return nil
+					}
+
+					fakeCheckSVC := mock.NewCheckService()
+					fakeCheckSVC.FindChecksFn = func(_ context.Context, f influxdb.CheckFilter, _ ...influxdb.FindOptions) ([]influxdb.Check, int, error) {
+						checks := []influxdb.Check{
+							&icheck.Deadman{Base: icheck.Base{ID: 11, OrgID: orgID, Name: "removed_check"}},
+							&icheck.Deadman{Base: icheck.Base{ID: 12, OrgID: orgID, Name: "unowned_check"}},
+						}
+						return checks, len(checks), nil
+					}
+					fakeCheckSVC.DeleteCheckFn = func(_ context.Context, id influxdb.ID) error {
+						deletedIDs = append(deletedIDs, id)
+						return nil
+					}
+
+					fakeDashSVC := mock.NewDashboardService()
+					fakeDashSVC.FindDashboardsF = func(_ context.Context, f influxdb.DashboardFilter, _ influxdb.FindOptions) ([]*influxdb.Dashboard, int, error) {
+						dashes := []*influxdb.Dashboard{
+							{ID: 13, OrganizationID: orgID, Name: "removed_dash"},
+							{ID: 14, OrganizationID: orgID, Name: "unowned_dash"},
+						}
+						return dashes, len(dashes), nil
+					}
+					fakeDashSVC.DeleteDashboardF = func(_ context.Context, id influxdb.ID) error {
+						deletedIDs = append(deletedIDs, id)
+						return nil
+					}
+
+					fakeLabelSVC := mock.NewLabelService()
+					fakeLabelSVC.FindLabelsFn = func(_ context.Context, f influxdb.LabelFilter) ([]*influxdb.Label, error) {
+						return []*influxdb.Label{
+							pkgLabel,
+							{ID: 6, OrgID: orgID, Name: "removed_label"},
+							{ID: 8, OrgID: orgID, Name: "unowned_label"},
+						}, nil
+					}
+					fakeLabelSVC.FindResourceLabelsFn = func(_ context.Context, f influxdb.LabelMappingFilter) ([]*influxdb.Label, error) {
+						switch f.ResourceID {
+						case 4, 6, 11, 13, 15, 17, 19, 21:
+							return []*influxdb.Label{pkgLabel}, nil
+						default:
+							return nil, nil
+						}
+					}
+					var mappings []influxdb.LabelMapping
+					fakeLabelSVC.CreateLabelMappingFn = func(_ context.Context, m *influxdb.LabelMapping) error {
+						mappings = append(mappings, *m)
+						return nil
+					}
+					fakeLabelSVC.DeleteLabelFn = func(_ context.Context, id influxdb.ID) error {
+						deletedIDs = append(deletedIDs, id)
+						return nil
+					}
+
+					fakeEndpointSVC := &mock.NotificationEndpointService{
+						FindNotificationEndpointsF: func(_ context.Context, f influxdb.NotificationEndpointFilter, _ ...influxdb.FindOptions) ([]influxdb.NotificationEndpoint, int, error) {
+							endpoints := []influxdb.NotificationEndpoint{
+								&endpoint.Slack{Base: endpoint.Base{ID: 15, OrgID: orgID, Name: "removed_endpoint"}},
+								&endpoint.Slack{Base: endpoint.Base{ID: 16, OrgID: orgID, Name: "unowned_endpoint"}},
+							}
+							return endpoints, len(endpoints), nil
+						},
+						DeleteNotificationEndpointF: func(_ context.Context, id influxdb.ID) ([]influxdb.SecretField, influxdb.ID, error) {
+							deletedIDs = append(deletedIDs, id)
+							return nil, orgID, nil
+						},
+					}
+
+					fakeRuleStore := &mock.NotificationRuleStore{
+						FindNotificationRulesF: func(_ context.Context, f influxdb.NotificationRuleFilter, _ ...influxdb.FindOptions) ([]influxdb.NotificationRule, int, error) {
+							rules := []influxdb.NotificationRule{
+								&rule.Slack{Base: rule.Base{ID: 17, OrgID: orgID, Name: "removed_rule"}},
+								&rule.Slack{Base: rule.Base{ID: 18, OrgID: orgID, Name: "unowned_rule"}},
+							}
+							return rules, len(rules), nil
+						},
+						DeleteNotificationRuleF: func(_ context.Context, id influxdb.ID) error {
+							deletedIDs = append(deletedIDs, id)
+							return nil
+						},
+					}
+
+					fakeTeleSVC := &mock.TelegrafConfigStore{
+						FindTelegrafConfigsF: func(_ context.Context, f influxdb.TelegrafConfigFilter, _ ...influxdb.FindOptions) ([]*influxdb.TelegrafConfig, int, error) {
+							teles := []*influxdb.TelegrafConfig{
+								{ID: 19, OrgID: orgID, Name: "removed_tele"},
+								{ID: 20, OrgID: orgID, Name: "unowned_tele"},
+							}
+							return teles, len(teles), nil
+						},
+						DeleteTelegrafConfigF: func(_ context.Context, id influxdb.ID) error {
+							deletedIDs = append(deletedIDs, id)
+							return nil
+						},
+					}
+
+					fakeVarSVC := mock.NewVariableService()
+					fakeVarSVC.FindVariablesF = func(_ context.Context, f influxdb.VariableFilter, _ ...influxdb.FindOptions) ([]*influxdb.Variable, error) {
+						return []*influxdb.Variable{
+							{ID: 21, OrganizationID: orgID, Name: "removed_var"},
+							{ID: 22, OrganizationID: orgID, Name: "unowned_var"},
+						}, nil
+					}
+					fakeVarSVC.DeleteVariableF = func(_ context.Context, id influxdb.ID) error {
+						deletedIDs = append(deletedIDs, id)
+						return nil
+					}
+
+					svc := NewService(
+						WithBucketSVC(fakeBktSVC),
+						WithCheckSVC(fakeCheckSVC),
+						WithDashboardSVC(fakeDashSVC),
+						WithLabelSVC(fakeLabelSVC),
+						WithNotificationEndpointSVC(fakeEndpointSVC),
+						WithNotificationRuleSVC(fakeRuleStore),
+						WithTelegrafSVC(fakeTeleSVC),
+						WithVariableSVC(fakeVarSVC),
+					)
+
+					_, diff, err := svc.DryRun(context.TODO(), orgID, pkg, WithReplace())
+					require.NoError(t, err)
+
+					expected := []DiffDeletion{
+						{Kind: KindBucket, ID: SafeID(4), Name: "removed_rucket"},
+						{Kind: KindCheck, ID: SafeID(11), Name: "removed_check"},
+						{Kind: KindDashboard, ID: SafeID(13), Name: "removed_dash"},
+						{Kind: KindLabel, ID: SafeID(6), Name: "removed_label"},
+						{Kind: KindNotificationEndpoint, ID: SafeID(15), Name: "removed_endpoint"},
+						{Kind: KindNotificationRule, ID: SafeID(17), Name: "removed_rule"},
+						{Kind: KindTelegraf, ID: SafeID(19), Name: "removed_tele"},
+						{Kind: KindVariable, ID: SafeID(21), Name: "removed_var"},
+					}
+					assert.Equal(t, expected, diff.Deletions)
+					assert.Empty(t, diff.Prunes)
+					assert.True(t, diff.HasChanges())
+
+					sum, err := svc.Apply(context.TODO(), orgID, pkg, WithReplace())
+					require.NoError(t, err)
+
+					require.Len(t, sum.Buckets, 1)
+					assert.Equal(t, influxdb.ID(3), sum.Buckets[0].ID)
+					assert.Equal(t, []influxdb.ID{4, 11, 13, 6, 15, 17, 19, 21}, deletedIDs)
+
+					expectedMapping := influxdb.LabelMapping{
+						LabelID:      pkgLabel.ID,
+						ResourceID:   influxdb.ID(3),
+						ResourceType: influxdb.BucketsResourceType,
+					}
+					assert.Equal(t, []influxdb.LabelMapping{expectedMapping}, mappings)
+				})
+			})
+
+			t.Run("deletes nothing before the pkg label exists", func(t *testing.T) {
+				testfileRunner(t, "testdata/bucket.yml", func(t *testing.T, pkg *Pkg) {
+					fakeBktSVC := mock.NewBucketService()
+					fakeBktSVC.FindBucketByNameFn = func(_ context.Context, id influxdb.ID, name string) (*influxdb.Bucket, error) {
+						return nil, errors.New("not found")
+					}
+					fakeBktSVC.FindBucketsFn = func(_ context.Context, f influxdb.BucketFilter, _ ...influxdb.FindOptions) ([]*influxdb.Bucket, int, error) {
+						return []*influxdb.Bucket{{ID: 4, Name: "unowned_rucket"}}, 1, nil
+					}
+					fakeBktSVC.CreateBucketFn = func(_ context.Context, b *influxdb.Bucket) error {
+						b.ID = influxdb.ID(3)
+						return nil
+					}
+					fakeBktSVC.DeleteBucketFn = func(_ context.Context, id influxdb.ID) error {
+						return errors.New("should not be called")
+					}
+
+					fakeLabelSVC := mock.NewLabelService()
+					var createdLabel *influxdb.Label
+					fakeLabelSVC.CreateLabelFn = func(_ context.Context, l *influxdb.Label) error {
+						l.ID = influxdb.ID(10)
+						createdLabel = l
+						return nil
+					}
+
+					svc := NewService(WithBucketSVC(fakeBktSVC), WithLabelSVC(fakeLabelSVC))
+
+					_, diff, err := svc.DryRun(context.TODO(), influxdb.ID(9000), pkg, WithReplace())
+					require.NoError(t, err)
+					assert.Empty(t, diff.Deletions)
+
+					_, err = svc.Apply(context.TODO(), influxdb.ID(9000), pkg, WithReplace())
+					require.NoError(t, err)
+
+					require.NotNil(t, createdLabel)
+					assert.Equal(t, PkgLabelName("pkg_name"), createdLabel.Name)
+				})
+			})
+
+			t.Run("does not delete without replace", func(t *testing.T) {
+				testfileRunner(t, "testdata/bucket.yml", func(t *testing.T, pkg *Pkg) {
+					fakeBktSVC := mock.NewBucketService()
+					fakeBktSVC.FindBucketByNameFn = func(_ context.Context, id influxdb.ID, name string) (*influxdb.Bucket, error) {
+						return nil, errors.New("not found")
+					}
+					fakeBktSVC.FindBucketsFn = func(_ context.Context, f influxdb.BucketFilter, _ ...influxdb.FindOptions) ([]*influxdb.Bucket, int, error) {
+						return []*influxdb.Bucket{{ID: 4, Name: "removed_rucket"}}, 1, nil
+					}
+					fakeBktSVC.DeleteBucketFn = func(_ context.Context, id influxdb.ID) error {
+						return errors.New("should not be called")
+					}
+
+					svc := NewService(WithBucketSVC(fakeBktSVC))
+
+					_, diff, err := svc.DryRun(context.TODO(), influxdb.ID(9000), pkg)
+					require.NoError(t, err)
+					assert.Empty(t, diff.Deletions)
+
+					_, err = svc.Apply(context.TODO(), influxdb.ID(9000), pkg)
+					require.NoError(t, err)
+				})
+			})
+		})
+
+		t.Run("with prune", func(t *testing.T) {
+			t.Run("deletes only the resources of the pkg no longer present in it", func(t *testing.T) {
+				testfileRunner(t, "testdata/bucket.yml", func(t *testing.T, pkg *Pkg) {
+					orgID := influxdb.ID(9000)
+					pkgLabel := &influxdb.Label{ID: 10, OrgID: orgID, Name: PkgLabelName("pkg_name")}
+
+					fakeBktSVC := mock.NewBucketService()
+					fakeBktSVC.FindBucketByNameFn = func(_ context.Context, id influxdb.ID, name string) (*influxdb.Bucket, error) {
+						if name != "rucket_11" {
+							return nil, errors.New("not found")
+						}
+						return &influxdb.Bucket{ID: 3, OrgID: orgID, Name: name}, nil
+					}
+					fakeBktSVC.FindBucketsFn = func(_ context.Context, f influxdb.BucketFilter, _ ...influxdb.FindOptions) ([]*influxdb.Bucket, int, error) {
+						bkts := []*influxdb.Bucket{
+							{ID: 3, OrgID: orgID, Name: "rucket_11"},
+							{ID: 4, OrgID: orgID, Name: "removed_rucket"},
+							{ID: 5, OrgID: orgID, Name: "_monitoring", Type: influxdb.BucketTypeSystem},
+							{ID: 7, OrgID: orgID, Name: "unowned_rucket"},
+						}
+						return bkts, len(bkts), nil
+					}
+					fakeBktSVC.UpdateBucketFn = func(_ context.Context, id influxdb.ID, upd influxdb.BucketUpdate) (*influxdb.Bucket, error) {
+						return &influxdb.Bucket{ID: id}, nil
+					}
+					var deletedBktIDs []influxdb.ID
+					fakeBktSVC.DeleteBucketFn = func(_ context.Context, id influxdb.ID) error {
+						deletedBktIDs = append(deletedBktIDs, id)
+						return nil
+					}
+
+					fakeLabelSVC := mock.NewLabelService()
+					fakeLabelSVC.FindLabelsFn = func(_ context.Context, f influxdb.LabelFilter) ([]*influxdb.Label, error) {
+						return []*influxdb.Label{
+							pkgLabel,
+							{ID: 6, OrgID: orgID, Name: "removed_label"},
+							{ID: 8, OrgID: orgID, Name: "unowned_label"},
+						}, nil
+					}
+					fakeLabelSVC.FindResourceLabelsFn = func(_ context.Context, f influxdb.LabelMappingFilter) ([]*influxdb.Label, error) {
+						switch f.ResourceID {
+						case 4, 6:
+							return []*influxdb.Label{pkgLabel}, nil
+						default:
+							return nil, nil
+						}
+					}
+					var mappings []influxdb.LabelMapping
+					fakeLabelSVC.CreateLabelMappingFn = func(_ context.Context, m *influxdb.LabelMapping) error {
+						mappings = append(mappings, *m)
+						return nil
+					}
+					var deletedLabelIDs []influxdb.ID
+					fakeLabelSVC.DeleteLabelFn = func(_ context.Context, id influxdb.ID) error {
+						deletedLabelIDs = append(deletedLabelIDs, id)
+						return nil
+					}
+
 					svc := NewService(
 						WithBucketSVC(fakeBktSVC),
+						WithDashboardSVC(mock.NewDashboardService()),
 						WithLabelSVC(fakeLabelSVC),
-						WithDashboardSVC(fakeDashSVC),
+						WithVariableSVC(mock.NewVariableService()),
 					)
 
-					orgID := influxdb.ID(9000)
+					_, diff, err := svc.DryRun(context.TODO(), orgID, pkg, WithPrune())
+					require.NoError(t, err)
 
-					_, err := svc.Apply(context.TODO(), orgID, pkg)
+					// the pkg declares no labels, so the removed label of the pkg is
+					// not pruned.
+					expected := []DiffDeletion{
+						{Kind: KindBucket, ID: SafeID(4), Name: "removed_rucket"},
+					}
+					assert.Equal(t, expected, diff.Prunes)
+					assert.Empty(t, diff.Deletions)
+					assert.True(t, diff.HasChanges())
+
+					_, err = svc.Apply(context.TODO(), orgID, pkg, WithPrune())
 					require.NoError(t, err)
 
-					assert.Equal(t, 4, numLabelMappings)
+					assert.Equal(t, []influxdb.ID{4}, deletedBktIDs)
+					assert.Empty(t, deletedLabelIDs)
+
+					expectedMapping := influxdb.LabelMapping{
+						LabelID:      pkgLabel.ID,
+						ResourceID:   influxdb.ID(3),
+						ResourceType: influxdb.BucketsResourceType,
+					}
+					assert.Equal(t, []influxdb.LabelMapping{expectedMapping}, mappings)
 				})
 			})
-		})
 
-		t.Run("variables", func(t *testing.T) {
-			t.Run("successfully creates pkg of variables", func(t *testing.T) {
-				testfileRunner(t, "testdata/variables.yml", func(t *testing.T, pkg *Pkg) {
-					fakeVarSVC := mock.NewVariableService()
-					id := 1
-					fakeVarSVC.CreateVariableF = func(_ context.Context, v *influxdb.Variable) error {
-						v.ID = influxdb.ID(id)
-						id++
+			t.Run("prunes only the kinds the pkg declares", func(t *testing.T) {
+				testfileRunner(t, "testdata/telegraf.yml", func(t *testing.T, pkg *Pkg) {
+					orgID := influxdb.ID(9000)
+					pkgLabel := &influxdb.Label{ID: 10, OrgID: orgID, Name: PkgLabelName("pkg_name")}
+
+					var deletedIDs []influxdb.ID
+
+					fakeCheckSVC := mock.NewCheckService()
+					fakeCheckSVC.FindChecksFn = func(_ context.Context, f influxdb.CheckFilter, _ ...influxdb.FindOptions) ([]influxdb.Check, int, error) {
+						checks := []influxdb.Check{
+							&icheck.Deadman{Base: icheck.Base{ID: 11, OrgID: orgID, Name: "removed_check"}},
+							&icheck.Deadman{Base: icheck.Base{ID: 12, OrgID: orgID, Name: "unowned_check"}},
+						}
+						return checks, len(checks), nil
+					}
+					fakeCheckSVC.DeleteCheckFn = func(_ context.Context, id influxdb.ID) error {
+						deletedIDs = append(deletedIDs, id)
+						return nil
+					}
+
+					fakeLabelSVC := mock.NewLabelService()
+					fakeLabelSVC.FindLabelsFn = func(_ context.Context, f influxdb.LabelFilter) ([]*influxdb.Label, error) {
+						if f.Name == "" || f.Name == pkgLabel.Name {
+							return []*influxdb.Label{pkgLabel}, nil
+						}
+						return nil, nil
+					}
+					fakeLabelSVC.CreateLabelFn = func(_ context.Context, l *influxdb.Label) error {
+						l.ID = influxdb.ID(1)
+						return nil
+					}
+					fakeLabelSVC.FindResourceLabelsFn = func(_ context.Context, f influxdb.LabelMappingFilter) ([]*influxdb.Label, error) {
+						switch f.ResourceID {
+						case 11, 15, 17, 19:
+							return []*influxdb.Label{pkgLabel}, nil
+						default:
+							return nil, nil
+						}
+					}
+					var pkgMappings []influxdb.LabelMapping
+					fakeLabelSVC.CreateLabelMappingFn = func(_ context.Context, m *influxdb.LabelMapping) error {
+						if m.LabelID == pkgLabel.ID {
+							pkgMappings = append(pkgMappings, *m)
+						}
 						return nil
 					}
 
+					fakeEndpointSVC := &mock.NotificationEndpointService{
+						FindNotificationEndpointsF: func(_ context.Context, f influxdb.NotificationEndpointFilter, _ ...influxdb.FindOptions) ([]influxdb.NotificationEndpoint, int, error) {
+							endpoints := []influxdb.NotificationEndpoint{
+								&endpoint.Slack{Base: endpoint.Base{ID: 15, OrgID: orgID, Name: "removed_endpoint"}},
+								&endpoint.Slack{Base: endpoint.Base{ID: 16, OrgID: orgID, Name: "unowned_endpoint"}},
+							}
+							return endpoints, len(endpoints), nil
+						},
+						DeleteNotificationEndpointF: func(_ context.Context, id influxdb.ID) ([]influxdb.SecretField, influxdb.ID, error) {
+							deletedIDs = append(deletedIDs, id)
+							return nil, orgID, nil
+						},
+					}
+
+					fakeRuleStore := &mock.NotificationRuleStore{
+						FindNotificationRulesF: func(_ context.Context, f influxdb.NotificationRuleFilter, _ ...influxdb.FindOptions) ([]influxdb.NotificationRule, int, error) {
+							rules := []influxdb.NotificationRule{
+								&rule.Slack{Base: rule.Base{ID: 17, OrgID: orgID, Name: "removed_rule"}},
+								&rule.Slack{Base: rule.Base{ID: 18, OrgID: orgID, Name: "unowned_rule"}},
+							}
+							return rules, len(rules), nil
+						},
+						DeleteNotificationRuleF: func(_ context.Context, id influxdb.ID) error {
+							deletedIDs = append(deletedIDs, id)
+							return nil
+						},
+					}
+
+					fakeTeleSVC := &mock.TelegrafConfigStore{
+						CreateTelegrafConfigF: func(_ context.Context, tc *influxdb.TelegrafConfig, userID influxdb.ID) error {
+							tc.ID = influxdb.ID(2)
+							return nil
+						},
+						FindTelegrafConfigsF: func(_ context.Context, f influxdb.TelegrafConfigFilter, _ ...influxdb.FindOptions) ([]*influxdb.TelegrafConfig, int, error) {
+							teles := []*influxdb.TelegrafConfig{
+								{ID: 19, OrgID: orgID, Name: "removed_tele"},
+								{ID: 20, OrgID: orgID, Name: "unowned_tele"},
+							}
+							return teles, len(teles), nil
+						},
+						DeleteTelegrafConfigF: func(_ context.Context, id influxdb.ID) error {
+							deletedIDs = append(deletedIDs, id)
+							return nil
+						},
+					}
+
 					svc := NewService(
-						WithLabelSVC(mock.NewLabelService()),
-						WithVariableSVC(fakeVarSVC),
+						WithCheckSVC(fakeCheckSVC),
+						WithLabelSVC(fakeLabelSVC),
+						WithNotificationEndpointSVC(fakeEndpointSVC),
+						WithNotificationRuleSVC(fakeRuleStore),
+						WithTelegrafSVC(fakeTeleSVC),
 					)
 
-					orgID := influxdb.ID(9000)
+					ctx := pctx.SetAuthorizer(context.TODO(), &influxdb.Authorization{UserID: influxdb.ID(3)})
 
-					sum, err := svc.Apply(context.TODO(), orgID, pkg)
+					_, diff, err := svc.DryRun(ctx, orgID, pkg, WithPrune())
 					require.NoError(t, err)
 
-					require.Len(t, sum.Variables, 4)
-					expected := sum.Variables[0]
-					assert.Equal(t, influxdb.ID(1), expected.ID)
-					assert.Equal(t, orgID, expected.OrganizationID)
-					assert.Equal(t, "var_const", expected.Name)
-					assert.Equal(t, "var_const desc", expected.Description)
-					require.NotNil(t, expected.Arguments)
-					assert.Equal(t, influxdb.VariableConstantValues{"first val"}, expected.Arguments.Values)
+					// the pkg declares only labels and telegrafs, the checks,
+					// notification endpoints and rules of the pkg are left be.
+					expected := []DiffDeletion{
+						{Kind: KindTelegraf, ID: SafeID(19), Name: "removed_tele"},
+					}
+					assert.Equal(t, expected, diff.Prunes)
 
-					for i := 1; i < 3; i++ {
-						expected = sum.Variables[i]
-						assert.Equal(t, influxdb.ID(i+1), expected.ID)
+					_, err = svc.Apply(ctx, orgID, pkg, WithPrune())
+					require.NoError(t, err)
+
+					assert.Equal(t, []influxdb.ID{19}, deletedIDs)
+
+					expectedMappings := []influxdb.LabelMapping{
+						{LabelID: pkgLabel.ID, ResourceID: influxdb.ID(1), ResourceType: influxdb.LabelsResourceType},
+						{LabelID: pkgLabel.ID, ResourceID: influxdb.ID(2), ResourceType: influxdb.TelegrafsResourceType},
 					}
+					assert.Equal(t, expectedMappings, pkgMappings)
 				})
 			})
 
-			t.Run("rolls back all created variables on an error", func(t *testing.T) {
-				testfileRunner(t, "testdata/variables.yml", func(t *testing.T, pkg *Pkg) {
-					fakeVarSVC := mock.NewVariableService()
-					var c int
-					fakeVarSVC.CreateVariableF = func(_ context.Context, l *influxdb.Variable) error {
-						// 4th variable will return the error here, and 3 before should be rolled back
-						if c == 3 {
-							return errors.New("blowed up ")
+			t.Run("keeps the applied resources when a deletion fails", func(t *testing.T) {
+				testfileRunner(t, "testdata/bucket.yml", func(t *testing.T, pkg *Pkg) {
+					orgID := influxdb.ID(9000)
+					pkgLabel := &influxdb.Label{ID: 10, OrgID: orgID, Name: PkgLabelName("pkg_name")}
+
+					fakeBktSVC := mock.NewBucketService()
+					fakeBktSVC.FindBucketByNameFn = func(_ context.Context, id influxdb.ID, name string) (*influxdb.Bucket, error) {
+						return nil, errors.New("not found")
+					}
+					fakeBktSVC.FindBucketsFn = func(_ context.Context, f influxdb.BucketFilter, _ ...influxdb.FindOptions) ([]*influxdb.Bucket, int, error) {
+						return []*influxdb.Bucket{{ID: 4, OrgID: orgID, Name: "removed_rucket"}}, 1, nil
+					}
+					fakeBktSVC.CreateBucketFn = func(_ context.Context, b *influxdb.Bucket) error {
+						b.ID = influxdb.ID(3)
+						return nil
+					}
+					var deletedBktIDs []influxdb.ID
+					fakeBktSVC.DeleteBucketFn = func(_ context.Context, id influxdb.ID) error {
+						deletedBktIDs = append(deletedBktIDs, id)
+						if id == 4 {
+							return errors.New("blowed up")
 						}
-						c++
 						return nil
 					}
-					var count int
-					fakeVarSVC.DeleteVariableF = func(_ context.Context, id influxdb.ID) error {
-						count++
+
+					fakeLabelSVC := mock.NewLabelService()
+					fakeLabelSVC.FindLabelsFn = func(_ context.Context, f influxdb.LabelFilter) ([]*influxdb.Label, error) {
+						return []*influxdb.Label{pkgLabel}, nil
+					}
+					fakeLabelSVC.FindResourceLabelsFn = func(_ context.Context, f influxdb.LabelMappingFilter) ([]*influxdb.Label, error) {
+						if f.ResourceID == 4 {
+							return []*influxdb.Label{pkgLabel}, nil
+						}
+						return nil, nil
+					}
+					var deletedMappings int
+					fakeLabelSVC.DeleteLabelMappingFn = func(_ context.Context, m *influxdb.LabelMapping) error {
+						deletedMappings++
 						return nil
 					}
 
 					svc := NewService(
-						WithLabelSVC(mock.NewLabelService()),
-						WithVariableSVC(fakeVarSVC),
+						WithBucketSVC(fakeBktSVC),
+						WithDashboardSVC(mock.NewDashboardService()),
+						WithLabelSVC(fakeLabelSVC),
+						WithVariableSVC(mock.NewVariableService()),
 					)
 
-					orgID := influxdb.ID(9000)
-
-					_, err := svc.Apply(context.TODO(), orgID, pkg)
+					sum, err := svc.Apply(context.TODO(), orgID, pkg, WithPrune())
 					require.Error(t, err)
 
-					assert.Equal(t, 3, count)
+					deleteErr, ok := IsDeleteErr(err)
+					require.True(t, ok, err)
+					require.Len(t, deleteErr.Resources, 1)
+					assert.Equal(t, KindBucket.String(), deleteErr.Resources[0].Kind)
+					assert.Equal(t, "removed_rucket", deleteErr.Resources[0].Name)
+
+					// the created bucket and its pkg label mapping are not rolled back.
+					assert.Equal(t, []influxdb.ID{4}, deletedBktIDs)
+					assert.Zero(t, deletedMappings)
+					require.Len(t, sum.Buckets, 1)
+					assert.Equal(t, SafeID(3), sum.Buckets[0].ID)
 				})
 			})
 
-			t.Run("will not apply variable if no changes to be applied", func(t *testing.T) {
-				testfileRunner(t, "testdata/variables.yml", func(t *testing.T, pkg *Pkg) {
-					orgID := influxdb.ID(9000)
-
-					pkg.isVerified = true
-					pkgLabel := pkg.mVariables["var_const"]
-					pkgLabel.existing = &influxdb.Variable{
-						// makes all pkg changes same as they are on the existing
-						ID:             influxdb.ID(1),
-						OrganizationID: orgID,
-						Name:           pkgLabel.Name,
-						Arguments: &influxdb.VariableArguments{
-							Type:   "constant",
-							Values: influxdb.VariableConstantValues{"first val"},
-						},
+			t.Run("prunes nothing before the pkg label exists", func(t *testing.T) {
+				testfileRunner(t, "testdata/bucket.yml", func(t *testing.T, pkg *Pkg) {
+					fakeBktSVC := mock.NewBucketService()
+					fakeBktSVC.FindBucketByNameFn = func(_ context.Context, id influxdb.ID, name string) (*influxdb.Bucket, error) {
+						return nil, errors.New("not found")
 					}
-
-					fakeVarSVC := mock.NewVariableService()
-					var createCallCount int
-					fakeVarSVC.CreateVariableF = func(_ context.Context, l *influxdb.Variable) error {
-						createCallCount++
-						if l.Name == "var_const" {
-							return errors.New("shouldn't get here")
-						}
+					fakeBktSVC.FindBucketsFn = func(_ context.Context, f influxdb.BucketFilter, _ ...influxdb.FindOptions) ([]*influxdb.Bucket, int, error) {
+						return []*influxdb.Bucket{{ID: 4, Name: "removed_rucket"}}, 1, nil
+					}
+					fakeBktSVC.CreateBucketFn = func(_ context.Context, b *influxdb.Bucket) error {
+						b.ID = influxdb.ID(3)
 						return nil
 					}
-					fakeVarSVC.UpdateVariableF = func(_ context.Context, id influxdb.ID, v *influxdb.VariableUpdate) (*influxdb.Variable, error) {
-						if id > influxdb.ID(1) {
-							return nil, errors.New("this id should not be updated")
-						}
-						return &influxdb.Variable{ID: id}, nil
+					fakeBktSVC.DeleteBucketFn = func(_ context.Context, id influxdb.ID) error {
+						return errors.New("should not be called")
 					}
 
-					svc := NewService(
-						WithLabelSVC(mock.NewLabelService()),
-						WithVariableSVC(fakeVarSVC),
-					)
+					fakeLabelSVC := mock.NewLabelService()
+					var createdLabel *influxdb.Label
+					fakeLabelSVC.CreateLabelFn = func(_ context.Context, l *influxdb.Label) error {
+						l.ID = influxdb.ID(10)
+						createdLabel = l
+						return nil
+					}
 
-					sum, err := svc.Apply(context.TODO(), orgID, pkg)
+					svc := NewService(WithBucketSVC(fakeBktSVC), WithLabelSVC(fakeLabelSVC))
+
+					_, diff, err := svc.DryRun(context.TODO(), influxdb.ID(9000), pkg, WithPrune())
 					require.NoError(t, err)
+					assert.Empty(t, diff.Prunes)
 
-					require.Len(t, sum.Variables, 4)
-					expected := sum.Variables[0]
-					assert.Equal(t, influxdb.ID(1), expected.ID)
-					assert.Equal(t, "var_const", expected.Name)
+					_, err = svc.Apply(context.TODO(), influxdb.ID(9000), pkg, WithPrune())
+					require.NoError(t, err)
 
-					assert.Equal(t, 3, createCallCount) // only called for last 3 labels
+					require.NotNil(t, createdLabel)
+					assert.Equal(t, PkgLabelName("pkg_name"), createdLabel.Name)
+				})
+			})
+
+			t.Run("can not be combined with replace", func(t *testing.T) {
+				testfileRunner(t, "testdata/bucket.yml", func(t *testing.T, pkg *Pkg) {
+					svc := NewService()
+
+					_, _, err := svc.DryRun(context.TODO(), influxdb.ID(9000), pkg, WithReplace(), WithPrune())
+					require.Error(t, err)
 				})
 			})
 		})
@@ -663,7 +2368,7 @@ func TestService(t *testing.T) {
 			bktSVC.FindBucketByIDFn = func(_ context.Context, id influxdb.ID) (*influxdb.Bucket, error) {
 				return &influxdb.Bucket{ID: 1, Name: "name"}, nil
 			}
-			svc := NewService(WithBucketSVC(bktSVC))
+			svc := NewService(WithBucketSVC(bktSVC), WithLabelSVC(mock.NewLabelService()))
 
 			expectedMeta := Metadata{
 				Description: "desc",
@@ -717,7 +2422,7 @@ func TestService(t *testing.T) {
 							return expected, nil
 						}
 
-						svc := NewService(WithBucketSVC(bktSVC))
+						svc := NewService(WithBucketSVC(bktSVC), WithLabelSVC(mock.NewLabelService()))
 
 						resToClone := ResourceToClone{
 							Kind: KindBucket,
@@ -923,7 +2628,11 @@ func TestService(t *testing.T) {
 							return nil, errors.New("wrongo ids")
 						}
 
-						svc := NewService(WithDashboardSVC(dashSVC))
+						svc := NewService(
+							WithDashboardSVC(dashSVC),
+							WithLabelSVC(mock.NewLabelService()),
+							WithVariableSVC(mock.NewVariableService()),
+						)
 
 						resToClone := ResourceToClone{
 							Kind: KindDashboard,
@@ -1081,7 +2790,7 @@ func TestService(t *testing.T) {
 							return &tt.expectedVar, nil
 						}
 
-						svc := NewService(WithVariableSVC(varSVC))
+						svc := NewService(WithLabelSVC(mock.NewLabelService()), WithVariableSVC(varSVC))
 
 						resToClone := ResourceToClone{
 							Kind: KindVariable,
@@ -1106,6 +2815,246 @@ func TestService(t *testing.T) {
 					t.Run(tt.name, fn)
 				}
 			})
+
+			t.Run("dashboard with dependencies", func(t *testing.T) {
+				expectedCell := &influxdb.Cell{
+					ID:           5,
+					CellProperty: influxdb.CellProperty{X: 1, Y: 2, W: 3, H: 4},
+				}
+				expected := &influxdb.Dashboard{
+					ID:             3,
+					OrganizationID: 9,
+					Name:           "dash name",
+					Cells:          []*influxdb.Cell{expectedCell},
+				}
+				expectedLabel := influxdb.Label{
+					ID:         7,
+					Name:       "label_1",
+					Properties: map[string]string{"color": "#FFFFFF"},
+				}
+				expectedVar := influxdb.Variable{
+					ID:   1,
+					Name: "bucket",
+					Arguments: &influxdb.VariableArguments{
+						Type:   "constant",
+						Values: influxdb.VariableConstantValues{"rucket"},
+					},
+				}
+
+				dashSVC := mock.NewDashboardService()
+				dashSVC.FindDashboardByIDF = func(_ context.Context, id influxdb.ID) (*influxdb.Dashboard, error) {
+					return expected, nil
+				}
+				dashSVC.GetDashboardCellViewF = func(_ context.Context, id influxdb.ID, cID influxdb.ID) (*influxdb.View, error) {
+					return &influxdb.View{
+						ViewContents: influxdb.ViewContents{Name: "view name"},
+						Properties: influxdb.SingleStatViewProperties{
+							Type: influxdb.ViewPropertyTypeSingleStat,
+							Queries: []influxdb.DashboardQuery{
+								{Text: "from(bucket: v.bucket) |> range(start: v.timeRangeStart)"},
+							},
+							ViewColors: []influxdb.ViewColor{{Type: "text", Hex: "red"}},
+						},
+					}, nil
+				}
+
+				labelSVC := mock.NewLabelService()
+				labelSVC.FindResourceLabelsFn = func(_ context.Context, f influxdb.LabelMappingFilter) ([]*influxdb.Label, error) {
+					if f.ResourceID != expected.ID || f.ResourceType != influxdb.DashboardsResourceType {
+						return nil, errors.New("wrong resource")
+					}
+					return []*influxdb.Label{&expectedLabel}, nil
+				}
+				labelSVC.FindLabelByIDFn = func(_ context.Context, id influxdb.ID) (*influxdb.Label, error) {
+					return &expectedLabel, nil
+				}
+
+				varSVC := mock.NewVariableService()
+				varSVC.FindVariablesF = func(_ context.Context, f influxdb.VariableFilter, _ ...influxdb.FindOptions) ([]*influxdb.Variable, error) {
+					if f.OrganizationID == nil || *f.OrganizationID != expected.OrganizationID {
+						return nil, errors.New("wrong org id")
+					}
+					return []*influxdb.Variable{
+						&expectedVar,
+						{ID: 2, Name: "unreferenced"},
+					}, nil
+				}
+				varSVC.FindVariableByIDF = func(_ context.Context, id influxdb.ID) (*influxdb.Variable, error) {
+					if id != expectedVar.ID {
+						return nil, errors.New("uh ohhh, wrong id here: " + id.String())
+					}
+					return &expectedVar, nil
+				}
+
+				svc := NewService(
+					WithDashboardSVC(dashSVC),
+					WithLabelSVC(labelSVC),
+					WithVariableSVC(varSVC),
+				)
+
+				resToClone := ResourceToClone{
+					Kind: KindDashboard,
+					ID:   expected.ID,
+				}
+				pkg, err := svc.CreatePkg(context.TODO(), WithResourceClones(resToClone))
+				require.NoError(t, err)
+
+				sum := pkg.Summary()
+
+				require.Len(t, sum.Variables, 1)
+				assert.Equal(t, expectedVar.Name, sum.Variables[0].Name)
+				assert.Equal(t, expectedVar.Arguments, sum.Variables[0].Arguments)
+
+				require.Len(t, sum.Labels, 1)
+				assert.Equal(t, expectedLabel.Name, sum.Labels[0].Name)
+
+				require.Len(t, sum.Dashboards, 1)
+				require.Len(t, sum.Dashboards[0].LabelAssociations, 1)
+				assert.Equal(t, expectedLabel.Name, sum.Dashboards[0].LabelAssociations[0].Name)
+
+				for _, r := range pkg.Spec.Resources {
+					k, err := r.kind()
+					require.NoError(t, err)
+					assert.Equal(t, !k.is(KindDashboard), r.boolShort(fieldDependency), k)
+				}
+			})
+		})
+	})
+
+	t.Run("Export", func(t *testing.T) {
+		orgID := influxdb.ID(9)
+
+		newSVC := func(t *testing.T) *Service {
+			bktSVC := mock.NewBucketService()
+			bktSVC.FindBucketsFn = func(_ context.Context, f influxdb.BucketFilter, _ ...influxdb.FindOptions) ([]*influxdb.Bucket, int, error) {
+				require.Equal(t, orgID, *f.OrganizationID)
+				return []*influxdb.Bucket{
+					{ID: 1, OrgID: orgID, Name: "rucket_1", RetentionPeriod: time.Hour},
+					{ID: 2, OrgID: orgID, Name: "_tasks", Type: influxdb.BucketTypeSystem},
+				}, 2, nil
+			}
+			bktSVC.FindBucketByIDFn = func(_ context.Context, id influxdb.ID) (*influxdb.Bucket, error) {
+				if id != 1 {
+					return nil, errors.New("uh ohhh, wrong id here: " + id.String())
+				}
+				return &influxdb.Bucket{ID: 1, OrgID: orgID, Name: "rucket_1", RetentionPeriod: time.Hour}, nil
+			}
+
+			label := &influxdb.Label{
+				ID:         3,
+				OrgID:      orgID,
+				Name:       "label_1",
+				Properties: map[string]string{"color": "#FFFFFF"},
+			}
+			labelSVC := mock.NewLabelService()
+			labelSVC.FindLabelsFn = func(_ context.Context, f influxdb.LabelFilter) ([]*influxdb.Label, error) {
+				require.Equal(t, orgID, *f.OrgID)
+				return []*influxdb.Label{label}, nil
+			}
+			labelSVC.FindLabelByIDFn = func(_ context.Context, id influxdb.ID) (*influxdb.Label, error) {
+				return label, nil
+			}
+			labelSVC.FindResourceLabelsFn = func(_ context.Context, f influxdb.LabelMappingFilter) ([]*influxdb.Label, error) {
+				if f.ResourceType == influxdb.BucketsResourceType && f.ResourceID == 1 {
+					return []*influxdb.Label{label}, nil
+				}
+				return nil, nil
+			}
+
+			dashSVC := mock.NewDashboardService()
+			dashSVC.FindDashboardsF = func(_ context.Context, f influxdb.DashboardFilter, _ influxdb.FindOptions) ([]*influxdb.Dashboard, int, error) {
+				require.Equal(t, orgID, *f.OrganizationID)
+				return nil, 0, nil
+			}
+
+			v := &influxdb.Variable{
+				ID:             4,
+				OrganizationID: orgID,
+				Name:           "var_1",
+				Arguments: &influxdb.VariableArguments{
+					Type:   "constant",
+					Values: influxdb.VariableConstantValues{"first"},
+				},
+			}
+			varSVC := mock.NewVariableService()
+			varSVC.FindVariablesF = func(_ context.Context, f influxdb.VariableFilter, _ ...influxdb.FindOptions) ([]*influxdb.Variable, error) {
+				require.Equal(t, orgID, *f.OrganizationID)
+				return []*influxdb.Variable{v}, nil
+			}
+			varSVC.FindVariableByIDF = func(_ context.Context, id influxdb.ID) (*influxdb.Variable, error) {
+				return v, nil
+			}
+
+			return NewService(
+				WithBucketSVC(bktSVC),
+				WithDashboardSVC(dashSVC),
+				WithLabelSVC(labelSVC),
+				WithVariableSVC(varSVC),
+			)
+		}
+
+		t.Run("exports the org resources with their label associations", func(t *testing.T) {
+			svc := newSVC(t)
+
+			pkg, err := svc.Export(context.TODO(), orgID, ExportFilter{}, WithMetadata(Metadata{Name: "exported", Version: "v1"}))
+			require.NoError(t, err)
+			assert.Equal(t, "exported", pkg.Metadata.Name)
+
+			sum := pkg.Summary()
+
+			require.Len(t, sum.Buckets, 1)
+			assert.Equal(t, "rucket_1", sum.Buckets[0].Name)
+			assert.Equal(t, time.Hour, sum.Buckets[0].RetentionPeriod)
+			require.Len(t, sum.Buckets[0].LabelAssociations, 1)
+			assert.Equal(t, "label_1", sum.Buckets[0].LabelAssociations[0].Name)
+
+			require.Len(t, sum.Labels, 1)
+			assert.Equal(t, "label_1", sum.Labels[0].Name)
+			assert.Equal(t, "#FFFFFF", sum.Labels[0].Properties["color"])
+
+			require.Len(t, sum.Variables, 1)
+			assert.Equal(t, "var_1", sum.Variables[0].Name)
+			assert.Empty(t, sum.Variables[0].LabelAssociations)
+
+			// the exported pkg does not reference the ids of the org it was exported from
+			for _, r := range pkg.Spec.Resources {
+				_, ok := r["id"]
+				assert.False(t, ok, r.Name())
+				assert.False(t, r.boolShort(fieldDependency), r.Name())
+			}
+
+			// the encoded pkg round trips to the same resources
+			b, err := pkg.Encode(EncodingYAML)
+			require.NoError(t, err)
+			newPkg, err := Parse(EncodingYAML, FromReader(bytes.NewReader(b)))
+			require.NoError(t, err)
+			assert.Equal(t, sum, newPkg.Summary())
+		})
+
+		t.Run("limits the export to the filtered kinds", func(t *testing.T) {
+			svc := newSVC(t)
+
+			pkg, err := svc.Export(context.TODO(), orgID, ExportFilter{Kinds: []Kind{KindBucket}})
+			require.NoError(t, err)
+
+			sum := pkg.Summary()
+			require.Len(t, sum.Buckets, 1)
+			assert.Empty(t, sum.Variables)
+
+			// the label is only exported as a dependency of the bucket
+			require.Len(t, sum.Labels, 1)
+			for _, r := range pkg.Spec.Resources {
+				k, err := r.kind()
+				require.NoError(t, err)
+				assert.Equal(t, k.is(KindLabel), r.boolShort(fieldDependency), k)
+			}
+		})
+
+		t.Run("errors when there is nothing to export", func(t *testing.T) {
+			svc := newSVC(t)
+
+			_, err := svc.Export(context.TODO(), orgID, ExportFilter{Kinds: []Kind{KindDashboard}})
+			require.Error(t, err)
 		})
 	})
 }
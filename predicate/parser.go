@@ -62,6 +62,28 @@ func (p *parser) scanIgnoreWhitespace() (tok influxql.Token, pos influxql.Pos, l
 	return
 }
 
+// ParseError is a predicate parsing error. Position is the offset of the
+// character in the predicate statement at which parsing failed.
+type ParseError struct {
+	Msg      string
+	Position int
+}
+
+// Error implements the error interface.
+func (e *ParseError) Error() string {
+	return e.Msg
+}
+
+func newParseError(pos influxql.Pos, msg string) error {
+	return &influxdb.Error{
+		Code: influxdb.EInvalid,
+		Err: &ParseError{
+			Msg:      msg,
+			Position: pos.Char,
+		},
+	}
+}
+
 // Parse the predicate statement.
 func Parse(sts string) (n Node, err error) {
 	if sts == "" {
@@ -72,85 +94,107 @@ func Parse(sts string) (n Node, err error) {
 	return p.parseLogicalNode()
 }
 
+// parseLogicalNode parses the whole predicate statement, any token left after
+// the outermost expression is an error.
 func (p *parser) parseLogicalNode() (Node, error) {
-	n := new(LogicalNode)
+	n, err := p.parseOrNode()
+	if err != nil {
+		return n, err
+	}
+	tok, pos, _ := p.scanIgnoreWhitespace()
+	switch tok {
+	case influxql.EOF:
+		return n, nil
+	case influxql.RPAREN:
+		return n, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("extra ) seen"),
+		}
+	default:
+		return n, newParseError(pos, fmt.Sprintf("bad logical expression, at position %d", pos.Char))
+	}
+}
+
+// parseOrNode parses expressions joined by OR. AND binds tighter than OR, so
+// each side of an OR is parsed as an AND expression.
+func (p *parser) parseOrNode() (Node, error) {
+	n, err := p.parseAndNode()
+	if err != nil {
+		return n, err
+	}
 	for {
-		tok, pos, _ := p.scanIgnoreWhitespace()
-		switch tok {
-		case influxql.NUMBER, influxql.INTEGER, influxql.NAME, influxql.IDENT:
+		if tok, _, _ := p.scanIgnoreWhitespace(); tok != influxql.OR {
 			p.unscan()
-			tr, err := p.parseTagRuleNode()
-			if err != nil {
-				return *n, err
-			}
-			if n.Children[0] == nil {
-				n.Children[0] = tr
-			} else {
-				n.Children[1] = tr
-			}
-		case influxql.AND:
-			n.Operator = LogicalAnd
-			if n.Children[1] == nil {
-				continue
-			}
-			var n1 Node
-			var err error
-			if tokNext := p.peekTok(); tokNext == influxql.LPAREN {
-				n1, err = p.parseLogicalNode()
-			} else {
-				n1, err = p.parseTagRuleNode()
-			}
-			if err != nil {
-				return *n, err
-			}
-			n = &LogicalNode{
-				Children: [2]Node{*n, n1},
-				Operator: LogicalAnd,
-			}
-		case influxql.OR:
-			return *n, &influxdb.Error{
-				Code: influxdb.EInvalid,
-				Msg:  fmt.Sprintf("the logical operator OR is not supported yet at position %d", pos.Char),
-			}
-		case influxql.LPAREN:
-			p.openParen++
-			currParen := p.openParen
-			n1, err := p.parseLogicalNode()
-			if err != nil {
-				return *n, err
-			}
-			if p.openParen != currParen-1 {
-				return *n, &influxdb.Error{
-					Code: influxdb.EInvalid,
-					Msg:  fmt.Sprintf("extra ( seen"),
-				}
-			}
-			if n.Children[0] == nil {
-				n.Children[0] = n1
-			} else {
-				n.Children[1] = n1
-			}
+			return n, nil
+		}
+		n1, err := p.parseAndNode()
+		if err != nil {
+			return n, err
+		}
+		n = LogicalNode{
+			Children: [2]Node{n, n1},
+			Operator: LogicalOr,
+		}
+	}
+}
+
+// parseAndNode parses expressions joined by AND.
+func (p *parser) parseAndNode() (Node, error) {
+	n, err := p.parseParenOrTagRuleNode()
+	if err != nil {
+		return n, err
+	}
+	for {
+		if tok, _, _ := p.scanIgnoreWhitespace(); tok != influxql.AND {
+			p.unscan()
+			return n, nil
+		}
+		n1, err := p.parseParenOrTagRuleNode()
+		if err != nil {
+			return n, err
+		}
+		n = LogicalNode{
+			Children: [2]Node{n, n1},
+			Operator: LogicalAnd,
+		}
+	}
+}
+
+// parseParenOrTagRuleNode parses a parenthesized expression or a single tag rule.
+func (p *parser) parseParenOrTagRuleNode() (Node, error) {
+	tok, pos, _ := p.scanIgnoreWhitespace()
+	switch tok {
+	case influxql.NUMBER, influxql.INTEGER, influxql.NAME, influxql.IDENT:
+		p.unscan()
+		return p.parseTagRuleNode()
+	case influxql.LPAREN:
+		p.openParen++
+		n, err := p.parseOrNode()
+		if err != nil {
+			return n, err
+		}
+		tok, pos, _ = p.scanIgnoreWhitespace()
+		switch tok {
 		case influxql.RPAREN:
 			p.openParen--
-			fallthrough
+			return n, nil
 		case influxql.EOF:
-			if p.openParen < 0 {
-				return *n, &influxdb.Error{
-					Code: influxdb.EInvalid,
-					Msg:  fmt.Sprintf("extra ) seen"),
-				}
-			}
-			if n.Children[1] == nil {
-				return n.Children[0], nil
+			return n, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("extra ( seen"),
 			}
-			return *n, nil
 		default:
-			return *n, &influxdb.Error{
+			return n, newParseError(pos, fmt.Sprintf("bad logical expression, at position %d", pos.Char))
+		}
+	case influxql.EOF:
+		if p.openParen > 0 {
+			return nil, &influxdb.Error{
 				Code: influxdb.EInvalid,
-				Msg:  fmt.Sprintf("bad logical expression, at position %d", pos.Char),
+				Msg:  fmt.Sprintf("extra ( seen"),
 			}
 		}
 	}
+	return nil, newParseError(pos, fmt.Sprintf("bad logical expression, at position %d", pos.Char))
 }
 
 func (p *parser) parseTagRuleNode() (TagRuleNode, error) {
@@ -163,10 +207,7 @@ func (p *parser) parseTagRuleNode() (TagRuleNode, error) {
 	case influxql.NAME:
 		n.Key = "name"
 	default:
-		return *n, &influxdb.Error{
-			Code: influxdb.EInvalid,
-			Msg:  fmt.Sprintf("bad tag key, at position %d", pos.Char),
-		}
+		return *n, newParseError(pos, fmt.Sprintf("bad tag key, at position %d", pos.Char))
 	}
 
 	tok, pos, _ = p.scanIgnoreWhitespace()
@@ -180,15 +221,9 @@ func (p *parser) parseTagRuleNode() (TagRuleNode, error) {
 	case influxql.EQREGEX:
 		fallthrough
 	case influxql.NEQREGEX:
-		return *n, &influxdb.Error{
-			Code: influxdb.EInvalid,
-			Msg:  fmt.Sprintf("operator: %q at position: %d is not supported yet", tok.String(), pos.Char),
-		}
+		return *n, newParseError(pos, fmt.Sprintf("operator: %q at position: %d is not supported yet", tok.String(), pos.Char))
 	default:
-		return *n, &influxdb.Error{
-			Code: influxdb.EInvalid,
-			Msg:  fmt.Sprintf("invalid operator %q at position: %d", tok.String(), pos.Char),
-		}
+		return *n, newParseError(pos, fmt.Sprintf("invalid operator %q at position: %d", tok.String(), pos.Char))
 	}
 	// scan the value
 scanRegularTagValue:
@@ -213,19 +248,6 @@ scanRegularTagValue:
 		n.Value = "false"
 		return *n, nil
 	default:
-		return *n, &influxdb.Error{
-			Code: influxdb.EInvalid,
-			Msg:  fmt.Sprintf("bad tag value: %q, at position %d", lit, pos.Char),
-		}
+		return *n, newParseError(pos, fmt.Sprintf("bad tag value: %q, at position %d", lit, pos.Char))
 	}
 }
-
-// peekRune returns the next rune that would be read by the scanner.
-func (p *parser) peekTok() influxql.Token {
-	tok, _, _ := p.scanIgnoreWhitespace()
-	if tok != influxql.EOF {
-		p.unscan()
-	}
-
-	return tok
-}
@@ -7,7 +7,6 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/influxdata/influxdb"
-	influxtesting "github.com/influxdata/influxdb/testing"
 	"github.com/influxdata/influxql"
 )
 
@@ -21,6 +20,13 @@ func TestParseNode(t *testing.T) {
 			str:  `abc=opq`,
 			node: TagRuleNode{Tag: influxdb.Tag{Key: "abc", Value: "opq"}},
 		},
+		{
+			str: `host!="a" and region="west"`,
+			node: LogicalNode{Operator: LogicalAnd, Children: [2]Node{
+				TagRuleNode{Tag: influxdb.Tag{Key: "host", Value: "a"}, Operator: influxdb.NotEqual},
+				TagRuleNode{Tag: influxdb.Tag{Key: "region", Value: "west"}},
+			}},
+		},
 		{
 			str: `abc=opq and gender="male"`,
 			node: LogicalNode{Operator: LogicalAnd, Children: [2]Node{
@@ -40,9 +46,52 @@ func TestParseNode(t *testing.T) {
 		},
 		{
 			str: ` abc="opq" Or gender="male" OR temp=1123`,
+			node: LogicalNode{Operator: LogicalOr, Children: [2]Node{
+				LogicalNode{Operator: LogicalOr, Children: [2]Node{
+					TagRuleNode{Tag: influxdb.Tag{Key: "abc", Value: "opq"}},
+					TagRuleNode{Tag: influxdb.Tag{Key: "gender", Value: "male"}},
+				}},
+				TagRuleNode{Tag: influxdb.Tag{Key: "temp", Value: "1123"}},
+			}},
+		},
+		{
+			str: `a="1" or b="2" and c="3"`,
+			node: LogicalNode{Operator: LogicalOr, Children: [2]Node{
+				TagRuleNode{Tag: influxdb.Tag{Key: "a", Value: "1"}},
+				LogicalNode{Operator: LogicalAnd, Children: [2]Node{
+					TagRuleNode{Tag: influxdb.Tag{Key: "b", Value: "2"}},
+					TagRuleNode{Tag: influxdb.Tag{Key: "c", Value: "3"}},
+				}},
+			}},
+		},
+		{
+			str: `a="1" and (b="2" or c="3")`,
+			node: LogicalNode{Operator: LogicalAnd, Children: [2]Node{
+				TagRuleNode{Tag: influxdb.Tag{Key: "a", Value: "1"}},
+				LogicalNode{Operator: LogicalOr, Children: [2]Node{
+					TagRuleNode{Tag: influxdb.Tag{Key: "b", Value: "2"}},
+					TagRuleNode{Tag: influxdb.Tag{Key: "c", Value: "3"}},
+				}},
+			}},
+		},
+		{
+			str: `abc="opq" or gender=~/male/`,
 			err: &influxdb.Error{
 				Code: influxdb.EInvalid,
-				Msg:  "the logical operator OR is not supported yet at position 11",
+				Err: &ParseError{
+					Msg:      `operator: "=~" at position: 19 is not supported yet`,
+					Position: 19,
+				},
+			},
+		},
+		{
+			str: `abc="opq" or`,
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Err: &ParseError{
+					Msg:      "bad logical expression, at position 12",
+					Position: 12,
+				},
 			},
 		},
 		{
@@ -81,7 +130,9 @@ func TestParseNode(t *testing.T) {
 	}
 	for _, c := range cases {
 		node, err := Parse(c.str)
-		influxtesting.ErrorsEqual(t, err, c.err)
+		if diff := cmp.Diff(c.err, err); diff != "" {
+			t.Errorf("error mismatch:\n  %s", diff)
+		}
 		if c.err == nil {
 			if diff := cmp.Diff(node, c.node); diff != "" {
 				t.Errorf("tag rule mismatch:\n  %s", diff)
@@ -136,21 +187,30 @@ func TestParseTagRule(t *testing.T) {
 			str: `abc!~/^payments\./`,
 			err: &influxdb.Error{
 				Code: influxdb.EInvalid,
-				Msg:  `operator: "!~" at position: 3 is not supported yet`,
+				Err: &ParseError{
+					Msg:      `operator: "!~" at position: 3 is not supported yet`,
+					Position: 3,
+				},
 			},
 		},
 		{
 			str: `abc=~/^payments\./`,
 			err: &influxdb.Error{
 				Code: influxdb.EInvalid,
-				Msg:  `operator: "=~" at position: 3 is not supported yet`,
+				Err: &ParseError{
+					Msg:      `operator: "=~" at position: 3 is not supported yet`,
+					Position: 3,
+				},
 			},
 		},
 		{
 			str: `abc>1000`,
 			err: &influxdb.Error{
 				Code: influxdb.EInvalid,
-				Msg:  `invalid operator ">" at position: 3`,
+				Err: &ParseError{
+					Msg:      `invalid operator ">" at position: 3`,
+					Position: 3,
+				},
 			},
 		},
 	}
@@ -158,7 +218,9 @@ func TestParseTagRule(t *testing.T) {
 		p := new(parser)
 		p.sc = influxql.NewScanner(strings.NewReader(c.str))
 		tr, err := p.parseTagRuleNode()
-		influxtesting.ErrorsEqual(t, err, c.err)
+		if diff := cmp.Diff(c.err, err); diff != "" {
+			t.Errorf("error mismatch:\n  %s", diff)
+		}
 		if c.err == nil {
 			if diff := cmp.Diff(tr, c.node); diff != "" {
 				t.Errorf("tag rule mismatch:\n  %s", diff)
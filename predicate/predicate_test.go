@@ -354,3 +354,82 @@ func TestDataTypeConversion(t *testing.T) {
 		}
 	}
 }
+
+func TestNotEqualMatches(t *testing.T) {
+	cases := []struct {
+		name    string
+		str     string
+		key     string
+		matches bool
+	}{
+		{
+			name:    "other tag value",
+			str:     `host!="a"`,
+			key:     "m,host=b",
+			matches: true,
+		},
+		{
+			name:    "same tag value",
+			str:     `host!="a"`,
+			key:     "m,host=a",
+			matches: false,
+		},
+		{
+			name:    "missing tag",
+			str:     `host!="a"`,
+			key:     "m,region=west",
+			matches: true,
+		},
+		{
+			name:    "and other tag matches",
+			str:     `host!="a" and region="west"`,
+			key:     "m,host=b,region=west",
+			matches: true,
+		},
+		{
+			name:    "and other tag does not match",
+			str:     `host!="a" and region="west"`,
+			key:     "m,host=b,region=east",
+			matches: false,
+		},
+		{
+			name:    "and excluded tag value",
+			str:     `host!="a" and region="west"`,
+			key:     "m,host=a,region=west",
+			matches: false,
+		},
+		{
+			name:    "or either tag matches",
+			str:     `host!="a" or region="west"`,
+			key:     "m,host=a,region=west",
+			matches: true,
+		},
+		{
+			name:    "or neither tag matches",
+			str:     `host!="a" or region="west"`,
+			key:     "m,host=a,region=east",
+			matches: false,
+		},
+		{
+			name:    "and with nested or",
+			str:     `host="a" and (region="west" or region="east")`,
+			key:     "m,host=a,region=east",
+			matches: true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			node, err := Parse(c.str)
+			if err != nil {
+				t.Fatalf("unexpected parse error: %v", err)
+			}
+			pred, err := New(node)
+			if err != nil {
+				t.Fatalf("unexpected predicate error: %v", err)
+			}
+			if got := pred.Matches([]byte(c.key)); got != c.matches {
+				t.Errorf("Matches(%q) = %v, want %v", c.key, got, c.matches)
+			}
+		})
+	}
+}
@@ -13,6 +13,7 @@ import (
 // ProxyQueryService mocks the idpe QueryService for testing.
 type ProxyQueryService struct {
 	QueryF func(ctx context.Context, w io.Writer, req *query.ProxyRequest) (flux.Statistics, error)
+	CheckF func(ctx context.Context) check.Response
 }
 
 // Query writes the results of the query request.
@@ -21,6 +22,9 @@ func (s *ProxyQueryService) Query(ctx context.Context, w io.Writer, req *query.P
 }
 
 func (s *ProxyQueryService) Check(ctx context.Context) check.Response {
+	if s.CheckF != nil {
+		return s.CheckF(ctx)
+	}
 	return check.Response{Name: "Mock Proxy Query Service", Status: check.StatusPass}
 }
 
@@ -683,6 +683,107 @@ func FindBuckets(
 				},
 			},
 		},
+		{
+			name: "find all buckets sorted by name",
+			fields: BucketFields{
+				Organizations: []*influxdb.Organization{
+					{
+						Name: "theorg",
+						ID:   MustIDBase16(orgOneID),
+					},
+				},
+				Buckets: []*influxdb.Bucket{
+					{
+						ID:    MustIDBase16(bucketOneID),
+						OrgID: MustIDBase16(orgOneID),
+						Name:  "xyz",
+					},
+					{
+						ID:    MustIDBase16(bucketTwoID),
+						OrgID: MustIDBase16(orgOneID),
+						Name:  "abc",
+					},
+					{
+						ID:    MustIDBase16(bucketThreeID),
+						OrgID: MustIDBase16(orgOneID),
+						Name:  "def",
+					},
+				},
+			},
+			args: args{
+				findOptions: influxdb.FindOptions{
+					SortBy: "name",
+				},
+			},
+			wants: wants{
+				buckets: []*influxdb.Bucket{
+					{
+						ID:    MustIDBase16(bucketTwoID),
+						OrgID: MustIDBase16(orgOneID),
+						Name:  "abc",
+					},
+					{
+						ID:    MustIDBase16(bucketThreeID),
+						OrgID: MustIDBase16(orgOneID),
+						Name:  "def",
+					},
+					{
+						ID:    MustIDBase16(bucketOneID),
+						OrgID: MustIDBase16(orgOneID),
+						Name:  "xyz",
+					},
+				},
+			},
+		},
+		{
+			name: "find all buckets sorted by name descending",
+			fields: BucketFields{
+				Organizations: []*influxdb.Organization{
+					{
+						Name: "theorg",
+						ID:   MustIDBase16(orgOneID),
+					},
+				},
+				Buckets: []*influxdb.Bucket{
+					{
+						ID:    MustIDBase16(bucketOneID),
+						OrgID: MustIDBase16(orgOneID),
+						Name:  "xyz",
+					},
+					{
+						ID:    MustIDBase16(bucketTwoID),
+						OrgID: MustIDBase16(orgOneID),
+						Name:  "abc",
+					},
+					{
+						ID:    MustIDBase16(bucketThreeID),
+						OrgID: MustIDBase16(orgOneID),
+						Name:  "def",
+					},
+				},
+			},
+			args: args{
+				findOptions: influxdb.FindOptions{
+					SortBy:     "name",
+					Descending: true,
+					Limit:      2,
+				},
+			},
+			wants: wants{
+				buckets: []*influxdb.Bucket{
+					{
+						ID:    MustIDBase16(bucketOneID),
+						OrgID: MustIDBase16(orgOneID),
+						Name:  "xyz",
+					},
+					{
+						ID:    MustIDBase16(bucketThreeID),
+						OrgID: MustIDBase16(orgOneID),
+						Name:  "def",
+					},
+				},
+			},
+		},
 		{
 			name: "find buckets by organization name",
 			fields: BucketFields{
@@ -131,9 +131,13 @@ func (p *predicateMatcher) Matches(key []byte) bool {
 		}
 	}
 
-	// If it always needed more then it didn't match. For example, consider if
-	// the predicate matches `tag1=val1` but tag1 is not present in the key.
-	return false
+	// Any tag not present in the key has the empty value. For example, consider
+	// if the predicate matches `tag1!=val1` but tag1 is not present in the key,
+	// the key matches, where it would not match `tag1=val1`.
+	if !p.state.SetMissing() {
+		return false
+	}
+	return p.root.Update() == predicateResponse_true
 }
 
 // Marshal returns a buffer representing the protobuf predicate.
@@ -317,6 +321,19 @@ func (p *predicateState) Reset() {
 	}
 }
 
+// SetMissing sets every key without a value to the empty value and returns true
+// if any key was set.
+func (p *predicateState) SetMissing() bool {
+	var set bool
+	for i := range p.values {
+		if p.values[i] == nil {
+			p.values[i] = []byte{}
+			set = true
+		}
+	}
+	return set
+}
+
 // Set sets the key to be the value and returns true if the key is part of the considered
 // set of keys.
 func (p *predicateState) Set(key, value []byte) bool {
@@ -149,6 +149,32 @@ func TestPredicate_Matches(t *testing.T) {
 			Matches: true,
 		},
 
+		{
+			Name: "Not Equal No Tag",
+			Predicate: predicate(
+				comparisonNode(datatypes.ComparisonNotEqual, tagNode("tag4"), stringNode("val4"))),
+			Key:     "bucketorg,tag3=val3",
+			Matches: true,
+		},
+
+		{
+			Name: "Not Equal Unmatching",
+			Predicate: predicate(
+				comparisonNode(datatypes.ComparisonNotEqual, tagNode("tag3"), stringNode("val3"))),
+			Key:     "bucketorg,tag3=val3",
+			Matches: false,
+		},
+
+		{
+			Name: "Logical And Not Equal No Tag",
+			Predicate: predicate(
+				andNode(
+					comparisonNode(datatypes.ComparisonEqual, tagNode("tag3"), stringNode("val3")),
+					comparisonNode(datatypes.ComparisonNotEqual, tagNode("tag4"), stringNode("val4")))),
+			Key:     "bucketorg,tag3=val3",
+			Matches: true,
+		},
+
 		{
 			Name: "Starts With",
 			Predicate: predicate(
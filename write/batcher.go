@@ -16,6 +16,8 @@ const (
 	DefaultMaxBytes = 500000
 	// DefaultInterval will flush every 10 seconds.
 	DefaultInterval = 10 * time.Second
+	// DefaultRetryInterval will wait 1 second before retrying a failed flush.
+	DefaultRetryInterval = time.Second
 )
 
 // batcher is a write service that batches for another write service.
@@ -25,7 +27,25 @@ var _ platform.WriteService = (*Batcher)(nil)
 type Batcher struct {
 	MaxFlushBytes    int                   // MaxFlushBytes is the maximum number of bytes to buffer before flushing
 	MaxFlushInterval time.Duration         // MaxFlushInterval is the maximum amount of time to wait before flushing
+	MaxRetries       int                   // MaxRetries is the number of times a failed batch is retried before giving up
+	RetryInterval    time.Duration         // RetryInterval is the amount of time to wait between retries of a failed batch
 	Service          platform.WriteService // Service receives batches flushed from Batcher.
+
+	// DeadLetter receives the lines the write service rejects, along with the
+	// error they were rejected with. When set, a batch the write service wrote
+	// partially, returning an error that implements LineErrors, passes its
+	// rejected lines to DeadLetter rather than failing the write.
+	DeadLetter func(line []byte, err error) error
+}
+
+// LineErrors is implemented by the error of a write service that wrote the
+// valid lines of a batch and rejected the others.
+type LineErrors interface {
+	error
+
+	// LineErrors returns the error of each rejected line by its number in
+	// the batch, lines are numbered from 1.
+	LineErrors() map[int]error
 }
 
 // Write reads r in batches and sends to the output.
@@ -62,14 +82,21 @@ func (b *Batcher) Write(ctx context.Context, org, bucket platform.ID, r io.Reade
 // read will close the line channel when there is no more data, or an error occurs.
 // it is possible for an io.Reader to block forever; Write's context can be
 // used to cancel, but, it's possible there will be dangling read go routines.
+// A line split across reads of r is carried over until it is completed by a
+// later read, only whole lines are sent.
 func (b *Batcher) read(ctx context.Context, r io.Reader, lines chan<- []byte, errC chan<- error) {
 	defer close(lines)
 	scanner := bufio.NewScanner(r)
 	scanner.Split(ScanLines)
 	for scanner.Scan() {
+		// the scanner reuses its buffer to carry over a partial line into the
+		// next read, the line is copied so it is not overwritten while it is
+		// still being batched.
+		line := append([]byte(nil), scanner.Bytes()...)
+
 		// exit early if the context is done
 		select {
-		case lines <- scanner.Bytes():
+		case lines <- line:
 		case <-ctx.Done():
 			errC <- ctx.Err()
 			return
@@ -109,9 +136,8 @@ func (b *Batcher) write(ctx context.Context, org, bucket platform.ID, lines <-ch
 			}
 			// write if we exceed the max lines OR read routine has finished
 			if len(buf) >= maxBytes || (!more && len(buf) > 0) {
-				r.Reset(buf)
 				timer.Reset(flushInterval)
-				if err := b.Service.Write(ctx, org, bucket, r); err != nil {
+				if err := b.flush(ctx, org, bucket, r, buf); err != nil {
 					errC <- err
 					return
 				}
@@ -119,9 +145,8 @@ func (b *Batcher) write(ctx context.Context, org, bucket platform.ID, lines <-ch
 			}
 		case <-timer.C:
 			if len(buf) > 0 {
-				r.Reset(buf)
 				timer.Reset(flushInterval)
-				if err := b.Service.Write(ctx, org, bucket, r); err != nil {
+				if err := b.flush(ctx, org, bucket, r, buf); err != nil {
 					errC <- err
 					return
 				}
@@ -136,6 +161,75 @@ func (b *Batcher) write(ctx context.Context, org, bucket platform.ID, lines <-ch
 	errC <- nil
 }
 
+// flush sends buf to the write service, retrying up to MaxRetries times
+// when the write service returns a transient error. Any other error fails
+// the same way every time, it is returned without retrying.
+func (b *Batcher) flush(ctx context.Context, org, bucket platform.ID, r *bytes.Reader, buf []byte) error {
+	retryInterval := b.RetryInterval
+	if retryInterval == 0 {
+		retryInterval = DefaultRetryInterval
+	}
+
+	var err error
+	for attempt := 0; attempt <= b.MaxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(retryInterval):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		r.Reset(buf)
+		if err = b.Service.Write(ctx, org, bucket, r); err == nil {
+			return nil
+		}
+		// the valid lines of a partial write are written, retrying it would
+		// write them again.
+		if lerr, ok := err.(LineErrors); ok && b.DeadLetter != nil {
+			return b.deadLetterLines(buf, lerr.LineErrors())
+		}
+		if !isTransientErr(err) {
+			return err
+		}
+	}
+	return err
+}
+
+// deadLetterLines passes the lines of buf rejected by the write service to
+// DeadLetter, lineErrs holds their errors by line number.
+func (b *Batcher) deadLetterLines(buf []byte, lineErrs map[int]error) error {
+	for n := 1; len(buf) > 0; n++ {
+		line := buf
+		if i := bytes.IndexByte(buf, '\n'); i >= 0 {
+			line = buf[:i+1]
+		}
+		buf = buf[len(line):]
+
+		lerr, ok := lineErrs[n]
+		if !ok {
+			continue
+		}
+		if err := b.DeadLetter(line, lerr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isTransientErr reports whether a failed write may succeed when retried.
+// Server errors, rate limiting and errors that are not platform errors, such
+// as network errors, are transient. Rejected writes, like invalid line
+// protocol or a missing authorization, are not.
+func isTransientErr(err error) bool {
+	switch platform.ErrorCode(err) {
+	case platform.EInternal, platform.EUnavailable, platform.ETooManyRequests:
+		return true
+	default:
+		return false
+	}
+}
+
 // ScanLines is used in bufio.Scanner.Split to split lines of line protocol.
 func ScanLines(data []byte, atEOF bool) (advance int, token []byte, err error) {
 	if atEOF && len(data) == 0 {
@@ -397,6 +397,51 @@ func TestBatcher_Write(t *testing.T) {
 	}
 }
 
+// chunkReader mocks a streamed io.Reader, each read returns the next chunk.
+type chunkReader struct {
+	chunks []string
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	if len(r.chunks) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(p, r.chunks[0])
+	r.chunks[0] = r.chunks[0][n:]
+	if r.chunks[0] == "" {
+		r.chunks = r.chunks[1:]
+	}
+	return n, nil
+}
+
+func TestBatcher_WritePartialLines(t *testing.T) {
+	var got []string
+	svc := &mock.WriteService{
+		WriteF: func(ctx context.Context, org, bucket platform.ID, r io.Reader) error {
+			b, err := ioutil.ReadAll(r)
+			got = append(got, string(b))
+			return err
+		},
+	}
+
+	b := &Batcher{
+		MaxFlushBytes:    1,
+		MaxFlushInterval: time.Hour,
+		Service:          svc,
+	}
+
+	// the second line is split mid way across the chunks of the stream
+	r := &chunkReader{chunks: []string{"m1,t1=v1 f1=1\nm2,t2=v2 f", "2=2\n"}}
+	if err := b.Write(context.Background(), platform.ID(1), platform.ID(2), r); err != nil {
+		t.Fatalf("Batcher.Write() error = %v", err)
+	}
+
+	want := []string{"m1,t1=v1 f1=1\n", "m2,t2=v2 f2=2\n"}
+	if !cmp.Equal(got, want) {
+		t.Errorf("Batcher.Write() partial lines = -got/+want %s", cmp.Diff(got, want))
+	}
+}
+
 func TestBatcher_WriteTimeout(t *testing.T) {
 	// mocking the write service here to either return an error
 	// or get back all the bytes from the reader.
@@ -427,3 +472,233 @@ func TestBatcher_WriteTimeout(t *testing.T) {
 		t.Errorf(" Batcher.Write() with timeout got %s", got)
 	}
 }
+
+func TestBatcher_WriteBatches(t *testing.T) {
+	t.Run("stream is split into batches by size", func(t *testing.T) {
+		var got []string
+		svc := &mock.WriteService{
+			WriteF: func(ctx context.Context, org, bucket platform.ID, r io.Reader) error {
+				b, err := ioutil.ReadAll(r)
+				got = append(got, string(b))
+				return err
+			},
+		}
+
+		b := &Batcher{
+			MaxFlushBytes:    2 * len("m1,t1=v1 f1=1\n"),
+			MaxFlushInterval: time.Hour,
+			Service:          svc,
+		}
+
+		r := strings.NewReader("m1,t1=v1 f1=1\nm2,t2=v2 f2=2\nm3,t3=v3 f3=3\nm4,t4=v4 f4=4\nm5,t5=v5 f5=5")
+		if err := b.Write(context.Background(), platform.ID(1), platform.ID(2), r); err != nil {
+			t.Fatalf("Batcher.Write() error = %v", err)
+		}
+
+		want := []string{
+			"m1,t1=v1 f1=1\nm2,t2=v2 f2=2\n",
+			"m3,t3=v3 f3=3\nm4,t4=v4 f4=4\n",
+			"m5,t5=v5 f5=5",
+		}
+		if !cmp.Equal(got, want) {
+			t.Errorf("Batcher.Write() batches = -got/+want %s", cmp.Diff(got, want))
+		}
+	})
+
+	t.Run("stream is split into batches by interval", func(t *testing.T) {
+		var got []string
+		svc := &mock.WriteService{
+			WriteF: func(ctx context.Context, org, bucket platform.ID, r io.Reader) error {
+				b, err := ioutil.ReadAll(r)
+				got = append(got, string(b))
+				return err
+			},
+		}
+
+		b := &Batcher{
+			MaxFlushInterval: time.Millisecond,
+			Service:          svc,
+		}
+
+		// this mimics a long lived process streaming lines to stdin.
+		r, w := io.Pipe()
+		go func() {
+			w.Write([]byte("m1,t1=v1 f1=1\n"))
+			time.Sleep(100 * time.Millisecond)
+			w.Write([]byte("m2,t2=v2 f2=2\n"))
+			w.Close()
+		}()
+
+		if err := b.Write(context.Background(), platform.ID(1), platform.ID(2), r); err != nil {
+			t.Fatalf("Batcher.Write() error = %v", err)
+		}
+
+		want := []string{"m1,t1=v1 f1=1\n", "m2,t2=v2 f2=2\n"}
+		if !cmp.Equal(got, want) {
+			t.Errorf("Batcher.Write() batches = -got/+want %s", cmp.Diff(got, want))
+		}
+	})
+}
+
+func TestBatcher_WriteRetries(t *testing.T) {
+	tests := []struct {
+		name         string
+		maxRetries   int
+		failures     int
+		err          error
+		want         string
+		wantAttempts int
+		wantErr      bool
+	}{
+		{
+			name:         "failed batch is retried until it succeeds",
+			maxRetries:   3,
+			failures:     2,
+			want:         "m1,t1=v1 f1=1",
+			wantAttempts: 3,
+		},
+		{
+			name:         "failed batch returns error after max retries",
+			maxRetries:   2,
+			failures:     5,
+			wantAttempts: 3,
+			wantErr:      true,
+		},
+		{
+			name:         "rate limited batch is retried",
+			maxRetries:   3,
+			failures:     1,
+			err:          &platform.Error{Code: platform.ETooManyRequests, Msg: "too many requests"},
+			want:         "m1,t1=v1 f1=1",
+			wantAttempts: 2,
+		},
+		{
+			name:         "invalid batch is not retried",
+			maxRetries:   3,
+			failures:     5,
+			err:          &platform.Error{Code: platform.EInvalid, Msg: "unable to parse"},
+			wantAttempts: 1,
+			wantErr:      true,
+		},
+		{
+			name:         "unauthorized batch is not retried",
+			maxRetries:   3,
+			failures:     5,
+			err:          &platform.Error{Code: platform.EUnauthorized, Msg: "unauthorized access"},
+			wantAttempts: 1,
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				got      string
+				attempts int
+			)
+			svc := &mock.WriteService{
+				WriteF: func(ctx context.Context, org, bucket platform.ID, r io.Reader) error {
+					attempts++
+					if attempts <= tt.failures {
+						if tt.err != nil {
+							return tt.err
+						}
+						return fmt.Errorf("error")
+					}
+					b, err := ioutil.ReadAll(r)
+					got = string(b)
+					return err
+				},
+			}
+
+			b := &Batcher{
+				MaxRetries:    tt.maxRetries,
+				RetryInterval: time.Millisecond,
+				Service:       svc,
+			}
+
+			err := b.Write(context.Background(), platform.ID(1), platform.ID(2), strings.NewReader("m1,t1=v1 f1=1"))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Batcher.Write() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if attempts != tt.wantAttempts {
+				t.Errorf("%q. Batcher.Write() attempts %d want %d", tt.name, attempts, tt.wantAttempts)
+			}
+			if !cmp.Equal(got, tt.want) {
+				t.Errorf("%q. Batcher.Write() = -got/+want %s", tt.name, cmp.Diff(got, tt.want))
+			}
+		})
+	}
+}
+
+// lineErrors is the error of a partial write that rejected some lines.
+type lineErrors map[int]error
+
+func (e lineErrors) Error() string             { return "partial write" }
+func (e lineErrors) LineErrors() map[int]error { return e }
+
+func TestBatcher_WriteDeadLetter(t *testing.T) {
+	var (
+		written  []string
+		attempts int
+	)
+	svc := &mock.WriteService{
+		WriteF: func(ctx context.Context, org, bucket platform.ID, r io.Reader) error {
+			attempts++
+			b, err := ioutil.ReadAll(r)
+			if err != nil {
+				return err
+			}
+
+			// a partial write writes the valid lines and reports the others
+			lerrs := lineErrors{}
+			for i, line := range strings.Split(string(b), "\n") {
+				if strings.Contains(line, "malformed") {
+					lerrs[i+1] = &platform.Error{Code: platform.EInvalid, Msg: "unable to parse '" + line + "'"}
+					continue
+				}
+				written = append(written, line)
+			}
+			if len(lerrs) > 0 {
+				return lerrs
+			}
+			return nil
+		},
+	}
+
+	type deadLetter struct {
+		line string
+		err  string
+	}
+	var deadLetters []deadLetter
+
+	b := &Batcher{
+		MaxRetries:    3,
+		RetryInterval: time.Millisecond,
+		Service:       svc,
+		DeadLetter: func(line []byte, err error) error {
+			deadLetters = append(deadLetters, deadLetter{line: string(line), err: err.Error()})
+			return nil
+		},
+	}
+
+	input := "m1,t1=v1 f1=1\nmalformed\n# a comment\nm2,t2=v2 f2=2\nmalformed again"
+	if err := b.Write(context.Background(), platform.ID(1), platform.ID(2), strings.NewReader(input)); err != nil {
+		t.Fatalf("Batcher.Write() unexpected error %v", err)
+	}
+
+	if attempts != 1 {
+		t.Errorf("Batcher.Write() attempts %d want 1, a partial write is not retried", attempts)
+	}
+	wantWritten := []string{"m1,t1=v1 f1=1", "# a comment", "m2,t2=v2 f2=2"}
+	if diff := cmp.Diff(wantWritten, written); diff != "" {
+		t.Errorf("unexpected lines written -want/+got\n\t%s", diff)
+	}
+	wantDeadLetters := []deadLetter{
+		{line: "malformed\n", err: "unable to parse 'malformed'"},
+		{line: "malformed again", err: "unable to parse 'malformed again'"},
+	}
+	if diff := cmp.Diff(wantDeadLetters, deadLetters, cmp.AllowUnexported(deadLetter{})); diff != "" {
+		t.Errorf("unexpected dead letters -want/+got\n\t%s", diff)
+	}
+}
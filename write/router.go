@@ -0,0 +1,132 @@
+package write
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+)
+
+// Router routes line protocol to buckets by measurement. Every line is written
+// to the bucket its measurement is mapped to.
+type Router struct {
+	Buckets       map[string]platform.ID // Buckets maps a measurement to the bucket its lines are written to
+	DefaultBucket platform.ID            // DefaultBucket receives the lines of unmapped measurements, unmapped measurements are an error when it is not valid
+	Service       platform.WriteService  // Service receives the lines routed to each bucket.
+}
+
+// Write splits the line protocol read from r by measurement and writes each
+// subset to its bucket within the org. Each bucket is written to concurrently
+// as its lines are read.
+func (rt *Router) Write(ctx context.Context, org platform.ID, r io.Reader) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	if rt.Service == nil {
+		return fmt.Errorf("destination write service required")
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		writeErr error
+	)
+	routes := make(map[platform.ID]*io.PipeWriter)
+	route := func(bucket platform.ID) *io.PipeWriter {
+		if pw, ok := routes[bucket]; ok {
+			return pw
+		}
+
+		pr, pw := io.Pipe()
+		routes[bucket] = pw
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := rt.Service.Write(ctx, org, bucket, pr)
+			// unblocks any further writes of lines to this bucket
+			pr.CloseWithError(err)
+			if err != nil {
+				mu.Lock()
+				if writeErr == nil {
+					writeErr = err
+				}
+				mu.Unlock()
+			}
+		}()
+		return pw
+	}
+
+	err := rt.route(r, route)
+	for _, pw := range routes {
+		pw.CloseWithError(err)
+	}
+	wg.Wait()
+
+	if writeErr != nil {
+		return writeErr
+	}
+	return err
+}
+
+func (rt *Router) route(r io.Reader, route func(bucket platform.ID) *io.PipeWriter) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Split(ScanLines)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		bucket, ok, err := rt.bucket(line)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+
+		pw := route(bucket)
+		if _, err := pw.Write(line); err != nil {
+			return err
+		}
+		// the final line may not be newline terminated
+		if line[len(line)-1] != '\n' {
+			if _, err := pw.Write([]byte{'\n'}); err != nil {
+				return err
+			}
+		}
+	}
+	return scanner.Err()
+}
+
+// bucket returns the bucket the line is routed to. Blank lines and comments
+// are not routed.
+func (rt *Router) bucket(line []byte) (platform.ID, bool, error) {
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 || line[0] == '#' {
+		return 0, false, nil
+	}
+
+	m := string(models.UnescapeMeasurement(measurement(line)))
+	if bucket, ok := rt.Buckets[m]; ok {
+		return bucket, true, nil
+	}
+	if rt.DefaultBucket.Valid() {
+		return rt.DefaultBucket, true, nil
+	}
+	return 0, false, fmt.Errorf("no bucket mapped for measurement %q", m)
+}
+
+// measurement returns the escaped measurement of a line of line protocol.
+func measurement(line []byte) []byte {
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++
+		case ',', ' ':
+			return line[:i]
+		}
+	}
+	return line
+}
@@ -0,0 +1,130 @@
+package write
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+)
+
+func newRecordingWriteService() (*mock.WriteService, map[platform.ID]string) {
+	var mu sync.Mutex
+	written := make(map[platform.ID]string)
+	svc := &mock.WriteService{
+		WriteF: func(ctx context.Context, org, bucket platform.ID, r io.Reader) error {
+			b, err := ioutil.ReadAll(r)
+			if err != nil {
+				return err
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			written[bucket] += string(b)
+			return nil
+		},
+	}
+	return svc, written
+}
+
+func TestRouter_Write(t *testing.T) {
+	const (
+		bucket1 = platform.ID(1)
+		bucket2 = platform.ID(2)
+		bucket3 = platform.ID(3)
+	)
+
+	tests := []struct {
+		name          string
+		input         string
+		buckets       map[string]platform.ID
+		defaultBucket platform.ID
+		want          map[platform.ID]string
+		wantErr       bool
+	}{
+		{
+			name:  "splits a mixed stream across buckets",
+			input: "cpu,host=a usage=1 1\nmem,host=a used=2 1\ncpu,host=b usage=3 1\nmem,host=b used=4 1",
+			buckets: map[string]platform.ID{
+				"cpu": bucket1,
+				"mem": bucket2,
+			},
+			want: map[platform.ID]string{
+				bucket1: "cpu,host=a usage=1 1\ncpu,host=b usage=3 1\n",
+				bucket2: "mem,host=a used=2 1\nmem,host=b used=4 1\n",
+			},
+		},
+		{
+			name:  "matches escaped measurements",
+			input: "my\\ cpu,host=a usage=1 1\nmy\\,mem used=2 1\n",
+			buckets: map[string]platform.ID{
+				"my cpu": bucket1,
+				"my,mem": bucket2,
+			},
+			want: map[platform.ID]string{
+				bucket1: "my\\ cpu,host=a usage=1 1\n",
+				bucket2: "my\\,mem used=2 1\n",
+			},
+		},
+		{
+			name:          "writes unmapped measurements to the default bucket",
+			input:         "cpu usage=1 1\n# a comment\n\ndisk used=2 1\n",
+			buckets:       map[string]platform.ID{"cpu": bucket1},
+			defaultBucket: bucket3,
+			want: map[platform.ID]string{
+				bucket1: "cpu usage=1 1\n",
+				bucket3: "disk used=2 1\n",
+			},
+		},
+		{
+			name:    "errors on unmapped measurement without a default bucket",
+			input:   "cpu usage=1 1\ndisk used=2 1\n",
+			buckets: map[string]platform.ID{"cpu": bucket1},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, written := newRecordingWriteService()
+			rt := &Router{
+				Buckets:       tt.buckets,
+				DefaultBucket: tt.defaultBucket,
+				Service:       svc,
+			}
+
+			err := rt.Write(context.Background(), platform.ID(9), strings.NewReader(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Router.Write() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if diff := cmp.Diff(tt.want, written); diff != "" {
+				t.Errorf("unexpected lines written -want/+got\n\t%s", diff)
+			}
+		})
+	}
+}
+
+func TestRouter_WriteServiceError(t *testing.T) {
+	svc := &mock.WriteService{
+		WriteF: func(ctx context.Context, org, bucket platform.ID, r io.Reader) error {
+			return fmt.Errorf("error")
+		},
+	}
+	rt := &Router{
+		Buckets: map[string]platform.ID{"cpu": platform.ID(1)},
+		Service: svc,
+	}
+
+	err := rt.Write(context.Background(), platform.ID(9), strings.NewReader("cpu usage=1 1\ncpu usage=2 2\n"))
+	if err == nil {
+		t.Fatal("expected the write service error")
+	}
+}